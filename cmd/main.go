@@ -22,8 +22,26 @@ func main() {
 	db := utils.InitializeDatabase(cfg)
 	redis := utils.InitializeRedis(cfg)
 
+	// Initialize object storage
+	store, err := utils.InitializeStorage(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize storage:", err)
+	}
+
+	// Initialize email delivery
+	mail, err := utils.InitializeMailer(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize mailer:", err)
+	}
+
+	// Initialize SMS delivery
+	sender, err := utils.InitializeSMSSender(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize SMS sender:", err)
+	}
+
 	// Setup routes
-	route.SetupRoutes(app, db, redis, cfg)
+	route.SetupRoutes(app, db, redis, store, mail, sender, cfg)
 
 	// Get port
 	port := utils.GetPort()
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -11,16 +12,18 @@ import (
 	"strings"
 
 	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/pkg/rls"
 )
 
 func main() {
 	// Parse command line flags
 	var (
-		action  = flag.String("action", "", "Migration action: up, down, create, version, force, wipe")
+		action  = flag.String("action", "", "Migration action: up, down, create, create-rls, version, force, wipe")
 		steps   = flag.Int("steps", 0, "Number of steps for up/down (0 = all)")
 		version = flag.Int("version", 0, "Version for force command")
 		name    = flag.String("name", "", "Name for create command")
 		confirm = flag.Bool("confirm", false, "Confirm wipe data (required for wipe action)")
+		tables  = flag.String("tables", "", "Comma-separated tenant-scoped tables for create-rls command")
 	)
 	flag.Parse()
 
@@ -36,14 +39,16 @@ func main() {
 	fmt.Printf("  DB_NAME: %s\n", cfg.Database.Name)
 	fmt.Printf("  DB_SSL_MODE: %s\n", cfg.Database.SSLMode)
 
-	// Build database URL
-	dbURL := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
+	// Build database URL. Migrations work across tenants, so the session
+	// turns on the row security bypass that tenant policies honor.
+	dbURL := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s&options=%s",
 		cfg.Database.User,
 		cfg.Database.Password,
 		cfg.Database.Host,
 		cfg.Database.Port,
 		cfg.Database.Name,
 		cfg.Database.SSLMode,
+		url.QueryEscape("-c "+rls.BypassSetting+"=on"),
 	)
 
 	// Get migrations directory
@@ -83,6 +88,15 @@ func main() {
 		}
 		createSequentialMigration(migrationsDir, *name)
 
+	case "create-rls":
+		if *tables == "" {
+			log.Fatal("Tables are required for create-rls command")
+		}
+		if *name == "" {
+			*name = "enable_row_level_security"
+		}
+		createRLSMigration(migrationsDir, *name, strings.Split(*tables, ","))
+
 	case "status":
 		runMigrateCommand("-path", migrationsDir, "-database", dbURL, "version")
 
@@ -117,6 +131,7 @@ func main() {
 		fmt.Println("  go run cmd/migrate/main.go -action=version     # Show current version")
 		fmt.Println("  go run cmd/migrate/main.go -action=force -version=1 # Force to version")
 		fmt.Println("  go run cmd/migrate/main.go -action=create -name=add_users # Create new migration")
+		fmt.Println("  go run cmd/migrate/main.go -action=create-rls -tables=orders,payments # Create tenant isolation policies")
 		fmt.Println("  go run cmd/migrate/main.go -action=status     # Show migration status")
 		fmt.Println("  go run cmd/migrate/main.go -action=wipe -confirm # Wipe all data and recreate schema")
 		fmt.Println("")
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"boilerplate-go-fiber-v2/pkg/rls"
+)
+
+// createRLSMigration creates a migration that isolates tables by their org_id
+// column, with user_id for rows outside any organization
+func createRLSMigration(migrationsDir, name string, tables []string) {
+	nextNumber := getNextMigrationNumber(migrationsDir)
+
+	up := []string{fmt.Sprintf("-- Migration %05d: %s\n-- Up migration", nextNumber, name)}
+	down := []string{fmt.Sprintf("-- Migration %05d: %s\n-- Down migration", nextNumber, name)}
+	for i := range tables {
+		enable, err := rls.EnableSQL(strings.TrimSpace(tables[i]), "org_id", "user_id")
+		if err != nil {
+			log.Fatal("Failed to generate policy:", err)
+		}
+		up = append(up, enable)
+
+		// Undo in reverse order
+		disable, err := rls.DisableSQL(strings.TrimSpace(tables[len(tables)-1-i]))
+		if err != nil {
+			log.Fatal("Failed to generate policy:", err)
+		}
+		down = append(down, disable)
+	}
+
+	upFile := fmt.Sprintf("%s/%05d_%s.up.sql", migrationsDir, nextNumber, name)
+	downFile := fmt.Sprintf("%s/%05d_%s.down.sql", migrationsDir, nextNumber, name)
+
+	if err := os.WriteFile(upFile, []byte(strings.Join(up, "\n")), 0644); err != nil {
+		log.Fatal("Failed to create up migration file:", err)
+	}
+
+	if err := os.WriteFile(downFile, []byte(strings.Join(down, "\n")), 0644); err != nil {
+		log.Fatal("Failed to create down migration file:", err)
+	}
+
+	fmt.Printf("✅ Created row level security migration files:\n")
+	fmt.Printf("  📄 %s\n", upFile)
+	fmt.Printf("  📄 %s\n", downFile)
+	fmt.Printf("  🔢 Migration number: %05d\n", nextNumber)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/pkg/pwned"
+)
+
+func main() {
+	// Parse command line flags
+	var (
+		action = flag.String("action", "", "Dataset action: import, status")
+		source = flag.String("source", "", "Range file directory or HASH:COUNT file for import")
+	)
+	flag.Parse()
+
+	// Load configuration
+	cfg := config.Load()
+	dir := cfg.Validation.BreachDatasetDir
+
+	// Execute action
+	switch *action {
+	case "import":
+		if *source == "" {
+			log.Fatal("Source is required for import command")
+		}
+
+		fmt.Printf("📥 Importing breached passwords from %s...\n", *source)
+		stats, err := pwned.Import(*source, dir)
+		if err != nil {
+			log.Fatal("Import failed: ", err)
+		}
+		fmt.Printf("✅ Imported %d hashes in %d range files into %s\n", stats.Hashes, stats.Prefixes, dir)
+
+	case "status":
+		stats, err := pwned.Stats(dir)
+		if err != nil {
+			log.Fatal("Failed to read dataset: ", err)
+		}
+		fmt.Printf("📂 Dataset: %s\n", dir)
+		fmt.Printf("  Range files: %d\n", stats.Prefixes)
+		fmt.Printf("  Hashes: %d\n", stats.Hashes)
+		fmt.Printf("  Block threshold: %d\n", cfg.Validation.BreachThreshold)
+
+	default:
+		fmt.Println("🔐 Breached Password Dataset Tool")
+		fmt.Println("")
+		fmt.Println("Usage:")
+		fmt.Println("  go run cmd/passwords/main.go -action=import -source=./pwned-passwords # Import range files")
+		fmt.Println("  go run cmd/passwords/main.go -action=import -source=./hashes.txt     # Import an ordered HASH:COUNT file")
+		fmt.Println("  go run cmd/passwords/main.go -action=status                          # Show dataset status")
+		fmt.Println("")
+		fmt.Printf("📂 Dataset: %s\n", dir)
+	}
+}
@@ -4,19 +4,39 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
-	Email    EmailConfig
-	TFA      TFAConfig
-	Payment  PaymentConfig
+	Server        ServerConfig
+	Database      DatabaseConfig
+	Redis         RedisConfig
+	JWT           JWTConfig
+	Email         EmailConfig
+	TFA           TFAConfig
+	Payment       PaymentConfig
+	Privacy       PrivacyConfig
+	Storage       StorageConfig
+	Avatar        AvatarConfig
+	Response      ResponseConfig
+	Validation    ValidationConfig
+	Hashing       HashingConfig
+	Password      PasswordConfig
+	SMS           SMSConfig
+	OTP           OTPConfig
+	StepUp        StepUpConfig
+	Impersonation ImpersonationConfig
+	RBAC          RBACConfig
+	APIToken      APITokenConfig
+	OAuth         OAuthConfig
+	OIDC          OIDCConfig
+	MagicLink     MagicLinkConfig
+	WebAuthn      WebAuthnConfig
+	Organization  OrganizationConfig
 }
 
 type ServerConfig struct {
@@ -47,18 +67,25 @@ type JWTConfig struct {
 }
 
 type EmailConfig struct {
+	Driver       string // "smtp" or "log"
+	From         string
 	SMTPHost     string
 	SMTPPort     int
 	SMTPUsername string
 	SMTPPassword string
 	SendGridKey  string
+
+	ChangeConfirmURL string // email change links; the token is appended
+	ChangeCancelURL  string
+	ChangeTokenTTL   time.Duration
 }
 
 type TFAConfig struct {
-	Issuer    string
-	Algorithm string
-	Digits    int
-	Period    int
+	Issuer       string
+	Algorithm    string
+	Digits       int
+	Period       int
+	ChallengeTTL time.Duration // how long a login may wait for the second factor
 }
 
 type PaymentConfig struct {
@@ -69,6 +96,153 @@ type PaymentConfig struct {
 	MidtransBaseURL   string
 }
 
+type PrivacyConfig struct {
+	ExportDir             string
+	ExportTTL             time.Duration
+	ExportTimeout         time.Duration // builds running longer are failed
+	DeletionGracePeriod   time.Duration
+	DeletionCheckInterval time.Duration
+}
+
+type StorageConfig struct {
+	Driver        string
+	Public        bool
+	URLExpiry     time.Duration
+	LocalDir      string
+	LocalBaseURL  string
+	SigningKey    string
+	S3Endpoint    string
+	S3Region      string
+	S3Bucket      string
+	S3AccessKey   string
+	S3SecretKey   string
+	S3PathStyle   bool
+	PublicBaseURL string
+}
+
+type AvatarConfig struct {
+	MaxSize      int64
+	MaxDimension int
+	Sizes        []int
+}
+
+type ResponseConfig struct {
+	ErrorFormat        string // "envelope" or "problem" (RFC 9457)
+	ProblemTypeBaseURL string
+}
+
+type ValidationConfig struct {
+	DefaultCountryCode    string
+	PasswordMinLength     int
+	PasswordMaxLength     int
+	PasswordRequireUpper  bool
+	PasswordRequireLower  bool
+	PasswordRequireDigit  bool
+	PasswordRequireSymbol bool
+	PasswordMinEntropy    int
+	BreachDatasetDir      string
+	BreachThreshold       int
+}
+
+type HashingConfig struct {
+	Algorithm         string // "argon2id" or "bcrypt"
+	Argon2Memory      int    // KiB
+	Argon2Iterations  int
+	Argon2Parallelism int
+	BcryptCost        int
+}
+
+type PasswordConfig struct {
+	HistorySize    int           // recent passwords that cannot be reused, 0 disables
+	MaxAge         time.Duration // forces a change at login once exceeded, 0 disables
+	ChangeTokenTTL time.Duration
+	ResetTokenTTL  time.Duration
+	ResetURL       string // reset link sent by email; the token is appended
+}
+
+type SMSConfig struct {
+	Driver           string // "twilio" or "capture"
+	From             string
+	TwilioBaseURL    string
+	TwilioAccountSID string
+	TwilioAuthToken  string
+}
+
+type OTPConfig struct {
+	Secret         string        // HMAC key for stored codes
+	TTL            time.Duration // phone verification codes
+	LoginTTL       time.Duration // sign-in and step-up codes
+	MaxAttempts    int
+	ResendInterval time.Duration
+	MaxPerHour     int
+}
+
+type StepUpConfig struct {
+	MaxAge time.Duration // how long a login or re-authentication counts as recent
+}
+
+type ImpersonationConfig struct {
+	TTL time.Duration // lifetime of impersonation tokens; they cannot be refreshed
+}
+
+type OrganizationConfig struct {
+	InvitationTTL time.Duration // how long an emailed invitation stays valid
+	InvitationURL string        // invitation link sent by email; the token is appended
+}
+
+type RBACConfig struct {
+	CacheTTL time.Duration // how long permissions stay cached in Redis
+}
+
+type APITokenConfig struct {
+	DefaultTTL       time.Duration // lifetime when the client does not ask for one
+	MaxTTL           time.Duration // upper bound on requested lifetimes
+	LastUsedInterval time.Duration // minimum gap between last-used writes
+}
+
+type OAuthConfig struct {
+	TokenTTL time.Duration // lifetime of client_credentials access tokens
+}
+
+type OIDCConfig struct {
+	RedirectURL string        // callback URL; {provider} is replaced by the provider name
+	StateTTL    time.Duration // how long a sign-in may take at the provider
+	Providers   []OIDCProviderConfig
+}
+
+type MagicLinkConfig struct {
+	TTL            time.Duration // how long a login link stays valid
+	URL            string        // login link sent by email; the token is appended
+	ResendInterval time.Duration
+	MaxPerHour     int
+	DeviceBinding  string // "off", "optional" or "required"
+	BindIP         bool   // links only work from the IP address that requested them
+}
+
+type WebAuthnConfig struct {
+	RPID             string   // domain passkeys are bound to
+	RPName           string   // shown by the authenticator
+	Origins          []string // origins allowed to run ceremonies
+	Timeout          time.Duration
+	ChallengeTTL     time.Duration // how long a ceremony may take
+	UserVerification string        // "required", "preferred" or "discouraged"
+	Attestation      string        // "none", "indirect" or "direct"
+}
+
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+// defaultOIDCIssuers are used for well-known providers without an issuer set
+var defaultOIDCIssuers = map[string]string{
+	"google":    "https://accounts.google.com",
+	"microsoft": "https://login.microsoftonline.com/common/v2.0",
+}
+
 var AppConfig *Config
 
 func Load() *Config {
@@ -83,6 +257,13 @@ func Load() *Config {
 		log.Printf("Warning: .env file not found, using environment variables")
 	}
 
+	// bcrypt ignores everything past 72 bytes; argon2id has no such limit
+	hashAlgorithm := getViperEnv("PASSWORD_HASH_ALGORITHM", "argon2id")
+	passwordMaxLength := 128
+	if hashAlgorithm == "bcrypt" {
+		passwordMaxLength = 72
+	}
+
 	config := &Config{
 		Server: ServerConfig{
 			Port: getViperEnv("PORT", "8080"),
@@ -108,17 +289,24 @@ func Load() *Config {
 			Expiry: getViperEnvAsDuration("JWT_EXPIRY", 24*time.Hour),
 		},
 		Email: EmailConfig{
+			Driver:       getViperEnv("EMAIL_DRIVER", "log"),
+			From:         getViperEnv("EMAIL_FROM", "no-reply@example.com"),
 			SMTPHost:     getViperEnv("SMTP_HOST", "smtp.gmail.com"),
 			SMTPPort:     getViperEnvAsInt("SMTP_PORT", 587),
 			SMTPUsername: getViperEnv("SMTP_USERNAME", ""),
 			SMTPPassword: getViperEnv("SMTP_PASSWORD", ""),
 			SendGridKey:  getViperEnv("SENDGRID_API_KEY", ""),
+
+			ChangeConfirmURL: getViperEnv("EMAIL_CHANGE_CONFIRM_URL", "http://localhost:3000/email/confirm?token="),
+			ChangeCancelURL:  getViperEnv("EMAIL_CHANGE_CANCEL_URL", "http://localhost:3000/email/cancel?token="),
+			ChangeTokenTTL:   getViperEnvAsDuration("EMAIL_CHANGE_TOKEN_TTL", 24*time.Hour),
 		},
 		TFA: TFAConfig{
-			Issuer:    getViperEnv("TFA_ISSUER", "YourApp"),
-			Algorithm: getViperEnv("TFA_ALGORITHM", "SHA1"),
-			Digits:    getViperEnvAsInt("TFA_DIGITS", 6),
-			Period:    getViperEnvAsInt("TFA_PERIOD", 30),
+			Issuer:       getViperEnv("TFA_ISSUER", "YourApp"),
+			Algorithm:    getViperEnv("TFA_ALGORITHM", "SHA1"),
+			Digits:       getViperEnvAsInt("TFA_DIGITS", 6),
+			Period:       getViperEnvAsInt("TFA_PERIOD", 30),
+			ChallengeTTL: getViperEnvAsDuration("TFA_CHALLENGE_TTL", 5*time.Minute),
 		},
 		Payment: PaymentConfig{
 			XenditAPIKey:      getViperEnv("XENDIT_API_KEY", ""),
@@ -127,6 +315,121 @@ func Load() *Config {
 			MidtransClientKey: getViperEnv("MIDTRANS_CLIENT_KEY", ""),
 			MidtransBaseURL:   getViperEnv("MIDTRANS_BASE_URL", "https://api.midtrans.com"),
 		},
+		Privacy: PrivacyConfig{
+			ExportDir:             getViperEnv("PRIVACY_EXPORT_DIR", "storage/exports"),
+			ExportTTL:             getViperEnvAsDuration("PRIVACY_EXPORT_TTL", 72*time.Hour),
+			ExportTimeout:         getViperEnvAsDuration("PRIVACY_EXPORT_TIMEOUT", 30*time.Minute),
+			DeletionGracePeriod:   getViperEnvAsDuration("ACCOUNT_DELETION_GRACE_PERIOD", 30*24*time.Hour),
+			DeletionCheckInterval: getViperEnvAsDuration("ACCOUNT_DELETION_CHECK_INTERVAL", time.Hour),
+		},
+		Storage: StorageConfig{
+			Driver:        getViperEnv("STORAGE_DRIVER", "local"),
+			Public:        getViperEnvAsBool("STORAGE_PUBLIC", false),
+			URLExpiry:     getViperEnvAsDuration("STORAGE_URL_EXPIRY", 15*time.Minute),
+			LocalDir:      getViperEnv("STORAGE_LOCAL_DIR", "storage/uploads"),
+			LocalBaseURL:  getViperEnv("STORAGE_LOCAL_BASE_URL", "http://localhost:8080/files"),
+			SigningKey:    getViperEnv("STORAGE_SIGNING_KEY", getViperEnv("JWT_SECRET", "your-super-secret-jwt-key")),
+			S3Endpoint:    getViperEnv("S3_ENDPOINT", "http://localhost:9000"),
+			S3Region:      getViperEnv("S3_REGION", "us-east-1"),
+			S3Bucket:      getViperEnv("S3_BUCKET", "uploads"),
+			S3AccessKey:   getViperEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey:   getViperEnv("S3_SECRET_KEY", ""),
+			S3PathStyle:   getViperEnvAsBool("S3_PATH_STYLE", true),
+			PublicBaseURL: getViperEnv("STORAGE_PUBLIC_BASE_URL", ""),
+		},
+		Avatar: AvatarConfig{
+			MaxSize:      int64(getViperEnvAsInt("AVATAR_MAX_SIZE", 2*1024*1024)),
+			MaxDimension: getViperEnvAsInt("AVATAR_MAX_DIMENSION", 4096),
+			Sizes:        getViperEnvAsIntSlice("AVATAR_SIZES", []int{64, 128, 256, 512}),
+		},
+		Response: ResponseConfig{
+			ErrorFormat:        getViperEnv("RESPONSE_ERROR_FORMAT", "envelope"),
+			ProblemTypeBaseURL: getViperEnv("PROBLEM_TYPE_BASE_URL", ""),
+		},
+		Validation: ValidationConfig{
+			DefaultCountryCode:    getViperEnv("PHONE_DEFAULT_COUNTRY_CODE", "62"),
+			PasswordMinLength:     getViperEnvAsInt("PASSWORD_MIN_LENGTH", 8),
+			PasswordMaxLength:     getViperEnvAsInt("PASSWORD_MAX_LENGTH", passwordMaxLength),
+			PasswordRequireUpper:  getViperEnvAsBool("PASSWORD_REQUIRE_UPPER", false),
+			PasswordRequireLower:  getViperEnvAsBool("PASSWORD_REQUIRE_LOWER", false),
+			PasswordRequireDigit:  getViperEnvAsBool("PASSWORD_REQUIRE_DIGIT", false),
+			PasswordRequireSymbol: getViperEnvAsBool("PASSWORD_REQUIRE_SYMBOL", false),
+			PasswordMinEntropy:    getViperEnvAsInt("PASSWORD_MIN_ENTROPY", 36),
+			BreachDatasetDir:      getViperEnv("PASSWORD_BREACH_DATASET_DIR", "storage/pwned"),
+			BreachThreshold:       getViperEnvAsIntOrZero("PASSWORD_BREACH_THRESHOLD", 1),
+		},
+		Hashing: HashingConfig{
+			Algorithm:         hashAlgorithm,
+			Argon2Memory:      getViperEnvAsInt("ARGON2_MEMORY", 64*1024),
+			Argon2Iterations:  getViperEnvAsInt("ARGON2_ITERATIONS", 3),
+			Argon2Parallelism: getViperEnvAsInt("ARGON2_PARALLELISM", 2),
+			BcryptCost:        getViperEnvAsInt("BCRYPT_COST", 12),
+		},
+		Password: PasswordConfig{
+			HistorySize:    getViperEnvAsIntOrZero("PASSWORD_HISTORY_SIZE", 5),
+			MaxAge:         getViperEnvAsDuration("PASSWORD_MAX_AGE", 0),
+			ChangeTokenTTL: getViperEnvAsDuration("PASSWORD_CHANGE_TOKEN_TTL", 15*time.Minute),
+			ResetTokenTTL:  getViperEnvAsDuration("PASSWORD_RESET_TOKEN_TTL", time.Hour),
+			ResetURL:       getViperEnv("PASSWORD_RESET_URL", "http://localhost:3000/reset-password?token="),
+		},
+		SMS: SMSConfig{
+			Driver:           getViperEnv("SMS_DRIVER", "capture"),
+			From:             getViperEnv("SMS_FROM", ""),
+			TwilioBaseURL:    getViperEnv("TWILIO_BASE_URL", "https://api.twilio.com"),
+			TwilioAccountSID: getViperEnv("TWILIO_ACCOUNT_SID", ""),
+			TwilioAuthToken:  getViperEnv("TWILIO_AUTH_TOKEN", ""),
+		},
+		OTP: OTPConfig{
+			Secret:         getViperEnv("OTP_SECRET", getViperEnv("JWT_SECRET", "your-super-secret-jwt-key")),
+			TTL:            getViperEnvAsDuration("OTP_TTL", 10*time.Minute),
+			LoginTTL:       getViperEnvAsDuration("OTP_LOGIN_TTL", 5*time.Minute),
+			MaxAttempts:    getViperEnvAsInt("OTP_MAX_ATTEMPTS", 5),
+			ResendInterval: getViperEnvAsDuration("OTP_RESEND_INTERVAL", time.Minute),
+			MaxPerHour:     getViperEnvAsInt("OTP_MAX_PER_HOUR", 5),
+		},
+		StepUp: StepUpConfig{
+			MaxAge: getViperEnvAsDuration("STEP_UP_MAX_AGE", 10*time.Minute),
+		},
+		Impersonation: ImpersonationConfig{
+			TTL: getViperEnvAsDuration("IMPERSONATION_TTL", 15*time.Minute),
+		},
+		RBAC: RBACConfig{
+			CacheTTL: getViperEnvAsDuration("RBAC_CACHE_TTL", 5*time.Minute),
+		},
+		APIToken: APITokenConfig{
+			DefaultTTL:       getViperEnvAsDuration("API_TOKEN_DEFAULT_TTL", 90*24*time.Hour),
+			MaxTTL:           getViperEnvAsDuration("API_TOKEN_MAX_TTL", 365*24*time.Hour),
+			LastUsedInterval: getViperEnvAsDuration("API_TOKEN_LAST_USED_INTERVAL", time.Minute),
+		},
+		OAuth: OAuthConfig{
+			TokenTTL: getViperEnvAsDuration("OAUTH_TOKEN_TTL", time.Hour),
+		},
+		OIDC: OIDCConfig{
+			RedirectURL: getViperEnv("OIDC_REDIRECT_URL", "http://localhost:3000/auth/callback/{provider}"),
+			StateTTL:    getViperEnvAsDuration("OIDC_STATE_TTL", 10*time.Minute),
+			Providers:   getOIDCProviders(),
+		},
+		MagicLink: MagicLinkConfig{
+			TTL:            getViperEnvAsDuration("MAGIC_LINK_TTL", 15*time.Minute),
+			URL:            getViperEnv("MAGIC_LINK_URL", "http://localhost:3000/magic-link?token="),
+			ResendInterval: getViperEnvAsDuration("MAGIC_LINK_RESEND_INTERVAL", time.Minute),
+			MaxPerHour:     getViperEnvAsInt("MAGIC_LINK_MAX_PER_HOUR", 5),
+			DeviceBinding:  getViperEnv("MAGIC_LINK_DEVICE_BINDING", "optional"),
+			BindIP:         getViperEnvAsBool("MAGIC_LINK_BIND_IP", false),
+		},
+		WebAuthn: WebAuthnConfig{
+			RPID:             getViperEnv("WEBAUTHN_RP_ID", "localhost"),
+			RPName:           getViperEnv("WEBAUTHN_RP_NAME", "YourApp"),
+			Origins:          getViperEnvAsStringSlice("WEBAUTHN_ORIGINS", []string{"http://localhost:3000"}),
+			Timeout:          getViperEnvAsDuration("WEBAUTHN_TIMEOUT", 5*time.Minute),
+			ChallengeTTL:     getViperEnvAsDuration("WEBAUTHN_CHALLENGE_TTL", 10*time.Minute),
+			UserVerification: getViperEnv("WEBAUTHN_USER_VERIFICATION", "preferred"),
+			Attestation:      getViperEnv("WEBAUTHN_ATTESTATION", "none"),
+		},
+		Organization: OrganizationConfig{
+			InvitationTTL: getViperEnvAsDuration("ORG_INVITATION_TTL", 7*24*time.Hour),
+			InvitationURL: getViperEnv("ORG_INVITATION_URL", "http://localhost:3000/invitations/accept?token="),
+		},
 	}
 
 	AppConfig = config
@@ -147,6 +450,52 @@ func getViperEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getViperEnvAsIntOrZero is like getViperEnvAsInt but keeps an explicit 0
+func getViperEnvAsIntOrZero(key string, defaultValue int) int {
+	if viper.IsSet(key) && viper.GetString(key) != "" {
+		return viper.GetInt(key)
+	}
+	return defaultValue
+}
+
+func getViperEnvAsBool(key string, defaultValue bool) bool {
+	if viper.IsSet(key) {
+		return viper.GetBool(key)
+	}
+	return defaultValue
+}
+
+func getViperEnvAsIntSlice(key string, defaultValue []int) []int {
+	value := viper.GetString(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var values []int
+	for _, part := range strings.Split(value, ",") {
+		number, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			log.Printf("Warning: invalid value %q in %s, using default", part, key)
+			return defaultValue
+		}
+		values = append(values, number)
+	}
+	return values
+}
+
+func getViperEnvAsStringSlice(key string, defaultValue []string) []string {
+	var values []string
+	for _, part := range strings.Split(viper.GetString(key), ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	if len(values) == 0 {
+		return defaultValue
+	}
+	return values
+}
+
 func getViperEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := viper.GetDuration(key); value != 0 {
 		return value
@@ -154,6 +503,33 @@ func getViperEnvAsDuration(key string, defaultValue time.Duration) time.Duration
 	return defaultValue
 }
 
+// getOIDCProviders reads the providers listed in OIDC_PROVIDERS. Each one is
+// configured with OIDC_<NAME>_ISSUER, _CLIENT_ID, _CLIENT_SECRET and _SCOPES.
+func getOIDCProviders() []OIDCProviderConfig {
+	var providers []OIDCProviderConfig
+	for _, name := range strings.Split(viper.GetString("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		provider := OIDCProviderConfig{
+			Name:         name,
+			Issuer:       getViperEnv(prefix+"ISSUER", defaultOIDCIssuers[name]),
+			ClientID:     getViperEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getViperEnv(prefix+"CLIENT_SECRET", ""),
+			Scopes:       strings.Fields(getViperEnv(prefix+"SCOPES", "openid email profile")),
+		}
+		if provider.Issuer == "" || provider.ClientID == "" {
+			log.Printf("Warning: OIDC provider %q needs %sISSUER and %sCLIENT_ID, skipping", name, prefix, prefix)
+			continue
+		}
+		providers = append(providers, provider)
+	}
+	return providers
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
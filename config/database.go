@@ -15,7 +15,8 @@ func NewDatabase(config *Config) *gorm.DB {
 	dsn := config.GetDatabaseDSN()
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger:         logger.Default.LogMode(logger.Info),
+		TranslateError: true, // map driver errors such as unique violations to gorm errors
 	})
 
 	if err != nil {
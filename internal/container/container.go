@@ -1,10 +1,18 @@
 package container
 
 import (
+	"context"
+	"log"
+	"time"
+
 	"boilerplate-go-fiber-v2/config"
 	"boilerplate-go-fiber-v2/internal/container/features"
 	domainService "boilerplate-go-fiber-v2/internal/domain/service"
 	"boilerplate-go-fiber-v2/internal/handler"
+	"boilerplate-go-fiber-v2/pkg/mailer"
+	"boilerplate-go-fiber-v2/pkg/sms"
+	"boilerplate-go-fiber-v2/pkg/storage"
+	"boilerplate-go-fiber-v2/pkg/utils"
 
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
@@ -13,26 +21,41 @@ import (
 // Container holds all application dependencies
 type Container struct {
 	// Feature containers
-	Auth *features.AuthContainer
-	User *features.UserContainer
+	Auth         *features.AuthContainer
+	User         *features.UserContainer
+	RBAC         *features.RBACContainer
+	OAuth        *features.OAuthContainer
+	Organization *features.OrganizationContainer
 
 	// Shared dependencies
-	DB     *gorm.DB
-	Redis  *redis.Client
-	Config *config.Config
+	DB      *gorm.DB
+	Redis   *redis.Client
+	Storage storage.Storage
+	Mailer  mailer.Mailer
+	SMS     sms.SMSSender
+	Config  *config.Config
 }
 
 // NewContainer creates and initializes all dependencies
-func NewContainer(db *gorm.DB, redis *redis.Client, cfg *config.Config) *Container {
+func NewContainer(db *gorm.DB, redis *redis.Client, store storage.Storage, mail mailer.Mailer, sender sms.SMSSender, cfg *config.Config) *Container {
 	container := &Container{
-		DB:     db,
-		Redis:  redis,
-		Config: cfg,
+		DB:      db,
+		Redis:   redis,
+		Storage: store,
+		Mailer:  mail,
+		SMS:     sender,
+		Config:  cfg,
 	}
 
+	// Shared password checks
+	passwordChecker := utils.InitializePasswordChecker(cfg)
+
 	// Initialize feature containers
-	container.Auth = features.NewAuthContainer(db, redis, cfg)
-	container.User = features.NewUserContainer(db, redis, cfg)
+	container.RBAC = features.NewRBACContainer(db, redis, cfg)
+	container.Auth = features.NewAuthContainer(db, redis, mail, sender, passwordChecker, container.GetRBACService(), cfg)
+	container.User = features.NewUserContainer(db, redis, store, mail, sender, passwordChecker, container.GetRBACService(), cfg)
+	container.OAuth = features.NewOAuthContainer(db, cfg)
+	container.Organization = features.NewOrganizationContainer(db, mail, container.GetAuthService(), cfg)
 
 	return container
 }
@@ -45,6 +68,102 @@ func (c *Container) GetAuthHandler() *handler.AuthHandler {
 	return nil
 }
 
+// GetOIDCHandler returns OpenID Connect handler
+func (c *Container) GetOIDCHandler() *handler.OIDCHandler {
+	if c.Auth != nil {
+		return c.Auth.GetOIDCHandler()
+	}
+	return nil
+}
+
+// GetWebAuthnHandler returns passkey handler
+func (c *Container) GetWebAuthnHandler() *handler.WebAuthnHandler {
+	if c.Auth != nil {
+		return c.Auth.GetWebAuthnHandler()
+	}
+	return nil
+}
+
+// GetImpersonationHandler returns impersonation handler
+func (c *Container) GetImpersonationHandler() *handler.ImpersonationHandler {
+	if c.Auth != nil {
+		return c.Auth.GetImpersonationHandler()
+	}
+	return nil
+}
+
+// GetUserHandler returns user handler
+func (c *Container) GetUserHandler() *handler.UserHandler {
+	if c.User != nil {
+		return c.User.GetUserHandler()
+	}
+	return nil
+}
+
+// GetRBACHandler returns RBAC handler
+func (c *Container) GetRBACHandler() *handler.RBACHandler {
+	if c.RBAC != nil {
+		return c.RBAC.GetRBACHandler()
+	}
+	return nil
+}
+
+// GetRBACService returns RBAC service
+func (c *Container) GetRBACService() domainService.RBACService {
+	if c.RBAC != nil {
+		return c.RBAC.GetRBACService()
+	}
+	return nil
+}
+
+// GetAuthorizationService returns authorization service
+func (c *Container) GetAuthorizationService() domainService.AuthorizationService {
+	if c.RBAC != nil {
+		return c.RBAC.GetAuthorizationService()
+	}
+	return nil
+}
+
+// GetAPITokenService returns API token service
+func (c *Container) GetAPITokenService() domainService.APITokenService {
+	if c.RBAC != nil {
+		return c.RBAC.GetAPITokenService()
+	}
+	return nil
+}
+
+// GetAPITokenHandler returns API token handler
+func (c *Container) GetAPITokenHandler() *handler.APITokenHandler {
+	if c.RBAC != nil {
+		return c.RBAC.GetAPITokenHandler()
+	}
+	return nil
+}
+
+// GetOAuthHandler returns OAuth handler
+func (c *Container) GetOAuthHandler() *handler.OAuthHandler {
+	if c.OAuth != nil {
+		return c.OAuth.GetOAuthHandler()
+	}
+	return nil
+}
+
+// GetOrganizationHandler returns organization handler
+func (c *Container) GetOrganizationHandler() *handler.OrganizationHandler {
+	if c.Organization != nil {
+		return c.Organization.GetOrganizationHandler()
+	}
+	return nil
+}
+
+// GetPrivacyService returns privacy service
+func (c *Container) GetPrivacyService() domainService.PrivacyService {
+	if c.User != nil {
+		return c.User.GetPrivacyService()
+	}
+	return nil
+}
+
 // GetUserService returns user service
 func (c *Container) GetUserService() domainService.UserService {
 	if c.Auth != nil {
@@ -60,3 +179,34 @@ func (c *Container) GetAuthService() domainService.AuthService {
 	}
 	return nil
 }
+
+// StartBackgroundJobs starts periodic maintenance jobs until ctx is cancelled
+func (c *Container) StartBackgroundJobs(ctx context.Context) {
+	privacyService := c.GetPrivacyService()
+	if privacyService == nil {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(c.Config.Privacy.DeletionCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				processed, err := privacyService.ProcessScheduledDeletions(ctx)
+				if err != nil {
+					log.Println("Failed to process scheduled account deletions:", err)
+				} else if processed > 0 {
+					log.Printf("Anonymized %d account(s) scheduled for deletion", processed)
+				}
+
+				if err := privacyService.CleanExpiredDataExports(ctx); err != nil {
+					log.Println("Failed to clean expired data exports:", err)
+				}
+			}
+		}
+	}()
+}
@@ -7,6 +7,9 @@ import (
 	"boilerplate-go-fiber-v2/internal/handler"
 	repo "boilerplate-go-fiber-v2/internal/repository"
 	"boilerplate-go-fiber-v2/internal/service"
+	"boilerplate-go-fiber-v2/pkg/mailer"
+	"boilerplate-go-fiber-v2/pkg/pwned"
+	"boilerplate-go-fiber-v2/pkg/sms"
 
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
@@ -15,37 +18,69 @@ import (
 // AuthContainer holds auth-related dependencies
 type AuthContainer struct {
 	// Repositories
-	UserRepo repository.UserRepository
-	AuthRepo repository.AuthRepository
+	UserRepo            repository.UserRepository
+	AuthRepo            repository.AuthRepository
+	SecurityEventRepo   repository.SecurityEventRepository
+	PasswordHistoryRepo repository.PasswordHistoryRepository
+	IdentityRepo        repository.IdentityRepository
+	WebAuthnRepo        repository.WebAuthnRepository
 
 	// Services
-	UserService domainService.UserService
-	AuthService domainService.AuthService
+	SecurityEventService domainService.SecurityEventService
+	PasswordService      domainService.PasswordService
+	UserService          domainService.UserService
+	CodeService          domainService.OneTimeCodeService
+	AuthService          domainService.AuthService
+	OIDCService          domainService.OIDCService
+	WebAuthnService      domainService.WebAuthnService
+	ImpersonationService domainService.ImpersonationService
 
 	// Handlers
-	AuthHandler *handler.AuthHandler
+	AuthHandler          *handler.AuthHandler
+	OIDCHandler          *handler.OIDCHandler
+	WebAuthnHandler      *handler.WebAuthnHandler
+	ImpersonationHandler *handler.ImpersonationHandler
 }
 
 // NewAuthContainer creates auth container
-func NewAuthContainer(db *gorm.DB, redis *redis.Client, cfg *config.Config) *AuthContainer {
+func NewAuthContainer(db *gorm.DB, redis *redis.Client, mail mailer.Mailer, sender sms.SMSSender, passwordChecker *pwned.Checker, rbacService domainService.RBACService, cfg *config.Config) *AuthContainer {
 	container := &AuthContainer{}
 
 	// Initialize repositories
 	if db != nil {
 		container.UserRepo = repo.NewUserRepository(db)
 		container.AuthRepo = repo.NewAuthRepository(db)
+		container.SecurityEventRepo = repo.NewSecurityEventRepository(db)
+		container.PasswordHistoryRepo = repo.NewPasswordHistoryRepository(db)
+		container.IdentityRepo = repo.NewIdentityRepository(db)
+		container.WebAuthnRepo = repo.NewWebAuthnRepository(db)
 	}
 
 	// Initialize services
 	if container.UserRepo != nil {
-		container.UserService = service.NewUserService(container.UserRepo)
-		container.AuthService = service.NewAuthService(container.UserRepo, container.AuthRepo, container.UserService, cfg)
+		container.SecurityEventService = service.NewSecurityEventService(container.SecurityEventRepo)
+		container.PasswordService = service.NewPasswordService(passwordChecker, container.PasswordHistoryRepo, cfg.Password.HistorySize)
+		container.UserService = service.NewUserService(container.UserRepo, container.PasswordService, container.SecurityEventService)
+		container.CodeService = service.NewOneTimeCodeService(container.AuthRepo, cfg, service.NewEmailCodeChannel(mail), service.NewSMSCodeChannel(sender))
+		container.AuthService = service.NewAuthService(container.UserRepo, container.AuthRepo, container.UserService, container.PasswordService, container.SecurityEventService, mail, container.CodeService, cfg)
+		container.OIDCService = service.NewOIDCService(container.IdentityRepo, container.WebAuthnRepo, container.UserRepo, container.UserService, container.AuthService, container.SecurityEventService, cfg)
+		container.ImpersonationService = service.NewImpersonationService(container.UserRepo, container.AuthRepo, rbacService, container.SecurityEventService, cfg)
+		container.WebAuthnService = service.NewWebAuthnService(container.WebAuthnRepo, container.UserRepo, container.IdentityRepo, container.AuthService, container.SecurityEventService, cfg)
 	}
 
 	// Initialize handlers
 	if container.AuthService != nil && container.UserService != nil {
 		container.AuthHandler = handler.NewAuthHandler(container.AuthService, container.UserService)
 	}
+	if container.OIDCService != nil {
+		container.OIDCHandler = handler.NewOIDCHandler(container.OIDCService)
+	}
+	if container.WebAuthnService != nil {
+		container.WebAuthnHandler = handler.NewWebAuthnHandler(container.WebAuthnService)
+	}
+	if container.ImpersonationService != nil {
+		container.ImpersonationHandler = handler.NewImpersonationHandler(container.ImpersonationService)
+	}
 
 	return container
 }
@@ -55,6 +90,21 @@ func (c *AuthContainer) GetAuthHandler() *handler.AuthHandler {
 	return c.AuthHandler
 }
 
+// GetOIDCHandler returns OpenID Connect handler
+func (c *AuthContainer) GetOIDCHandler() *handler.OIDCHandler {
+	return c.OIDCHandler
+}
+
+// GetWebAuthnHandler returns passkey handler
+func (c *AuthContainer) GetWebAuthnHandler() *handler.WebAuthnHandler {
+	return c.WebAuthnHandler
+}
+
+// GetImpersonationHandler returns impersonation handler
+func (c *AuthContainer) GetImpersonationHandler() *handler.ImpersonationHandler {
+	return c.ImpersonationHandler
+}
+
 // GetUserService returns user service
 func (c *AuthContainer) GetUserService() domainService.UserService {
 	return c.UserService
@@ -0,0 +1,62 @@
+package features
+
+import (
+	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	domainService "boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/internal/handler"
+	repo "boilerplate-go-fiber-v2/internal/repository"
+	"boilerplate-go-fiber-v2/internal/service"
+
+	"gorm.io/gorm"
+)
+
+// OAuthContainer holds OAuth client and token endpoint dependencies
+type OAuthContainer struct {
+	// Repositories
+	OAuthClientRepo   repository.OAuthClientRepository
+	AuthRepo          repository.AuthRepository
+	SecurityEventRepo repository.SecurityEventRepository
+
+	// Services
+	SecurityEventService domainService.SecurityEventService
+	OAuthService         domainService.OAuthService
+
+	// Handlers
+	OAuthHandler *handler.OAuthHandler
+}
+
+// NewOAuthContainer creates OAuth container
+func NewOAuthContainer(db *gorm.DB, cfg *config.Config) *OAuthContainer {
+	container := &OAuthContainer{}
+
+	// Initialize repositories
+	if db != nil {
+		container.OAuthClientRepo = repo.NewOAuthClientRepository(db)
+		container.AuthRepo = repo.NewAuthRepository(db)
+		container.SecurityEventRepo = repo.NewSecurityEventRepository(db)
+	}
+
+	// Initialize services
+	if container.OAuthClientRepo != nil {
+		container.SecurityEventService = service.NewSecurityEventService(container.SecurityEventRepo)
+		container.OAuthService = service.NewOAuthService(container.OAuthClientRepo, container.AuthRepo, container.SecurityEventService, cfg)
+	}
+
+	// Initialize handlers
+	if container.OAuthService != nil {
+		container.OAuthHandler = handler.NewOAuthHandler(container.OAuthService)
+	}
+
+	return container
+}
+
+// GetOAuthService returns OAuth service
+func (c *OAuthContainer) GetOAuthService() domainService.OAuthService {
+	return c.OAuthService
+}
+
+// GetOAuthHandler returns OAuth handler
+func (c *OAuthContainer) GetOAuthHandler() *handler.OAuthHandler {
+	return c.OAuthHandler
+}
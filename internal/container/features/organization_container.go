@@ -0,0 +1,67 @@
+package features
+
+import (
+	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	domainService "boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/internal/handler"
+	repo "boilerplate-go-fiber-v2/internal/repository"
+	"boilerplate-go-fiber-v2/internal/service"
+	"boilerplate-go-fiber-v2/pkg/mailer"
+
+	"gorm.io/gorm"
+)
+
+// OrganizationContainer holds organization, membership and invitation
+// dependencies
+type OrganizationContainer struct {
+	// Repositories
+	OrganizationRepo  repository.OrganizationRepository
+	UserRepo          repository.UserRepository
+	AuthRepo          repository.AuthRepository
+	SecurityEventRepo repository.SecurityEventRepository
+
+	// Services
+	SecurityEventService domainService.SecurityEventService
+	OrganizationService  domainService.OrganizationService
+
+	// Handlers
+	OrganizationHandler *handler.OrganizationHandler
+}
+
+// NewOrganizationContainer creates organization container. authService
+// reissues tokens when a session switches organization.
+func NewOrganizationContainer(db *gorm.DB, mail mailer.Mailer, authService domainService.AuthService, cfg *config.Config) *OrganizationContainer {
+	container := &OrganizationContainer{}
+
+	// Initialize repositories
+	if db != nil {
+		container.OrganizationRepo = repo.NewOrganizationRepository(db)
+		container.UserRepo = repo.NewUserRepository(db)
+		container.AuthRepo = repo.NewAuthRepository(db)
+		container.SecurityEventRepo = repo.NewSecurityEventRepository(db)
+	}
+
+	// Initialize services
+	if container.OrganizationRepo != nil && authService != nil {
+		container.SecurityEventService = service.NewSecurityEventService(container.SecurityEventRepo)
+		container.OrganizationService = service.NewOrganizationService(container.OrganizationRepo, container.UserRepo, container.AuthRepo, authService, container.SecurityEventService, mail, cfg)
+	}
+
+	// Initialize handlers
+	if container.OrganizationService != nil {
+		container.OrganizationHandler = handler.NewOrganizationHandler(container.OrganizationService)
+	}
+
+	return container
+}
+
+// GetOrganizationService returns organization service
+func (c *OrganizationContainer) GetOrganizationService() domainService.OrganizationService {
+	return c.OrganizationService
+}
+
+// GetOrganizationHandler returns organization handler
+func (c *OrganizationContainer) GetOrganizationHandler() *handler.OrganizationHandler {
+	return c.OrganizationHandler
+}
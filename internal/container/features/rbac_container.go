@@ -0,0 +1,88 @@
+package features
+
+import (
+	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	domainService "boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/internal/handler"
+	repo "boilerplate-go-fiber-v2/internal/repository"
+	"boilerplate-go-fiber-v2/internal/service"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// RBACContainer holds role and permission dependencies
+type RBACContainer struct {
+	// Repositories
+	RBACRepo          repository.RBACRepository
+	UserRepo          repository.UserRepository
+	SecurityEventRepo repository.SecurityEventRepository
+	APITokenRepo      repository.APITokenRepository
+	OrganizationRepo  repository.OrganizationRepository
+
+	// Services
+	SecurityEventService domainService.SecurityEventService
+	RBACService          domainService.RBACService
+	AuthorizationService domainService.AuthorizationService
+	APITokenService      domainService.APITokenService
+
+	// Handlers
+	RBACHandler     *handler.RBACHandler
+	APITokenHandler *handler.APITokenHandler
+}
+
+// NewRBACContainer creates RBAC container
+func NewRBACContainer(db *gorm.DB, redis *redis.Client, cfg *config.Config) *RBACContainer {
+	container := &RBACContainer{}
+
+	// Initialize repositories
+	if db != nil {
+		container.RBACRepo = repo.NewRBACRepository(db)
+		container.UserRepo = repo.NewUserRepository(db)
+		container.SecurityEventRepo = repo.NewSecurityEventRepository(db)
+		container.APITokenRepo = repo.NewAPITokenRepository(db)
+		container.OrganizationRepo = repo.NewOrganizationRepository(db)
+	}
+
+	// Initialize services
+	if container.RBACRepo != nil {
+		container.SecurityEventService = service.NewSecurityEventService(container.SecurityEventRepo)
+		container.RBACService = service.NewRBACService(container.RBACRepo, container.UserRepo, container.SecurityEventService, redis, cfg)
+		container.AuthorizationService = service.NewAuthorizationService(container.RBACService, service.DefaultPolicies()...)
+		container.APITokenService = service.NewAPITokenService(container.APITokenRepo, container.UserRepo, container.OrganizationRepo, container.RBACService, container.SecurityEventService, cfg)
+	}
+
+	// Initialize handlers
+	if container.RBACService != nil {
+		container.RBACHandler = handler.NewRBACHandler(container.RBACService)
+		container.APITokenHandler = handler.NewAPITokenHandler(container.APITokenService)
+	}
+
+	return container
+}
+
+// GetRBACService returns RBAC service
+func (c *RBACContainer) GetRBACService() domainService.RBACService {
+	return c.RBACService
+}
+
+// GetAuthorizationService returns authorization service
+func (c *RBACContainer) GetAuthorizationService() domainService.AuthorizationService {
+	return c.AuthorizationService
+}
+
+// GetAPITokenService returns API token service
+func (c *RBACContainer) GetAPITokenService() domainService.APITokenService {
+	return c.APITokenService
+}
+
+// GetAPITokenHandler returns API token handler
+func (c *RBACContainer) GetAPITokenHandler() *handler.APITokenHandler {
+	return c.APITokenHandler
+}
+
+// GetRBACHandler returns RBAC handler
+func (c *RBACContainer) GetRBACHandler() *handler.RBACHandler {
+	return c.RBACHandler
+}
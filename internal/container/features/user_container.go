@@ -4,8 +4,13 @@ import (
 	"boilerplate-go-fiber-v2/config"
 	"boilerplate-go-fiber-v2/internal/domain/repository"
 	domainService "boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/internal/handler"
 	repo "boilerplate-go-fiber-v2/internal/repository"
 	"boilerplate-go-fiber-v2/internal/service"
+	"boilerplate-go-fiber-v2/pkg/mailer"
+	"boilerplate-go-fiber-v2/pkg/pwned"
+	"boilerplate-go-fiber-v2/pkg/sms"
+	"boilerplate-go-fiber-v2/pkg/storage"
 
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
@@ -14,33 +19,79 @@ import (
 // UserContainer holds user-related dependencies
 type UserContainer struct {
 	// Repositories
-	UserRepo repository.UserRepository
+	UserRepo              repository.UserRepository
+	AuthRepo              repository.AuthRepository
+	OrderRepo             repository.OrderRepository
+	PaymentRepo           repository.PaymentRepository
+	PrivacyRepo           repository.PrivacyRepository
+	SecurityEventRepo     repository.SecurityEventRepository
+	PasswordHistoryRepo   repository.PasswordHistoryRepository
+	EmailVerificationRepo repository.EmailVerificationRepository
 
 	// Services
-	UserService domainService.UserService
+	SecurityEventService domainService.SecurityEventService
+	PasswordService      domainService.PasswordService
+	UserService          domainService.UserService
+	AvatarService        domainService.AvatarService
+	PrivacyService       domainService.PrivacyService
+	EmailChangeService   domainService.EmailChangeService
+	CodeService          domainService.OneTimeCodeService
+	PhoneService         domainService.PhoneVerificationService
 
 	// Handlers
-	// UserHandler *handler.UserHandler // TODO: Implement when needed
+	UserHandler *handler.UserHandler
 }
 
 // NewUserContainer creates user container
-func NewUserContainer(db *gorm.DB, redis *redis.Client, cfg *config.Config) *UserContainer {
+func NewUserContainer(db *gorm.DB, redis *redis.Client, store storage.Storage, mail mailer.Mailer, sender sms.SMSSender, passwordChecker *pwned.Checker, rbacService domainService.RBACService, cfg *config.Config) *UserContainer {
 	container := &UserContainer{}
 
 	// Initialize repositories
 	if db != nil {
 		container.UserRepo = repo.NewUserRepository(db)
+		container.AuthRepo = repo.NewAuthRepository(db)
+		container.OrderRepo = repo.NewOrderRepository(db)
+		container.PaymentRepo = repo.NewPaymentRepository(db)
+		container.PrivacyRepo = repo.NewPrivacyRepository(db)
+		container.SecurityEventRepo = repo.NewSecurityEventRepository(db)
+		container.PasswordHistoryRepo = repo.NewPasswordHistoryRepository(db)
+		container.EmailVerificationRepo = repo.NewEmailVerificationRepository(db)
 	}
 
 	// Initialize services
 	if container.UserRepo != nil {
-		container.UserService = service.NewUserService(container.UserRepo)
+		container.SecurityEventService = service.NewSecurityEventService(container.SecurityEventRepo)
+		container.PasswordService = service.NewPasswordService(passwordChecker, container.PasswordHistoryRepo, cfg.Password.HistorySize)
+		container.UserService = service.NewUserService(container.UserRepo, container.PasswordService, container.SecurityEventService)
+		container.AvatarService = service.NewAvatarService(container.UserRepo, store, cfg)
+		container.PrivacyService = service.NewPrivacyService(
+			container.UserRepo,
+			container.AuthRepo,
+			container.OrderRepo,
+			container.PaymentRepo,
+			container.PrivacyRepo,
+			container.SecurityEventRepo,
+			container.SecurityEventService,
+			container.AvatarService,
+			rbacService,
+			cfg,
+		)
+		container.EmailChangeService = service.NewEmailChangeService(
+			container.UserRepo,
+			container.EmailVerificationRepo,
+			container.AuthRepo,
+			container.SecurityEventService,
+			mail,
+			cfg,
+		)
+		container.CodeService = service.NewOneTimeCodeService(container.AuthRepo, cfg, service.NewEmailCodeChannel(mail), service.NewSMSCodeChannel(sender))
+		container.PhoneService = service.NewPhoneVerificationService(container.UserRepo, container.CodeService, container.SecurityEventService)
 	}
 
-	// Initialize handlers (when UserHandler is implemented)
-	// if container.UserService != nil {
-	//     container.UserHandler = handler.NewUserHandler(container.UserService)
-	// }
+	// Initialize handlers
+	if container.UserService != nil && container.PrivacyService != nil {
+		container.UserHandler = handler.NewUserHandler(container.UserService, container.AvatarService, container.PrivacyService, container.EmailChangeService, container.PhoneService)
+	}
 
 	return container
 }
@@ -50,7 +101,12 @@ func (c *UserContainer) GetUserService() domainService.UserService {
 	return c.UserService
 }
 
-// GetUserHandler returns user handler (when implemented)
-// func (c *UserContainer) GetUserHandler() *handler.UserHandler {
-//     return c.UserHandler
-// }
+// GetPrivacyService returns privacy service
+func (c *UserContainer) GetPrivacyService() domainService.PrivacyService {
+	return c.PrivacyService
+}
+
+// GetUserHandler returns user handler
+func (c *UserContainer) GetUserHandler() *handler.UserHandler {
+	return c.UserHandler
+}
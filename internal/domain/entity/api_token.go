@@ -0,0 +1,97 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// API token kinds and their visible prefixes
+const (
+	APITokenKindPersonal = "personal"
+	APITokenKindService  = "service"
+)
+
+var apiTokenPrefixes = map[string]string{
+	APITokenKindPersonal: "pat",
+	APITokenKindService:  "sk",
+}
+
+// APIToken is a personal access token owned by a user, or a service key owned
+// by an organization. Exactly one of UserID and OrgID is set.
+type APIToken struct {
+	ID         uint
+	UserID     uint
+	OrgID      uint
+	CreatedBy  uint // member who issued a service key
+	Kind       string
+	Name       string
+	Prefix     string // e.g. pat_1a2b3c4d
+	SecretHash string // SHA-256 hex digest, see utils.HashToken
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	LastUsedIP string
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// APITokenPrefix returns the visible prefix of a token kind
+func APITokenPrefix(kind string) string {
+	return apiTokenPrefixes[kind]
+}
+
+// IsAPIToken reports whether a credential looks like an API token rather
+// than a JWT
+func IsAPIToken(raw string) bool {
+	for _, prefix := range apiTokenPrefixes {
+		if strings.HasPrefix(raw, prefix+"_") {
+			return true
+		}
+	}
+	return false
+}
+
+// Business methods for APIToken
+func (t *APIToken) IsExpired() bool {
+	return t.ExpiresAt != nil && time.Now().After(*t.ExpiresAt)
+}
+
+func (t *APIToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+func (t *APIToken) IsActive() bool {
+	return !t.IsExpired() && !t.IsRevoked()
+}
+
+// BelongsToOrganization reports whether the token is a service key of an
+// organization rather than a user's token
+func (t *APIToken) BelongsToOrganization() bool {
+	return t.OrgID != 0
+}
+
+func (t *APIToken) Revoke() {
+	now := time.Now()
+	t.RevokedAt = &now
+}
+
+// Restrict narrows access to what the token's scopes allow: a permission
+// counts when the holder has it and a scope covers it. Roles are dropped, so
+// a token never passes a role check.
+func (t *APIToken) Restrict(access *UserAccess) *UserAccess {
+	scoped := &UserAccess{Roles: []string{}, Permissions: []string{}}
+	tokenAccess := &UserAccess{Permissions: t.Scopes}
+
+	for _, permission := range access.Permissions {
+		if tokenAccess.HasPermission(permission) {
+			scoped.Permissions = append(scoped.Permissions, permission)
+		}
+	}
+	for _, scope := range t.Scopes {
+		if access.HasPermission(scope) && !scoped.HasPermission(scope) {
+			scoped.Permissions = append(scoped.Permissions, scope)
+		}
+	}
+	return scoped
+}
@@ -1,14 +1,46 @@
 package entity
 
 import (
+	"strings"
 	"time"
 )
 
+// Authentication methods (RFC 8176)
+const (
+	AuthMethodPassword  = "pwd"
+	AuthMethodOTP       = "otp"
+	AuthMethodSMS       = "sms"
+	AuthMethodEmail     = "email"
+	AuthMethodOIDC      = "oidc"       // signed in through an OpenID Connect provider
+	AuthMethodMagicLink = "magic_link" // signed in with a link sent by email
+	AuthMethodPasskey   = "hwk"        // proof of possession of a passkey
+	AuthMethodMFA       = "mfa"        // a user-verifying passkey counts as several factors
+)
+
+// IsMultiFactor reports whether methods prove more than one factor: two
+// different methods, or a passkey that verified the user
+func IsMultiFactor(methods []string) bool {
+	distinct := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		if method == AuthMethodMFA {
+			return true
+		}
+		distinct[method] = true
+	}
+	return len(distinct) > 1
+}
+
 type AuthSession struct {
 	ID           uint
 	UserID       uint
 	Token        string
 	RefreshToken string
+	AuthTime     time.Time // last login or step-up
+	AMR          string    // comma-separated authentication methods
+	ClientID     *uint     // OAuth client of a client_credentials token
+	Scope        string    // space-separated scopes of a client token
+	ActorID      *uint     // admin acting as the user in an impersonation session
+	OrgID        *uint     // active organization; nil for the personal account
 	ExpiresAt    time.Time
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
@@ -17,32 +49,93 @@ type AuthSession struct {
 type PasswordReset struct {
 	ID        uint
 	UserID    uint
-	Token     string
+	Token     string // SHA-256 hex digest, see utils.HashToken
 	ExpiresAt time.Time
 	Used      bool
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
-type TFACode struct {
+// Magic link device binding modes
+const (
+	MagicLinkBindingOff      = "off"      // links work on any device
+	MagicLinkBindingOptional = "optional" // the requester chooses
+	MagicLinkBindingRequired = "required" // links only work on the requesting device
+)
+
+// MagicLink is a single-use login link sent by email
+type MagicLink struct {
+	ID         uint
+	UserID     uint
+	Token      string // SHA-256 hex digest, see utils.HashToken
+	DeviceHash string // digest of the device token when bound to the requesting device
+	IPAddress  string
+	ExpiresAt  time.Time
+	Used       bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// PendingLogin is a login waiting for a second factor from a user with TFA
+// enabled. The token, handed out in a TFA challenge, lets the client finish
+// it once.
+type PendingLogin struct {
 	ID        uint
 	UserID    uint
-	Code      string
+	Token     string // SHA-256 hex digest, see utils.HashToken
+	AMR       string // comma-separated methods proven before the challenge
 	ExpiresAt time.Time
 	Used      bool
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// One-time code purposes
+const (
+	TFACodePurposeLogin       = "login"
+	TFACodePurposeStepUp      = "step_up"
+	TFACodePurposePhoneVerify = "phone_verify"
+)
+
+// One-time code delivery channels
+const (
+	TFAChannelEmail = "email"
+	TFAChannelSMS   = "sms"
+)
+
+type TFACode struct {
+	ID          uint
+	UserID      uint
+	Code        string
+	Purpose     string
+	Channel     string
+	Destination string
+	Attempts    int
+	ExpiresAt   time.Time
+	Used        bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Email verification purposes
+const (
+	EmailVerificationPurposeVerify = "verify"
+	EmailVerificationPurposeChange = "change"
+)
+
 type EmailVerification struct {
-	ID         uint
-	UserID     uint
-	Email      string
-	Token      string
-	ExpiresAt  time.Time
-	VerifiedAt *time.Time
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	ID            uint
+	UserID        uint
+	Email         string
+	Purpose       string
+	PreviousEmail string
+	Token         string
+	CancelToken   string
+	ExpiresAt     time.Time
+	VerifiedAt    *time.Time
+	CancelledAt   *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
 }
 
 // Business methods for AuthSession
@@ -54,6 +147,54 @@ func (a *AuthSession) IsValid() bool {
 	return !a.IsExpired()
 }
 
+// IsClientSession reports whether the session holds a client_credentials
+// token rather than a user login
+func (a *AuthSession) IsClientSession() bool {
+	return a.ClientID != nil
+}
+
+// IsImpersonation reports whether an admin holds the session on the user's
+// behalf
+func (a *AuthSession) IsImpersonation() bool {
+	return a.ActorID != nil
+}
+
+// ActiveOrgID returns the session's active organization, or 0
+func (a *AuthSession) ActiveOrgID() uint {
+	if a.OrgID == nil {
+		return 0
+	}
+	return *a.OrgID
+}
+
+// Methods returns the authentication methods used in the session
+func (a *AuthSession) Methods() []string {
+	if a.AMR == "" {
+		return nil
+	}
+	return strings.Split(a.AMR, ",")
+}
+
+// AuthenticatedWithin reports whether the user authenticated within maxAge
+func (a *AuthSession) AuthenticatedWithin(maxAge time.Duration) bool {
+	return !a.AuthTime.IsZero() && time.Since(a.AuthTime) <= maxAge
+}
+
+// Authenticate records a successful login or step-up with a method
+func (a *AuthSession) Authenticate(method string) {
+	a.AuthTime = time.Now()
+	for _, m := range a.Methods() {
+		if m == method {
+			return
+		}
+	}
+	if a.AMR == "" {
+		a.AMR = method
+	} else {
+		a.AMR += "," + method
+	}
+}
+
 // Business methods for PasswordReset
 func (p *PasswordReset) IsExpired() bool {
 	return time.Now().After(p.ExpiresAt)
@@ -67,6 +208,37 @@ func (p *PasswordReset) MarkAsUsed() {
 	p.Used = true
 }
 
+// Business methods for MagicLink
+func (m *MagicLink) IsExpired() bool {
+	return time.Now().After(m.ExpiresAt)
+}
+
+func (m *MagicLink) IsValid() bool {
+	return !m.IsExpired() && !m.Used
+}
+
+// IsDeviceBound reports whether the link only works on the requesting device
+func (m *MagicLink) IsDeviceBound() bool {
+	return m.DeviceHash != ""
+}
+
+// Business methods for PendingLogin
+func (p *PendingLogin) IsExpired() bool {
+	return time.Now().After(p.ExpiresAt)
+}
+
+func (p *PendingLogin) IsValid() bool {
+	return !p.IsExpired() && !p.Used
+}
+
+// Methods returns the authentication methods proven before the challenge
+func (p *PendingLogin) Methods() []string {
+	if p.AMR == "" {
+		return nil
+	}
+	return strings.Split(p.AMR, ",")
+}
+
 // Business methods for TFACode
 func (t *TFACode) IsExpired() bool {
 	return time.Now().After(t.ExpiresAt)
@@ -80,16 +252,29 @@ func (t *TFACode) MarkAsUsed() {
 	t.Used = true
 }
 
+func (t *TFACode) AttemptsExhausted(maxAttempts int) bool {
+	return maxAttempts > 0 && t.Attempts >= maxAttempts
+}
+
 // Business methods for EmailVerification
 func (ev *EmailVerification) IsExpired() bool {
 	return time.Now().After(ev.ExpiresAt)
 }
 
 func (ev *EmailVerification) IsValid() bool {
-	return ev.VerifiedAt == nil && time.Now().Before(ev.ExpiresAt)
+	return ev.VerifiedAt == nil && ev.CancelledAt == nil && time.Now().Before(ev.ExpiresAt)
+}
+
+func (ev *EmailVerification) IsCancelled() bool {
+	return ev.CancelledAt != nil
 }
 
 func (ev *EmailVerification) MarkVerified() {
 	now := time.Now()
 	ev.VerifiedAt = &now
 }
+
+func (ev *EmailVerification) MarkCancelled() {
+	now := time.Now()
+	ev.CancelledAt = &now
+}
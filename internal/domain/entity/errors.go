@@ -0,0 +1,127 @@
+package entity
+
+import "boilerplate-go-fiber-v2/pkg/apperror"
+
+// Domain errors shared by repositories, services and handlers. Codes are part
+// of the API contract and must not change once released.
+var (
+	// User errors
+	ErrUserNotFound       = apperror.NotFound("user_not_found", "User not found")
+	ErrUserAlreadyExists  = apperror.Conflict("user_already_exists", "A user with this email or username already exists")
+	ErrInvalidUserStatus  = apperror.Validation("invalid_user_status", "Invalid user status")
+	ErrInvalidPassword    = apperror.Unauthorized("invalid_password", "Invalid password")
+	ErrInvalidOldPassword = apperror.Validation("invalid_old_password", "Invalid old password")
+
+	// Authentication errors
+	ErrInvalidCredentials    = apperror.Unauthorized("invalid_credentials", "Invalid credentials")
+	ErrAccountInactive       = apperror.Forbidden("account_inactive", "Account is not active")
+	ErrEmailNotVerified      = apperror.Forbidden("email_not_verified", "Email not verified")
+	ErrMissingToken          = apperror.Unauthorized("missing_token", "Authorization header required")
+	ErrInvalidToken          = apperror.Unauthorized("invalid_token", "Invalid or expired token")
+	ErrInvalidRefreshToken   = apperror.Unauthorized("invalid_refresh_token", "Invalid refresh token")
+	ErrSessionNotFound       = apperror.Unauthorized("session_not_found", "Session not found")
+	ErrSessionExpired        = apperror.Unauthorized("session_expired", "Session expired")
+	ErrInsufficientRole      = apperror.Forbidden("insufficient_permissions", "Insufficient permissions")
+	ErrForbidden             = apperror.Forbidden("forbidden", "You are not allowed to perform this action")
+	ErrReauthRequired        = apperror.Unauthorized("reauthentication_required", "Please confirm your identity to continue")
+	ErrImpersonationDenied   = apperror.Forbidden("impersonation_denied", "This action is not available while impersonating a user")
+	ErrCannotImpersonate     = apperror.Forbidden("cannot_impersonate", "This user cannot be impersonated")
+	ErrImpersonationNotFound = apperror.NotFound("impersonation_not_found", "Impersonation session not found")
+	ErrPasswordResetNotFound = apperror.NotFound("password_reset_not_found", "Password reset not found")
+	ErrInvalidResetToken     = apperror.Validation("invalid_reset_token", "Invalid reset token")
+	ErrResetTokenExpired     = apperror.Validation("reset_token_expired", "Reset token expired or already used")
+	ErrTFACodeNotFound       = apperror.NotFound("tfa_code_not_found", "TFA code not found")
+	ErrInvalidTFACode        = apperror.Unauthorized("invalid_tfa_code", "Invalid TFA code")
+	ErrTFACodeExpired        = apperror.Unauthorized("tfa_code_expired", "TFA code expired or already used")
+	ErrTFANotEnabled         = apperror.Validation("tfa_not_enabled", "TFA not enabled")
+	ErrUnknownCodeChannel    = apperror.Validation("unknown_code_channel", "Unsupported code delivery channel")
+	ErrPendingLoginNotFound  = apperror.NotFound("pending_login_not_found", "Pending login not found")
+	ErrInvalidTFAChallenge   = apperror.Unauthorized("invalid_tfa_challenge", "TFA challenge is invalid, expired or already used, please log in again")
+
+	// Magic link errors
+	ErrMagicLinkNotFound       = apperror.NotFound("magic_link_not_found", "Login link not found")
+	ErrInvalidMagicLink        = apperror.Unauthorized("invalid_magic_link", "Login link is invalid, expired or already used")
+	ErrMagicLinkDeviceMismatch = apperror.Forbidden("magic_link_device_mismatch", "Open the login link on the device that requested it")
+
+	// Email change errors
+	ErrEmailVerificationNotFound = apperror.NotFound("email_verification_not_found", "Email verification not found")
+	ErrInvalidEmailChangeToken   = apperror.Validation("invalid_email_change_token", "Invalid or expired email change token")
+	ErrEmailUnchanged            = apperror.Validation("email_unchanged", "New email must differ from the current email")
+
+	// Role and permission errors
+	ErrRoleNotFound       = apperror.NotFound("role_not_found", "Role not found")
+	ErrRoleAlreadyExists  = apperror.Conflict("role_already_exists", "Role already exists")
+	ErrPermissionNotFound = apperror.Validation("permission_not_found", "Unknown permission")
+	ErrSystemRole         = apperror.Forbidden("system_role", "System roles cannot be deleted or renamed")
+
+	// API token errors
+	ErrAPITokenNotFound   = apperror.NotFound("api_token_not_found", "API token not found")
+	ErrInvalidAPIToken    = apperror.Unauthorized("invalid_api_token", "Invalid, expired or revoked API token")
+	ErrInvalidTokenScope  = apperror.Validation("invalid_token_scope", "Token scopes must be permissions you hold")
+	ErrInvalidTokenKind   = apperror.Validation("invalid_token_kind", "Unsupported API token kind")
+	ErrAPITokenNotAllowed = apperror.Forbidden("api_token_not_allowed", "API tokens cannot be used on this route")
+	ErrInsufficientScope  = apperror.Forbidden("insufficient_scope", "The API token's scopes do not allow this request")
+
+	// OAuth errors; codes follow RFC 6749 section 5.2 where one applies
+	ErrOAuthClientNotFound  = apperror.NotFound("oauth_client_not_found", "OAuth client not found")
+	ErrInvalidClient        = apperror.Unauthorized("invalid_client", "Client authentication failed")
+	ErrInvalidScope         = apperror.Validation("invalid_scope", "The requested scope is not allowed for this client")
+	ErrUnsupportedGrantType = apperror.Validation("unsupported_grant_type", "The grant type is not supported")
+
+	// Sign-in provider errors
+	ErrUnknownOIDCProvider   = apperror.NotFound("oidc_provider_not_found", "Unknown sign-in provider")
+	ErrInvalidOIDCState      = apperror.Validation("invalid_oidc_state", "Sign-in request expired or already used, please start again")
+	ErrOIDCLoginFailed       = apperror.Unauthorized("oidc_login_failed", "Sign-in with the provider failed")
+	ErrOIDCEmailRequired     = apperror.Validation("oidc_email_required", "The provider did not share a verified email address")
+	ErrIdentityNotFound      = apperror.NotFound("identity_not_found", "Linked account not found")
+	ErrIdentityAlreadyLinked = apperror.Conflict("identity_already_linked", "This provider account is already linked")
+	ErrIdentityEmailInUse    = apperror.Conflict("identity_email_in_use", "An account with this email already exists. Sign in and link the provider from your profile.")
+	ErrLastSignInMethod      = apperror.Conflict("last_sign_in_method", "Set a password, add a passkey or link a provider before removing your last sign-in method")
+
+	// Passkey errors
+	ErrPasskeyNotFound           = apperror.NotFound("passkey_not_found", "Passkey not found")
+	ErrPasskeyAlreadyRegistered  = apperror.Conflict("passkey_already_registered", "This passkey is already registered")
+	ErrInvalidWebAuthnChallenge  = apperror.Validation("invalid_webauthn_challenge", "Passkey request expired or already used, please start again")
+	ErrPasskeyVerificationFailed = apperror.Unauthorized("passkey_verification_failed", "Passkey could not be verified")
+
+	// Phone verification errors
+	ErrPhoneRequired           = apperror.Validation("phone_required", "A phone number is required")
+	ErrPhoneAlreadyVerified    = apperror.Conflict("phone_already_verified", "Phone number is already verified")
+	ErrPhoneNotVerified        = apperror.Validation("phone_not_verified", "Phone number is not verified")
+	ErrOTPResendTooSoon        = apperror.RateLimited("otp_resend_too_soon", "Please wait before requesting another code")
+	ErrOTPRateLimited          = apperror.RateLimited("otp_rate_limited", "Too many codes requested, try again later")
+	ErrOTPAttemptsExceeded     = apperror.Validation("otp_attempts_exceeded", "Too many attempts, request a new code")
+	ErrInvalidVerificationCode = apperror.Validation("invalid_verification_code", "Invalid verification code")
+	ErrVerificationCodeExpired = apperror.Validation("verification_code_expired", "Verification code expired or already used")
+
+	// Organization errors; non-members get ErrOrganizationNotFound so they
+	// cannot probe which organizations exist
+	ErrOrganizationNotFound = apperror.NotFound("organization_not_found", "Organization not found")
+	ErrOrgRoleRequired      = apperror.Forbidden("organization_role_required", "Your role in this organization does not allow this action")
+	ErrInvalidOrgRole       = apperror.Validation("invalid_organization_role", "Unknown organization role")
+	ErrMemberNotFound       = apperror.NotFound("member_not_found", "Member not found")
+	ErrAlreadyMember        = apperror.Conflict("already_member", "This user is already a member of the organization")
+	ErrLastOwner            = apperror.Conflict("last_owner", "An organization needs at least one owner")
+	ErrOrganizationInUse    = apperror.Conflict("organization_in_use", "Organizations with orders or payments cannot be deleted")
+	ErrInvitationNotFound   = apperror.NotFound("invitation_not_found", "Invitation not found")
+	ErrInvalidInvitation    = apperror.Validation("invalid_invitation", "Invitation expired or already used")
+	ErrInvitationEmail      = apperror.Forbidden("invitation_email_mismatch", "This invitation was sent to a different email address")
+
+	// Order and payment errors
+	ErrOrderNotFound   = apperror.NotFound("order_not_found", "Order not found")
+	ErrPaymentNotFound = apperror.NotFound("payment_not_found", "Payment not found")
+
+	// Privacy errors
+	ErrDataExportNotFound       = apperror.NotFound("data_export_not_found", "Data export not found")
+	ErrDataExportInProgress     = apperror.Conflict("data_export_in_progress", "Data export already in progress")
+	ErrDataExportUnavailable    = apperror.NotFound("data_export_unavailable", "Data export is not available for download")
+	ErrDeletionAlreadyScheduled = apperror.Conflict("deletion_already_scheduled", "Account deletion already scheduled")
+	ErrDeletionNotScheduled     = apperror.Conflict("deletion_not_scheduled", "No account deletion scheduled")
+
+	// Avatar errors
+	ErrAvatarNotFound     = apperror.NotFound("avatar_not_found", "Avatar not found")
+	ErrAvatarRequired     = apperror.Validation("avatar_required", "Avatar file is required")
+	ErrAvatarTooLarge     = apperror.Validation("avatar_too_large", "Avatar file is too large")
+	ErrAvatarInvalidType  = apperror.Validation("avatar_invalid_type", "Avatar must be a JPEG, PNG, GIF or WebP image")
+	ErrAvatarInvalidImage = apperror.Validation("avatar_invalid_image", "Avatar is not a valid image")
+)
@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+// OpenID Connect sign-in purposes
+const (
+	OIDCPurposeLogin = "login"
+	OIDCPurposeLink  = "link"
+)
+
+// UserIdentity links a user to an account at an OpenID Connect provider
+type UserIdentity struct {
+	ID            uint
+	UserID        uint
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	LastLoginAt   *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// OIDCAuthRequest remembers a sign-in sent to a provider until its callback
+type OIDCAuthRequest struct {
+	ID           uint
+	StateHash    string // SHA-256 hex digest, see utils.HashToken
+	Provider     string
+	Purpose      string
+	UserID       uint // user linking the identity; 0 for sign-in
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+// Business methods for OIDCAuthRequest
+func (r *OIDCAuthRequest) IsExpired() bool {
+	return time.Now().After(r.ExpiresAt)
+}
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// OAuth grant and token types (RFC 6749, RFC 7009)
+const (
+	GrantTypeClientCredentials = "client_credentials"
+	TokenTypeHintAccessToken   = "access_token"
+	TokenTypeHintRefreshToken  = "refresh_token"
+)
+
+type OAuthClient struct {
+	ID         uint
+	ClientID   string
+	Name       string
+	SecretHash string // SHA-256 hex digest, see utils.HashToken
+	Scopes     []string
+	CreatedBy  uint
+	DisabledAt *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// TokenIntrospection is the RFC 7662 view of a token. Inactive tokens carry
+// no other information.
+type TokenIntrospection struct {
+	Active    bool
+	Scope     string
+	ClientID  string
+	Username  string
+	TokenType string
+	Subject   string
+	ExpiresAt time.Time
+	IssuedAt  time.Time
+}
+
+// Business methods for OAuthClient
+func (c *OAuthClient) IsActive() bool {
+	return c.DisabledAt == nil
+}
+
+func (c *OAuthClient) Disable() {
+	now := time.Now()
+	c.DisabledAt = &now
+}
+
+// GrantScopes returns the scopes a token request is granted: every allowed
+// scope when none are requested, otherwise the requested ones if the client
+// may use all of them
+func (c *OAuthClient) GrantScopes(requested string) ([]string, bool) {
+	scopes := strings.Fields(requested)
+	if len(scopes) == 0 {
+		return c.Scopes, true
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(c.Scopes, scope) {
+			return nil, false
+		}
+	}
+	return scopes, true
+}
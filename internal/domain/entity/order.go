@@ -7,6 +7,7 @@ import (
 type Order struct {
 	ID          uint
 	UserID      uint
+	OrgID       *uint // organization the order belongs to; nil for personal orders
 	OrderNumber string
 	TotalAmount float64
 	Status      string
@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// Organization roles, from most to least privileged
+const (
+	OrgRoleOwner  = "owner"  // manages the organization, its members and owners
+	OrgRoleAdmin  = "admin"  // manages members and invitations
+	OrgRoleMember = "member" // works with the organization's data
+)
+
+var orgRoleRanks = map[string]int{
+	OrgRoleOwner:  3,
+	OrgRoleAdmin:  2,
+	OrgRoleMember: 1,
+}
+
+// IsOrgRole reports whether role is a known organization role
+func IsOrgRole(role string) bool {
+	_, ok := orgRoleRanks[role]
+	return ok
+}
+
+// Organization is a team account that owns tenant-scoped data
+type Organization struct {
+	ID        uint
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Membership gives a user a role in an organization
+type Membership struct {
+	ID           uint
+	OrgID        uint
+	UserID       uint
+	Role         string
+	Organization *Organization // loaded when listing a user's organizations
+	User         *User         // loaded when listing an organization's members
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Business methods for Membership
+func (m *Membership) IsOwner() bool {
+	return m.Role == OrgRoleOwner
+}
+
+// HasRole reports whether the member holds role or a more privileged one
+func (m *Membership) HasRole(role string) bool {
+	return orgRoleRanks[m.Role] >= orgRoleRanks[role]
+}
+
+// CanManageMembers reports whether the member can invite, update and remove
+// members
+func (m *Membership) CanManageMembers() bool {
+	return m.HasRole(OrgRoleAdmin)
+}
+
+// CanGrant reports whether the member can give role to someone; only owners
+// make owners
+func (m *Membership) CanGrant(role string) bool {
+	if role == OrgRoleOwner {
+		return m.IsOwner()
+	}
+	return m.CanManageMembers()
+}
+
+// Invitation asks someone to join an organization by email
+type Invitation struct {
+	ID         uint
+	OrgID      uint
+	Email      string
+	Role       string
+	Token      string // SHA-256 hex digest, see utils.HashToken
+	InvitedBy  uint
+	ExpiresAt  time.Time
+	AcceptedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Business methods for Invitation
+func (i *Invitation) IsExpired() bool {
+	return time.Now().After(i.ExpiresAt)
+}
+
+func (i *Invitation) IsAccepted() bool {
+	return i.AcceptedAt != nil
+}
+
+// IsFor reports whether the invitation was sent to email
+func (i *Invitation) IsFor(email string) bool {
+	return strings.EqualFold(i.Email, email)
+}
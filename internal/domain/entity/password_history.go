@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"time"
+)
+
+// Login challenge types
+const (
+	LoginChallengePasswordChange = "password_change_required"
+	LoginChallengeTFA            = "tfa_required"
+)
+
+type PasswordHistory struct {
+	ID           uint
+	UserID       uint
+	PasswordHash string
+	CreatedAt    time.Time
+}
+
+// LoginChallenge is returned by login instead of a session when the user has
+// to complete another step first. Token authorizes that step.
+type LoginChallenge struct {
+	Type      string
+	Token     string
+	ExpiresAt time.Time
+}
@@ -11,6 +11,7 @@ type Payment struct {
 	ID            uint
 	OrderID       uint
 	UserID        uint
+	OrgID         *uint // organization the payment belongs to; nil for personal payments
 	Amount        float64
 	Currency      string
 	PaymentMethod string
@@ -0,0 +1,40 @@
+package entity
+
+import "fmt"
+
+// Subject is who an authorization decision is made for
+type Subject struct {
+	UserID uint
+	Access *UserAccess
+}
+
+// Resource is anything a policy can be written for
+type Resource interface {
+	ResourceType() string
+}
+
+// OwnedResource is a resource that belongs to a user
+type OwnedResource interface {
+	Resource
+	OwnerID() uint
+}
+
+// PolicyDenial explains why a policy refused an action. It is attached to
+// ErrForbidden as the cause, so it reaches logs but not clients.
+type PolicyDenial struct {
+	Action       string
+	ResourceType string
+	Reason       string
+}
+
+func (d *PolicyDenial) Error() string {
+	return fmt.Sprintf("%s on %s denied: %s", d.Action, d.ResourceType, d.Reason)
+}
+
+// Resource types
+func (o *Order) ResourceType() string   { return "order" }
+func (o *Order) OwnerID() uint          { return o.UserID }
+func (p *Payment) ResourceType() string { return "payment" }
+func (p *Payment) OwnerID() uint        { return p.UserID }
+func (u *User) ResourceType() string    { return "user" }
+func (u *User) OwnerID() uint           { return u.ID }
@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"time"
+)
+
+type DataExport struct {
+	ID          uint
+	UserID      uint
+	Status      string
+	FilePath    string
+	Error       string
+	ExpiresAt   *time.Time
+	CompletedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Business methods for DataExport
+func (d *DataExport) IsPending() bool {
+	return d.Status == "pending" || d.Status == "processing"
+}
+
+// IsStale reports whether a pending export has not progressed within
+// timeout, e.g. because the server restarted while building it
+func (d *DataExport) IsStale(timeout time.Duration) bool {
+	return d.IsPending() && time.Since(d.UpdatedAt) > timeout
+}
+
+func (d *DataExport) IsCompleted() bool {
+	return d.Status == "completed"
+}
+
+func (d *DataExport) IsFailed() bool {
+	return d.Status == "failed"
+}
+
+func (d *DataExport) IsExpired() bool {
+	if d.ExpiresAt == nil {
+		return false
+	}
+	return time.Now().After(*d.ExpiresAt)
+}
+
+func (d *DataExport) IsDownloadable() bool {
+	return d.IsCompleted() && !d.IsExpired()
+}
+
+func (d *DataExport) MarkAsProcessing() {
+	d.Status = "processing"
+}
+
+func (d *DataExport) MarkAsCompleted(filePath string, ttl time.Duration) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	d.Status = "completed"
+	d.FilePath = filePath
+	d.CompletedAt = &now
+	d.ExpiresAt = &expiresAt
+}
+
+func (d *DataExport) MarkAsFailed(err error) {
+	d.Status = "failed"
+	d.Error = err.Error()
+}
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// PermissionAll grants every permission
+const PermissionAll = "*"
+
+type Role struct {
+	ID          uint
+	Name        string
+	Description string
+	IsSystem    bool
+	Permissions []string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type Permission struct {
+	ID          uint
+	Name        string
+	Description string
+	CreatedAt   time.Time
+}
+
+// UserAccess is the set of roles and permissions a user holds
+type UserAccess struct {
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
+
+// HasRole checks if the user holds a role
+func (a *UserAccess) HasRole(role string) bool {
+	for _, r := range a.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission checks if the user holds a permission, either directly, via
+// a resource wildcard such as "orders:*" or via "*"
+func (a *UserAccess) HasPermission(permission string) bool {
+	resource, _, _ := strings.Cut(permission, ":")
+	for _, p := range a.Permissions {
+		if p == permission || p == PermissionAll || p == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"time"
+)
+
+// Security event types
+const (
+	SecurityEventLogin                = "login"
+	SecurityEventLoginFailed          = "login_failed"
+	SecurityEventLogout               = "logout"
+	SecurityEventReauthenticated      = "reauthenticated"
+	SecurityEventPasswordResetRequest = "password_reset_requested"
+	SecurityEventMagicLinkRequested   = "magic_link_requested"
+	SecurityEventPasswordReset        = "password_reset"
+	SecurityEventPasswordChanged      = "password_changed"
+	SecurityEventPasswordExpired      = "password_expired"
+	SecurityEventEmailChangeRequested = "email_change_requested"
+	SecurityEventEmailChanged         = "email_changed"
+	SecurityEventEmailChangeCancelled = "email_change_cancelled"
+	SecurityEventEmailChangeReverted  = "email_change_reverted"
+	SecurityEventPhoneVerifyStarted   = "phone_verification_started"
+	SecurityEventPhoneVerified        = "phone_verified"
+	SecurityEventRoleAssigned         = "role_assigned"
+	SecurityEventRoleRevoked          = "role_revoked"
+	SecurityEventAPITokenCreated      = "api_token_created"
+	SecurityEventAPITokenRevoked      = "api_token_revoked"
+	SecurityEventOAuthClientCreated   = "oauth_client_created"
+	SecurityEventOAuthClientDisabled  = "oauth_client_disabled"
+	SecurityEventIdentityLinked       = "identity_linked"
+	SecurityEventIdentityUnlinked     = "identity_unlinked"
+	SecurityEventPasskeyRegistered    = "passkey_registered"
+	SecurityEventPasskeyRemoved       = "passkey_removed"
+	SecurityEventImpersonationStarted = "impersonation_started"
+	SecurityEventImpersonationEnded   = "impersonation_ended"
+	SecurityEventOrgInvitationSent    = "organization_invitation_sent"
+	SecurityEventOrgJoined            = "organization_joined"
+	SecurityEventOrgRoleChanged       = "organization_role_changed"
+	SecurityEventOrgLeft              = "organization_left"
+	SecurityEventTFAEnabled           = "tfa_enabled"
+	SecurityEventTFADisabled          = "tfa_disabled"
+	SecurityEventDataExportRequested  = "data_export_requested"
+	SecurityEventDeletionRequested    = "account_deletion_requested"
+	SecurityEventDeletionCancelled    = "account_deletion_cancelled"
+	SecurityEventAccountAnonymized    = "account_anonymized"
+)
+
+type SecurityEvent struct {
+	ID        uint
+	UserID    uint
+	Event     string
+	IPAddress string
+	UserAgent string
+	Metadata  JSONB
+	CreatedAt time.Time
+}
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -13,17 +14,22 @@ type User struct {
 	LastName                  string
 	Phone                     string
 	Avatar                    string
+	AvatarVariants            map[string]string
 	Role                      string
 	Status                    string
 	EmailVerifiedAt           *time.Time
 	PhoneVerifiedAt           *time.Time
 	LastLoginAt               *time.Time
+	PasswordChangedAt         *time.Time
 	TFAEnabled                bool
 	TFASecret                 *string
 	TFABackupCodes            []string
 	EmailVerificationToken    *string
 	EmailVerificationSentAt   *time.Time
 	EmailVerificationAttempts int
+	DeletionRequestedAt       *time.Time
+	DeletionScheduledAt       *time.Time
+	AnonymizedAt              *time.Time
 	CreatedAt                 time.Time
 	UpdatedAt                 time.Time
 }
@@ -33,6 +39,12 @@ func (u *User) IsActive() bool {
 	return u.Status == "active"
 }
 
+// HasPassword reports whether the user can sign in with a password; accounts
+// created through a sign-in provider have none until they reset it
+func (u *User) HasPassword() bool {
+	return u.Password != ""
+}
+
 // IsEmailVerified checks if user email is verified
 func (u *User) IsEmailVerified() bool {
 	return u.EmailVerifiedAt != nil
@@ -43,6 +55,12 @@ func (u *User) IsPhoneVerified() bool {
 	return u.PhoneVerifiedAt != nil
 }
 
+// IsPasswordExpired checks if the password is older than maxAge; a zero
+// maxAge never expires
+func (u *User) IsPasswordExpired(maxAge time.Duration) bool {
+	return maxAge > 0 && u.PasswordChangedAt != nil && time.Since(*u.PasswordChangedAt) > maxAge
+}
+
 // IsAdmin checks if user is admin
 func (u *User) IsAdmin() bool {
 	return u.Role == "admin"
@@ -98,3 +116,57 @@ func (u *User) MarkEmailVerified() {
 	now := time.Now()
 	u.EmailVerifiedAt = &now
 }
+
+// HasUploadedAvatar checks if user has an uploaded avatar
+func (u *User) HasUploadedAvatar() bool {
+	return len(u.AvatarVariants) > 0
+}
+
+// IsPendingDeletion checks if user has scheduled account deletion
+func (u *User) IsPendingDeletion() bool {
+	return u.DeletionScheduledAt != nil && u.AnonymizedAt == nil
+}
+
+// IsAnonymized checks if user personal data has been erased
+func (u *User) IsAnonymized() bool {
+	return u.AnonymizedAt != nil
+}
+
+// ScheduleDeletion schedules account deletion after the grace period
+func (u *User) ScheduleDeletion(gracePeriod time.Duration) {
+	now := time.Now()
+	scheduledAt := now.Add(gracePeriod)
+	u.DeletionRequestedAt = &now
+	u.DeletionScheduledAt = &scheduledAt
+}
+
+// CancelDeletion cancels a scheduled account deletion
+func (u *User) CancelDeletion() {
+	u.DeletionRequestedAt = nil
+	u.DeletionScheduledAt = nil
+}
+
+// Anonymize erases personal data while keeping the record for references
+func (u *User) Anonymize() {
+	now := time.Now()
+	u.Email = fmt.Sprintf("deleted-%d@anonymized.invalid", u.ID)
+	u.Username = fmt.Sprintf("deleted_%d", u.ID)
+	u.Password = ""
+	u.FirstName = "Deleted"
+	u.LastName = "User"
+	u.Phone = ""
+	u.Avatar = ""
+	u.AvatarVariants = nil
+	u.Status = "deleted"
+	u.EmailVerifiedAt = nil
+	u.PhoneVerifiedAt = nil
+	u.LastLoginAt = nil
+	u.EmailVerificationToken = nil
+	u.EmailVerificationSentAt = nil
+	u.EmailVerificationAttempts = 0
+	u.DisableTFA()
+	u.DeletionRequestedAt = nil
+	u.DeletionScheduledAt = nil
+	u.AnonymizedAt = &now
+	u.UpdatedAt = now
+}
@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+// WebAuthn ceremony purposes
+const (
+	WebAuthnPurposeRegister = "register"
+	WebAuthnPurposeLogin    = "login"
+	WebAuthnPurposeVerify   = "verify" // confirm the current session as step-up
+	WebAuthnPurposeTFA      = "tfa"    // second factor of a login held by a TFA challenge
+)
+
+// WebAuthnCredential is a passkey registered by a user
+type WebAuthnCredential struct {
+	ID                uint
+	UserID            uint
+	Name              string
+	CredentialID      []byte
+	PublicKey         []byte // COSE_Key
+	Algorithm         int64
+	SignCount         uint32
+	AAGUID            []byte
+	UserHandle        []byte // opaque WebAuthn user.id, shared by the user's credentials
+	AttestationFormat string
+	Transports        []string
+	BackupEligible    bool
+	BackedUp          bool
+	LastUsedAt        *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// WebAuthnChallenge remembers a ceremony until its response arrives
+type WebAuthnChallenge struct {
+	ID            uint
+	ChallengeHash string // SHA-256 hex digest, see utils.HashToken
+	Purpose       string
+	UserID        uint   // 0 for passkey login, where the user is not known yet
+	UserHandle    []byte // handle offered to the authenticator during registration
+	ExpiresAt     time.Time
+	CreatedAt     time.Time
+}
+
+// Business methods for WebAuthnChallenge
+func (c *WebAuthnChallenge) IsExpired() bool {
+	return time.Now().After(c.ExpiresAt)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+)
+
+type APITokenRepository interface {
+	Create(ctx context.Context, token *entity.APIToken) error
+	GetByPrefix(ctx context.Context, prefix string) (*entity.APIToken, error)
+	ListByUserID(ctx context.Context, userID uint) ([]*entity.APIToken, error)
+	Revoke(ctx context.Context, id, userID uint) error
+	ListByOrgID(ctx context.Context, orgID uint) ([]*entity.APIToken, error)
+	RevokeForOrganization(ctx context.Context, id, orgID uint) error
+	TouchLastUsed(ctx context.Context, id uint, ip string, before time.Time) error
+}
@@ -3,27 +3,52 @@ package repository
 import (
 	"boilerplate-go-fiber-v2/internal/domain/entity"
 	"context"
+	"time"
 )
 
 type AuthRepository interface {
 	// Session management
 	CreateSession(ctx context.Context, session *entity.AuthSession) error
+	GetSessionByID(ctx context.Context, id uint) (*entity.AuthSession, error)
 	GetSessionByToken(ctx context.Context, token string) (*entity.AuthSession, error)
 	GetSessionByRefreshToken(ctx context.Context, refreshToken string) (*entity.AuthSession, error)
+	GetSessionsByUserID(ctx context.Context, userID uint) ([]*entity.AuthSession, error)
+	GetSessionsByActorID(ctx context.Context, actorID uint) ([]*entity.AuthSession, error)
 	UpdateSession(ctx context.Context, session *entity.AuthSession) error
 	DeleteSession(ctx context.Context, token string) error
 	DeleteSessionsByUserID(ctx context.Context, userID uint) error
+	DeleteSessionsByClientID(ctx context.Context, clientID uint) error
+	ClearSessionOrganization(ctx context.Context, userID, orgID uint) error
 	CleanExpiredSessions(ctx context.Context) error
 
 	// Password reset
 	CreatePasswordReset(ctx context.Context, reset *entity.PasswordReset) error
-	GetPasswordResetByToken(ctx context.Context, token string) (*entity.PasswordReset, error)
-	MarkPasswordResetUsed(ctx context.Context, token string) error
+	GetPasswordResetByToken(ctx context.Context, tokenHash string) (*entity.PasswordReset, error)
+	MarkPasswordResetUsed(ctx context.Context, tokenHash string) error
+	InvalidatePasswordResets(ctx context.Context, userID uint) error
 	CleanExpiredPasswordResets(ctx context.Context) error
 
+	// Magic links
+	CreateMagicLink(ctx context.Context, link *entity.MagicLink) error
+	GetMagicLinkByToken(ctx context.Context, tokenHash string) (*entity.MagicLink, error)
+	GetLatestMagicLink(ctx context.Context, userID uint) (*entity.MagicLink, error)
+	CountMagicLinksSince(ctx context.Context, userID uint, since time.Time) (int64, error)
+	MarkMagicLinkUsed(ctx context.Context, tokenHash string) error
+	InvalidateMagicLinks(ctx context.Context, userID uint) error
+	CleanExpiredMagicLinks(ctx context.Context) error
+
+	// Logins pending a second factor
+	CreatePendingLogin(ctx context.Context, login *entity.PendingLogin) error
+	GetPendingLoginByToken(ctx context.Context, tokenHash string) (*entity.PendingLogin, error)
+	MarkPendingLoginUsed(ctx context.Context, tokenHash string) error
+	CleanExpiredPendingLogins(ctx context.Context) error
+
 	// TFA codes
 	CreateTFACode(ctx context.Context, code *entity.TFACode) error
-	GetTFACodeByCode(ctx context.Context, code string) (*entity.TFACode, error)
-	MarkTFACodeUsed(ctx context.Context, code string) error
+	GetLatestTFACode(ctx context.Context, userID uint, purpose string) (*entity.TFACode, error)
+	CountTFACodesSince(ctx context.Context, userID uint, purpose string, since time.Time) (int64, error)
+	ReserveTFACodeAttempt(ctx context.Context, id uint, maxAttempts int) error
+	ClaimTFACode(ctx context.Context, id uint) error
+	InvalidateTFACodes(ctx context.Context, userID uint, purpose string) error
 	CleanExpiredTFACodes(ctx context.Context) error
 }
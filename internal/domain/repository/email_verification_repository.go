@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"context"
+)
+
+type EmailVerificationRepository interface {
+	Create(ctx context.Context, verification *entity.EmailVerification) error
+	GetByToken(ctx context.Context, tokenHash string) (*entity.EmailVerification, error)
+	GetByCancelToken(ctx context.Context, tokenHash string) (*entity.EmailVerification, error)
+	Update(ctx context.Context, verification *entity.EmailVerification) error
+	CancelPending(ctx context.Context, userID uint, purpose string) error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+)
+
+type IdentityRepository interface {
+	// Linked identities
+	Create(ctx context.Context, identity *entity.UserIdentity) error
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error)
+	ListByUserID(ctx context.Context, userID uint) ([]*entity.UserIdentity, error)
+	Update(ctx context.Context, identity *entity.UserIdentity) error
+	Delete(ctx context.Context, id, userID uint) error
+
+	// Sign-ins in progress
+	CreateAuthRequest(ctx context.Context, request *entity.OIDCAuthRequest) error
+	ClaimAuthRequest(ctx context.Context, stateHash string) (*entity.OIDCAuthRequest, error)
+	CleanExpiredAuthRequests(ctx context.Context) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+)
+
+type OAuthClientRepository interface {
+	Create(ctx context.Context, client *entity.OAuthClient) error
+	GetByID(ctx context.Context, id uint) (*entity.OAuthClient, error)
+	GetByClientID(ctx context.Context, clientID string) (*entity.OAuthClient, error)
+	List(ctx context.Context) ([]*entity.OAuthClient, error)
+	Update(ctx context.Context, client *entity.OAuthClient) error
+	TouchLastUsed(ctx context.Context, id uint) error
+}
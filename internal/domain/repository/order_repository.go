@@ -19,6 +19,7 @@ type OrderRepository interface {
 
 type OrderFilter struct {
 	UserID    uint    `json:"user_id"`
+	OrgID     uint    `json:"org_id"` // also applied from the active organization in the context
 	Status    string  `json:"status"`
 	MinAmount float64 `json:"min_amount"`
 	MaxAmount float64 `json:"max_amount"`
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+)
+
+type OrganizationRepository interface {
+	// Organizations
+	CreateOrganization(ctx context.Context, org *entity.Organization, owner *entity.Membership) error
+	GetOrganization(ctx context.Context, id uint) (*entity.Organization, error)
+	UpdateOrganization(ctx context.Context, org *entity.Organization) error
+	DeleteOrganization(ctx context.Context, id uint) error
+
+	// Memberships
+	GetMembership(ctx context.Context, orgID, userID uint) (*entity.Membership, error)
+	ListMembershipsByUser(ctx context.Context, userID uint) ([]*entity.Membership, error)
+	ListMembers(ctx context.Context, orgID uint) ([]*entity.Membership, error)
+	UpdateMemberRole(ctx context.Context, orgID, userID uint, role string) error
+	DeleteMembership(ctx context.Context, orgID, userID uint) error
+
+	// Invitations
+	CreateInvitation(ctx context.Context, invitation *entity.Invitation) error
+	GetInvitationByToken(ctx context.Context, tokenHash string) (*entity.Invitation, error)
+	ListPendingInvitations(ctx context.Context, orgID uint) ([]*entity.Invitation, error)
+	DeleteInvitation(ctx context.Context, id, orgID uint) error
+	AcceptInvitation(ctx context.Context, invitation *entity.Invitation, membership *entity.Membership) error
+	CleanExpiredInvitations(ctx context.Context) error
+}
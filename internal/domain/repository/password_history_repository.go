@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"context"
+)
+
+type PasswordHistoryRepository interface {
+	Create(ctx context.Context, entry *entity.PasswordHistory) error
+	ListRecent(ctx context.Context, userID uint, limit int) ([]*entity.PasswordHistory, error)
+	Prune(ctx context.Context, userID uint, keep int) error
+}
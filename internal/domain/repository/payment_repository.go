@@ -20,6 +20,7 @@ type PaymentRepository interface {
 
 type PaymentFilter struct {
 	UserID        uint    `json:"user_id"`
+	OrgID         uint    `json:"org_id"` // also applied from the active organization in the context
 	OrderID       uint    `json:"order_id"`
 	Status        string  `json:"status"`
 	Gateway       string  `json:"gateway"`
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"context"
+)
+
+type PrivacyRepository interface {
+	CreateDataExport(ctx context.Context, export *entity.DataExport) error
+	GetDataExportByID(ctx context.Context, id uint) (*entity.DataExport, error)
+	GetDataExportsByUserID(ctx context.Context, userID uint) ([]*entity.DataExport, error)
+	UpdateDataExport(ctx context.Context, export *entity.DataExport) error
+	GetExpiredDataExports(ctx context.Context) ([]*entity.DataExport, error)
+	DeleteDataExport(ctx context.Context, id uint) error
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+)
+
+type RBACRepository interface {
+	// Roles
+	ListRoles(ctx context.Context) ([]*entity.Role, error)
+	GetRoleByID(ctx context.Context, id uint) (*entity.Role, error)
+	GetRoleByName(ctx context.Context, name string) (*entity.Role, error)
+	CreateRole(ctx context.Context, role *entity.Role) error
+	UpdateRole(ctx context.Context, role *entity.Role) error
+	DeleteRole(ctx context.Context, id uint) error
+	SetRolePermissions(ctx context.Context, roleID uint, permissions []string) error
+
+	// Permissions
+	ListPermissions(ctx context.Context) ([]*entity.Permission, error)
+
+	// User roles
+	GetUserRoles(ctx context.Context, userID uint) ([]*entity.Role, error)
+	AssignRole(ctx context.Context, userID, roleID uint) error
+	RevokeRole(ctx context.Context, userID, roleID uint) error
+	GetUserAccess(ctx context.Context, userID uint) (*entity.UserAccess, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"context"
+)
+
+type SecurityEventRepository interface {
+	Create(ctx context.Context, event *entity.SecurityEvent) error
+	GetByUserID(ctx context.Context, userID uint, filter SecurityEventFilter) ([]*entity.SecurityEvent, error)
+	AnonymizeByUserID(ctx context.Context, userID uint) error
+}
+
+type SecurityEventFilter struct {
+	Event    string `json:"event"`
+	Page     int    `json:"page"`
+	Limit    int    `json:"limit"`
+	SortDesc bool   `json:"sort_desc"`
+}
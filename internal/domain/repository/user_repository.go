@@ -3,6 +3,7 @@ package repository
 import (
 	"boilerplate-go-fiber-v2/internal/domain/entity"
 	"context"
+	"time"
 )
 
 type UserRepository interface {
@@ -10,13 +11,17 @@ type UserRepository interface {
 	GetByID(ctx context.Context, id uint) (*entity.User, error)
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
 	GetByUsername(ctx context.Context, username string) (*entity.User, error)
+	ExistsBy(ctx context.Context, field, value string, excludeID uint) (bool, error)
 	Update(ctx context.Context, user *entity.User) error
 	Delete(ctx context.Context, id uint) error
 	List(ctx context.Context, filter UserFilter) ([]*entity.User, error)
 	Count(ctx context.Context, filter UserFilter) (int64, error)
 	UpdateLastLogin(ctx context.Context, userID uint) error
 	UpdateStatus(ctx context.Context, userID uint, status string) error
+	UpdatePassword(ctx context.Context, userID uint, hash string) error
 	UpdateTFA(ctx context.Context, userID uint, enabled bool, secret string, backupCodes []string) error
+	GetDueForDeletion(ctx context.Context, before time.Time) ([]*entity.User, error)
+	Anonymize(ctx context.Context, user *entity.User) error
 }
 
 type UserFilter struct {
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+)
+
+type WebAuthnRepository interface {
+	// Credentials
+	CreateCredential(ctx context.Context, credential *entity.WebAuthnCredential) error
+	GetCredentialByCredentialID(ctx context.Context, credentialID []byte) (*entity.WebAuthnCredential, error)
+	ListCredentials(ctx context.Context, userID uint) ([]*entity.WebAuthnCredential, error)
+	UpdateCredential(ctx context.Context, credential *entity.WebAuthnCredential) error
+	RenameCredential(ctx context.Context, id, userID uint, name string) (*entity.WebAuthnCredential, error)
+	DeleteCredential(ctx context.Context, id, userID uint) error
+
+	// Ceremonies in progress
+	CreateChallenge(ctx context.Context, challenge *entity.WebAuthnChallenge) error
+	ClaimChallenge(ctx context.Context, challengeHash string) (*entity.WebAuthnChallenge, error)
+	CleanExpiredChallenges(ctx context.Context) error
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+)
+
+type APITokenService interface {
+	// Create issues a personal access token and returns it with its
+	// plaintext value, which is never shown again
+	Create(ctx context.Context, userID uint, name string, scopes []string, ttl time.Duration) (*entity.APIToken, string, error)
+	List(ctx context.Context, userID uint) ([]*entity.APIToken, error)
+	Revoke(ctx context.Context, userID, tokenID uint) error
+
+	// Service keys belong to an organization and are managed by its owners
+	// and admins; userID is the acting member
+	CreateForOrganization(ctx context.Context, userID, orgID uint, name string, scopes []string, ttl time.Duration) (*entity.APIToken, string, error)
+	ListForOrganization(ctx context.Context, userID, orgID uint) ([]*entity.APIToken, error)
+	RevokeForOrganization(ctx context.Context, userID, orgID, tokenID uint) error
+
+	// Authenticate resolves a raw token to the token and its owner. The user
+	// is nil for service keys, which act for their organization.
+	Authenticate(ctx context.Context, raw, ip string) (*entity.APIToken, *entity.User, error)
+}
@@ -8,16 +8,29 @@ import (
 )
 
 type AuthService interface {
-	Login(ctx context.Context, email, password string) (*entity.User, *entity.AuthSession, error)
+	Login(ctx context.Context, email, password string) (*entity.User, *entity.AuthSession, *entity.LoginChallenge, error)
+	RequestMagicLink(ctx context.Context, email, ip string, bindDevice bool) (string, error)
+	LoginWithMagicLink(ctx context.Context, token, deviceToken, ip string) (*entity.User, *entity.AuthSession, *entity.LoginChallenge, error)
+	CompleteLogin(ctx context.Context, user *entity.User, methods ...string) (*entity.User, *entity.AuthSession, *entity.LoginChallenge, error)
+	GetPendingLogin(ctx context.Context, token string) (*entity.PendingLogin, error)
+	CreateTFALoginCode(ctx context.Context, token, channel string) error
+	LoginWithTFACode(ctx context.Context, token, code string) (*entity.User, *entity.AuthSession, *entity.LoginChallenge, error)
+	ContinueLogin(ctx context.Context, token string, methods ...string) (*entity.User, *entity.AuthSession, *entity.LoginChallenge, error)
+	StartSession(ctx context.Context, user *entity.User, methods ...string) (*entity.AuthSession, error)
 	Logout(ctx context.Context, token string) error
 	RefreshToken(ctx context.Context, refreshToken string) (*entity.AuthSession, error)
 	Register(ctx context.Context, user *entity.User) error
 	ValidateToken(ctx context.Context, token string) (*jwt.Claims, error)
+	AuthenticateToken(ctx context.Context, token string) (*jwt.Claims, *entity.AuthSession, error)
+	Reauthenticate(ctx context.Context, sessionID uint, password, code string) (*entity.AuthSession, error)
+	StepUp(ctx context.Context, sessionID uint, methods ...string) (*entity.AuthSession, error)
+	SetActiveOrganization(ctx context.Context, sessionID, orgID uint) (*entity.AuthSession, error)
+	CreateStepUpCode(ctx context.Context, userID uint, channel string) error
 	CreatePasswordReset(ctx context.Context, email string) error
 	ResetPassword(ctx context.Context, token, newPassword string) error
-	CreateTFACode(ctx context.Context, userID uint) error
+	CreateTFACode(ctx context.Context, userID uint, channel string) error
 	VerifyTFACode(ctx context.Context, userID uint, code string) error
-	EnableTFA(ctx context.Context, userID uint) (*entity.User, error)
+	EnableTFA(ctx context.Context, userID uint) (*entity.User, string, error)
 	DisableTFA(ctx context.Context, userID uint) error
 	VerifyTFA(ctx context.Context, userID uint, code string) error
 }
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+)
+
+// Condition decides one rule of a policy
+type Condition func(ctx context.Context, subject *entity.Subject, resource entity.Resource) bool
+
+// Policy lists, per action, the condition under which a resource type may be
+// acted on. Actions without a rule are denied.
+type Policy struct {
+	ResourceType string
+	Rules        map[string]Condition
+}
+
+type AuthorizationService interface {
+	// Subject builds the subject of an authenticated user
+	Subject(ctx context.Context, userID uint) (*entity.Subject, error)
+	// Authorize returns entity.ErrForbidden unless a policy allows the action
+	Authorize(ctx context.Context, subject *entity.Subject, action string, resource entity.Resource) error
+	// Can reports whether a policy allows the action
+	Can(ctx context.Context, subject *entity.Subject, action string, resource entity.Resource) bool
+}
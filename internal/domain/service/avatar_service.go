@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+	"io"
+)
+
+type AvatarService interface {
+	Upload(ctx context.Context, userID uint, src io.Reader) (map[string]string, error)
+	GetURLs(ctx context.Context, userID uint) (map[string]string, error)
+	Delete(ctx context.Context, userID uint) error
+}
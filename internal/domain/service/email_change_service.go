@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+)
+
+type EmailChangeService interface {
+	RequestChange(ctx context.Context, userID uint, newEmail string) (*entity.EmailVerification, error)
+	ConfirmChange(ctx context.Context, token string) error
+	CancelChange(ctx context.Context, token string) error
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+)
+
+type ImpersonationService interface {
+	// Start signs an admin in as another user with a short-lived token that
+	// carries the admin as its actor
+	Start(ctx context.Context, actorID, userID uint, reason string) (*entity.User, *entity.AuthSession, error)
+
+	// Active impersonation sessions, as seen by the admin or by the user
+	ListByActor(ctx context.Context, actorID uint) ([]*entity.AuthSession, error)
+	ListByUser(ctx context.Context, userID uint) ([]*entity.AuthSession, error)
+
+	// End terminates an impersonation session; partyID is the admin or the
+	// impersonated user
+	End(ctx context.Context, partyID, sessionID uint) error
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+)
+
+type OAuthService interface {
+	// Token endpoints; each call authenticates the client first
+	IssueToken(ctx context.Context, grantType, clientID, clientSecret, scope string) (*entity.AuthSession, error)
+	Introspect(ctx context.Context, clientID, clientSecret, token string) (*entity.TokenIntrospection, error)
+	Revoke(ctx context.Context, clientID, clientSecret, token, tokenTypeHint string) error
+
+	// Client administration
+	CreateClient(ctx context.Context, actorID uint, name string, scopes []string) (*entity.OAuthClient, string, error)
+	ListClients(ctx context.Context) ([]*entity.OAuthClient, error)
+	DisableClient(ctx context.Context, actorID, id uint) error
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+)
+
+type OIDCService interface {
+	// Providers lists the names of the configured sign-in providers
+	Providers() []string
+
+	// AuthorizationURL starts a sign-in (userID 0) or an account link at a
+	// provider and returns the URL to send the user to
+	AuthorizationURL(ctx context.Context, provider, purpose string, userID uint) (string, error)
+	// Login signs in with a provider. Like every login, it returns either a
+	// session or a challenge to complete first.
+	Login(ctx context.Context, provider, code, state string) (*entity.User, *entity.AuthSession, *entity.LoginChallenge, error)
+
+	// Linked identities
+	Link(ctx context.Context, userID uint, provider, code, state string) (*entity.UserIdentity, error)
+	ListIdentities(ctx context.Context, userID uint) ([]*entity.UserIdentity, error)
+	Unlink(ctx context.Context, userID, id uint) error
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+)
+
+// CodeDelivery is a one-time code on its way to a user
+type CodeDelivery struct {
+	User        *entity.User
+	Destination string
+	Code        string
+	Purpose     string
+	ExpiresAt   time.Time
+}
+
+// CodeChannel delivers one-time codes, e.g. by email or SMS
+type CodeChannel interface {
+	Name() string
+	// Destination returns where the user receives codes for a purpose
+	Destination(user *entity.User, purpose string) (string, error)
+	Deliver(ctx context.Context, delivery CodeDelivery) error
+}
+
+type OneTimeCodeService interface {
+	Issue(ctx context.Context, user *entity.User, purpose, channel string) (*entity.TFACode, error)
+	Verify(ctx context.Context, userID uint, purpose, code string) (*entity.TFACode, error)
+}
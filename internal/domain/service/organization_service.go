@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+)
+
+// OrganizationService manages team accounts. Every method takes the acting
+// user and checks their role in the organization; non-members get
+// entity.ErrOrganizationNotFound.
+type OrganizationService interface {
+	// Organizations
+	Create(ctx context.Context, userID uint, name string) (*entity.Organization, error)
+	ListForUser(ctx context.Context, userID uint) ([]*entity.Membership, error)
+	Get(ctx context.Context, userID, orgID uint) (*entity.Organization, *entity.Membership, error)
+	Update(ctx context.Context, userID, orgID uint, name string) (*entity.Organization, *entity.Membership, error)
+	Delete(ctx context.Context, userID, orgID uint) error
+
+	// Members
+	ListMembers(ctx context.Context, userID, orgID uint) ([]*entity.Membership, error)
+	UpdateMemberRole(ctx context.Context, userID, orgID, memberID uint, role string) error
+	RemoveMember(ctx context.Context, userID, orgID, memberID uint) error
+
+	// Invitations
+	Invite(ctx context.Context, userID, orgID uint, email, role string) (*entity.Invitation, error)
+	ListInvitations(ctx context.Context, userID, orgID uint) ([]*entity.Invitation, error)
+	RevokeInvitation(ctx context.Context, userID, orgID, invitationID uint) error
+	AcceptInvitation(ctx context.Context, userID uint, token string) (*entity.Membership, error)
+
+	// Switch makes orgID the active organization of a session, or the
+	// personal account when orgID is 0
+	Switch(ctx context.Context, userID, sessionID, orgID uint) (*entity.AuthSession, error)
+}
@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+)
+
+type PasswordService interface {
+	CheckCandidate(ctx context.Context, field, password string) error
+	CheckReuse(ctx context.Context, user *entity.User, field, password string) error
+	Remember(ctx context.Context, userID uint, hash string)
+}
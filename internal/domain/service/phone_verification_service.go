@@ -0,0 +1,11 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+type PhoneVerificationService interface {
+	Start(ctx context.Context, userID uint) (time.Time, error)
+	Confirm(ctx context.Context, userID uint, code string) error
+}
@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+)
+
+type PrivacyService interface {
+	RequestDataExport(ctx context.Context, userID uint) (*entity.DataExport, error)
+	GetDataExport(ctx context.Context, userID, exportID uint) (*entity.DataExport, error)
+	ListDataExports(ctx context.Context, userID uint) ([]*entity.DataExport, error)
+	RequestAccountDeletion(ctx context.Context, userID uint) (*entity.User, error)
+	CancelAccountDeletion(ctx context.Context, userID uint) error
+	ProcessScheduledDeletions(ctx context.Context) (int, error)
+	CleanExpiredDataExports(ctx context.Context) error
+}
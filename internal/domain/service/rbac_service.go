@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+)
+
+type RBACService interface {
+	// Authorization checks
+	GetUserAccess(ctx context.Context, userID uint) (*entity.UserAccess, error)
+	HasPermission(ctx context.Context, userID uint, permission string) (bool, error)
+	InvalidateUserAccess(ctx context.Context, userID uint)
+
+	// Administration
+	ListRoles(ctx context.Context) ([]*entity.Role, error)
+	GetRole(ctx context.Context, id uint) (*entity.Role, error)
+	CreateRole(ctx context.Context, role *entity.Role, permissions []string) (*entity.Role, error)
+	UpdateRole(ctx context.Context, id uint, name, description string) (*entity.Role, error)
+	DeleteRole(ctx context.Context, id uint) error
+	SetRolePermissions(ctx context.Context, id uint, permissions []string) (*entity.Role, error)
+	ListPermissions(ctx context.Context) ([]*entity.Permission, error)
+	GetUserRoles(ctx context.Context, userID uint) ([]*entity.Role, error)
+	AssignRole(ctx context.Context, actorID, userID uint, roleName string) error
+	RevokeRole(ctx context.Context, actorID, userID, roleID uint) error
+}
@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+)
+
+type SecurityEventService interface {
+	Record(ctx context.Context, userID uint, event string, metadata map[string]interface{})
+	GetByUserID(ctx context.Context, userID uint, filter repository.SecurityEventFilter) ([]*entity.SecurityEvent, error)
+}
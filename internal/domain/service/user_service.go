@@ -9,6 +9,7 @@ import (
 
 type UserService interface {
 	Register(ctx context.Context, user *entity.User) error
+	RegisterExternal(ctx context.Context, user *entity.User) error
 	GetByID(ctx context.Context, id uint) (*entity.User, error)
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
 	Update(ctx context.Context, user *entity.User) error
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/pkg/webauthn"
+)
+
+type WebAuthnService interface {
+	// Registration of a passkey by a signed-in user
+	BeginRegistration(ctx context.Context, userID uint) (*webauthn.CreationOptions, error)
+	FinishRegistration(ctx context.Context, userID uint, name string, resp *webauthn.RegistrationResponse) (*entity.WebAuthnCredential, error)
+
+	// Passkey login, where the passkey tells who the user is
+	BeginLogin(ctx context.Context) (*webauthn.RequestOptions, error)
+	FinishLogin(ctx context.Context, resp *webauthn.AssertionResponse) (*entity.User, *entity.AuthSession, *entity.LoginChallenge, error)
+
+	// Passkey as the second factor of a login held by a TFA challenge
+	BeginTFA(ctx context.Context, token string) (*webauthn.RequestOptions, error)
+	FinishTFA(ctx context.Context, token string, resp *webauthn.AssertionResponse) (*entity.User, *entity.AuthSession, *entity.LoginChallenge, error)
+
+	// Confirming the current session with a passkey as step-up
+	BeginVerify(ctx context.Context, userID uint) (*webauthn.RequestOptions, error)
+	FinishVerify(ctx context.Context, userID, sessionID uint, resp *webauthn.AssertionResponse) (*entity.AuthSession, error)
+
+	// Passkey management
+	ListCredentials(ctx context.Context, userID uint) ([]*entity.WebAuthnCredential, error)
+	RenameCredential(ctx context.Context, userID, id uint, name string) (*entity.WebAuthnCredential, error)
+	DeleteCredential(ctx context.Context, userID, id uint) error
+}
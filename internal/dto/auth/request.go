@@ -1,12 +1,14 @@
 package auth
 
+import "boilerplate-go-fiber-v2/pkg/webauthn"
+
 type RegisterRequest struct {
 	Email     string `json:"email" validate:"required,email"`
-	Username  string `json:"username" validate:"required,min=3,max=20"`
-	Password  string `json:"password" validate:"required,min=8"`
+	Username  string `json:"username" validate:"required,min=3,max=20,username"`
+	Password  string `json:"password" validate:"required,password"`
 	FirstName string `json:"first_name" validate:"required"`
 	LastName  string `json:"last_name" validate:"required"`
-	Phone     string `json:"phone" validate:"required"`
+	Phone     string `json:"phone" validate:"required,phone"`
 }
 
 type LoginRequest struct {
@@ -18,27 +20,99 @@ type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
 }
 
+// MagicLinkRequest asks for a login link by email. BindDevice restricts the
+// link to the requesting device when binding is optional.
+type MagicLinkRequest struct {
+	Email      string `json:"email" validate:"required,email"`
+	BindDevice bool   `json:"bind_device"`
+}
+
+// MagicLinkLoginRequest logs in with a login link
+type MagicLinkLoginRequest struct {
+	Token       string `json:"token" validate:"required"`
+	DeviceToken string `json:"device_token"`
+}
+
+// TFALoginRequest finishes a login held by a TFA challenge with a code from
+// an authenticator app or one sent by email or SMS
+type TFALoginRequest struct {
+	Token string `json:"token" validate:"required"`
+	Code  string `json:"code" validate:"required,numeric,min=6,max=8"`
+}
+
+// TFALoginCodeRequest asks for a sign-in code for a login held by a TFA
+// challenge
+type TFALoginCodeRequest struct {
+	Token   string `json:"token" validate:"required"`
+	Channel string `json:"channel" validate:"omitempty,oneof=email sms"`
+}
+
 type PasswordResetRequest struct {
 	Email string `json:"email" validate:"required,email"`
 }
 
 type ResetPasswordRequest struct {
 	Token       string `json:"token" validate:"required"`
-	NewPassword string `json:"new_password" validate:"required,min=8"`
+	NewPassword string `json:"new_password" validate:"required,password"`
 }
 
-type TFACodeRequest struct {
-	Code string `json:"code" validate:"required,min=6,max=6"`
+type CreateTFACodeRequest struct {
+	Channel string `json:"channel" validate:"omitempty,oneof=email sms"`
 }
 
-type EnableTFARequest struct {
-	Password string `json:"password" validate:"required"`
+type TFACodeRequest struct {
+	Code string `json:"code" validate:"required,min=6,max=6"`
 }
 
-type DisableTFARequest struct {
-	Password string `json:"password" validate:"required"`
+// ReauthenticateRequest confirms the user's identity with either the
+// password or a code from an authenticator app or a step-up code
+type ReauthenticateRequest struct {
+	Password string `json:"password" validate:"required_without=Code"`
+	Code     string `json:"code" validate:"required_without=Password,omitempty,numeric,min=6,max=8"`
 }
 
 type VerifyTFARequest struct {
 	Code string `json:"code" validate:"required,min=6,max=6"`
 }
+
+// OIDCProviderParams names a configured sign-in provider
+type OIDCProviderParams struct {
+	Provider string `params:"provider" validate:"required"`
+}
+
+// OIDCCallbackRequest carries the authorization response from a provider,
+// either posted by the frontend or as the query string of a redirect
+type OIDCCallbackRequest struct {
+	Provider string `params:"provider" validate:"required"`
+	Code     string `json:"code" query:"code" validate:"required"`
+	State    string `json:"state" query:"state" validate:"required"`
+}
+
+// PasskeyAssertionRequest carries the credential returned by
+// navigator.credentials.get for a passkey login or verification
+type PasskeyAssertionRequest struct {
+	Credential webauthn.AssertionResponse `json:"credential"`
+}
+
+// PasskeyTFARequest starts answering a TFA challenge with a passkey
+type PasskeyTFARequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// PasskeyTFAAssertionRequest answers a TFA challenge with the credential
+// returned by navigator.credentials.get
+type PasskeyTFAAssertionRequest struct {
+	Token      string                     `json:"token" validate:"required"`
+	Credential webauthn.AssertionResponse `json:"credential"`
+}
+
+// StartImpersonationRequest names the user to act as and why, for the audit
+// trail
+type StartImpersonationRequest struct {
+	UserID uint   `params:"id" validate:"required,min=1"`
+	Reason string `json:"reason" validate:"required,min=3,max=500"`
+}
+
+type ImpersonationParams struct {
+	ID uint `params:"id" validate:"required,min=1"`
+}
@@ -1,6 +1,10 @@
 package auth
 
-import "time"
+import (
+	"time"
+
+	"boilerplate-go-fiber-v2/pkg/webauthn"
+)
 
 type LoginResponse struct {
 	User         UserResponse `json:"user"`
@@ -10,6 +14,15 @@ type LoginResponse struct {
 	TokenType    string       `json:"token_type"`
 }
 
+// LoginChallengeResponse is returned instead of tokens when another step is
+// required before a session is issued
+type LoginChallengeResponse struct {
+	Challenge string    `json:"challenge"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Message   string    `json:"message"`
+}
+
 type UserResponse struct {
 	ID              uint       `json:"id"`
 	Email           string     `json:"email"`
@@ -40,6 +53,11 @@ type RefreshTokenResponse struct {
 	TokenType    string    `json:"token_type"`
 }
 
+type MagicLinkResponse struct {
+	DeviceToken string `json:"device_token,omitempty"` // present the link together with this token
+	Message     string `json:"message"`
+}
+
 type PasswordResetResponse struct {
 	Message string `json:"message"`
 }
@@ -47,6 +65,7 @@ type PasswordResetResponse struct {
 type TFAResponse struct {
 	Secret      string   `json:"secret"`
 	QRCode      string   `json:"qr_code"`
+	OTPAuthURL  string   `json:"otpauth_url"`
 	BackupCodes []string `json:"backup_codes"`
 	Message     string   `json:"message"`
 }
@@ -59,6 +78,45 @@ type TFACodeResponse struct {
 	Message string `json:"message"`
 }
 
+type ReauthenticateResponse struct {
+	AuthTime time.Time `json:"auth_time"`
+	Methods  []string  `json:"amr"`
+	Message  string    `json:"message"`
+}
+
 type LogoutResponse struct {
 	Message string `json:"message"`
 }
+
+type OIDCProvidersResponse struct {
+	Providers []string `json:"providers"`
+}
+
+type OIDCAuthorizationResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+// PasskeyRequestOptionsResponse is passed to navigator.credentials.get
+type PasskeyRequestOptionsResponse struct {
+	PublicKey *webauthn.RequestOptions `json:"publicKey"`
+}
+
+type ImpersonationResponse struct {
+	User        UserResponse `json:"user"`
+	SessionID   uint         `json:"session_id"`
+	AccessToken string       `json:"access_token"`
+	ExpiresAt   time.Time    `json:"expires_at"`
+}
+
+type ImpersonationSessionResponse struct {
+	ID        uint      `json:"id"`
+	UserID    uint      `json:"user_id"`
+	ActorID   uint      `json:"actor_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type EndImpersonationResponse struct {
+	ID      uint   `json:"id"`
+	Message string `json:"message"`
+}
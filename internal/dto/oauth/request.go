@@ -0,0 +1,35 @@
+package oauth
+
+// Client credentials may also be sent with HTTP Basic authentication, which
+// takes precedence over the form fields (RFC 6749 section 2.3.1)
+type ClientCredentials struct {
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+}
+
+type TokenRequest struct {
+	ClientCredentials
+	GrantType string `form:"grant_type" validate:"required"`
+	Scope     string `form:"scope" validate:"max=1000"`
+}
+
+type IntrospectRequest struct {
+	ClientCredentials
+	Token         string `form:"token" validate:"required"`
+	TokenTypeHint string `form:"token_type_hint"`
+}
+
+type RevokeRequest struct {
+	ClientCredentials
+	Token         string `form:"token" validate:"required"`
+	TokenTypeHint string `form:"token_type_hint"`
+}
+
+type CreateClientRequest struct {
+	Name   string   `json:"name" validate:"required,min=1,max=100"`
+	Scopes []string `json:"scopes" validate:"dive,required,max=100,excludesall= "`
+}
+
+type ClientParams struct {
+	ID uint `params:"id" validate:"required,min=1"`
+}
@@ -0,0 +1,41 @@
+package oauth
+
+import "time"
+
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope,omitempty"`
+}
+
+// IntrospectResponse follows RFC 7662 section 2.2; exp and iat are Unix times
+type IntrospectResponse struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Username  string `json:"username,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+	Sub       string `json:"sub,omitempty"`
+}
+
+type ClientResponse struct {
+	ID         uint       `json:"id"`
+	ClientID   string     `json:"client_id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	DisabledAt *time.Time `json:"disabled_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type CreateClientResponse struct {
+	ClientResponse
+	ClientSecret string `json:"client_secret"` // shown once
+}
+
+type MessageResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,58 @@
+package organization
+
+type OrganizationParams struct {
+	ID uint `params:"id" validate:"required,min=1"`
+}
+
+type CreateOrganizationRequest struct {
+	Name string `json:"name" validate:"required,min=2,max=100"`
+}
+
+type UpdateOrganizationRequest struct {
+	ID   uint   `params:"id" validate:"required,min=1"`
+	Name string `json:"name" validate:"required,min=2,max=100"`
+}
+
+type MemberParams struct {
+	ID     uint `params:"id" validate:"required,min=1"`
+	UserID uint `params:"userId" validate:"required,min=1"`
+}
+
+type UpdateMemberRoleRequest struct {
+	ID     uint   `params:"id" validate:"required,min=1"`
+	UserID uint   `params:"userId" validate:"required,min=1"`
+	Role   string `json:"role" validate:"required,oneof=owner admin member"`
+}
+
+type InviteRequest struct {
+	ID    uint   `params:"id" validate:"required,min=1"`
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=owner admin member"`
+}
+
+type InvitationParams struct {
+	ID           uint `params:"id" validate:"required,min=1"`
+	InvitationID uint `params:"invitationId" validate:"required,min=1"`
+}
+
+type CreateAPIKeyRequest struct {
+	ID            uint     `params:"id" validate:"required,min=1"`
+	Name          string   `json:"name" validate:"required,min=1,max=100"`
+	Scopes        []string `json:"scopes" validate:"dive,required,max=100"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"omitempty,min=1"`
+}
+
+type APIKeyParams struct {
+	ID    uint `params:"id" validate:"required,min=1"`
+	KeyID uint `params:"keyId" validate:"required,min=1"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// SwitchOrganizationRequest selects the active organization; omit org_id to
+// go back to the personal account
+type SwitchOrganizationRequest struct {
+	OrgID uint `json:"org_id"`
+}
@@ -0,0 +1,40 @@
+package organization
+
+import "time"
+
+type OrganizationResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"` // the caller's role
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type MemberResponse struct {
+	UserID    uint      `json:"user_id"`
+	Email     string    `json:"email"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Role      string    `json:"role"`
+	JoinedAt  time.Time `json:"joined_at"`
+}
+
+type InvitationResponse struct {
+	ID        uint      `json:"id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	InvitedBy uint      `json:"invited_by"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SwitchOrganizationResponse struct {
+	OrgID       *uint     `json:"org_id"`
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	TokenType   string    `json:"token_type"`
+}
+
+type MessageResponse struct {
+	Message string `json:"message"`
+}
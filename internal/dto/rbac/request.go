@@ -0,0 +1,36 @@
+package rbac
+
+type RoleParams struct {
+	ID uint `params:"id" validate:"required,min=1"`
+}
+
+type CreateRoleRequest struct {
+	Name        string   `json:"name" validate:"required,min=2,max=50,lowercase,excludesall= :*"`
+	Description string   `json:"description" validate:"max=255"`
+	Permissions []string `json:"permissions" validate:"dive,required,max=100"`
+}
+
+type UpdateRoleRequest struct {
+	ID          uint   `params:"id" validate:"required,min=1"`
+	Name        string `json:"name" validate:"omitempty,min=2,max=50,lowercase,excludesall= :*"`
+	Description string `json:"description" validate:"max=255"`
+}
+
+type SetRolePermissionsRequest struct {
+	ID          uint     `params:"id" validate:"required,min=1"`
+	Permissions []string `json:"permissions" validate:"dive,required,max=100"`
+}
+
+type UserParams struct {
+	UserID uint `params:"id" validate:"required,min=1"`
+}
+
+type AssignRoleRequest struct {
+	UserID uint   `params:"id" validate:"required,min=1"`
+	Role   string `json:"role" validate:"required"`
+}
+
+type RevokeRoleParams struct {
+	UserID uint `params:"id" validate:"required,min=1"`
+	RoleID uint `params:"roleId" validate:"required,min=1"`
+}
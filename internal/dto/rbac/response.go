@@ -0,0 +1,28 @@
+package rbac
+
+import "time"
+
+type RoleResponse struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	IsSystem    bool      `json:"is_system"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type PermissionResponse struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type UserRolesResponse struct {
+	UserID uint           `json:"user_id"`
+	Roles  []RoleResponse `json:"roles"`
+}
+
+type MessageResponse struct {
+	Message string `json:"message"`
+}
@@ -1,15 +1,17 @@
 package user
 
+import "boilerplate-go-fiber-v2/pkg/webauthn"
+
 type UpdateProfileRequest struct {
 	FirstName string `json:"first_name" validate:"required"`
 	LastName  string `json:"last_name" validate:"required"`
-	Phone     string `json:"phone" validate:"required"`
+	Phone     string `json:"phone" validate:"required,phone"`
 	Avatar    string `json:"avatar"`
 }
 
 type ChangePasswordRequest struct {
 	OldPassword string `json:"old_password" validate:"required"`
-	NewPassword string `json:"new_password" validate:"required,min=8"`
+	NewPassword string `json:"new_password" validate:"required,password"`
 }
 
 type UpdateStatusRequest struct {
@@ -25,3 +27,49 @@ type UserFilterRequest struct {
 	SortBy   string `query:"sort_by"`
 	SortDesc bool   `query:"sort_desc"`
 }
+
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" validate:"required,email,max=255"`
+}
+
+type EmailChangeTokenRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type ConfirmPhoneRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+type DataExportParams struct {
+	ID uint `params:"id" validate:"required,min=1"`
+}
+
+type CreateAPITokenRequest struct {
+	Name          string   `json:"name" validate:"required,min=1,max=100"`
+	Scopes        []string `json:"scopes" validate:"dive,required,max=100"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"omitempty,min=1"`
+}
+
+type APITokenParams struct {
+	ID uint `params:"id" validate:"required,min=1"`
+}
+
+type IdentityParams struct {
+	ID uint `params:"id" validate:"required,min=1"`
+}
+
+// RegisterPasskeyRequest carries the credential returned by
+// navigator.credentials.create
+type RegisterPasskeyRequest struct {
+	Name       string                        `json:"name" validate:"omitempty,max=100"`
+	Credential webauthn.RegistrationResponse `json:"credential"`
+}
+
+type RenamePasskeyRequest struct {
+	ID   uint   `params:"id" validate:"required,min=1"`
+	Name string `json:"name" validate:"required,min=1,max=100"`
+}
+
+type PasskeyParams struct {
+	ID uint `params:"id" validate:"required,min=1"`
+}
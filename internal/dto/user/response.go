@@ -1,6 +1,10 @@
 package user
 
-import "time"
+import (
+	"time"
+
+	"boilerplate-go-fiber-v2/pkg/webauthn"
+)
 
 type UserResponse struct {
 	ID              uint       `json:"id"`
@@ -41,7 +45,96 @@ type ChangePasswordResponse struct {
 	Message string `json:"message"`
 }
 
+type EmailChangeResponse struct {
+	NewEmail  string     `json:"new_email,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Message   string     `json:"message"`
+}
+
+type PhoneVerificationResponse struct {
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Message   string     `json:"message"`
+}
+
 type UpdateStatusResponse struct {
 	User    UserResponse `json:"user"`
 	Message string       `json:"message"`
 }
+
+type DataExportResponse struct {
+	ID          uint       `json:"id"`
+	Status      string     `json:"status"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type AccountDeletionResponse struct {
+	ScheduledAt *time.Time `json:"scheduled_at"`
+	Message     string     `json:"message"`
+}
+
+type AvatarResponse struct {
+	Variants map[string]string `json:"variants"`
+	Message  string            `json:"message,omitempty"`
+}
+
+type APITokenResponse struct {
+	ID         uint       `json:"id"`
+	Kind       string     `json:"kind"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP string     `json:"last_used_ip,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type CreateAPITokenResponse struct {
+	APITokenResponse
+	Token string `json:"token"` // shown once
+}
+
+type RevokeAPITokenResponse struct {
+	ID      uint   `json:"id"`
+	Message string `json:"message"`
+}
+
+type IdentityResponse struct {
+	ID            uint       `json:"id"`
+	Provider      string     `json:"provider"`
+	Email         string     `json:"email"`
+	EmailVerified bool       `json:"email_verified"`
+	LastLoginAt   *time.Time `json:"last_login_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type UnlinkIdentityResponse struct {
+	ID      uint   `json:"id"`
+	Message string `json:"message"`
+}
+
+type PasskeyResponse struct {
+	ID                uint       `json:"id"`
+	Name              string     `json:"name"`
+	AAGUID            string     `json:"aaguid"`
+	AttestationFormat string     `json:"attestation_format"`
+	Transports        []string   `json:"transports"`
+	BackupEligible    bool       `json:"backup_eligible"`
+	BackedUp          bool       `json:"backed_up"`
+	LastUsedAt        *time.Time `json:"last_used_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// PasskeyCreationOptionsResponse is passed to navigator.credentials.create
+type PasskeyCreationOptionsResponse struct {
+	PublicKey *webauthn.CreationOptions `json:"publicKey"`
+}
+
+type DeletePasskeyResponse struct {
+	ID      uint   `json:"id"`
+	Message string `json:"message"`
+}
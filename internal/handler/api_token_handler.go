@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"time"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/internal/dto/organization"
+	"boilerplate-go-fiber-v2/internal/dto/user"
+	"boilerplate-go-fiber-v2/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type APITokenHandler struct {
+	tokenService service.APITokenService
+}
+
+// NewAPITokenHandler creates a new API token handler
+func NewAPITokenHandler(tokenService service.APITokenService) *APITokenHandler {
+	return &APITokenHandler{
+		tokenService: tokenService,
+	}
+}
+
+// ListTokens lists the current user's API tokens
+func (h *APITokenHandler) ListTokens(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	tokens, err := h.tokenService.List(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]user.APITokenResponse, len(tokens))
+	for i, token := range tokens {
+		resp[i] = mapAPIToken(token)
+	}
+	return response.Success(c, "API tokens retrieved", resp)
+}
+
+// CreateToken issues an API token. The plaintext token is only returned here.
+func (h *APITokenHandler) CreateToken(c *fiber.Ctx, req *user.CreateAPITokenRequest) error {
+	userID := c.Locals("user_id").(uint)
+
+	ttl := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+
+	token, plaintext, err := h.tokenService.Create(c.Context(), userID, req.Name, req.Scopes, ttl)
+	if err != nil {
+		return err
+	}
+
+	c.Status(fiber.StatusCreated)
+	return response.Success(c, "API token created", user.CreateAPITokenResponse{
+		APITokenResponse: mapAPIToken(token),
+		Token:            plaintext,
+	})
+}
+
+// RevokeToken revokes one of the current user's API tokens
+func (h *APITokenHandler) RevokeToken(c *fiber.Ctx, req *user.APITokenParams) error {
+	userID := c.Locals("user_id").(uint)
+
+	if err := h.tokenService.Revoke(c.Context(), userID, req.ID); err != nil {
+		return err
+	}
+
+	resp := user.RevokeAPITokenResponse{
+		ID:      req.ID,
+		Message: "The token can no longer be used",
+	}
+
+	return response.Success(c, "API token revoked", resp)
+}
+
+// ListOrganizationKeys lists the service keys of an organization
+func (h *APITokenHandler) ListOrganizationKeys(c *fiber.Ctx, req *organization.OrganizationParams) error {
+	userID := c.Locals("user_id").(uint)
+
+	tokens, err := h.tokenService.ListForOrganization(c.Context(), userID, req.ID)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]user.APITokenResponse, len(tokens))
+	for i, token := range tokens {
+		resp[i] = mapAPIToken(token)
+	}
+	return response.Success(c, "API keys retrieved", resp)
+}
+
+// CreateOrganizationKey issues a service key for an organization. The
+// plaintext key is only returned here.
+func (h *APITokenHandler) CreateOrganizationKey(c *fiber.Ctx, req *organization.CreateAPIKeyRequest) error {
+	userID := c.Locals("user_id").(uint)
+
+	ttl := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+
+	token, plaintext, err := h.tokenService.CreateForOrganization(c.Context(), userID, req.ID, req.Name, req.Scopes, ttl)
+	if err != nil {
+		return err
+	}
+
+	c.Status(fiber.StatusCreated)
+	return response.Success(c, "API key created", user.CreateAPITokenResponse{
+		APITokenResponse: mapAPIToken(token),
+		Token:            plaintext,
+	})
+}
+
+// RevokeOrganizationKey revokes one of an organization's service keys
+func (h *APITokenHandler) RevokeOrganizationKey(c *fiber.Ctx, req *organization.APIKeyParams) error {
+	userID := c.Locals("user_id").(uint)
+
+	if err := h.tokenService.RevokeForOrganization(c.Context(), userID, req.ID, req.KeyID); err != nil {
+		return err
+	}
+
+	resp := user.RevokeAPITokenResponse{
+		ID:      req.KeyID,
+		Message: "The key can no longer be used",
+	}
+
+	return response.Success(c, "API key revoked", resp)
+}
+
+func mapAPIToken(token *entity.APIToken) user.APITokenResponse {
+	scopes := token.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return user.APITokenResponse{
+		ID:         token.ID,
+		Kind:       token.Kind,
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		LastUsedIP: token.LastUsedIP,
+		RevokedAt:  token.RevokedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
+
+// actingUserID returns the user an admin action is recorded for: the
+// authenticated user, or the creator of the service key that made the request
+func actingUserID(c *fiber.Ctx) uint {
+	if token, ok := c.Locals("api_token").(*entity.APIToken); ok && token.BelongsToOrganization() {
+		return token.CreatedBy
+	}
+	return c.Locals("user_id").(uint)
+}
@@ -6,7 +6,6 @@ import (
 	"boilerplate-go-fiber-v2/internal/dto/auth"
 	"boilerplate-go-fiber-v2/pkg/response"
 	"boilerplate-go-fiber-v2/pkg/utils"
-	"boilerplate-go-fiber-v2/pkg/validator"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -25,17 +24,7 @@ func NewAuthHandler(authService service.AuthService, userService service.UserSer
 }
 
 // Register handles user registration
-func (h *AuthHandler) Register(c *fiber.Ctx) error {
-	var req auth.RegisterRequest
-	if err := c.BodyParser(&req); err != nil {
-		return response.ValidationError(c, "Invalid request body")
-	}
-
-	// Validate request
-	if err := validator.ValidateStruct(req); err != nil {
-		return response.ValidationError(c, err.Error())
-	}
-
+func (h *AuthHandler) Register(c *fiber.Ctx, req *auth.RegisterRequest) error {
 	// Create user entity
 	user := &entity.User{
 		Email:     req.Email,
@@ -49,12 +38,12 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 	// Register user
 	err := h.authService.Register(c.Context(), user)
 	if err != nil {
-		return response.Error(c, err.Error(), fiber.StatusBadRequest)
+		return err
 	}
 
 	// Create response
 	resp := auth.RegisterResponse{
-		User:    h.mapUserToResponse(user),
+		User:    mapAuthUser(user),
 		Message: "User registered successfully",
 	}
 
@@ -62,26 +51,86 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 }
 
 // Login handles user login
-func (h *AuthHandler) Login(c *fiber.Ctx) error {
-	var req auth.LoginRequest
-	if err := c.BodyParser(&req); err != nil {
-		return response.ValidationError(c, "Invalid request body")
+func (h *AuthHandler) Login(c *fiber.Ctx, req *auth.LoginRequest) error {
+	// Login user
+	user, session, challenge, err := h.authService.Login(c.Context(), req.Email, req.Password)
+	if err != nil {
+		return err
 	}
 
-	// Validate request
-	if err := validator.ValidateStruct(req); err != nil {
-		return response.ValidationError(c, err.Error())
+	return loginResponse(c, user, session, challenge)
+}
+
+// RequestMagicLink emails a login link
+func (h *AuthHandler) RequestMagicLink(c *fiber.Ctx, req *auth.MagicLinkRequest) error {
+	deviceToken, err := h.authService.RequestMagicLink(c.Context(), req.Email, c.IP(), req.BindDevice)
+	if err != nil {
+		return err
 	}
 
-	// Login user
-	user, session, err := h.authService.Login(c.Context(), req.Email, req.Password)
+	resp := auth.MagicLinkResponse{
+		DeviceToken: deviceToken,
+		Message:     "If an account exists for this email, a login link has been sent",
+	}
+
+	return response.Success(c, "Login link requested", resp)
+}
+
+// LoginWithMagicLink handles login with an emailed login link
+func (h *AuthHandler) LoginWithMagicLink(c *fiber.Ctx, req *auth.MagicLinkLoginRequest) error {
+	user, session, challenge, err := h.authService.LoginWithMagicLink(c.Context(), req.Token, req.DeviceToken, c.IP())
+	if err != nil {
+		return err
+	}
+
+	return loginResponse(c, user, session, challenge)
+}
+
+// LoginWithTFACode finishes a login held by a TFA challenge with a code
+func (h *AuthHandler) LoginWithTFACode(c *fiber.Ctx, req *auth.TFALoginRequest) error {
+	user, session, challenge, err := h.authService.LoginWithTFACode(c.Context(), req.Token, req.Code)
 	if err != nil {
-		return response.Unauthorized(c, err.Error())
+		return err
+	}
+
+	return loginResponse(c, user, session, challenge)
+}
+
+// CreateTFALoginCode sends a sign-in code for a login held by a TFA challenge
+func (h *AuthHandler) CreateTFALoginCode(c *fiber.Ctx, req *auth.TFALoginCodeRequest) error {
+	if err := h.authService.CreateTFALoginCode(c.Context(), req.Token, req.Channel); err != nil {
+		return err
+	}
+
+	resp := auth.TFACodeResponse{
+		Message: "TFA code sent",
+	}
+
+	return response.Success(c, "TFA code created", resp)
+}
+
+// loginResponse returns the session of a login, or the challenge the user
+// has to complete first
+func loginResponse(c *fiber.Ctx, user *entity.User, session *entity.AuthSession, challenge *entity.LoginChallenge) error {
+	if challenge != nil {
+		resp := auth.LoginChallengeResponse{
+			Challenge: challenge.Type,
+			Token:     challenge.Token,
+			ExpiresAt: challenge.ExpiresAt,
+		}
+		switch challenge.Type {
+		case entity.LoginChallengeTFA:
+			resp.Message = "Confirm the login with a second factor: send this token with a code from your authenticator app, or one requested at /api/v1/auth/tfa/login/code, to /api/v1/auth/tfa/login, or answer it with a passkey at /api/v1/auth/passkey/tfa/start."
+			return response.Success(c, "TFA code required", resp)
+		default:
+			resp.Message = "Your password has expired. Set a new one with this token at /api/v1/auth/reset-password."
+			return response.Success(c, "Password change required", resp)
+		}
 	}
 
 	// Create response
 	resp := auth.LoginResponse{
-		User:         h.mapUserToResponse(user),
+		User:         mapAuthUser(user),
 		AccessToken:  session.Token,
 		RefreshToken: session.RefreshToken,
 		ExpiresAt:    session.ExpiresAt,
@@ -95,7 +144,7 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 func (h *AuthHandler) Logout(c *fiber.Ctx) error {
 	token := c.Get("Authorization")
 	if token == "" {
-		return response.Unauthorized(c, "Token required")
+		return entity.ErrMissingToken
 	}
 
 	// Remove "Bearer " prefix
@@ -105,7 +154,7 @@ func (h *AuthHandler) Logout(c *fiber.Ctx) error {
 
 	err := h.authService.Logout(c.Context(), token)
 	if err != nil {
-		return response.Error(c, err.Error(), fiber.StatusBadRequest)
+		return err
 	}
 
 	resp := auth.LogoutResponse{
@@ -116,21 +165,11 @@ func (h *AuthHandler) Logout(c *fiber.Ctx) error {
 }
 
 // RefreshToken handles token refresh
-func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
-	var req auth.RefreshTokenRequest
-	if err := c.BodyParser(&req); err != nil {
-		return response.ValidationError(c, "Invalid request body")
-	}
-
-	// Validate request
-	if err := validator.ValidateStruct(req); err != nil {
-		return response.ValidationError(c, err.Error())
-	}
-
+func (h *AuthHandler) RefreshToken(c *fiber.Ctx, req *auth.RefreshTokenRequest) error {
 	// Refresh token
 	session, err := h.authService.RefreshToken(c.Context(), req.RefreshToken)
 	if err != nil {
-		return response.Unauthorized(c, err.Error())
+		return err
 	}
 
 	// Create response
@@ -145,46 +184,26 @@ func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 }
 
 // CreatePasswordReset handles password reset request
-func (h *AuthHandler) CreatePasswordReset(c *fiber.Ctx) error {
-	var req auth.PasswordResetRequest
-	if err := c.BodyParser(&req); err != nil {
-		return response.ValidationError(c, "Invalid request body")
-	}
-
-	// Validate request
-	if err := validator.ValidateStruct(req); err != nil {
-		return response.ValidationError(c, err.Error())
-	}
-
+func (h *AuthHandler) CreatePasswordReset(c *fiber.Ctx, req *auth.PasswordResetRequest) error {
 	// Create password reset
 	err := h.authService.CreatePasswordReset(c.Context(), req.Email)
 	if err != nil {
-		return response.Error(c, err.Error(), fiber.StatusBadRequest)
+		return err
 	}
 
 	resp := auth.PasswordResetResponse{
-		Message: "Password reset email sent",
+		Message: "If an account exists for this email, a password reset link has been sent",
 	}
 
 	return response.Success(c, "Password reset initiated", resp)
 }
 
 // ResetPassword handles password reset
-func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
-	var req auth.ResetPasswordRequest
-	if err := c.BodyParser(&req); err != nil {
-		return response.ValidationError(c, "Invalid request body")
-	}
-
-	// Validate request
-	if err := validator.ValidateStruct(req); err != nil {
-		return response.ValidationError(c, err.Error())
-	}
-
+func (h *AuthHandler) ResetPassword(c *fiber.Ctx, req *auth.ResetPasswordRequest) error {
 	// Reset password
 	err := h.authService.ResetPassword(c.Context(), req.Token, req.NewPassword)
 	if err != nil {
-		return response.Error(c, err.Error(), fiber.StatusBadRequest)
+		return err
 	}
 
 	resp := auth.PasswordResetResponse{
@@ -194,13 +213,13 @@ func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
 	return response.Success(c, "Password reset successful", resp)
 }
 
-// CreateTFACode creates TFA code
-func (h *AuthHandler) CreateTFACode(c *fiber.Ctx) error {
+// CreateTFACode creates TFA code and sends it over the requested channel
+func (h *AuthHandler) CreateTFACode(c *fiber.Ctx, req *auth.CreateTFACodeRequest) error {
 	userID := c.Locals("user_id").(uint)
 
-	err := h.authService.CreateTFACode(c.Context(), userID)
+	err := h.authService.CreateTFACode(c.Context(), userID, req.Channel)
 	if err != nil {
-		return response.Error(c, err.Error(), fiber.StatusBadRequest)
+		return err
 	}
 
 	resp := auth.TFACodeResponse{
@@ -210,39 +229,21 @@ func (h *AuthHandler) CreateTFACode(c *fiber.Ctx) error {
 	return response.Success(c, "TFA code created", resp)
 }
 
-// EnableTFA enables TFA for user
+// EnableTFA enables TFA for user. The route requires a recent
+// authentication instead of asking for the password again.
 func (h *AuthHandler) EnableTFA(c *fiber.Ctx) error {
-	var req auth.EnableTFARequest
-	if err := c.BodyParser(&req); err != nil {
-		return response.ValidationError(c, "Invalid request body")
-	}
-
-	// Validate request
-	if err := validator.ValidateStruct(req); err != nil {
-		return response.ValidationError(c, err.Error())
-	}
-
 	userID := c.Locals("user_id").(uint)
 
-	// Verify password first
-	user, err := h.userService.GetByID(c.Context(), userID)
-	if err != nil {
-		return response.Error(c, "User not found", fiber.StatusNotFound)
-	}
-
-	if !utils.CheckPassword(req.Password, user.Password) {
-		return response.Unauthorized(c, "Invalid password")
-	}
-
 	// Enable TFA
-	user, err = h.authService.EnableTFA(c.Context(), userID)
+	user, otpauthURL, err := h.authService.EnableTFA(c.Context(), userID)
 	if err != nil {
-		return response.Error(c, err.Error(), fiber.StatusBadRequest)
+		return err
 	}
 
 	resp := auth.TFAResponse{
 		Secret:      utils.SafePtr(user.TFASecret, ""),
 		QRCode:      "", // TODO: Generate QR code
+		OTPAuthURL:  otpauthURL,
 		BackupCodes: user.TFABackupCodes,
 		Message:     "TFA enabled successfully",
 	}
@@ -250,61 +251,69 @@ func (h *AuthHandler) EnableTFA(c *fiber.Ctx) error {
 	return response.Success(c, "TFA enabled", resp)
 }
 
-// DisableTFA disables TFA for user
+// DisableTFA disables TFA for user. The route requires a recent
+// authentication instead of asking for the password again.
 func (h *AuthHandler) DisableTFA(c *fiber.Ctx) error {
-	var req auth.DisableTFARequest
-	if err := c.BodyParser(&req); err != nil {
-		return response.ValidationError(c, "Invalid request body")
-	}
-
-	// Validate request
-	if err := validator.ValidateStruct(req); err != nil {
-		return response.ValidationError(c, err.Error())
-	}
-
 	userID := c.Locals("user_id").(uint)
 
-	// Verify password first
-	user, err := h.userService.GetByID(c.Context(), userID)
+	// Disable TFA
+	err := h.authService.DisableTFA(c.Context(), userID)
 	if err != nil {
-		return response.Error(c, "User not found", fiber.StatusNotFound)
+		return err
 	}
 
-	if !utils.CheckPassword(req.Password, user.Password) {
-		return response.Unauthorized(c, "Invalid password")
+	resp := auth.TFADisableResponse{
+		Message: "TFA disabled successfully",
 	}
 
-	// Disable TFA
-	err = h.authService.DisableTFA(c.Context(), userID)
+	return response.Success(c, "TFA disabled", resp)
+}
+
+// Reauthenticate confirms the user's identity for sensitive operations
+func (h *AuthHandler) Reauthenticate(c *fiber.Ctx, req *auth.ReauthenticateRequest) error {
+	// API tokens have no session to step up
+	sessionID, ok := c.Locals("session_id").(uint)
+	if !ok {
+		return entity.ErrSessionNotFound
+	}
+
+	session, err := h.authService.Reauthenticate(c.Context(), sessionID, req.Password, req.Code)
 	if err != nil {
-		return response.Error(c, err.Error(), fiber.StatusBadRequest)
+		return err
 	}
 
-	resp := auth.TFADisableResponse{
-		Message: "TFA disabled successfully",
+	resp := auth.ReauthenticateResponse{
+		AuthTime: session.AuthTime,
+		Methods:  session.Methods(),
+		Message:  "Identity confirmed",
 	}
 
-	return response.Success(c, "TFA disabled", resp)
+	return response.Success(c, "Reauthenticated", resp)
 }
 
-// VerifyTFA verifies TFA code
-func (h *AuthHandler) VerifyTFA(c *fiber.Ctx) error {
-	var req auth.VerifyTFARequest
-	if err := c.BodyParser(&req); err != nil {
-		return response.ValidationError(c, "Invalid request body")
+// CreateStepUpCode sends a code that can be used to re-authenticate
+func (h *AuthHandler) CreateStepUpCode(c *fiber.Ctx, req *auth.CreateTFACodeRequest) error {
+	userID := c.Locals("user_id").(uint)
+
+	if err := h.authService.CreateStepUpCode(c.Context(), userID, req.Channel); err != nil {
+		return err
 	}
 
-	// Validate request
-	if err := validator.ValidateStruct(req); err != nil {
-		return response.ValidationError(c, err.Error())
+	resp := auth.TFACodeResponse{
+		Message: "Confirmation code sent",
 	}
 
+	return response.Success(c, "Confirmation code sent", resp)
+}
+
+// VerifyTFA verifies TFA code
+func (h *AuthHandler) VerifyTFA(c *fiber.Ctx, req *auth.VerifyTFARequest) error {
 	userID := c.Locals("user_id").(uint)
 
 	// Verify TFA
 	err := h.authService.VerifyTFA(c.Context(), userID, req.Code)
 	if err != nil {
-		return response.Unauthorized(c, err.Error())
+		return err
 	}
 
 	resp := auth.TFACodeResponse{
@@ -314,8 +323,8 @@ func (h *AuthHandler) VerifyTFA(c *fiber.Ctx) error {
 	return response.Success(c, "TFA verified", resp)
 }
 
-// Helper method to map user entity to response
-func (h *AuthHandler) mapUserToResponse(user *entity.User) auth.UserResponse {
+// mapAuthUser maps a user entity to the user shown in auth responses
+func mapAuthUser(user *entity.User) auth.UserResponse {
 	return auth.UserResponse{
 		ID:              user.ID,
 		Email:           user.Email,
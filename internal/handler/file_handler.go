@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"boilerplate-go-fiber-v2/pkg/response"
+	"boilerplate-go-fiber-v2/pkg/storage"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type FileHandler struct {
+	storage *storage.LocalStorage
+}
+
+// NewFileHandler creates a new file handler
+func NewFileHandler(storage *storage.LocalStorage) *FileHandler {
+	return &FileHandler{
+		storage: storage,
+	}
+}
+
+// Serve serves a file from local storage, checking the URL signature when
+// the storage is private
+func (h *FileHandler) Serve(c *fiber.Ctx) error {
+	key := c.Params("*")
+
+	if !h.storage.IsPublic() {
+		if err := h.storage.VerifySignature(key, c.Query("expires"), c.Query("signature")); err != nil {
+			return response.Forbidden(c, "Invalid or expired file URL")
+		}
+	}
+
+	path, err := h.storage.Path(key)
+	if err != nil {
+		return response.NotFound(c, "File not found")
+	}
+
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	if err := c.SendFile(path); err != nil {
+		return response.NotFound(c, "File not found")
+	}
+	return nil
+}
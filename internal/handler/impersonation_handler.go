@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/internal/dto/auth"
+	"boilerplate-go-fiber-v2/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ImpersonationHandler struct {
+	impersonationService service.ImpersonationService
+}
+
+// NewImpersonationHandler creates a new impersonation handler
+func NewImpersonationHandler(impersonationService service.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		impersonationService: impersonationService,
+	}
+}
+
+// Start issues a token for acting as another user
+func (h *ImpersonationHandler) Start(c *fiber.Ctx, req *auth.StartImpersonationRequest) error {
+	actorID := c.Locals("user_id").(uint)
+
+	u, session, err := h.impersonationService.Start(c.Context(), actorID, req.UserID, req.Reason)
+	if err != nil {
+		return err
+	}
+
+	c.Status(fiber.StatusCreated)
+	return response.Success(c, "Impersonation started", auth.ImpersonationResponse{
+		User:        mapAuthUser(u),
+		SessionID:   session.ID,
+		AccessToken: session.Token,
+		ExpiresAt:   session.ExpiresAt,
+	})
+}
+
+// ListStarted lists the impersonation sessions the current admin holds
+func (h *ImpersonationHandler) ListStarted(c *fiber.Ctx) error {
+	actorID := c.Locals("user_id").(uint)
+
+	sessions, err := h.impersonationService.ListByActor(c.Context(), actorID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Impersonations retrieved", mapImpersonations(sessions))
+}
+
+// ListMine lists the impersonation sessions on the current user's account
+func (h *ImpersonationHandler) ListMine(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	sessions, err := h.impersonationService.ListByUser(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Impersonations retrieved", mapImpersonations(sessions))
+}
+
+// End terminates an impersonation session held by or on the current user
+func (h *ImpersonationHandler) End(c *fiber.Ctx, req *auth.ImpersonationParams) error {
+	userID := c.Locals("user_id").(uint)
+
+	if err := h.impersonationService.End(c.Context(), userID, req.ID); err != nil {
+		return err
+	}
+
+	return response.Success(c, "Impersonation ended", auth.EndImpersonationResponse{
+		ID:      req.ID,
+		Message: "The impersonation token can no longer be used",
+	})
+}
+
+func mapImpersonations(sessions []*entity.AuthSession) []auth.ImpersonationSessionResponse {
+	resp := make([]auth.ImpersonationSessionResponse, len(sessions))
+	for i, session := range sessions {
+		resp[i] = auth.ImpersonationSessionResponse{
+			ID:        session.ID,
+			UserID:    session.UserID,
+			ActorID:   *session.ActorID,
+			ExpiresAt: session.ExpiresAt,
+			CreatedAt: session.CreatedAt,
+		}
+	}
+	return resp
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"time"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/internal/dto/oauth"
+	"boilerplate-go-fiber-v2/pkg/binder"
+	"boilerplate-go-fiber-v2/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type OAuthHandler struct {
+	oauthService service.OAuthService
+}
+
+// NewOAuthHandler creates a new OAuth handler
+func NewOAuthHandler(oauthService service.OAuthService) *OAuthHandler {
+	return &OAuthHandler{
+		oauthService: oauthService,
+	}
+}
+
+// Token handles the client_credentials grant. The token endpoints bind their
+// own requests so that binding errors are also reported in the RFC format.
+func (h *OAuthHandler) Token(c *fiber.Ctx) error {
+	req, err := binder.Bind[oauth.TokenRequest](c)
+	if err != nil {
+		return response.OAuthError(c, err)
+	}
+
+	clientID, clientSecret := clientCredentials(c, req.ClientCredentials)
+
+	session, err := h.oauthService.IssueToken(c.Context(), req.GrantType, clientID, clientSecret, req.Scope)
+	if err != nil {
+		return response.OAuthError(c, err)
+	}
+
+	return response.OAuth(c, oauth.TokenResponse{
+		AccessToken: session.Token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(time.Until(session.ExpiresAt).Seconds()),
+		Scope:       session.Scope,
+	})
+}
+
+// Introspect reports whether a token is active (RFC 7662)
+func (h *OAuthHandler) Introspect(c *fiber.Ctx) error {
+	req, err := binder.Bind[oauth.IntrospectRequest](c)
+	if err != nil {
+		return response.OAuthError(c, err)
+	}
+
+	clientID, clientSecret := clientCredentials(c, req.ClientCredentials)
+
+	introspection, err := h.oauthService.Introspect(c.Context(), clientID, clientSecret, req.Token)
+	if err != nil {
+		return response.OAuthError(c, err)
+	}
+
+	resp := oauth.IntrospectResponse{Active: introspection.Active}
+	if introspection.Active {
+		resp.Scope = introspection.Scope
+		resp.ClientID = introspection.ClientID
+		resp.Username = introspection.Username
+		resp.TokenType = introspection.TokenType
+		resp.Exp = introspection.ExpiresAt.Unix()
+		resp.Sub = introspection.Subject
+		if !introspection.IssuedAt.IsZero() {
+			resp.Iat = introspection.IssuedAt.Unix()
+		}
+	}
+
+	return response.OAuth(c, resp)
+}
+
+// Revoke revokes a token (RFC 7009). Unknown tokens still get 200.
+func (h *OAuthHandler) Revoke(c *fiber.Ctx) error {
+	req, err := binder.Bind[oauth.RevokeRequest](c)
+	if err != nil {
+		return response.OAuthError(c, err)
+	}
+
+	clientID, clientSecret := clientCredentials(c, req.ClientCredentials)
+
+	if err := h.oauthService.Revoke(c.Context(), clientID, clientSecret, req.Token, req.TokenTypeHint); err != nil {
+		return response.OAuthError(c, err)
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// ListClients lists OAuth clients
+func (h *OAuthHandler) ListClients(c *fiber.Ctx) error {
+	clients, err := h.oauthService.ListClients(c.Context())
+	if err != nil {
+		return err
+	}
+
+	resp := make([]oauth.ClientResponse, len(clients))
+	for i, client := range clients {
+		resp[i] = mapOAuthClient(client)
+	}
+	return response.Success(c, "OAuth clients retrieved", resp)
+}
+
+// CreateClient registers an OAuth client. The secret is only returned here.
+func (h *OAuthHandler) CreateClient(c *fiber.Ctx, req *oauth.CreateClientRequest) error {
+	actorID := actingUserID(c)
+
+	client, secret, err := h.oauthService.CreateClient(c.Context(), actorID, req.Name, req.Scopes)
+	if err != nil {
+		return err
+	}
+
+	c.Status(fiber.StatusCreated)
+	return response.Success(c, "OAuth client created", oauth.CreateClientResponse{
+		ClientResponse: mapOAuthClient(client),
+		ClientSecret:   secret,
+	})
+}
+
+// DisableClient disables an OAuth client and revokes its tokens
+func (h *OAuthHandler) DisableClient(c *fiber.Ctx, req *oauth.ClientParams) error {
+	actorID := actingUserID(c)
+
+	if err := h.oauthService.DisableClient(c.Context(), actorID, req.ID); err != nil {
+		return err
+	}
+
+	return response.Success(c, "OAuth client disabled", oauth.MessageResponse{Message: "The client and its tokens can no longer be used"})
+}
+
+// clientCredentials returns the client ID and secret from HTTP Basic
+// authentication, falling back to the request body
+func clientCredentials(c *fiber.Ctx, form oauth.ClientCredentials) (string, string) {
+	authHeader := c.Get(fiber.HeaderAuthorization)
+	if !strings.HasPrefix(authHeader, "Basic ") {
+		return form.ClientID, form.ClientSecret
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(authHeader, "Basic "))
+	if err != nil {
+		return "", ""
+	}
+
+	id, secret, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return "", ""
+	}
+
+	// Both parts are form-encoded before being joined (RFC 6749 section 2.3.1)
+	id, errID := url.QueryUnescape(id)
+	secret, errSecret := url.QueryUnescape(secret)
+	if errID != nil || errSecret != nil {
+		return "", ""
+	}
+	return id, secret
+}
+
+func mapOAuthClient(client *entity.OAuthClient) oauth.ClientResponse {
+	scopes := client.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return oauth.ClientResponse{
+		ID:         client.ID,
+		ClientID:   client.ClientID,
+		Name:       client.Name,
+		Scopes:     scopes,
+		DisabledAt: client.DisabledAt,
+		LastUsedAt: client.LastUsedAt,
+		CreatedAt:  client.CreatedAt,
+	}
+}
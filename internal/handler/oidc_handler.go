@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/internal/dto/auth"
+	"boilerplate-go-fiber-v2/internal/dto/user"
+	"boilerplate-go-fiber-v2/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type OIDCHandler struct {
+	oidcService service.OIDCService
+}
+
+// NewOIDCHandler creates a new OpenID Connect handler
+func NewOIDCHandler(oidcService service.OIDCService) *OIDCHandler {
+	return &OIDCHandler{
+		oidcService: oidcService,
+	}
+}
+
+// Providers lists the configured sign-in providers
+func (h *OIDCHandler) Providers(c *fiber.Ctx) error {
+	return response.Success(c, "Sign-in providers retrieved", auth.OIDCProvidersResponse{
+		Providers: h.oidcService.Providers(),
+	})
+}
+
+// StartLogin returns the provider URL that starts a sign-in
+func (h *OIDCHandler) StartLogin(c *fiber.Ctx, req *auth.OIDCProviderParams) error {
+	authURL, err := h.oidcService.AuthorizationURL(c.Context(), req.Provider, entity.OIDCPurposeLogin, 0)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Sign-in started", auth.OIDCAuthorizationResponse{
+		AuthorizationURL: authURL,
+	})
+}
+
+// Callback completes a sign-in and issues a session or a login challenge
+func (h *OIDCHandler) Callback(c *fiber.Ctx, req *auth.OIDCCallbackRequest) error {
+	u, session, challenge, err := h.oidcService.Login(c.Context(), req.Provider, req.Code, req.State)
+	if err != nil {
+		return err
+	}
+
+	return loginResponse(c, u, session, challenge)
+}
+
+// ListIdentities lists the current user's linked identities
+func (h *OIDCHandler) ListIdentities(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	identities, err := h.oidcService.ListIdentities(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]user.IdentityResponse, len(identities))
+	for i, identity := range identities {
+		resp[i] = mapIdentity(identity)
+	}
+	return response.Success(c, "Linked accounts retrieved", resp)
+}
+
+// StartLink returns the provider URL that links an identity to the current user
+func (h *OIDCHandler) StartLink(c *fiber.Ctx, req *auth.OIDCProviderParams) error {
+	userID := c.Locals("user_id").(uint)
+
+	authURL, err := h.oidcService.AuthorizationURL(c.Context(), req.Provider, entity.OIDCPurposeLink, userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Account link started", auth.OIDCAuthorizationResponse{
+		AuthorizationURL: authURL,
+	})
+}
+
+// LinkCallback completes an account link
+func (h *OIDCHandler) LinkCallback(c *fiber.Ctx, req *auth.OIDCCallbackRequest) error {
+	userID := c.Locals("user_id").(uint)
+
+	identity, err := h.oidcService.Link(c.Context(), userID, req.Provider, req.Code, req.State)
+	if err != nil {
+		return err
+	}
+
+	c.Status(fiber.StatusCreated)
+	return response.Success(c, "Account linked", mapIdentity(identity))
+}
+
+// Unlink removes a linked identity from the current user
+func (h *OIDCHandler) Unlink(c *fiber.Ctx, req *user.IdentityParams) error {
+	userID := c.Locals("user_id").(uint)
+
+	if err := h.oidcService.Unlink(c.Context(), userID, req.ID); err != nil {
+		return err
+	}
+
+	return response.Success(c, "Account unlinked", user.UnlinkIdentityResponse{
+		ID:      req.ID,
+		Message: "The provider can no longer be used to sign in",
+	})
+}
+
+func mapIdentity(identity *entity.UserIdentity) user.IdentityResponse {
+	return user.IdentityResponse{
+		ID:            identity.ID,
+		Provider:      identity.Provider,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+		LastLoginAt:   identity.LastLoginAt,
+		CreatedAt:     identity.CreatedAt,
+	}
+}
@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/internal/dto/organization"
+	"boilerplate-go-fiber-v2/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type OrganizationHandler struct {
+	orgService service.OrganizationService
+}
+
+// NewOrganizationHandler creates a new organization handler
+func NewOrganizationHandler(orgService service.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{
+		orgService: orgService,
+	}
+}
+
+// ListOrganizations lists the organizations of the current user
+func (h *OrganizationHandler) ListOrganizations(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	memberships, err := h.orgService.ListForUser(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]organization.OrganizationResponse, len(memberships))
+	for i, membership := range memberships {
+		resp[i] = mapOrganization(membership.Organization, membership)
+	}
+	return response.Success(c, "Organizations retrieved", resp)
+}
+
+// CreateOrganization creates an organization owned by the current user
+func (h *OrganizationHandler) CreateOrganization(c *fiber.Ctx, req *organization.CreateOrganizationRequest) error {
+	userID := c.Locals("user_id").(uint)
+
+	org, err := h.orgService.Create(c.Context(), userID, req.Name)
+	if err != nil {
+		return err
+	}
+
+	c.Status(fiber.StatusCreated)
+	return response.Success(c, "Organization created", mapOrganization(org, &entity.Membership{Role: entity.OrgRoleOwner}))
+}
+
+// GetOrganization gets an organization of the current user
+func (h *OrganizationHandler) GetOrganization(c *fiber.Ctx, req *organization.OrganizationParams) error {
+	userID := c.Locals("user_id").(uint)
+
+	org, membership, err := h.orgService.Get(c.Context(), userID, req.ID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Organization retrieved", mapOrganization(org, membership))
+}
+
+// UpdateOrganization renames an organization
+func (h *OrganizationHandler) UpdateOrganization(c *fiber.Ctx, req *organization.UpdateOrganizationRequest) error {
+	userID := c.Locals("user_id").(uint)
+
+	org, membership, err := h.orgService.Update(c.Context(), userID, req.ID, req.Name)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Organization updated", mapOrganization(org, membership))
+}
+
+// DeleteOrganization deletes an organization
+func (h *OrganizationHandler) DeleteOrganization(c *fiber.Ctx, req *organization.OrganizationParams) error {
+	userID := c.Locals("user_id").(uint)
+
+	if err := h.orgService.Delete(c.Context(), userID, req.ID); err != nil {
+		return err
+	}
+
+	return response.Success(c, "Organization deleted", organization.MessageResponse{Message: "Organization deleted"})
+}
+
+// ListMembers lists the members of an organization
+func (h *OrganizationHandler) ListMembers(c *fiber.Ctx, req *organization.OrganizationParams) error {
+	userID := c.Locals("user_id").(uint)
+
+	memberships, err := h.orgService.ListMembers(c.Context(), userID, req.ID)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]organization.MemberResponse, len(memberships))
+	for i, membership := range memberships {
+		resp[i] = organization.MemberResponse{
+			UserID:   membership.UserID,
+			Role:     membership.Role,
+			JoinedAt: membership.CreatedAt,
+		}
+		if membership.User != nil {
+			resp[i].Email = membership.User.Email
+			resp[i].FirstName = membership.User.FirstName
+			resp[i].LastName = membership.User.LastName
+		}
+	}
+	return response.Success(c, "Members retrieved", resp)
+}
+
+// UpdateMemberRole changes the role of a member
+func (h *OrganizationHandler) UpdateMemberRole(c *fiber.Ctx, req *organization.UpdateMemberRoleRequest) error {
+	userID := c.Locals("user_id").(uint)
+
+	if err := h.orgService.UpdateMemberRole(c.Context(), userID, req.ID, req.UserID, req.Role); err != nil {
+		return err
+	}
+
+	return response.Success(c, "Member updated", organization.MessageResponse{Message: "Role changed to " + req.Role})
+}
+
+// RemoveMember removes a member, or lets the current user leave
+func (h *OrganizationHandler) RemoveMember(c *fiber.Ctx, req *organization.MemberParams) error {
+	userID := c.Locals("user_id").(uint)
+
+	if err := h.orgService.RemoveMember(c.Context(), userID, req.ID, req.UserID); err != nil {
+		return err
+	}
+
+	return response.Success(c, "Member removed", organization.MessageResponse{Message: "Member removed"})
+}
+
+// ListInvitations lists the pending invitations of an organization
+func (h *OrganizationHandler) ListInvitations(c *fiber.Ctx, req *organization.OrganizationParams) error {
+	userID := c.Locals("user_id").(uint)
+
+	invitations, err := h.orgService.ListInvitations(c.Context(), userID, req.ID)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]organization.InvitationResponse, len(invitations))
+	for i, invitation := range invitations {
+		resp[i] = mapInvitation(invitation)
+	}
+	return response.Success(c, "Invitations retrieved", resp)
+}
+
+// Invite emails an invitation to join an organization
+func (h *OrganizationHandler) Invite(c *fiber.Ctx, req *organization.InviteRequest) error {
+	userID := c.Locals("user_id").(uint)
+
+	invitation, err := h.orgService.Invite(c.Context(), userID, req.ID, req.Email, req.Role)
+	if err != nil {
+		return err
+	}
+
+	c.Status(fiber.StatusCreated)
+	return response.Success(c, "Invitation sent", mapInvitation(invitation))
+}
+
+// RevokeInvitation revokes a pending invitation
+func (h *OrganizationHandler) RevokeInvitation(c *fiber.Ctx, req *organization.InvitationParams) error {
+	userID := c.Locals("user_id").(uint)
+
+	if err := h.orgService.RevokeInvitation(c.Context(), userID, req.ID, req.InvitationID); err != nil {
+		return err
+	}
+
+	return response.Success(c, "Invitation revoked", organization.MessageResponse{Message: "The invitation link can no longer be used"})
+}
+
+// AcceptInvitation joins the organization of an emailed invitation
+func (h *OrganizationHandler) AcceptInvitation(c *fiber.Ctx, req *organization.AcceptInvitationRequest) error {
+	userID := c.Locals("user_id").(uint)
+
+	membership, err := h.orgService.AcceptInvitation(c.Context(), userID, req.Token)
+	if err != nil {
+		return err
+	}
+
+	org, _, err := h.orgService.Get(c.Context(), userID, membership.OrgID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Invitation accepted", mapOrganization(org, membership))
+}
+
+// SwitchOrganization reissues the access token with another active
+// organization
+func (h *OrganizationHandler) SwitchOrganization(c *fiber.Ctx, req *organization.SwitchOrganizationRequest) error {
+	userID := c.Locals("user_id").(uint)
+
+	// API tokens have no session to switch
+	sessionID, ok := c.Locals("session_id").(uint)
+	if !ok {
+		return entity.ErrSessionNotFound
+	}
+
+	session, err := h.orgService.Switch(c.Context(), userID, sessionID, req.OrgID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Organization switched", organization.SwitchOrganizationResponse{
+		OrgID:       session.OrgID,
+		AccessToken: session.Token,
+		ExpiresAt:   session.ExpiresAt,
+		TokenType:   "Bearer",
+	})
+}
+
+func mapOrganization(org *entity.Organization, membership *entity.Membership) organization.OrganizationResponse {
+	return organization.OrganizationResponse{
+		ID:        org.ID,
+		Name:      org.Name,
+		Role:      membership.Role,
+		CreatedAt: org.CreatedAt,
+		UpdatedAt: org.UpdatedAt,
+	}
+}
+
+func mapInvitation(invitation *entity.Invitation) organization.InvitationResponse {
+	return organization.InvitationResponse{
+		ID:        invitation.ID,
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		InvitedBy: invitation.InvitedBy,
+		ExpiresAt: invitation.ExpiresAt,
+		CreatedAt: invitation.CreatedAt,
+	}
+}
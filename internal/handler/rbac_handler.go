@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/internal/dto/rbac"
+	"boilerplate-go-fiber-v2/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RBACHandler struct {
+	rbacService service.RBACService
+}
+
+// NewRBACHandler creates a new RBAC handler
+func NewRBACHandler(rbacService service.RBACService) *RBACHandler {
+	return &RBACHandler{
+		rbacService: rbacService,
+	}
+}
+
+// ListRoles lists all roles
+func (h *RBACHandler) ListRoles(c *fiber.Ctx) error {
+	roles, err := h.rbacService.ListRoles(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Roles retrieved", mapRoles(roles))
+}
+
+// GetRole gets a role
+func (h *RBACHandler) GetRole(c *fiber.Ctx, req *rbac.RoleParams) error {
+	role, err := h.rbacService.GetRole(c.Context(), req.ID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Role retrieved", mapRole(role))
+}
+
+// CreateRole creates a role
+func (h *RBACHandler) CreateRole(c *fiber.Ctx, req *rbac.CreateRoleRequest) error {
+	role, err := h.rbacService.CreateRole(c.Context(), &entity.Role{
+		Name:        req.Name,
+		Description: req.Description,
+	}, req.Permissions)
+	if err != nil {
+		return err
+	}
+
+	c.Status(fiber.StatusCreated)
+	return response.Success(c, "Role created", mapRole(role))
+}
+
+// UpdateRole renames or describes a role
+func (h *RBACHandler) UpdateRole(c *fiber.Ctx, req *rbac.UpdateRoleRequest) error {
+	role, err := h.rbacService.UpdateRole(c.Context(), req.ID, req.Name, req.Description)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Role updated", mapRole(role))
+}
+
+// DeleteRole deletes a custom role
+func (h *RBACHandler) DeleteRole(c *fiber.Ctx, req *rbac.RoleParams) error {
+	if err := h.rbacService.DeleteRole(c.Context(), req.ID); err != nil {
+		return err
+	}
+
+	return response.Success(c, "Role deleted", rbac.MessageResponse{Message: "Role deleted"})
+}
+
+// SetRolePermissions replaces the permissions of a role
+func (h *RBACHandler) SetRolePermissions(c *fiber.Ctx, req *rbac.SetRolePermissionsRequest) error {
+	role, err := h.rbacService.SetRolePermissions(c.Context(), req.ID, req.Permissions)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Role permissions updated", mapRole(role))
+}
+
+// ListPermissions lists all permissions
+func (h *RBACHandler) ListPermissions(c *fiber.Ctx) error {
+	permissions, err := h.rbacService.ListPermissions(c.Context())
+	if err != nil {
+		return err
+	}
+
+	resp := make([]rbac.PermissionResponse, len(permissions))
+	for i, p := range permissions {
+		resp[i] = rbac.PermissionResponse{
+			ID:          p.ID,
+			Name:        p.Name,
+			Description: p.Description,
+		}
+	}
+
+	return response.Success(c, "Permissions retrieved", resp)
+}
+
+// GetUserRoles lists the roles of a user
+func (h *RBACHandler) GetUserRoles(c *fiber.Ctx, req *rbac.UserParams) error {
+	roles, err := h.rbacService.GetUserRoles(c.Context(), req.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "User roles retrieved", rbac.UserRolesResponse{
+		UserID: req.UserID,
+		Roles:  mapRoles(roles),
+	})
+}
+
+// AssignRole gives a role to a user
+func (h *RBACHandler) AssignRole(c *fiber.Ctx, req *rbac.AssignRoleRequest) error {
+	actorID := actingUserID(c)
+
+	if err := h.rbacService.AssignRole(c.Context(), actorID, req.UserID, req.Role); err != nil {
+		return err
+	}
+
+	return response.Success(c, "Role assigned", rbac.MessageResponse{Message: "Role assigned"})
+}
+
+// RevokeRole removes a role from a user
+func (h *RBACHandler) RevokeRole(c *fiber.Ctx, req *rbac.RevokeRoleParams) error {
+	actorID := actingUserID(c)
+
+	if err := h.rbacService.RevokeRole(c.Context(), actorID, req.UserID, req.RoleID); err != nil {
+		return err
+	}
+
+	return response.Success(c, "Role revoked", rbac.MessageResponse{Message: "Role revoked"})
+}
+
+func mapRole(role *entity.Role) rbac.RoleResponse {
+	return rbac.RoleResponse{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		IsSystem:    role.IsSystem,
+		Permissions: role.Permissions,
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+	}
+}
+
+func mapRoles(roles []*entity.Role) []rbac.RoleResponse {
+	resp := make([]rbac.RoleResponse, len(roles))
+	for i, role := range roles {
+		resp[i] = mapRole(role)
+	}
+	return resp
+}
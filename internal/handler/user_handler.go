@@ -0,0 +1,275 @@
+package handler
+
+import (
+	"fmt"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/internal/dto/user"
+	"boilerplate-go-fiber-v2/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type UserHandler struct {
+	userService        service.UserService
+	avatarService      service.AvatarService
+	privacyService     service.PrivacyService
+	emailChangeService service.EmailChangeService
+	phoneService       service.PhoneVerificationService
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(userService service.UserService, avatarService service.AvatarService, privacyService service.PrivacyService, emailChangeService service.EmailChangeService, phoneService service.PhoneVerificationService) *UserHandler {
+	return &UserHandler{
+		userService:        userService,
+		avatarService:      avatarService,
+		privacyService:     privacyService,
+		emailChangeService: emailChangeService,
+		phoneService:       phoneService,
+	}
+}
+
+// UploadAvatar handles multipart avatar upload
+func (h *UserHandler) UploadAvatar(c *fiber.Ctx) error {
+	file, err := c.FormFile("avatar")
+	if err != nil {
+		return entity.ErrAvatarRequired
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return response.ValidationError(c, "Invalid avatar file")
+	}
+	defer src.Close()
+
+	userID := c.Locals("user_id").(uint)
+
+	// The service reads at most the configured size limit
+	variants, err := h.avatarService.Upload(c.Context(), userID, src)
+	if err != nil {
+		return err
+	}
+
+	resp := user.AvatarResponse{
+		Variants: variants,
+		Message:  "Avatar uploaded successfully",
+	}
+
+	return response.Success(c, "Avatar uploaded", resp)
+}
+
+// GetAvatar returns URLs of the current avatar variants
+func (h *UserHandler) GetAvatar(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	variants, err := h.avatarService.GetURLs(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Avatar retrieved", user.AvatarResponse{Variants: variants})
+}
+
+// DeleteAvatar removes the uploaded avatar
+func (h *UserHandler) DeleteAvatar(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	if err := h.avatarService.Delete(c.Context(), userID); err != nil {
+		return err
+	}
+
+	resp := user.AvatarResponse{
+		Message: "Avatar deleted successfully",
+	}
+
+	return response.Success(c, "Avatar deleted", resp)
+}
+
+// RequestDataExport starts building a personal data export
+func (h *UserHandler) RequestDataExport(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	export, err := h.privacyService.RequestDataExport(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	c.Status(fiber.StatusAccepted)
+	return response.Success(c, "Data export requested", h.mapDataExportToResponse(c, export))
+}
+
+// ListDataExports lists the user's data exports
+func (h *UserHandler) ListDataExports(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	exports, err := h.privacyService.ListDataExports(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]user.DataExportResponse, len(exports))
+	for i, export := range exports {
+		resp[i] = h.mapDataExportToResponse(c, export)
+	}
+
+	return response.Success(c, "Data exports retrieved", resp)
+}
+
+// GetDataExport returns the status of a data export
+func (h *UserHandler) GetDataExport(c *fiber.Ctx, req *user.DataExportParams) error {
+	userID := c.Locals("user_id").(uint)
+
+	export, err := h.privacyService.GetDataExport(c.Context(), userID, req.ID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Data export retrieved", h.mapDataExportToResponse(c, export))
+}
+
+// DownloadDataExport streams a completed data export archive
+func (h *UserHandler) DownloadDataExport(c *fiber.Ctx, req *user.DataExportParams) error {
+	userID := c.Locals("user_id").(uint)
+
+	export, err := h.privacyService.GetDataExport(c.Context(), userID, req.ID)
+	if err != nil {
+		return err
+	}
+
+	if !export.IsDownloadable() {
+		return entity.ErrDataExportUnavailable
+	}
+
+	return c.Download(export.FilePath, fmt.Sprintf("data-export-%s.zip", export.CreatedAt.Format("20060102")))
+}
+
+// RequestAccountDeletion schedules account deletion after the grace period.
+// The route requires a recent authentication instead of the password, which
+// passwordless accounts do not have.
+func (h *UserHandler) RequestAccountDeletion(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	deletedUser, err := h.privacyService.RequestAccountDeletion(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	resp := user.AccountDeletionResponse{
+		ScheduledAt: deletedUser.DeletionScheduledAt,
+		Message:     "Your account will be deleted at the end of the grace period. Log in and cancel to keep it.",
+	}
+
+	return response.Success(c, "Account deletion scheduled", resp)
+}
+
+// CancelAccountDeletion cancels a scheduled account deletion
+func (h *UserHandler) CancelAccountDeletion(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	err := h.privacyService.CancelAccountDeletion(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	resp := user.AccountDeletionResponse{
+		Message: "Account deletion cancelled",
+	}
+
+	return response.Success(c, "Account deletion cancelled", resp)
+}
+
+// RequestEmailChange starts changing the email address
+func (h *UserHandler) RequestEmailChange(c *fiber.Ctx, req *user.ChangeEmailRequest) error {
+	userID := c.Locals("user_id").(uint)
+
+	verification, err := h.emailChangeService.RequestChange(c.Context(), userID, req.NewEmail)
+	if err != nil {
+		return err
+	}
+
+	resp := user.EmailChangeResponse{
+		NewEmail:  verification.Email,
+		ExpiresAt: &verification.ExpiresAt,
+		Message:   "Check your new inbox to confirm the change. Your current address received a link to cancel it.",
+	}
+
+	c.Status(fiber.StatusAccepted)
+	return response.Success(c, "Email change requested", resp)
+}
+
+// ConfirmEmailChange confirms the new email address
+func (h *UserHandler) ConfirmEmailChange(c *fiber.Ctx, req *user.EmailChangeTokenRequest) error {
+	if err := h.emailChangeService.ConfirmChange(c.Context(), req.Token); err != nil {
+		return err
+	}
+
+	resp := user.EmailChangeResponse{
+		Message: "Email changed. Log in again with your new address.",
+	}
+
+	return response.Success(c, "Email changed", resp)
+}
+
+// CancelEmailChange cancels or reverts an email change
+func (h *UserHandler) CancelEmailChange(c *fiber.Ctx, req *user.EmailChangeTokenRequest) error {
+	if err := h.emailChangeService.CancelChange(c.Context(), req.Token); err != nil {
+		return err
+	}
+
+	resp := user.EmailChangeResponse{
+		Message: "Email change cancelled",
+	}
+
+	return response.Success(c, "Email change cancelled", resp)
+}
+
+// Helper method to map data export entity to response
+func (h *UserHandler) mapDataExportToResponse(c *fiber.Ctx, export *entity.DataExport) user.DataExportResponse {
+	resp := user.DataExportResponse{
+		ID:          export.ID,
+		Status:      export.Status,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+		CreatedAt:   export.CreatedAt,
+	}
+
+	if export.IsDownloadable() {
+		resp.DownloadURL = c.BaseURL() + fmt.Sprintf("/api/v1/users/me/export/%d/download", export.ID)
+	}
+
+	return resp
+}
+
+// StartPhoneVerification sends a verification code to the user's phone
+func (h *UserHandler) StartPhoneVerification(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	expiresAt, err := h.phoneService.Start(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	resp := user.PhoneVerificationResponse{
+		ExpiresAt: &expiresAt,
+		Message:   "Verification code sent",
+	}
+
+	c.Status(fiber.StatusAccepted)
+	return response.Success(c, "Verification code sent", resp)
+}
+
+// ConfirmPhoneVerification verifies the user's phone with the received code
+func (h *UserHandler) ConfirmPhoneVerification(c *fiber.Ctx, req *user.ConfirmPhoneRequest) error {
+	userID := c.Locals("user_id").(uint)
+
+	if err := h.phoneService.Confirm(c.Context(), userID, req.Code); err != nil {
+		return err
+	}
+
+	resp := user.PhoneVerificationResponse{
+		Message: "Phone number verified",
+	}
+
+	return response.Success(c, "Phone number verified", resp)
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"encoding/hex"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/internal/dto/auth"
+	"boilerplate-go-fiber-v2/internal/dto/user"
+	"boilerplate-go-fiber-v2/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type WebAuthnHandler struct {
+	webauthnService service.WebAuthnService
+}
+
+// NewWebAuthnHandler creates a new passkey handler
+func NewWebAuthnHandler(webauthnService service.WebAuthnService) *WebAuthnHandler {
+	return &WebAuthnHandler{
+		webauthnService: webauthnService,
+	}
+}
+
+// StartLogin returns the options of a passkey login
+func (h *WebAuthnHandler) StartLogin(c *fiber.Ctx) error {
+	options, err := h.webauthnService.BeginLogin(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Passkey login started", auth.PasskeyRequestOptionsResponse{
+		PublicKey: options,
+	})
+}
+
+// FinishLogin signs in with a passkey and issues a session
+func (h *WebAuthnHandler) FinishLogin(c *fiber.Ctx, req *auth.PasskeyAssertionRequest) error {
+	u, session, challenge, err := h.webauthnService.FinishLogin(c.Context(), &req.Credential)
+	if err != nil {
+		return err
+	}
+
+	return loginResponse(c, u, session, challenge)
+}
+
+// StartTFA returns the options that answer a TFA challenge with a passkey
+func (h *WebAuthnHandler) StartTFA(c *fiber.Ctx, req *auth.PasskeyTFARequest) error {
+	options, err := h.webauthnService.BeginTFA(c.Context(), req.Token)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Passkey verification started", auth.PasskeyRequestOptionsResponse{
+		PublicKey: options,
+	})
+}
+
+// FinishTFA finishes a login held by a TFA challenge with a passkey
+func (h *WebAuthnHandler) FinishTFA(c *fiber.Ctx, req *auth.PasskeyTFAAssertionRequest) error {
+	u, session, challenge, err := h.webauthnService.FinishTFA(c.Context(), req.Token, &req.Credential)
+	if err != nil {
+		return err
+	}
+
+	return loginResponse(c, u, session, challenge)
+}
+
+// StartVerify returns the options that confirm the current session with a passkey
+func (h *WebAuthnHandler) StartVerify(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	options, err := h.webauthnService.BeginVerify(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Passkey verification started", auth.PasskeyRequestOptionsResponse{
+		PublicKey: options,
+	})
+}
+
+// FinishVerify confirms the current session with a passkey as step-up
+func (h *WebAuthnHandler) FinishVerify(c *fiber.Ctx, req *auth.PasskeyAssertionRequest) error {
+	userID := c.Locals("user_id").(uint)
+
+	// API tokens have no session to step up
+	sessionID, ok := c.Locals("session_id").(uint)
+	if !ok {
+		return entity.ErrSessionNotFound
+	}
+
+	session, err := h.webauthnService.FinishVerify(c.Context(), userID, sessionID, &req.Credential)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Passkey verified", auth.ReauthenticateResponse{
+		AuthTime: session.AuthTime,
+		Methods:  session.Methods(),
+		Message:  "Identity confirmed",
+	})
+}
+
+// ListPasskeys lists the current user's passkeys
+func (h *WebAuthnHandler) ListPasskeys(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	credentials, err := h.webauthnService.ListCredentials(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]user.PasskeyResponse, len(credentials))
+	for i, credential := range credentials {
+		resp[i] = mapPasskey(credential)
+	}
+	return response.Success(c, "Passkeys retrieved", resp)
+}
+
+// StartRegistration returns the options that create a passkey for the current user
+func (h *WebAuthnHandler) StartRegistration(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	options, err := h.webauthnService.BeginRegistration(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Passkey registration started", user.PasskeyCreationOptionsResponse{
+		PublicKey: options,
+	})
+}
+
+// FinishRegistration stores a new passkey for the current user
+func (h *WebAuthnHandler) FinishRegistration(c *fiber.Ctx, req *user.RegisterPasskeyRequest) error {
+	userID := c.Locals("user_id").(uint)
+
+	credential, err := h.webauthnService.FinishRegistration(c.Context(), userID, req.Name, &req.Credential)
+	if err != nil {
+		return err
+	}
+
+	c.Status(fiber.StatusCreated)
+	return response.Success(c, "Passkey registered", mapPasskey(credential))
+}
+
+// RenamePasskey renames one of the current user's passkeys
+func (h *WebAuthnHandler) RenamePasskey(c *fiber.Ctx, req *user.RenamePasskeyRequest) error {
+	userID := c.Locals("user_id").(uint)
+
+	credential, err := h.webauthnService.RenameCredential(c.Context(), userID, req.ID, req.Name)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Passkey renamed", mapPasskey(credential))
+}
+
+// DeletePasskey removes one of the current user's passkeys
+func (h *WebAuthnHandler) DeletePasskey(c *fiber.Ctx, req *user.PasskeyParams) error {
+	userID := c.Locals("user_id").(uint)
+
+	if err := h.webauthnService.DeleteCredential(c.Context(), userID, req.ID); err != nil {
+		return err
+	}
+
+	return response.Success(c, "Passkey deleted", user.DeletePasskeyResponse{
+		ID:      req.ID,
+		Message: "The passkey can no longer be used to sign in",
+	})
+}
+
+func mapPasskey(credential *entity.WebAuthnCredential) user.PasskeyResponse {
+	transports := credential.Transports
+	if transports == nil {
+		transports = []string{}
+	}
+	return user.PasskeyResponse{
+		ID:                credential.ID,
+		Name:              credential.Name,
+		AAGUID:            hex.EncodeToString(credential.AAGUID),
+		AttestationFormat: credential.AttestationFormat,
+		Transports:        transports,
+		BackupEligible:    credential.BackupEligible,
+		BackedUp:          credential.BackedUp,
+		LastUsedAt:        credential.LastUsedAt,
+		CreatedAt:         credential.CreatedAt,
+	}
+}
@@ -2,86 +2,196 @@ package middleware
 
 import (
 	"strings"
+	"time"
 
 	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/internal/domain/entity"
 	"boilerplate-go-fiber-v2/internal/domain/service"
-	"boilerplate-go-fiber-v2/pkg/response"
+	"boilerplate-go-fiber-v2/pkg/jwt"
+	"boilerplate-go-fiber-v2/pkg/utils"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// apiKeyHeader carries API tokens for clients that cannot set Authorization
+const apiKeyHeader = "X-API-Key"
+
 type AuthMiddleware struct {
-	authService service.AuthService
-	config      *config.Config
+	authService  service.AuthService
+	rbacService  service.RBACService
+	tokenService service.APITokenService
+	config       *config.Config
 }
 
-// NewAuthMiddleware creates a new auth middleware
-func NewAuthMiddleware(authService service.AuthService, config *config.Config) *AuthMiddleware {
+// NewAuthMiddleware creates a new auth middleware. tokenService may be nil,
+// in which case API tokens are rejected.
+func NewAuthMiddleware(authService service.AuthService, rbacService service.RBACService, tokenService service.APITokenService, config *config.Config) *AuthMiddleware {
 	return &AuthMiddleware{
-		authService: authService,
-		config:      config,
+		authService:  authService,
+		rbacService:  rbacService,
+		tokenService: tokenService,
+		config:       config,
 	}
 }
 
-// Authenticate validates JWT token and sets user context
+// Authenticate validates a JWT and sets user context. API tokens are
+// rejected; routes that accept them use RequireScope instead.
 func (m *AuthMiddleware) Authenticate() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// Get Authorization header
-		authHeader := c.Get("Authorization")
-		if authHeader == "" {
-			return response.Unauthorized(c, "Authorization header required")
+		if err := m.authenticate(c, nil); err != nil {
+			return err
 		}
+		return c.Next()
+	}
+}
 
-		// Check Bearer token format
-		if !strings.HasPrefix(authHeader, "Bearer ") {
-			return response.Unauthorized(c, "Invalid token format")
+// RequireScope authenticates like Authenticate, but also accepts API tokens
+// whose scopes cover every listed scope, e.g. RequireScope("orders:read").
+// Scopes are permission names, so a token passes only when its owner still
+// holds them. Session requests are not limited by scopes; guard them with
+// RequirePermission as usual.
+func (m *AuthMiddleware) RequireScope(scopes ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := m.authenticate(c, scopes); err != nil {
+			return err
 		}
+		return c.Next()
+	}
+}
 
-		// Extract token
-		token := strings.TrimPrefix(authHeader, "Bearer ")
+// authenticate validates the request's credentials. API tokens are denied
+// unless the route declares scopes and the token covers all of them.
+func (m *AuthMiddleware) authenticate(c *fiber.Ctx, scopes []string) error {
+	// API tokens come in X-API-Key or as a bearer token with a known prefix
+	if raw := m.apiToken(c); raw != "" {
+		if len(scopes) == 0 {
+			return entity.ErrAPITokenNotAllowed
+		}
+		if err := m.authenticateAPIToken(c, raw); err != nil {
+			return err
+		}
 
-		// Validate token
-		claims, err := m.authService.ValidateToken(c.Context(), token)
+		access, err := m.userAccess(c)
 		if err != nil {
-			return response.Unauthorized(c, "Invalid or expired token")
+			return err
 		}
+		for _, scope := range scopes {
+			if !access.HasPermission(scope) {
+				return entity.ErrInsufficientScope
+			}
+		}
+		return nil
+	}
 
-		// Set user context
-		c.Locals("user_id", claims.UserID)
-		c.Locals("user_email", claims.Email)
-		c.Locals("user_role", claims.Role)
+	// Get Authorization header
+	authHeader := c.Get("Authorization")
+	if authHeader == "" {
+		return entity.ErrMissingToken
+	}
 
-		return c.Next()
+	// Check Bearer token format
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		return entity.ErrInvalidToken.WithMessage("Invalid token format")
+	}
+
+	// Extract token
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+
+	// Validate token
+	claims, session, err := m.authService.AuthenticateToken(c.Context(), token)
+	if err != nil {
+		return entity.ErrInvalidToken
 	}
+
+	// Set user context
+	setAuthLocals(c, claims, session)
+	return nil
 }
 
-// RequireRole checks if user has required role
+// RequireRole checks if user has required role. It must run after
+// Authenticate; unauthenticated requests are rejected instead of panicking.
 func (m *AuthMiddleware) RequireRole(role string) fiber.Handler {
-	return func(c *fiber.Ctx) error {
-		userRole := c.Locals("user_role").(string)
-		if userRole != role {
-			return response.Forbidden(c, "Insufficient permissions")
-		}
-		return c.Next()
-	}
+	return m.RequireRoles(role)
 }
 
 // RequireRoles checks if user has any of the required roles
 func (m *AuthMiddleware) RequireRoles(roles ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		userRole := c.Locals("user_role").(string)
+		access, err := m.userAccess(c)
+		if err != nil {
+			return err
+		}
+
 		for _, role := range roles {
-			if userRole == role {
+			if access.HasRole(role) {
 				return c.Next()
 			}
 		}
-		return response.Forbidden(c, "Insufficient permissions")
+		return entity.ErrInsufficientRole
+	}
+}
+
+// RequirePermission checks if user holds every listed permission, e.g.
+// RequirePermission("orders:refund")
+func (m *AuthMiddleware) RequirePermission(permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		access, err := m.userAccess(c)
+		if err != nil {
+			return err
+		}
+
+		for _, permission := range permissions {
+			if !access.HasPermission(permission) {
+				return entity.ErrInsufficientRole
+			}
+		}
+		return c.Next()
+	}
+}
+
+// userAccess loads the roles and permissions of the authenticated user
+func (m *AuthMiddleware) userAccess(c *fiber.Ctx) (*entity.UserAccess, error) {
+	token, isToken := c.Locals("api_token").(*entity.APIToken)
+
+	userID, ok := c.Locals(utils.UserIDKey).(uint)
+	if isToken && token.BelongsToOrganization() {
+		// Service keys act with their creator's current permissions; a key
+		// whose creator was deleted keeps none
+		if token.CreatedBy == 0 {
+			return &entity.UserAccess{Roles: []string{}, Permissions: []string{}}, nil
+		}
+		userID, ok = token.CreatedBy, true
+	}
+	if !ok {
+		return nil, entity.ErrMissingToken
+	}
+
+	if m.rbacService == nil {
+		return nil, entity.ErrInsufficientRole
+	}
+
+	access, err := m.rbacService.GetUserAccess(c.Context(), userID)
+	if err != nil {
+		return nil, err
 	}
+
+	// API tokens only carry the permissions their scopes allow
+	if isToken {
+		access = token.Restrict(access)
+	}
+
+	c.Locals("user_roles", access.Roles)
+	return access, nil
 }
 
-// OptionalAuth validates token if present but doesn't require it
+// OptionalAuth validates token if present but doesn't require it. API
+// tokens declare no scope here, so they are treated as anonymous.
 func (m *AuthMiddleware) OptionalAuth() fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		if m.apiToken(c) != "" {
+			return c.Next()
+		}
+
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
 			return c.Next()
@@ -93,16 +203,106 @@ func (m *AuthMiddleware) OptionalAuth() fiber.Handler {
 
 		token := strings.TrimPrefix(authHeader, "Bearer ")
 
-		claims, err := m.authService.ValidateToken(c.Context(), token)
+		claims, session, err := m.authService.AuthenticateToken(c.Context(), token)
 		if err != nil {
 			return c.Next()
 		}
 
 		// Set user context if valid
-		c.Locals("user_id", claims.UserID)
-		c.Locals("user_email", claims.Email)
-		c.Locals("user_role", claims.Role)
+		setAuthLocals(c, claims, session)
+
+		return c.Next()
+	}
+}
+
+// RequireRecentAuth requires the user to have logged in or re-authenticated
+// within maxAge. It must run after Authenticate; clients that get
+// reauthentication_required call POST /auth/reauthenticate and retry.
+func (m *AuthMiddleware) RequireRecentAuth(maxAge time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if isImpersonating(c) {
+			return entity.ErrImpersonationDenied
+		}
+
+		authTime, ok := c.Locals("auth_time").(time.Time)
+		if !ok || authTime.IsZero() || time.Since(authTime) > maxAge {
+			return entity.ErrReauthRequired
+		}
+		return c.Next()
+	}
+}
 
+// DenyImpersonation keeps impersonation sessions away from a route. It must
+// run after Authenticate. Routes behind RequireRecentAuth are already closed
+// to impersonation; use this on other routes an admin must not use as the
+// user, such as payments or TFA verification.
+func (m *AuthMiddleware) DenyImpersonation() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if isImpersonating(c) {
+			return entity.ErrImpersonationDenied
+		}
 		return c.Next()
 	}
 }
+
+// isImpersonating reports whether an admin is acting as the request's user
+func isImpersonating(c *fiber.Ctx) bool {
+	actorID, _ := c.Locals(utils.ActorIDKey).(uint)
+	return actorID != 0
+}
+
+// apiToken returns the API token sent with the request, if any
+func (m *AuthMiddleware) apiToken(c *fiber.Ctx) string {
+	if raw := c.Get(apiKeyHeader); raw != "" {
+		return raw
+	}
+
+	raw := strings.TrimPrefix(c.Get("Authorization"), "Bearer ")
+	if entity.IsAPIToken(raw) {
+		return raw
+	}
+	return ""
+}
+
+// authenticateAPIToken resolves an API token and stores its owner in the
+// request context: the user of a personal token, or the organization of a
+// service key, which sets no user_id. No session or auth time is set, so
+// routes behind RequireRecentAuth stay closed to tokens.
+func (m *AuthMiddleware) authenticateAPIToken(c *fiber.Ctx, raw string) error {
+	if m.tokenService == nil {
+		return entity.ErrInvalidAPIToken
+	}
+
+	token, user, err := m.tokenService.Authenticate(c.Context(), raw, c.IP())
+	if err != nil {
+		return err
+	}
+
+	if token.BelongsToOrganization() {
+		c.Locals(utils.OrgIDKey, token.OrgID)
+	} else {
+		c.Locals(utils.UserIDKey, user.ID)
+		c.Locals("user_email", user.Email)
+		c.Locals("user_role", user.Role)
+	}
+	c.Locals("token_id", token.ID)
+	c.Locals("token_scopes", token.Scopes)
+	c.Locals("api_token", token)
+	return nil
+}
+
+// setAuthLocals stores the authenticated user and session in the request context
+func setAuthLocals(c *fiber.Ctx, claims *jwt.Claims, session *entity.AuthSession) {
+	c.Locals(utils.UserIDKey, claims.UserID)
+	c.Locals("user_email", claims.Email)
+	c.Locals("user_role", claims.Role)
+	c.Locals("session_id", session.ID)
+	c.Locals("auth_time", session.AuthTime)
+	c.Locals("amr", session.Methods())
+	if session.IsImpersonation() {
+		c.Locals(utils.ActorIDKey, *session.ActorID)
+	}
+	if orgID := session.ActiveOrgID(); orgID != 0 {
+		c.Locals(utils.OrgIDKey, orgID)
+	}
+}
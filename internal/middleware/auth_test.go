@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// The fakes embed their interface and implement only what the middleware
+// calls, so any other call panics
+
+type fakeTokenService struct {
+	service.APITokenService
+	tokens map[string]*entity.APIToken
+	users  map[uint]*entity.User
+}
+
+func (s *fakeTokenService) Authenticate(ctx context.Context, raw, ip string) (*entity.APIToken, *entity.User, error) {
+	token, ok := s.tokens[raw]
+	if !ok {
+		return nil, nil, entity.ErrInvalidAPIToken
+	}
+	if token.BelongsToOrganization() {
+		return token, nil, nil
+	}
+	return token, s.users[token.UserID], nil
+}
+
+type fakeRBACService struct {
+	service.RBACService
+	access map[uint]*entity.UserAccess
+}
+
+func (s *fakeRBACService) GetUserAccess(ctx context.Context, userID uint) (*entity.UserAccess, error) {
+	if access, ok := s.access[userID]; ok {
+		return access, nil
+	}
+	return &entity.UserAccess{Roles: []string{}, Permissions: []string{}}, nil
+}
+
+const (
+	adminID   uint = 1
+	supportID uint = 2
+)
+
+// newTestApp serves a route for each middleware under test and records the
+// error each request failed with
+func newTestApp(t *testing.T) (*fiber.App, *error) {
+	t.Helper()
+
+	tokens := &fakeTokenService{
+		tokens: map[string]*entity.APIToken{
+			"pat_admin_roles":   {ID: 1, UserID: adminID, Kind: entity.APITokenKindPersonal, Scopes: []string{"roles:manage"}},
+			"pat_admin_orders":  {ID: 2, UserID: adminID, Kind: entity.APITokenKindPersonal, Scopes: []string{"orders:read"}},
+			"pat_support_roles": {ID: 3, UserID: supportID, Kind: entity.APITokenKindPersonal, Scopes: []string{"roles:manage"}},
+			"sk_acme_roles":     {ID: 4, OrgID: 10, CreatedBy: adminID, Kind: entity.APITokenKindService, Scopes: []string{"roles:manage"}},
+			"sk_acme_orders":    {ID: 5, OrgID: 10, CreatedBy: adminID, Kind: entity.APITokenKindService, Scopes: []string{"orders:read"}},
+			"sk_acme_orphaned":  {ID: 6, OrgID: 10, Kind: entity.APITokenKindService, Scopes: []string{"roles:manage"}},
+		},
+		users: map[uint]*entity.User{
+			adminID:   {ID: adminID, Email: "admin@example.com", Role: "admin"},
+			supportID: {ID: supportID, Email: "support@example.com", Role: "user"},
+		},
+	}
+	rbac := &fakeRBACService{access: map[uint]*entity.UserAccess{
+		adminID:   {Roles: []string{"admin"}, Permissions: []string{entity.PermissionAll}},
+		supportID: {Roles: []string{"support"}, Permissions: []string{"users:read", "orders:read"}},
+	}}
+	m := NewAuthMiddleware(nil, rbac, tokens, nil)
+
+	var failure error
+	app := fiber.New(fiber.Config{ErrorHandler: func(c *fiber.Ctx, err error) error {
+		failure = err
+		return response.ErrorHandler(c, err)
+	}})
+	ok := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusNoContent) }
+
+	app.Get("/session-only", m.Authenticate(), ok)
+	app.Get("/roles", m.RequireScope("roles:manage"), m.RequirePermission("roles:manage"), ok)
+	app.Get("/admin-role", m.RequireScope("roles:manage"), m.RequireRoles("admin"), ok)
+	return app, &failure
+}
+
+func TestAPITokenScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		header  string
+		token   string
+		wantErr error
+	}{
+		{name: "personal token in scope", path: "/roles", header: "Authorization", token: "Bearer pat_admin_roles"},
+		{name: "personal token in X-API-Key", path: "/roles", header: apiKeyHeader, token: "pat_admin_roles"},
+		{name: "service key in scope", path: "/roles", header: apiKeyHeader, token: "sk_acme_roles"},
+		{name: "personal token out of scope", path: "/roles", header: apiKeyHeader, token: "pat_admin_orders", wantErr: entity.ErrInsufficientScope},
+		{name: "service key out of scope", path: "/roles", header: apiKeyHeader, token: "sk_acme_orders", wantErr: entity.ErrInsufficientScope},
+		{name: "scope the owner does not hold", path: "/roles", header: apiKeyHeader, token: "pat_support_roles", wantErr: entity.ErrInsufficientScope},
+		{name: "service key of a deleted creator", path: "/roles", header: apiKeyHeader, token: "sk_acme_orphaned", wantErr: entity.ErrInsufficientScope},
+		{name: "unknown token", path: "/roles", header: apiKeyHeader, token: "pat_unknown", wantErr: entity.ErrInvalidAPIToken},
+		{name: "route without scopes", path: "/session-only", header: apiKeyHeader, token: "pat_admin_roles", wantErr: entity.ErrAPITokenNotAllowed},
+		{name: "tokens carry no roles", path: "/admin-role", header: apiKeyHeader, token: "pat_admin_roles", wantErr: entity.ErrInsufficientRole},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, failure := newTestApp(t)
+
+			req := httptest.NewRequest(fiber.MethodGet, tt.path, nil)
+			req.Header.Set(tt.header, tt.token)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+
+			if tt.wantErr == nil {
+				if resp.StatusCode != fiber.StatusNoContent {
+					t.Errorf("status = %d (%v), want %d", resp.StatusCode, *failure, fiber.StatusNoContent)
+				}
+				return
+			}
+			if !errors.Is(*failure, tt.wantErr) {
+				t.Errorf("error = %v, want %v", *failure, tt.wantErr)
+			}
+			if resp.StatusCode < 400 {
+				t.Errorf("status = %d, want an error status", resp.StatusCode)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"boilerplate-go-fiber-v2/pkg/utils"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/helmet"
@@ -23,25 +25,21 @@ func SetupMiddleware(app *fiber.App) {
 		AllowMethods: "GET, POST, PUT, DELETE, OPTIONS",
 	}))
 
+	// Request context middleware
+	app.Use(RequestContext())
+
 	// Logger middleware
 	app.Use(logger.New(logger.Config{
 		Format: "[${time}] ${status} - ${latency} ${method} ${path}\n",
 	}))
 }
 
-// CustomErrorHandler handles application errors
-func CustomErrorHandler(c *fiber.Ctx, err error) error {
-	// Default error
-	code := fiber.StatusInternalServerError
-
-	// Check if it's a fiber error
-	if e, ok := err.(*fiber.Error); ok {
-		code = e.Code
+// RequestContext stores request metadata for the service layer
+func RequestContext() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals(utils.ClientIPKey, c.IP())
+		c.Locals(utils.UserAgentKey, c.Get(fiber.HeaderUserAgent))
+		c.Locals(utils.LanguageKey, c.Get(fiber.HeaderAcceptLanguage))
+		return c.Next()
 	}
-
-	// Return error response
-	return c.Status(code).JSON(fiber.Map{
-		"success": false,
-		"error":   err.Error(),
-	})
 }
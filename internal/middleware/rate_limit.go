@@ -3,6 +3,8 @@ package middleware
 import (
 	"time"
 
+	"boilerplate-go-fiber-v2/pkg/apperror"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"github.com/redis/go-redis/v9"
@@ -28,10 +30,7 @@ func (m *RateLimitMiddleware) GeneralRateLimit() fiber.Handler {
 			return c.IP() // Rate limit by IP
 		},
 		LimitReached: func(c *fiber.Ctx) error {
-			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
-				"success": false,
-				"error":   "Rate limit exceeded",
-			})
+			return apperror.RateLimited("rate_limit_exceeded", "Rate limit exceeded")
 		},
 	})
 }
@@ -45,10 +44,7 @@ func (m *RateLimitMiddleware) AuthRateLimit() fiber.Handler {
 			return "auth:" + c.IP() // Rate limit by IP for auth
 		},
 		LimitReached: func(c *fiber.Ctx) error {
-			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
-				"success": false,
-				"error":   "Too many authentication attempts",
-			})
+			return apperror.RateLimited("auth_rate_limit_exceeded", "Too many authentication attempts")
 		},
 	})
 }
@@ -69,10 +65,7 @@ func (m *RateLimitMiddleware) UserRateLimit() fiber.Handler {
 				return "user:" + userID.(string)
 			},
 			LimitReached: func(c *fiber.Ctx) error {
-				return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
-					"success": false,
-					"error":   "User rate limit exceeded",
-				})
+				return apperror.RateLimited("user_rate_limit_exceeded", "User rate limit exceeded")
 			},
 		})
 
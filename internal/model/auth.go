@@ -1,16 +1,24 @@
 package model
 
 import (
+	"strings"
 	"time"
 
 	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/pkg/utils"
 )
 
 type AuthSessionModel struct {
-	ID           uint      `gorm:"primaryKey;autoIncrement"`
-	UserID       uint      `gorm:"not null"`
-	Token        string    `gorm:"uniqueIndex;not null"`
-	RefreshToken string    `gorm:"uniqueIndex;not null"`
+	ID           uint   `gorm:"primaryKey;autoIncrement"`
+	UserID       uint   `gorm:"not null"`
+	Token        string `gorm:"uniqueIndex;not null"`
+	RefreshToken string `gorm:"uniqueIndex;not null"`
+	AuthTime     time.Time
+	AMR          string `gorm:"column:amr;default:''"`
+	ClientID     *uint
+	Scope        string `gorm:"default:''"`
+	ActorID      *uint
+	OrgID        *uint
 	ExpiresAt    time.Time `gorm:"not null"`
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
@@ -27,24 +35,32 @@ type PasswordResetModel struct {
 }
 
 type TFACodeModel struct {
-	ID        uint      `gorm:"primaryKey;autoIncrement"`
-	UserID    uint      `gorm:"not null"`
-	Code      string    `gorm:"not null"`
-	ExpiresAt time.Time `gorm:"not null"`
-	Used      bool      `gorm:"default:false"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID          uint   `gorm:"primaryKey;autoIncrement"`
+	UserID      uint   `gorm:"not null"`
+	Code        string `gorm:"not null"`
+	Purpose     string `gorm:"default:'login'"`
+	Channel     string `gorm:"default:'email'"`
+	Destination string
+	Attempts    int       `gorm:"default:0"`
+	ExpiresAt   time.Time `gorm:"not null"`
+	Used        bool      `gorm:"default:false"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
 type EmailVerificationModel struct {
-	ID         uint      `gorm:"primaryKey;autoIncrement"`
-	UserID     uint      `gorm:"not null"`
-	Email      string    `gorm:"not null"`
-	Token      string    `gorm:"uniqueIndex;not null"`
-	ExpiresAt  time.Time `gorm:"not null"`
-	VerifiedAt *time.Time
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	ID            uint   `gorm:"primaryKey;autoIncrement"`
+	UserID        uint   `gorm:"not null"`
+	Email         string `gorm:"not null"`
+	Purpose       string `gorm:"default:'verify'"`
+	PreviousEmail *string
+	Token         string    `gorm:"uniqueIndex;not null"`
+	CancelToken   *string   `gorm:"uniqueIndex"`
+	ExpiresAt     time.Time `gorm:"not null"`
+	VerifiedAt    *time.Time
+	CancelledAt   *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
 }
 
 func (AuthSessionModel) TableName() string {
@@ -70,6 +86,12 @@ func (m *AuthSessionModel) ToEntity() *entity.AuthSession {
 		UserID:       m.UserID,
 		Token:        m.Token,
 		RefreshToken: m.RefreshToken,
+		AuthTime:     m.AuthTime,
+		AMR:          m.AMR,
+		ClientID:     m.ClientID,
+		Scope:        m.Scope,
+		ActorID:      m.ActorID,
+		OrgID:        m.OrgID,
 		ExpiresAt:    m.ExpiresAt,
 		CreatedAt:    m.CreatedAt,
 		UpdatedAt:    m.UpdatedAt,
@@ -81,6 +103,12 @@ func (m *AuthSessionModel) FromEntity(session *entity.AuthSession) {
 	m.UserID = session.UserID
 	m.Token = session.Token
 	m.RefreshToken = session.RefreshToken
+	m.AuthTime = session.AuthTime
+	m.AMR = session.AMR
+	m.ClientID = session.ClientID
+	m.Scope = session.Scope
+	m.ActorID = session.ActorID
+	m.OrgID = session.OrgID
 	m.ExpiresAt = session.ExpiresAt
 	m.CreatedAt = session.CreatedAt
 	m.UpdatedAt = session.UpdatedAt
@@ -112,13 +140,17 @@ func (m *PasswordResetModel) FromEntity(reset *entity.PasswordReset) {
 // TFACode conversion methods
 func (m *TFACodeModel) ToEntity() *entity.TFACode {
 	return &entity.TFACode{
-		ID:        m.ID,
-		UserID:    m.UserID,
-		Code:      m.Code,
-		ExpiresAt: m.ExpiresAt,
-		Used:      m.Used,
-		CreatedAt: m.CreatedAt,
-		UpdatedAt: m.UpdatedAt,
+		ID:          m.ID,
+		UserID:      m.UserID,
+		Code:        m.Code,
+		Purpose:     m.Purpose,
+		Channel:     m.Channel,
+		Destination: m.Destination,
+		Attempts:    m.Attempts,
+		ExpiresAt:   m.ExpiresAt,
+		Used:        m.Used,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
 	}
 }
 
@@ -126,6 +158,10 @@ func (m *TFACodeModel) FromEntity(tfa *entity.TFACode) {
 	m.ID = tfa.ID
 	m.UserID = tfa.UserID
 	m.Code = tfa.Code
+	m.Purpose = tfa.Purpose
+	m.Channel = tfa.Channel
+	m.Destination = tfa.Destination
+	m.Attempts = tfa.Attempts
 	m.ExpiresAt = tfa.ExpiresAt
 	m.Used = tfa.Used
 	m.CreatedAt = tfa.CreatedAt
@@ -135,14 +171,18 @@ func (m *TFACodeModel) FromEntity(tfa *entity.TFACode) {
 // EmailVerification conversion methods
 func (m *EmailVerificationModel) ToEntity() *entity.EmailVerification {
 	return &entity.EmailVerification{
-		ID:         m.ID,
-		UserID:     m.UserID,
-		Email:      m.Email,
-		Token:      m.Token,
-		ExpiresAt:  m.ExpiresAt,
-		VerifiedAt: m.VerifiedAt,
-		CreatedAt:  m.CreatedAt,
-		UpdatedAt:  m.UpdatedAt,
+		ID:            m.ID,
+		UserID:        m.UserID,
+		Email:         m.Email,
+		Purpose:       m.Purpose,
+		PreviousEmail: utils.SafePtr(m.PreviousEmail, ""),
+		Token:         m.Token,
+		CancelToken:   utils.SafePtr(m.CancelToken, ""),
+		ExpiresAt:     m.ExpiresAt,
+		VerifiedAt:    m.VerifiedAt,
+		CancelledAt:   m.CancelledAt,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
 	}
 }
 
@@ -150,9 +190,148 @@ func (m *EmailVerificationModel) FromEntity(verification *entity.EmailVerificati
 	m.ID = verification.ID
 	m.UserID = verification.UserID
 	m.Email = verification.Email
+	m.Purpose = verification.Purpose
+	m.PreviousEmail = utils.NilIfZero(verification.PreviousEmail)
 	m.Token = verification.Token
+	m.CancelToken = utils.NilIfZero(verification.CancelToken)
 	m.ExpiresAt = verification.ExpiresAt
 	m.VerifiedAt = verification.VerifiedAt
+	m.CancelledAt = verification.CancelledAt
 	m.CreatedAt = verification.CreatedAt
 	m.UpdatedAt = verification.UpdatedAt
 }
+
+type PasswordHistoryModel struct {
+	ID           uint   `gorm:"primaryKey;autoIncrement"`
+	UserID       uint   `gorm:"not null"`
+	PasswordHash string `gorm:"not null"`
+	CreatedAt    time.Time
+}
+
+func (PasswordHistoryModel) TableName() string {
+	return "password_history"
+}
+
+// PasswordHistory conversion methods
+func (m *PasswordHistoryModel) ToEntity() *entity.PasswordHistory {
+	return &entity.PasswordHistory{
+		ID:           m.ID,
+		UserID:       m.UserID,
+		PasswordHash: m.PasswordHash,
+		CreatedAt:    m.CreatedAt,
+	}
+}
+
+func (m *PasswordHistoryModel) FromEntity(entry *entity.PasswordHistory) {
+	m.ID = entry.ID
+	m.UserID = entry.UserID
+	m.PasswordHash = entry.PasswordHash
+	m.CreatedAt = entry.CreatedAt
+}
+
+type APITokenModel struct {
+	ID         uint `gorm:"primaryKey;autoIncrement"`
+	UserID     *uint
+	OrgID      *uint
+	CreatedBy  *uint
+	Kind       string `gorm:"default:'personal'"`
+	Name       string `gorm:"not null"`
+	Prefix     string `gorm:"uniqueIndex;not null"`
+	SecretHash string `gorm:"not null"`
+	Scopes     string `gorm:"default:''"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	LastUsedIP *string
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (APITokenModel) TableName() string {
+	return "api_tokens"
+}
+
+// APIToken conversion methods
+func (m *APITokenModel) ToEntity() *entity.APIToken {
+	return &entity.APIToken{
+		ID:         m.ID,
+		UserID:     utils.SafePtr(m.UserID, 0),
+		OrgID:      utils.SafePtr(m.OrgID, 0),
+		CreatedBy:  utils.SafePtr(m.CreatedBy, 0),
+		Kind:       m.Kind,
+		Name:       m.Name,
+		Prefix:     m.Prefix,
+		SecretHash: m.SecretHash,
+		Scopes:     strings.Fields(m.Scopes),
+		ExpiresAt:  m.ExpiresAt,
+		LastUsedAt: m.LastUsedAt,
+		LastUsedIP: utils.SafePtr(m.LastUsedIP, ""),
+		RevokedAt:  m.RevokedAt,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+func (m *APITokenModel) FromEntity(token *entity.APIToken) {
+	m.ID = token.ID
+	m.UserID = utils.NilIfZero(token.UserID)
+	m.OrgID = utils.NilIfZero(token.OrgID)
+	m.CreatedBy = utils.NilIfZero(token.CreatedBy)
+	m.Kind = token.Kind
+	m.Name = token.Name
+	m.Prefix = token.Prefix
+	m.SecretHash = token.SecretHash
+	m.Scopes = strings.Join(token.Scopes, " ")
+	m.ExpiresAt = token.ExpiresAt
+	m.LastUsedAt = token.LastUsedAt
+	m.LastUsedIP = utils.NilIfZero(token.LastUsedIP)
+	m.RevokedAt = token.RevokedAt
+	m.CreatedAt = token.CreatedAt
+	m.UpdatedAt = token.UpdatedAt
+}
+
+type OAuthClientModel struct {
+	ID         uint   `gorm:"primaryKey;autoIncrement"`
+	ClientID   string `gorm:"uniqueIndex;not null"`
+	Name       string `gorm:"not null"`
+	SecretHash string `gorm:"not null"`
+	Scopes     string `gorm:"default:''"`
+	CreatedBy  *uint
+	DisabledAt *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (OAuthClientModel) TableName() string {
+	return "oauth_clients"
+}
+
+// OAuthClient conversion methods
+func (m *OAuthClientModel) ToEntity() *entity.OAuthClient {
+	return &entity.OAuthClient{
+		ID:         m.ID,
+		ClientID:   m.ClientID,
+		Name:       m.Name,
+		SecretHash: m.SecretHash,
+		Scopes:     strings.Fields(m.Scopes),
+		CreatedBy:  utils.SafePtr(m.CreatedBy, 0),
+		DisabledAt: m.DisabledAt,
+		LastUsedAt: m.LastUsedAt,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+func (m *OAuthClientModel) FromEntity(client *entity.OAuthClient) {
+	m.ID = client.ID
+	m.ClientID = client.ClientID
+	m.Name = client.Name
+	m.SecretHash = client.SecretHash
+	m.Scopes = strings.Join(client.Scopes, " ")
+	m.CreatedBy = utils.NilIfZero(client.CreatedBy)
+	m.DisabledAt = client.DisabledAt
+	m.LastUsedAt = client.LastUsedAt
+	m.CreatedAt = client.CreatedAt
+	m.UpdatedAt = client.UpdatedAt
+}
@@ -0,0 +1,94 @@
+package model
+
+import (
+	"time"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/pkg/utils"
+)
+
+type UserIdentityModel struct {
+	ID            uint   `gorm:"primaryKey;autoIncrement"`
+	UserID        uint   `gorm:"not null"`
+	Provider      string `gorm:"not null"`
+	Subject       string `gorm:"not null"`
+	Email         *string
+	EmailVerified bool `gorm:"default:false"`
+	LastLoginAt   *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type OIDCAuthRequestModel struct {
+	ID           uint   `gorm:"primaryKey;autoIncrement"`
+	StateHash    string `gorm:"uniqueIndex;not null"`
+	Provider     string `gorm:"not null"`
+	Purpose      string `gorm:"not null"`
+	UserID       *uint
+	Nonce        string    `gorm:"not null"`
+	CodeVerifier string    `gorm:"not null"`
+	ExpiresAt    time.Time `gorm:"not null"`
+	CreatedAt    time.Time
+}
+
+func (UserIdentityModel) TableName() string {
+	return "user_identities"
+}
+
+func (OIDCAuthRequestModel) TableName() string {
+	return "oidc_auth_requests"
+}
+
+// UserIdentity conversion methods
+func (m *UserIdentityModel) ToEntity() *entity.UserIdentity {
+	return &entity.UserIdentity{
+		ID:            m.ID,
+		UserID:        m.UserID,
+		Provider:      m.Provider,
+		Subject:       m.Subject,
+		Email:         utils.SafePtr(m.Email, ""),
+		EmailVerified: m.EmailVerified,
+		LastLoginAt:   m.LastLoginAt,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+	}
+}
+
+func (m *UserIdentityModel) FromEntity(identity *entity.UserIdentity) {
+	m.ID = identity.ID
+	m.UserID = identity.UserID
+	m.Provider = identity.Provider
+	m.Subject = identity.Subject
+	m.Email = utils.NilIfZero(identity.Email)
+	m.EmailVerified = identity.EmailVerified
+	m.LastLoginAt = identity.LastLoginAt
+	m.CreatedAt = identity.CreatedAt
+	m.UpdatedAt = identity.UpdatedAt
+}
+
+// OIDCAuthRequest conversion methods
+func (m *OIDCAuthRequestModel) ToEntity() *entity.OIDCAuthRequest {
+	return &entity.OIDCAuthRequest{
+		ID:           m.ID,
+		StateHash:    m.StateHash,
+		Provider:     m.Provider,
+		Purpose:      m.Purpose,
+		UserID:       utils.SafePtr(m.UserID, 0),
+		Nonce:        m.Nonce,
+		CodeVerifier: m.CodeVerifier,
+		ExpiresAt:    m.ExpiresAt,
+		CreatedAt:    m.CreatedAt,
+	}
+}
+
+func (m *OIDCAuthRequestModel) FromEntity(request *entity.OIDCAuthRequest) {
+	m.ID = request.ID
+	m.StateHash = request.StateHash
+	m.Provider = request.Provider
+	m.Purpose = request.Purpose
+	m.UserID = utils.NilIfZero(request.UserID)
+	m.Nonce = request.Nonce
+	m.CodeVerifier = request.CodeVerifier
+	m.ExpiresAt = request.ExpiresAt
+	m.CreatedAt = request.CreatedAt
+}
@@ -0,0 +1,124 @@
+package model
+
+import (
+	"time"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+)
+
+type OrganizationModel struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	Name      string `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type MembershipModel struct {
+	ID           uint               `gorm:"primaryKey;autoIncrement"`
+	OrgID        uint               `gorm:"not null"`
+	UserID       uint               `gorm:"not null"`
+	Role         string             `gorm:"not null"`
+	Organization *OrganizationModel `gorm:"foreignKey:OrgID"`
+	User         *UserModel         `gorm:"foreignKey:UserID"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type InvitationModel struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement"`
+	OrgID      uint      `gorm:"not null"`
+	Email      string    `gorm:"not null"`
+	Role       string    `gorm:"not null"`
+	Token      string    `gorm:"uniqueIndex;not null"`
+	InvitedBy  uint      `gorm:"not null"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	AcceptedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (OrganizationModel) TableName() string {
+	return "organizations"
+}
+
+func (MembershipModel) TableName() string {
+	return "organization_members"
+}
+
+func (InvitationModel) TableName() string {
+	return "organization_invitations"
+}
+
+// Organization conversion methods
+func (m *OrganizationModel) ToEntity() *entity.Organization {
+	return &entity.Organization{
+		ID:        m.ID,
+		Name:      m.Name,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+func (m *OrganizationModel) FromEntity(org *entity.Organization) {
+	m.ID = org.ID
+	m.Name = org.Name
+	m.CreatedAt = org.CreatedAt
+	m.UpdatedAt = org.UpdatedAt
+}
+
+// Membership conversion methods
+func (m *MembershipModel) ToEntity() *entity.Membership {
+	membership := &entity.Membership{
+		ID:        m.ID,
+		OrgID:     m.OrgID,
+		UserID:    m.UserID,
+		Role:      m.Role,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+	if m.Organization != nil {
+		membership.Organization = m.Organization.ToEntity()
+	}
+	if m.User != nil {
+		membership.User = m.User.ToEntity()
+	}
+	return membership
+}
+
+func (m *MembershipModel) FromEntity(membership *entity.Membership) {
+	m.ID = membership.ID
+	m.OrgID = membership.OrgID
+	m.UserID = membership.UserID
+	m.Role = membership.Role
+	m.CreatedAt = membership.CreatedAt
+	m.UpdatedAt = membership.UpdatedAt
+}
+
+// Invitation conversion methods
+func (m *InvitationModel) ToEntity() *entity.Invitation {
+	return &entity.Invitation{
+		ID:         m.ID,
+		OrgID:      m.OrgID,
+		Email:      m.Email,
+		Role:       m.Role,
+		Token:      m.Token,
+		InvitedBy:  m.InvitedBy,
+		ExpiresAt:  m.ExpiresAt,
+		AcceptedAt: m.AcceptedAt,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+func (m *InvitationModel) FromEntity(invitation *entity.Invitation) {
+	m.ID = invitation.ID
+	m.OrgID = invitation.OrgID
+	m.Email = invitation.Email
+	m.Role = invitation.Role
+	m.Token = invitation.Token
+	m.InvitedBy = invitation.InvitedBy
+	m.ExpiresAt = invitation.ExpiresAt
+	m.AcceptedAt = invitation.AcceptedAt
+	m.CreatedAt = invitation.CreatedAt
+	m.UpdatedAt = invitation.UpdatedAt
+}
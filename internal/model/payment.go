@@ -7,9 +7,10 @@ import (
 
 // PaymentModel represents the database model for Payment entity
 type PaymentModel struct {
-	ID            uint    `gorm:"primaryKey"`
-	OrderID       uint    `gorm:"not null"`
-	UserID        uint    `gorm:"not null"`
+	ID            uint `gorm:"primaryKey"`
+	OrderID       uint `gorm:"not null"`
+	UserID        uint `gorm:"not null"`
+	OrgID         *uint
 	Amount        float64 `gorm:"not null"`
 	Currency      string  `gorm:"default:IDR"`
 	PaymentMethod string  `gorm:"not null"`
@@ -33,6 +34,7 @@ func (m *PaymentModel) ToEntity() *entity.Payment {
 		ID:            m.ID,
 		OrderID:       m.OrderID,
 		UserID:        m.UserID,
+		OrgID:         m.OrgID,
 		Amount:        m.Amount,
 		Currency:      m.Currency,
 		PaymentMethod: m.PaymentMethod,
@@ -52,6 +54,7 @@ func (m *PaymentModel) FromEntity(payment *entity.Payment) {
 	m.ID = payment.ID
 	m.OrderID = payment.OrderID
 	m.UserID = payment.UserID
+	m.OrgID = payment.OrgID
 	m.Amount = payment.Amount
 	m.Currency = payment.Currency
 	m.PaymentMethod = payment.PaymentMethod
@@ -67,8 +70,9 @@ func (m *PaymentModel) FromEntity(payment *entity.Payment) {
 
 // OrderModel represents the database model for Order entity
 type OrderModel struct {
-	ID          uint    `gorm:"primaryKey"`
-	UserID      uint    `gorm:"not null"`
+	ID          uint `gorm:"primaryKey"`
+	UserID      uint `gorm:"not null"`
+	OrgID       *uint
 	OrderNumber string  `gorm:"uniqueIndex;not null"`
 	TotalAmount float64 `gorm:"not null"`
 	Status      string  `gorm:"default:pending"`
@@ -86,6 +90,7 @@ func (m *OrderModel) ToEntity() *entity.Order {
 	return &entity.Order{
 		ID:          m.ID,
 		UserID:      m.UserID,
+		OrgID:       m.OrgID,
 		OrderNumber: m.OrderNumber,
 		TotalAmount: m.TotalAmount,
 		Status:      m.Status,
@@ -99,6 +104,7 @@ func (m *OrderModel) ToEntity() *entity.Order {
 func (m *OrderModel) FromEntity(order *entity.Order) {
 	m.ID = order.ID
 	m.UserID = order.UserID
+	m.OrgID = order.OrgID
 	m.OrderNumber = order.OrderNumber
 	m.TotalAmount = order.TotalAmount
 	m.Status = order.Status
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"time"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+)
+
+type DataExportModel struct {
+	ID          uint   `gorm:"primaryKey;autoIncrement"`
+	UserID      uint   `gorm:"not null"`
+	Status      string `gorm:"default:'pending'"`
+	FilePath    string
+	Error       string
+	ExpiresAt   *time.Time
+	CompletedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type SecurityEventModel struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	UserID    uint   `gorm:"not null"`
+	Event     string `gorm:"not null"`
+	IPAddress string
+	UserAgent string
+	Metadata  entity.JSONB `gorm:"type:jsonb"`
+	CreatedAt time.Time
+}
+
+func (DataExportModel) TableName() string {
+	return "data_exports"
+}
+
+func (SecurityEventModel) TableName() string {
+	return "security_events"
+}
+
+// DataExport conversion methods
+func (m *DataExportModel) ToEntity() *entity.DataExport {
+	return &entity.DataExport{
+		ID:          m.ID,
+		UserID:      m.UserID,
+		Status:      m.Status,
+		FilePath:    m.FilePath,
+		Error:       m.Error,
+		ExpiresAt:   m.ExpiresAt,
+		CompletedAt: m.CompletedAt,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+func (m *DataExportModel) FromEntity(export *entity.DataExport) {
+	m.ID = export.ID
+	m.UserID = export.UserID
+	m.Status = export.Status
+	m.FilePath = export.FilePath
+	m.Error = export.Error
+	m.ExpiresAt = export.ExpiresAt
+	m.CompletedAt = export.CompletedAt
+	m.CreatedAt = export.CreatedAt
+	m.UpdatedAt = export.UpdatedAt
+}
+
+// SecurityEvent conversion methods
+func (m *SecurityEventModel) ToEntity() *entity.SecurityEvent {
+	return &entity.SecurityEvent{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		Event:     m.Event,
+		IPAddress: m.IPAddress,
+		UserAgent: m.UserAgent,
+		Metadata:  m.Metadata,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+func (m *SecurityEventModel) FromEntity(event *entity.SecurityEvent) {
+	m.ID = event.ID
+	m.UserID = event.UserID
+	m.Event = event.Event
+	m.IPAddress = event.IPAddress
+	m.UserAgent = event.UserAgent
+	m.Metadata = event.Metadata
+	m.CreatedAt = event.CreatedAt
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"time"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/pkg/utils"
+)
+
+type RoleModel struct {
+	ID          uint   `gorm:"primaryKey;autoIncrement"`
+	Name        string `gorm:"uniqueIndex;not null"`
+	Description *string
+	IsSystem    bool              `gorm:"default:false"`
+	Permissions []PermissionModel `gorm:"many2many:role_permissions;joinForeignKey:RoleID;joinReferences:PermissionID"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type PermissionModel struct {
+	ID          uint   `gorm:"primaryKey;autoIncrement"`
+	Name        string `gorm:"uniqueIndex;not null"`
+	Description *string
+	CreatedAt   time.Time
+}
+
+type UserRoleModel struct {
+	UserID    uint `gorm:"primaryKey"`
+	RoleID    uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+}
+
+func (RoleModel) TableName() string {
+	return "roles"
+}
+
+func (PermissionModel) TableName() string {
+	return "permissions"
+}
+
+func (UserRoleModel) TableName() string {
+	return "user_roles"
+}
+
+// Role conversion methods
+func (m *RoleModel) ToEntity() *entity.Role {
+	permissions := make([]string, len(m.Permissions))
+	for i, p := range m.Permissions {
+		permissions[i] = p.Name
+	}
+
+	return &entity.Role{
+		ID:          m.ID,
+		Name:        m.Name,
+		Description: utils.SafePtr(m.Description, ""),
+		IsSystem:    m.IsSystem,
+		Permissions: permissions,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+func (m *RoleModel) FromEntity(role *entity.Role) {
+	m.ID = role.ID
+	m.Name = role.Name
+	m.Description = utils.NilIfZero(role.Description)
+	m.IsSystem = role.IsSystem
+	m.CreatedAt = role.CreatedAt
+	m.UpdatedAt = role.UpdatedAt
+}
+
+// Permission conversion methods
+func (m *PermissionModel) ToEntity() *entity.Permission {
+	return &entity.Permission{
+		ID:          m.ID,
+		Name:        m.Name,
+		Description: utils.SafePtr(m.Description, ""),
+		CreatedAt:   m.CreatedAt,
+	}
+}
@@ -15,17 +15,22 @@ type UserModel struct {
 	LastName                  string `gorm:"not null"`
 	Phone                     string `gorm:"uniqueIndex"`
 	Avatar                    string
-	Role                      string `gorm:"default:'user'"`
-	Status                    string `gorm:"default:'active'"`
+	AvatarVariants            entity.JSONB `gorm:"type:jsonb"`
+	Role                      string       `gorm:"default:'user'"`
+	Status                    string       `gorm:"default:'active'"`
 	EmailVerifiedAt           *time.Time
 	PhoneVerifiedAt           *time.Time
 	LastLoginAt               *time.Time
+	PasswordChangedAt         *time.Time
 	TFAEnabled                bool `gorm:"default:false"`
 	TFASecret                 *string
 	TFABackupCodes            []string `gorm:"type:text[]"`
 	EmailVerificationToken    *string
 	EmailVerificationSentAt   *time.Time
 	EmailVerificationAttempts int `gorm:"default:0"`
+	DeletionRequestedAt       *time.Time
+	DeletionScheduledAt       *time.Time
+	AnonymizedAt              *time.Time
 	CreatedAt                 time.Time
 	UpdatedAt                 time.Time
 }
@@ -44,17 +49,22 @@ func (m *UserModel) ToEntity() *entity.User {
 		LastName:                  m.LastName,
 		Phone:                     m.Phone,
 		Avatar:                    m.Avatar,
+		AvatarVariants:            avatarVariantsToEntity(m.AvatarVariants),
 		Role:                      m.Role,
 		Status:                    m.Status,
 		EmailVerifiedAt:           m.EmailVerifiedAt,
 		PhoneVerifiedAt:           m.PhoneVerifiedAt,
 		LastLoginAt:               m.LastLoginAt,
+		PasswordChangedAt:         m.PasswordChangedAt,
 		TFAEnabled:                m.TFAEnabled,
 		TFASecret:                 m.TFASecret,
 		TFABackupCodes:            m.TFABackupCodes,
 		EmailVerificationToken:    m.EmailVerificationToken,
 		EmailVerificationSentAt:   m.EmailVerificationSentAt,
 		EmailVerificationAttempts: m.EmailVerificationAttempts,
+		DeletionRequestedAt:       m.DeletionRequestedAt,
+		DeletionScheduledAt:       m.DeletionScheduledAt,
+		AnonymizedAt:              m.AnonymizedAt,
 		CreatedAt:                 m.CreatedAt,
 		UpdatedAt:                 m.UpdatedAt,
 	}
@@ -69,17 +79,48 @@ func (m *UserModel) FromEntity(user *entity.User) {
 	m.LastName = user.LastName
 	m.Phone = user.Phone
 	m.Avatar = user.Avatar
+	m.AvatarVariants = avatarVariantsFromEntity(user.AvatarVariants)
 	m.Role = user.Role
 	m.Status = user.Status
 	m.EmailVerifiedAt = user.EmailVerifiedAt
 	m.PhoneVerifiedAt = user.PhoneVerifiedAt
 	m.LastLoginAt = user.LastLoginAt
+	m.PasswordChangedAt = user.PasswordChangedAt
 	m.TFAEnabled = user.TFAEnabled
 	m.TFASecret = user.TFASecret
 	m.TFABackupCodes = user.TFABackupCodes
 	m.EmailVerificationToken = user.EmailVerificationToken
 	m.EmailVerificationSentAt = user.EmailVerificationSentAt
 	m.EmailVerificationAttempts = user.EmailVerificationAttempts
+	m.DeletionRequestedAt = user.DeletionRequestedAt
+	m.DeletionScheduledAt = user.DeletionScheduledAt
+	m.AnonymizedAt = user.AnonymizedAt
 	m.CreatedAt = user.CreatedAt
 	m.UpdatedAt = user.UpdatedAt
 }
+
+func avatarVariantsToEntity(variants entity.JSONB) map[string]string {
+	if variants == nil {
+		return nil
+	}
+
+	keys := make(map[string]string, len(variants))
+	for size, key := range variants {
+		if key, ok := key.(string); ok {
+			keys[size] = key
+		}
+	}
+	return keys
+}
+
+func avatarVariantsFromEntity(variants map[string]string) entity.JSONB {
+	if variants == nil {
+		return nil
+	}
+
+	keys := make(entity.JSONB, len(variants))
+	for size, key := range variants {
+		keys[size] = key
+	}
+	return keys
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/pkg/utils"
+)
+
+type WebAuthnCredentialModel struct {
+	ID                uint   `gorm:"primaryKey;autoIncrement"`
+	UserID            uint   `gorm:"not null"`
+	Name              string `gorm:"not null"`
+	CredentialID      []byte `gorm:"uniqueIndex;not null"`
+	PublicKey         []byte `gorm:"not null"`
+	Algorithm         int64  `gorm:"not null"`
+	SignCount         int64  `gorm:"not null;default:0"`
+	AAGUID            []byte `gorm:"column:aaguid"`
+	UserHandle        []byte `gorm:"not null"`
+	AttestationFormat string `gorm:"not null"`
+	Transports        *string
+	BackupEligible    bool `gorm:"default:false"`
+	BackedUp          bool `gorm:"default:false"`
+	LastUsedAt        *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+type WebAuthnChallengeModel struct {
+	ID            uint   `gorm:"primaryKey;autoIncrement"`
+	ChallengeHash string `gorm:"uniqueIndex;not null"`
+	Purpose       string `gorm:"not null"`
+	UserID        *uint
+	UserHandle    []byte
+	ExpiresAt     time.Time `gorm:"not null"`
+	CreatedAt     time.Time
+}
+
+func (WebAuthnCredentialModel) TableName() string {
+	return "webauthn_credentials"
+}
+
+func (WebAuthnChallengeModel) TableName() string {
+	return "webauthn_challenges"
+}
+
+// WebAuthnCredential conversion methods
+func (m *WebAuthnCredentialModel) ToEntity() *entity.WebAuthnCredential {
+	return &entity.WebAuthnCredential{
+		ID:                m.ID,
+		UserID:            m.UserID,
+		Name:              m.Name,
+		CredentialID:      m.CredentialID,
+		PublicKey:         m.PublicKey,
+		Algorithm:         m.Algorithm,
+		SignCount:         uint32(m.SignCount),
+		AAGUID:            m.AAGUID,
+		UserHandle:        m.UserHandle,
+		AttestationFormat: m.AttestationFormat,
+		Transports:        strings.Fields(utils.SafePtr(m.Transports, "")),
+		BackupEligible:    m.BackupEligible,
+		BackedUp:          m.BackedUp,
+		LastUsedAt:        m.LastUsedAt,
+		CreatedAt:         m.CreatedAt,
+		UpdatedAt:         m.UpdatedAt,
+	}
+}
+
+func (m *WebAuthnCredentialModel) FromEntity(credential *entity.WebAuthnCredential) {
+	m.ID = credential.ID
+	m.UserID = credential.UserID
+	m.Name = credential.Name
+	m.CredentialID = credential.CredentialID
+	m.PublicKey = credential.PublicKey
+	m.Algorithm = credential.Algorithm
+	m.SignCount = int64(credential.SignCount)
+	m.AAGUID = credential.AAGUID
+	m.UserHandle = credential.UserHandle
+	m.AttestationFormat = credential.AttestationFormat
+	m.Transports = utils.NilIfZero(strings.Join(credential.Transports, " "))
+	m.BackupEligible = credential.BackupEligible
+	m.BackedUp = credential.BackedUp
+	m.LastUsedAt = credential.LastUsedAt
+	m.CreatedAt = credential.CreatedAt
+	m.UpdatedAt = credential.UpdatedAt
+}
+
+// WebAuthnChallenge conversion methods
+func (m *WebAuthnChallengeModel) ToEntity() *entity.WebAuthnChallenge {
+	return &entity.WebAuthnChallenge{
+		ID:            m.ID,
+		ChallengeHash: m.ChallengeHash,
+		Purpose:       m.Purpose,
+		UserID:        utils.SafePtr(m.UserID, 0),
+		UserHandle:    m.UserHandle,
+		ExpiresAt:     m.ExpiresAt,
+		CreatedAt:     m.CreatedAt,
+	}
+}
+
+func (m *WebAuthnChallengeModel) FromEntity(challenge *entity.WebAuthnChallenge) {
+	m.ID = challenge.ID
+	m.ChallengeHash = challenge.ChallengeHash
+	m.Purpose = challenge.Purpose
+	m.UserID = utils.NilIfZero(challenge.UserID)
+	m.UserHandle = challenge.UserHandle
+	m.ExpiresAt = challenge.ExpiresAt
+	m.CreatedAt = challenge.CreatedAt
+}
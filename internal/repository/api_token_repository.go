@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type apiTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewAPITokenRepository creates a new API token repository
+func NewAPITokenRepository(db *gorm.DB) repository.APITokenRepository {
+	return &apiTokenRepository{db: db}
+}
+
+// Create creates a new API token
+func (r *apiTokenRepository) Create(ctx context.Context, token *entity.APIToken) error {
+	tokenModel := &model.APITokenModel{}
+	tokenModel.FromEntity(token)
+
+	if err := r.db.WithContext(ctx).Create(tokenModel).Error; err != nil {
+		return err
+	}
+
+	token.ID = tokenModel.ID
+	token.CreatedAt = tokenModel.CreatedAt
+	token.UpdatedAt = tokenModel.UpdatedAt
+	return nil
+}
+
+// GetByPrefix gets an API token by its lookup prefix
+func (r *apiTokenRepository) GetByPrefix(ctx context.Context, prefix string) (*entity.APIToken, error) {
+	var tokenModel model.APITokenModel
+	err := r.db.WithContext(ctx).Where("prefix = ?", prefix).First(&tokenModel).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrAPITokenNotFound
+		}
+		return nil, err
+	}
+	return tokenModel.ToEntity(), nil
+}
+
+// ListByUserID lists the API tokens of a user, newest first
+func (r *apiTokenRepository) ListByUserID(ctx context.Context, userID uint) ([]*entity.APIToken, error) {
+	return r.list(ctx, "user_id = ?", userID)
+}
+
+// Revoke revokes an active API token owned by the user
+func (r *apiTokenRepository) Revoke(ctx context.Context, id, userID uint) error {
+	return r.revoke(ctx, "id = ? AND user_id = ?", id, userID)
+}
+
+// ListByOrgID lists the service keys of an organization, newest first
+func (r *apiTokenRepository) ListByOrgID(ctx context.Context, orgID uint) ([]*entity.APIToken, error) {
+	return r.list(ctx, "org_id = ?", orgID)
+}
+
+// RevokeForOrganization revokes an active service key of an organization
+func (r *apiTokenRepository) RevokeForOrganization(ctx context.Context, id, orgID uint) error {
+	return r.revoke(ctx, "id = ? AND org_id = ?", id, orgID)
+}
+
+func (r *apiTokenRepository) list(ctx context.Context, query string, args ...interface{}) ([]*entity.APIToken, error) {
+	var tokenModels []model.APITokenModel
+	err := r.db.WithContext(ctx).
+		Where(query, args...).
+		Order("created_at DESC").
+		Find(&tokenModels).Error
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]*entity.APIToken, len(tokenModels))
+	for i := range tokenModels {
+		tokens[i] = tokenModels[i].ToEntity()
+	}
+	return tokens, nil
+}
+
+func (r *apiTokenRepository) revoke(ctx context.Context, query string, args ...interface{}) error {
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&model.APITokenModel{}).
+		Where(query, args...).
+		Where("revoked_at IS NULL").
+		Updates(map[string]interface{}{
+			"revoked_at": now,
+			"updated_at": now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrAPITokenNotFound
+	}
+	return nil
+}
+
+// TouchLastUsed records token usage unless it was already recorded after
+// before, which keeps hot tokens from writing on every request
+func (r *apiTokenRepository) TouchLastUsed(ctx context.Context, id uint, ip string, before time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&model.APITokenModel{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, before).
+		UpdateColumns(map[string]interface{}{
+			"last_used_at": time.Now(),
+			"last_used_ip": ip,
+		}).Error
+}
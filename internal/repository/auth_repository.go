@@ -24,7 +24,30 @@ func NewAuthRepository(db *gorm.DB) repository.AuthRepository {
 
 // CreateSession creates a new auth session
 func (r *authRepository) CreateSession(ctx context.Context, session *entity.AuthSession) error {
-	return r.db.WithContext(ctx).Create(session).Error
+	db := r.db.WithContext(ctx)
+
+	// Client tokens have neither a user nor a refresh token, and
+	// impersonation tokens cannot be refreshed
+	switch {
+	case session.IsClientSession():
+		db = db.Omit("UserID", "RefreshToken")
+	case session.IsImpersonation():
+		db = db.Omit("RefreshToken")
+	}
+	return db.Create(session).Error
+}
+
+// GetSessionByID gets a session by ID
+func (r *authRepository) GetSessionByID(ctx context.Context, id uint) (*entity.AuthSession, error) {
+	var session entity.AuthSession
+	err := r.db.WithContext(ctx).First(&session, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return &session, nil
 }
 
 // GetSessionByToken gets a session by token
@@ -33,7 +56,7 @@ func (r *authRepository) GetSessionByToken(ctx context.Context, token string) (*
 	err := r.db.WithContext(ctx).Where("token = ?", token).First(&session).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("session not found")
+			return nil, entity.ErrSessionNotFound
 		}
 		return nil, err
 	}
@@ -46,13 +69,27 @@ func (r *authRepository) GetSessionByRefreshToken(ctx context.Context, refreshTo
 	err := r.db.WithContext(ctx).Where("refresh_token = ?", refreshToken).First(&session).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("session not found")
+			return nil, entity.ErrSessionNotFound
 		}
 		return nil, err
 	}
 	return &session, nil
 }
 
+// GetSessionsByUserID gets all sessions for a user
+func (r *authRepository) GetSessionsByUserID(ctx context.Context, userID uint) ([]*entity.AuthSession, error) {
+	var sessions []*entity.AuthSession
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+// GetSessionsByActorID gets the impersonation sessions an admin holds
+func (r *authRepository) GetSessionsByActorID(ctx context.Context, actorID uint) ([]*entity.AuthSession, error) {
+	var sessions []*entity.AuthSession
+	err := r.db.WithContext(ctx).Where("actor_id = ?", actorID).Order("created_at DESC").Find(&sessions).Error
+	return sessions, err
+}
+
 // DeleteSession deletes a session by token
 func (r *authRepository) DeleteSession(ctx context.Context, token string) error {
 	return r.db.WithContext(ctx).Where("token = ?", token).Delete(&entity.AuthSession{}).Error
@@ -63,6 +100,19 @@ func (r *authRepository) DeleteSessionsByUserID(ctx context.Context, userID uint
 	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entity.AuthSession{}).Error
 }
 
+// DeleteSessionsByClientID deletes all tokens issued to an OAuth client
+func (r *authRepository) DeleteSessionsByClientID(ctx context.Context, clientID uint) error {
+	return r.db.WithContext(ctx).Where("client_id = ?", clientID).Delete(&entity.AuthSession{}).Error
+}
+
+// ClearSessionOrganization switches a user's sessions in an organization back
+// to the personal account
+func (r *authRepository) ClearSessionOrganization(ctx context.Context, userID, orgID uint) error {
+	return r.db.WithContext(ctx).Model(&entity.AuthSession{}).
+		Where("user_id = ? AND org_id = ?", userID, orgID).
+		Update("org_id", nil).Error
+}
+
 // CleanExpiredSessions removes expired sessions
 func (r *authRepository) CleanExpiredSessions(ctx context.Context) error {
 	return r.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&entity.AuthSession{}).Error
@@ -75,22 +125,35 @@ func (r *authRepository) CreatePasswordReset(ctx context.Context, reset *entity.
 	return r.db.WithContext(ctx).Create(reset).Error
 }
 
-// GetPasswordResetByToken gets a password reset by token
-func (r *authRepository) GetPasswordResetByToken(ctx context.Context, token string) (*entity.PasswordReset, error) {
+// GetPasswordResetByToken gets a password reset by token hash
+func (r *authRepository) GetPasswordResetByToken(ctx context.Context, tokenHash string) (*entity.PasswordReset, error) {
 	var reset entity.PasswordReset
-	err := r.db.WithContext(ctx).Where("token = ?", token).First(&reset).Error
+	err := r.db.WithContext(ctx).Where("token = ?", tokenHash).First(&reset).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("password reset not found")
+			return nil, entity.ErrPasswordResetNotFound
 		}
 		return nil, err
 	}
 	return &reset, nil
 }
 
-// MarkPasswordResetUsed marks a password reset as used
-func (r *authRepository) MarkPasswordResetUsed(ctx context.Context, token string) error {
-	return r.db.WithContext(ctx).Model(&entity.PasswordReset{}).Where("token = ?", token).Update("used", true).Error
+// MarkPasswordResetUsed marks an unused password reset as used. Only one
+// caller can claim a token; the others get ErrPasswordResetNotFound.
+func (r *authRepository) MarkPasswordResetUsed(ctx context.Context, tokenHash string) error {
+	result := r.db.WithContext(ctx).Model(&entity.PasswordReset{}).Where("token = ? AND used = ?", tokenHash, false).Update("used", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrPasswordResetNotFound
+	}
+	return nil
+}
+
+// InvalidatePasswordResets marks all unused password resets of a user as used
+func (r *authRepository) InvalidatePasswordResets(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Model(&entity.PasswordReset{}).Where("user_id = ? AND used = ?", userID, false).Update("used", true).Error
 }
 
 // CleanExpiredPasswordResets removes expired password resets
@@ -98,6 +161,109 @@ func (r *authRepository) CleanExpiredPasswordResets(ctx context.Context) error {
 	return r.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&entity.PasswordReset{}).Error
 }
 
+// Magic link methods
+
+// CreateMagicLink creates a new magic link
+func (r *authRepository) CreateMagicLink(ctx context.Context, link *entity.MagicLink) error {
+	return r.db.WithContext(ctx).Create(link).Error
+}
+
+// GetMagicLinkByToken gets a magic link by token hash
+func (r *authRepository) GetMagicLinkByToken(ctx context.Context, tokenHash string) (*entity.MagicLink, error) {
+	var link entity.MagicLink
+	err := r.db.WithContext(ctx).Where("token = ?", tokenHash).First(&link).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrMagicLinkNotFound
+		}
+		return nil, err
+	}
+	return &link, nil
+}
+
+// GetLatestMagicLink gets the most recent magic link of a user
+func (r *authRepository) GetLatestMagicLink(ctx context.Context, userID uint) (*entity.MagicLink, error) {
+	var link entity.MagicLink
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC, id DESC").First(&link).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrMagicLinkNotFound
+		}
+		return nil, err
+	}
+	return &link, nil
+}
+
+// CountMagicLinksSince counts the magic links sent to a user since a point in time
+func (r *authRepository) CountMagicLinksSince(ctx context.Context, userID uint, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.MagicLink{}).Where("user_id = ? AND created_at >= ?", userID, since).Count(&count).Error
+	return count, err
+}
+
+// MarkMagicLinkUsed marks an unused magic link as used. Only one caller can
+// claim a link; the others get ErrMagicLinkNotFound.
+func (r *authRepository) MarkMagicLinkUsed(ctx context.Context, tokenHash string) error {
+	result := r.db.WithContext(ctx).Model(&entity.MagicLink{}).Where("token = ? AND used = ?", tokenHash, false).Update("used", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrMagicLinkNotFound
+	}
+	return nil
+}
+
+// InvalidateMagicLinks marks all unused magic links of a user as used
+func (r *authRepository) InvalidateMagicLinks(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Model(&entity.MagicLink{}).Where("user_id = ? AND used = ?", userID, false).Update("used", true).Error
+}
+
+// CleanExpiredMagicLinks removes expired magic links
+func (r *authRepository) CleanExpiredMagicLinks(ctx context.Context) error {
+	return r.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&entity.MagicLink{}).Error
+}
+
+// Pending login methods
+
+// CreatePendingLogin creates a login waiting for a second factor
+func (r *authRepository) CreatePendingLogin(ctx context.Context, login *entity.PendingLogin) error {
+	return r.db.WithContext(ctx).Create(login).Error
+}
+
+// GetPendingLoginByToken gets a pending login by token hash
+func (r *authRepository) GetPendingLoginByToken(ctx context.Context, tokenHash string) (*entity.PendingLogin, error) {
+	var login entity.PendingLogin
+	err := r.db.WithContext(ctx).Where("token = ?", tokenHash).First(&login).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrPendingLoginNotFound
+		}
+		return nil, err
+	}
+	return &login, nil
+}
+
+// MarkPendingLoginUsed marks an unused pending login as used. Only one caller
+// can claim a login; the others get ErrPendingLoginNotFound.
+func (r *authRepository) MarkPendingLoginUsed(ctx context.Context, tokenHash string) error {
+	result := r.db.WithContext(ctx).Model(&entity.PendingLogin{}).
+		Where("token = ? AND used = ? AND expires_at > ?", tokenHash, false, time.Now()).
+		Update("used", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrPendingLoginNotFound
+	}
+	return nil
+}
+
+// CleanExpiredPendingLogins removes expired pending logins
+func (r *authRepository) CleanExpiredPendingLogins(ctx context.Context) error {
+	return r.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&entity.PendingLogin{}).Error
+}
+
 // TFA code methods
 
 // CreateTFACode creates a new TFA code
@@ -105,22 +271,60 @@ func (r *authRepository) CreateTFACode(ctx context.Context, code *entity.TFACode
 	return r.db.WithContext(ctx).Create(code).Error
 }
 
-// GetTFACodeByCode gets a TFA code by code
-func (r *authRepository) GetTFACodeByCode(ctx context.Context, code string) (*entity.TFACode, error) {
+// GetLatestTFACode gets the most recent code of a user for a purpose
+func (r *authRepository) GetLatestTFACode(ctx context.Context, userID uint, purpose string) (*entity.TFACode, error) {
 	var tfaCode entity.TFACode
-	err := r.db.WithContext(ctx).Where("code = ?", code).First(&tfaCode).Error
+	err := r.db.WithContext(ctx).Where("user_id = ? AND purpose = ?", userID, purpose).Order("created_at DESC, id DESC").First(&tfaCode).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("TFA code not found")
+			return nil, entity.ErrTFACodeNotFound
 		}
 		return nil, err
 	}
 	return &tfaCode, nil
 }
 
-// MarkTFACodeUsed marks a TFA code as used
-func (r *authRepository) MarkTFACodeUsed(ctx context.Context, code string) error {
-	return r.db.WithContext(ctx).Model(&entity.TFACode{}).Where("code = ?", code).Update("used", true).Error
+// CountTFACodesSince counts the codes issued to a user for a purpose since a point in time
+func (r *authRepository) CountTFACodesSince(ctx context.Context, userID uint, purpose string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.TFACode{}).Where("user_id = ? AND purpose = ? AND created_at >= ?", userID, purpose, since).Count(&count).Error
+	return count, err
+}
+
+// ReserveTFACodeAttempt counts a verification attempt against a code. The
+// check and the increment are one statement, so concurrent guesses cannot
+// exceed maxAttempts; 0 allows unlimited attempts.
+func (r *authRepository) ReserveTFACodeAttempt(ctx context.Context, id uint, maxAttempts int) error {
+	query := r.db.WithContext(ctx).Model(&entity.TFACode{}).Where("id = ?", id)
+	if maxAttempts > 0 {
+		query = query.Where("attempts < ?", maxAttempts)
+	}
+
+	result := query.Update("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrOTPAttemptsExceeded
+	}
+	return nil
+}
+
+// ClaimTFACode marks an unused code as used, failing if it was already used
+func (r *authRepository) ClaimTFACode(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Model(&entity.TFACode{}).Where("id = ? AND used = ?", id, false).Update("used", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrTFACodeNotFound
+	}
+	return nil
+}
+
+// InvalidateTFACodes marks all unused codes of a user for a purpose as used
+func (r *authRepository) InvalidateTFACodes(ctx context.Context, userID uint, purpose string) error {
+	return r.db.WithContext(ctx).Model(&entity.TFACode{}).Where("user_id = ? AND purpose = ? AND used = ?", userID, purpose, false).Update("used", true).Error
 }
 
 // UpdateSession updates a session
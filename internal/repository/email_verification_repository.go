@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type emailVerificationRepository struct {
+	db *gorm.DB
+}
+
+// NewEmailVerificationRepository creates a new email verification repository
+func NewEmailVerificationRepository(db *gorm.DB) repository.EmailVerificationRepository {
+	return &emailVerificationRepository{db: db}
+}
+
+// Create stores a new email verification
+func (r *emailVerificationRepository) Create(ctx context.Context, verification *entity.EmailVerification) error {
+	verificationModel := &model.EmailVerificationModel{}
+	verificationModel.FromEntity(verification)
+
+	if err := r.db.WithContext(ctx).Create(verificationModel).Error; err != nil {
+		return err
+	}
+
+	verification.ID = verificationModel.ID
+	return nil
+}
+
+// GetByToken gets an email verification by token hash
+func (r *emailVerificationRepository) GetByToken(ctx context.Context, tokenHash string) (*entity.EmailVerification, error) {
+	return r.getBy(ctx, "token", tokenHash)
+}
+
+// GetByCancelToken gets an email verification by cancel token hash
+func (r *emailVerificationRepository) GetByCancelToken(ctx context.Context, tokenHash string) (*entity.EmailVerification, error) {
+	return r.getBy(ctx, "cancel_token", tokenHash)
+}
+
+func (r *emailVerificationRepository) getBy(ctx context.Context, column, value string) (*entity.EmailVerification, error) {
+	var verificationModel model.EmailVerificationModel
+	err := r.db.WithContext(ctx).Where(column+" = ?", value).First(&verificationModel).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrEmailVerificationNotFound
+		}
+		return nil, err
+	}
+	return verificationModel.ToEntity(), nil
+}
+
+// Update saves an email verification
+func (r *emailVerificationRepository) Update(ctx context.Context, verification *entity.EmailVerification) error {
+	verificationModel := &model.EmailVerificationModel{}
+	verificationModel.FromEntity(verification)
+	return r.db.WithContext(ctx).Save(verificationModel).Error
+}
+
+// CancelPending cancels a user's unverified, uncancelled verifications
+func (r *emailVerificationRepository) CancelPending(ctx context.Context, userID uint, purpose string) error {
+	return r.db.WithContext(ctx).Model(&model.EmailVerificationModel{}).
+		Where("user_id = ? AND purpose = ? AND verified_at IS NULL AND cancelled_at IS NULL", userID, purpose).
+		Update("cancelled_at", time.Now()).Error
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type identityRepository struct {
+	db *gorm.DB
+}
+
+// NewIdentityRepository creates a new identity repository
+func NewIdentityRepository(db *gorm.DB) repository.IdentityRepository {
+	return &identityRepository{db: db}
+}
+
+// Create links an identity to a user
+func (r *identityRepository) Create(ctx context.Context, identity *entity.UserIdentity) error {
+	identityModel := &model.UserIdentityModel{}
+	identityModel.FromEntity(identity)
+
+	if err := r.db.WithContext(ctx).Create(identityModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return entity.ErrIdentityAlreadyLinked.Wrap(err)
+		}
+		return err
+	}
+
+	identity.ID = identityModel.ID
+	identity.CreatedAt = identityModel.CreatedAt
+	identity.UpdatedAt = identityModel.UpdatedAt
+	return nil
+}
+
+// GetByProviderSubject gets the identity of a provider account
+func (r *identityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error) {
+	var identityModel model.UserIdentityModel
+	err := r.db.WithContext(ctx).
+		Where("provider = ? AND subject = ?", provider, subject).
+		First(&identityModel).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrIdentityNotFound
+		}
+		return nil, err
+	}
+	return identityModel.ToEntity(), nil
+}
+
+// ListByUserID lists the identities linked to a user
+func (r *identityRepository) ListByUserID(ctx context.Context, userID uint) ([]*entity.UserIdentity, error) {
+	var identityModels []model.UserIdentityModel
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at").
+		Find(&identityModels).Error
+	if err != nil {
+		return nil, err
+	}
+
+	identities := make([]*entity.UserIdentity, len(identityModels))
+	for i := range identityModels {
+		identities[i] = identityModels[i].ToEntity()
+	}
+	return identities, nil
+}
+
+// Update updates an identity
+func (r *identityRepository) Update(ctx context.Context, identity *entity.UserIdentity) error {
+	identityModel := &model.UserIdentityModel{}
+	identityModel.FromEntity(identity)
+	return r.db.WithContext(ctx).Save(identityModel).Error
+}
+
+// Delete unlinks an identity owned by the user
+func (r *identityRepository) Delete(ctx context.Context, id, userID uint) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&model.UserIdentityModel{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrIdentityNotFound
+	}
+	return nil
+}
+
+// CreateAuthRequest stores a sign-in sent to a provider
+func (r *identityRepository) CreateAuthRequest(ctx context.Context, request *entity.OIDCAuthRequest) error {
+	requestModel := &model.OIDCAuthRequestModel{}
+	requestModel.FromEntity(request)
+
+	if err := r.db.WithContext(ctx).Create(requestModel).Error; err != nil {
+		return err
+	}
+
+	request.ID = requestModel.ID
+	request.CreatedAt = requestModel.CreatedAt
+	return nil
+}
+
+// ClaimAuthRequest deletes and returns the sign-in with a state, so that a
+// state can only be used once even by concurrent callbacks
+func (r *identityRepository) ClaimAuthRequest(ctx context.Context, stateHash string) (*entity.OIDCAuthRequest, error) {
+	var requestModels []model.OIDCAuthRequestModel
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("state_hash = ?", stateHash).
+		Delete(&requestModels).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(requestModels) == 0 {
+		return nil, entity.ErrInvalidOIDCState
+	}
+	return requestModels[0].ToEntity(), nil
+}
+
+// CleanExpiredAuthRequests removes abandoned sign-ins
+func (r *identityRepository) CleanExpiredAuthRequests(ctx context.Context) error {
+	return r.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&model.OIDCAuthRequestModel{}).Error
+}
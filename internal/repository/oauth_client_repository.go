@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type oauthClientRepository struct {
+	db *gorm.DB
+}
+
+// NewOAuthClientRepository creates a new OAuth client repository
+func NewOAuthClientRepository(db *gorm.DB) repository.OAuthClientRepository {
+	return &oauthClientRepository{db: db}
+}
+
+// Create registers a new OAuth client
+func (r *oauthClientRepository) Create(ctx context.Context, client *entity.OAuthClient) error {
+	clientModel := &model.OAuthClientModel{}
+	clientModel.FromEntity(client)
+
+	if err := r.db.WithContext(ctx).Create(clientModel).Error; err != nil {
+		return err
+	}
+
+	client.ID = clientModel.ID
+	client.CreatedAt = clientModel.CreatedAt
+	client.UpdatedAt = clientModel.UpdatedAt
+	return nil
+}
+
+// GetByID gets an OAuth client by ID
+func (r *oauthClientRepository) GetByID(ctx context.Context, id uint) (*entity.OAuthClient, error) {
+	return r.get(ctx, "id = ?", id)
+}
+
+// GetByClientID gets an OAuth client by its public client ID
+func (r *oauthClientRepository) GetByClientID(ctx context.Context, clientID string) (*entity.OAuthClient, error) {
+	return r.get(ctx, "client_id = ?", clientID)
+}
+
+func (r *oauthClientRepository) get(ctx context.Context, query string, arg interface{}) (*entity.OAuthClient, error) {
+	var clientModel model.OAuthClientModel
+	err := r.db.WithContext(ctx).Where(query, arg).First(&clientModel).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrOAuthClientNotFound
+		}
+		return nil, err
+	}
+	return clientModel.ToEntity(), nil
+}
+
+// List lists all OAuth clients, newest first
+func (r *oauthClientRepository) List(ctx context.Context) ([]*entity.OAuthClient, error) {
+	var clientModels []model.OAuthClientModel
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Find(&clientModels).Error; err != nil {
+		return nil, err
+	}
+
+	clients := make([]*entity.OAuthClient, len(clientModels))
+	for i := range clientModels {
+		clients[i] = clientModels[i].ToEntity()
+	}
+	return clients, nil
+}
+
+// Update updates an OAuth client
+func (r *oauthClientRepository) Update(ctx context.Context, client *entity.OAuthClient) error {
+	clientModel := &model.OAuthClientModel{}
+	clientModel.FromEntity(client)
+	return r.db.WithContext(ctx).Save(clientModel).Error
+}
+
+// TouchLastUsed records that a client obtained a token
+func (r *oauthClientRepository) TouchLastUsed(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).
+		Model(&model.OAuthClientModel{}).
+		Where("id = ?", id).
+		UpdateColumn("last_used_at", time.Now()).Error
+}
@@ -21,16 +21,17 @@ func NewOrderRepository(db *gorm.DB) repository.OrderRepository {
 
 // Create creates a new order
 func (r *orderRepository) Create(ctx context.Context, order *entity.Order) error {
+	order.OrgID = tenantID(ctx, order.OrgID)
 	return r.db.WithContext(ctx).Create(order).Error
 }
 
 // GetByID gets an order by ID
 func (r *orderRepository) GetByID(ctx context.Context, id uint) (*entity.Order, error) {
 	var order entity.Order
-	err := r.db.WithContext(ctx).First(&order, id).Error
+	err := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).First(&order, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("order not found")
+			return nil, entity.ErrOrderNotFound
 		}
 		return nil, err
 	}
@@ -40,10 +41,10 @@ func (r *orderRepository) GetByID(ctx context.Context, id uint) (*entity.Order,
 // GetByOrderNumber gets an order by order number
 func (r *orderRepository) GetByOrderNumber(ctx context.Context, orderNumber string) (*entity.Order, error) {
 	var order entity.Order
-	err := r.db.WithContext(ctx).Where("order_number = ?", orderNumber).First(&order).Error
+	err := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Where("order_number = ?", orderNumber).First(&order).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("order not found")
+			return nil, entity.ErrOrderNotFound
 		}
 		return nil, err
 	}
@@ -53,9 +54,13 @@ func (r *orderRepository) GetByOrderNumber(ctx context.Context, orderNumber stri
 // GetByUserID gets orders by user ID with filtering
 func (r *orderRepository) GetByUserID(ctx context.Context, userID uint, filter repository.OrderFilter) ([]*entity.Order, error) {
 	var orders []*entity.Order
-	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	query := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Where("user_id = ?", userID)
 
 	// Apply filters
+	if filter.OrgID > 0 {
+		query = query.Where("org_id = ?", filter.OrgID)
+	}
+
 	if filter.Status != "" {
 		query = query.Where("status = ?", filter.Status)
 	}
@@ -90,25 +95,31 @@ func (r *orderRepository) GetByUserID(ctx context.Context, userID uint, filter r
 
 // Update updates an order
 func (r *orderRepository) Update(ctx context.Context, order *entity.Order) error {
-	return r.db.WithContext(ctx).Save(order).Error
+	// Select avoids Save's insert fallback when the scope matches no row;
+	// an order never moves to another organization
+	return r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Select("*").Omit("org_id", "created_at").Updates(order).Error
 }
 
 // UpdateStatus updates order status
 func (r *orderRepository) UpdateStatus(ctx context.Context, id uint, status string) error {
-	return r.db.WithContext(ctx).Model(&entity.Order{}).Where("id = ?", id).Update("status", status).Error
+	return r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Model(&entity.Order{}).Where("id = ?", id).Update("status", status).Error
 }
 
 // Delete deletes an order
 func (r *orderRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&entity.Order{}, id).Error
+	return r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Delete(&entity.Order{}, id).Error
 }
 
 // List gets orders with filtering and pagination
 func (r *orderRepository) List(ctx context.Context, filter repository.OrderFilter) ([]*entity.Order, error) {
 	var orders []*entity.Order
-	query := r.db.WithContext(ctx)
+	query := r.db.WithContext(ctx).Scopes(tenantScope(ctx))
 
 	// Apply filters
+	if filter.OrgID > 0 {
+		query = query.Where("org_id = ?", filter.OrgID)
+	}
+
 	if filter.UserID > 0 {
 		query = query.Where("user_id = ?", filter.UserID)
 	}
@@ -148,9 +159,13 @@ func (r *orderRepository) List(ctx context.Context, filter repository.OrderFilte
 // Count counts orders with filtering
 func (r *orderRepository) Count(ctx context.Context, filter repository.OrderFilter) (int64, error) {
 	var count int64
-	query := r.db.WithContext(ctx).Model(&entity.Order{})
+	query := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Model(&entity.Order{})
 
 	// Apply filters
+	if filter.OrgID > 0 {
+		query = query.Where("org_id = ?", filter.OrgID)
+	}
+
 	if filter.UserID > 0 {
 		query = query.Where("user_id = ?", filter.UserID)
 	}
@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type organizationRepository struct {
+	db *gorm.DB
+}
+
+// NewOrganizationRepository creates a new organization repository
+func NewOrganizationRepository(db *gorm.DB) repository.OrganizationRepository {
+	return &organizationRepository{db: db}
+}
+
+// CreateOrganization creates an organization together with its first owner
+func (r *organizationRepository) CreateOrganization(ctx context.Context, org *entity.Organization, owner *entity.Membership) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		orgModel := &model.OrganizationModel{}
+		orgModel.FromEntity(org)
+		if err := tx.Create(orgModel).Error; err != nil {
+			return err
+		}
+
+		owner.OrgID = orgModel.ID
+		membershipModel := &model.MembershipModel{}
+		membershipModel.FromEntity(owner)
+		if err := tx.Create(membershipModel).Error; err != nil {
+			return err
+		}
+
+		org.ID = orgModel.ID
+		org.CreatedAt = orgModel.CreatedAt
+		org.UpdatedAt = orgModel.UpdatedAt
+		owner.ID = membershipModel.ID
+		owner.CreatedAt = membershipModel.CreatedAt
+		owner.UpdatedAt = membershipModel.UpdatedAt
+		return nil
+	})
+}
+
+// GetOrganization gets an organization by ID
+func (r *organizationRepository) GetOrganization(ctx context.Context, id uint) (*entity.Organization, error) {
+	var orgModel model.OrganizationModel
+	err := r.db.WithContext(ctx).First(&orgModel, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+	return orgModel.ToEntity(), nil
+}
+
+// UpdateOrganization updates the name of an organization
+func (r *organizationRepository) UpdateOrganization(ctx context.Context, org *entity.Organization) error {
+	orgModel := &model.OrganizationModel{}
+	orgModel.FromEntity(org)
+	return r.db.WithContext(ctx).Model(orgModel).Select("name", "updated_at").Updates(orgModel).Error
+}
+
+// DeleteOrganization deletes an organization; memberships and invitations
+// cascade, while orders and payments keep it from being deleted
+func (r *organizationRepository) DeleteOrganization(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Delete(&model.OrganizationModel{}, id).Error
+	if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return entity.ErrOrganizationInUse.Wrap(err)
+	}
+	return err
+}
+
+// GetMembership gets a user's membership in an organization
+func (r *organizationRepository) GetMembership(ctx context.Context, orgID, userID uint) (*entity.Membership, error) {
+	var membershipModel model.MembershipModel
+	err := r.db.WithContext(ctx).Where("org_id = ? AND user_id = ?", orgID, userID).First(&membershipModel).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrMemberNotFound
+		}
+		return nil, err
+	}
+	return membershipModel.ToEntity(), nil
+}
+
+// ListMembershipsByUser lists the organizations a user belongs to
+func (r *organizationRepository) ListMembershipsByUser(ctx context.Context, userID uint) ([]*entity.Membership, error) {
+	var membershipModels []model.MembershipModel
+	err := r.db.WithContext(ctx).
+		Preload("Organization").
+		Where("user_id = ?", userID).
+		Order("created_at").
+		Find(&membershipModels).Error
+	if err != nil {
+		return nil, err
+	}
+	return toMemberships(membershipModels), nil
+}
+
+// ListMembers lists the members of an organization with their accounts
+func (r *organizationRepository) ListMembers(ctx context.Context, orgID uint) ([]*entity.Membership, error) {
+	var membershipModels []model.MembershipModel
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("org_id = ?", orgID).
+		Order("created_at").
+		Find(&membershipModels).Error
+	if err != nil {
+		return nil, err
+	}
+	return toMemberships(membershipModels), nil
+}
+
+// UpdateMemberRole changes the role of a member. The last owner cannot be
+// demoted.
+func (r *organizationRepository) UpdateMemberRole(ctx context.Context, orgID, userID uint, role string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if role != entity.OrgRoleOwner {
+			if err := ensureAnotherOwner(tx, orgID, userID); err != nil {
+				return err
+			}
+		}
+
+		result := tx.Model(&model.MembershipModel{}).
+			Where("org_id = ? AND user_id = ?", orgID, userID).
+			Updates(map[string]interface{}{"role": role, "updated_at": time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entity.ErrMemberNotFound
+		}
+		return nil
+	})
+}
+
+// DeleteMembership removes a user from an organization. The last owner
+// cannot be removed.
+func (r *organizationRepository) DeleteMembership(ctx context.Context, orgID, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureAnotherOwner(tx, orgID, userID); err != nil {
+			return err
+		}
+
+		result := tx.Where("org_id = ? AND user_id = ?", orgID, userID).Delete(&model.MembershipModel{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entity.ErrMemberNotFound
+		}
+		return nil
+	})
+}
+
+// ensureAnotherOwner refuses to let userID stop being an owner when they are
+// the only one. The owner memberships stay locked until the transaction
+// ends, so two owners demoting each other at the same time cannot both pass.
+func ensureAnotherOwner(tx *gorm.DB, orgID, userID uint) error {
+	var owners []uint
+	err := tx.Model(&model.MembershipModel{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("org_id = ? AND role = ?", orgID, entity.OrgRoleOwner).
+		Pluck("user_id", &owners).Error
+	if err != nil {
+		return err
+	}
+	if len(owners) <= 1 && slices.Contains(owners, userID) {
+		return entity.ErrLastOwner
+	}
+	return nil
+}
+
+// CreateInvitation stores an invitation, replacing any pending invitation
+// to the same address
+func (r *organizationRepository) CreateInvitation(ctx context.Context, invitation *entity.Invitation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("org_id = ? AND LOWER(email) = LOWER(?) AND accepted_at IS NULL", invitation.OrgID, invitation.Email).
+			Delete(&model.InvitationModel{}).Error
+		if err != nil {
+			return err
+		}
+
+		invitationModel := &model.InvitationModel{}
+		invitationModel.FromEntity(invitation)
+		if err := tx.Create(invitationModel).Error; err != nil {
+			return err
+		}
+
+		invitation.ID = invitationModel.ID
+		invitation.CreatedAt = invitationModel.CreatedAt
+		invitation.UpdatedAt = invitationModel.UpdatedAt
+		return nil
+	})
+}
+
+// GetInvitationByToken gets an invitation by the digest of its token
+func (r *organizationRepository) GetInvitationByToken(ctx context.Context, tokenHash string) (*entity.Invitation, error) {
+	var invitationModel model.InvitationModel
+	err := r.db.WithContext(ctx).Where("token = ?", tokenHash).First(&invitationModel).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	return invitationModel.ToEntity(), nil
+}
+
+// ListPendingInvitations lists the unaccepted, unexpired invitations of an
+// organization
+func (r *organizationRepository) ListPendingInvitations(ctx context.Context, orgID uint) ([]*entity.Invitation, error) {
+	var invitationModels []model.InvitationModel
+	err := r.db.WithContext(ctx).
+		Where("org_id = ? AND accepted_at IS NULL AND expires_at > ?", orgID, time.Now()).
+		Order("created_at DESC").
+		Find(&invitationModels).Error
+	if err != nil {
+		return nil, err
+	}
+
+	invitations := make([]*entity.Invitation, len(invitationModels))
+	for i := range invitationModels {
+		invitations[i] = invitationModels[i].ToEntity()
+	}
+	return invitations, nil
+}
+
+// DeleteInvitation revokes a pending invitation of an organization
+func (r *organizationRepository) DeleteInvitation(ctx context.Context, id, orgID uint) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND org_id = ? AND accepted_at IS NULL", id, orgID).
+		Delete(&model.InvitationModel{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrInvitationNotFound
+	}
+	return nil
+}
+
+// AcceptInvitation marks an invitation accepted and creates the membership
+// it grants. An invitation is accepted once, even by concurrent requests.
+func (r *organizationRepository) AcceptInvitation(ctx context.Context, invitation *entity.Invitation, membership *entity.Membership) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&model.InvitationModel{}).
+			Where("id = ? AND accepted_at IS NULL AND expires_at > ?", invitation.ID, now).
+			Updates(map[string]interface{}{"accepted_at": now, "updated_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entity.ErrInvalidInvitation
+		}
+
+		membershipModel := &model.MembershipModel{}
+		membershipModel.FromEntity(membership)
+		if err := tx.Create(membershipModel).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return entity.ErrAlreadyMember.Wrap(err)
+			}
+			return err
+		}
+
+		invitation.AcceptedAt = &now
+		membership.ID = membershipModel.ID
+		membership.CreatedAt = membershipModel.CreatedAt
+		membership.UpdatedAt = membershipModel.UpdatedAt
+		return nil
+	})
+}
+
+// CleanExpiredInvitations removes invitations that expired unaccepted
+func (r *organizationRepository) CleanExpiredInvitations(ctx context.Context) error {
+	return r.db.WithContext(ctx).Where("accepted_at IS NULL AND expires_at < ?", time.Now()).Delete(&model.InvitationModel{}).Error
+}
+
+func toMemberships(membershipModels []model.MembershipModel) []*entity.Membership {
+	memberships := make([]*entity.Membership, len(membershipModels))
+	for i := range membershipModels {
+		memberships[i] = membershipModels[i].ToEntity()
+	}
+	return memberships
+}
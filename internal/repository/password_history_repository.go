@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type passwordHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewPasswordHistoryRepository creates a new password history repository
+func NewPasswordHistoryRepository(db *gorm.DB) repository.PasswordHistoryRepository {
+	return &passwordHistoryRepository{db: db}
+}
+
+// Create records a password hash
+func (r *passwordHistoryRepository) Create(ctx context.Context, entry *entity.PasswordHistory) error {
+	entryModel := &model.PasswordHistoryModel{}
+	entryModel.FromEntity(entry)
+
+	if err := r.db.WithContext(ctx).Create(entryModel).Error; err != nil {
+		return err
+	}
+
+	entry.ID = entryModel.ID
+	return nil
+}
+
+// ListRecent gets the most recent password hashes of a user, newest first
+func (r *passwordHistoryRepository) ListRecent(ctx context.Context, userID uint, limit int) ([]*entity.PasswordHistory, error) {
+	var entryModels []model.PasswordHistoryModel
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&entryModels).Error
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*entity.PasswordHistory, len(entryModels))
+	for i, entryModel := range entryModels {
+		entries[i] = entryModel.ToEntity()
+	}
+	return entries, nil
+}
+
+// Prune deletes all but the keep most recent hashes of a user
+func (r *passwordHistoryRepository) Prune(ctx context.Context, userID uint, keep int) error {
+	recent := r.db.Model(&model.PasswordHistoryModel{}).
+		Select("id").
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(keep)
+
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND id NOT IN (?)", userID, recent).
+		Delete(&model.PasswordHistoryModel{}).Error
+}
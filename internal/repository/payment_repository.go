@@ -22,16 +22,17 @@ func NewPaymentRepository(db *gorm.DB) repository.PaymentRepository {
 
 // Create creates a new payment
 func (r *paymentRepository) Create(ctx context.Context, payment *entity.Payment) error {
+	payment.OrgID = tenantID(ctx, payment.OrgID)
 	return r.db.WithContext(ctx).Create(payment).Error
 }
 
 // GetByID gets a payment by ID
 func (r *paymentRepository) GetByID(ctx context.Context, id uint) (*entity.Payment, error) {
 	var payment entity.Payment
-	err := r.db.WithContext(ctx).First(&payment, id).Error
+	err := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).First(&payment, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("payment not found")
+			return nil, entity.ErrPaymentNotFound
 		}
 		return nil, err
 	}
@@ -41,10 +42,10 @@ func (r *paymentRepository) GetByID(ctx context.Context, id uint) (*entity.Payme
 // GetByGatewayRef gets a payment by gateway reference
 func (r *paymentRepository) GetByGatewayRef(ctx context.Context, gatewayRef string) (*entity.Payment, error) {
 	var payment entity.Payment
-	err := r.db.WithContext(ctx).Where("gateway_ref = ?", gatewayRef).First(&payment).Error
+	err := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Where("gateway_ref = ?", gatewayRef).First(&payment).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("payment not found")
+			return nil, entity.ErrPaymentNotFound
 		}
 		return nil, err
 	}
@@ -54,16 +55,20 @@ func (r *paymentRepository) GetByGatewayRef(ctx context.Context, gatewayRef stri
 // GetByOrderID gets payments by order ID
 func (r *paymentRepository) GetByOrderID(ctx context.Context, orderID uint) ([]*entity.Payment, error) {
 	var payments []*entity.Payment
-	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Find(&payments).Error
+	err := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Where("order_id = ?", orderID).Find(&payments).Error
 	return payments, err
 }
 
 // GetByUserID gets payments by user ID with filtering
 func (r *paymentRepository) GetByUserID(ctx context.Context, userID uint, filter repository.PaymentFilter) ([]*entity.Payment, error) {
 	var payments []*entity.Payment
-	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	query := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Where("user_id = ?", userID)
 
 	// Apply filters
+	if filter.OrgID > 0 {
+		query = query.Where("org_id = ?", filter.OrgID)
+	}
+
 	if filter.OrderID > 0 {
 		query = query.Where("order_id = ?", filter.OrderID)
 	}
@@ -110,25 +115,31 @@ func (r *paymentRepository) GetByUserID(ctx context.Context, userID uint, filter
 
 // Update updates a payment
 func (r *paymentRepository) Update(ctx context.Context, payment *entity.Payment) error {
-	return r.db.WithContext(ctx).Save(payment).Error
+	// Select avoids Save's insert fallback when the scope matches no row;
+	// a payment never moves to another organization
+	return r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Select("*").Omit("org_id", "created_at").Updates(payment).Error
 }
 
 // UpdateStatus updates payment status
 func (r *paymentRepository) UpdateStatus(ctx context.Context, id uint, status string) error {
-	return r.db.WithContext(ctx).Model(&entity.Payment{}).Where("id = ?", id).Update("status", status).Error
+	return r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Model(&entity.Payment{}).Where("id = ?", id).Update("status", status).Error
 }
 
 // Delete deletes a payment
 func (r *paymentRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&entity.Payment{}, id).Error
+	return r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Delete(&entity.Payment{}, id).Error
 }
 
 // Count counts payments with filtering
 func (r *paymentRepository) Count(ctx context.Context, filter repository.PaymentFilter) (int64, error) {
 	var count int64
-	query := r.db.WithContext(ctx).Model(&entity.Payment{})
+	query := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Model(&entity.Payment{})
 
 	// Apply filters
+	if filter.OrgID > 0 {
+		query = query.Where("org_id = ?", filter.OrgID)
+	}
+
 	if filter.UserID > 0 {
 		query = query.Where("user_id = ?", filter.UserID)
 	}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type privacyRepository struct {
+	db *gorm.DB
+}
+
+// NewPrivacyRepository creates a new privacy repository
+func NewPrivacyRepository(db *gorm.DB) repository.PrivacyRepository {
+	return &privacyRepository{db: db}
+}
+
+// CreateDataExport creates a new data export
+func (r *privacyRepository) CreateDataExport(ctx context.Context, export *entity.DataExport) error {
+	exportModel := &model.DataExportModel{}
+	exportModel.FromEntity(export)
+
+	if err := r.db.WithContext(ctx).Create(exportModel).Error; err != nil {
+		return err
+	}
+
+	export.ID = exportModel.ID
+	return nil
+}
+
+// GetDataExportByID gets a data export by ID
+func (r *privacyRepository) GetDataExportByID(ctx context.Context, id uint) (*entity.DataExport, error) {
+	var exportModel model.DataExportModel
+	err := r.db.WithContext(ctx).First(&exportModel, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrDataExportNotFound
+		}
+		return nil, err
+	}
+	return exportModel.ToEntity(), nil
+}
+
+// GetDataExportsByUserID gets all data exports for a user, newest first
+func (r *privacyRepository) GetDataExportsByUserID(ctx context.Context, userID uint) ([]*entity.DataExport, error) {
+	var exportModels []model.DataExportModel
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&exportModels).Error; err != nil {
+		return nil, err
+	}
+
+	exports := make([]*entity.DataExport, len(exportModels))
+	for i, exportModel := range exportModels {
+		exports[i] = exportModel.ToEntity()
+	}
+	return exports, nil
+}
+
+// UpdateDataExport updates a data export
+func (r *privacyRepository) UpdateDataExport(ctx context.Context, export *entity.DataExport) error {
+	exportModel := &model.DataExportModel{}
+	exportModel.FromEntity(export)
+	return r.db.WithContext(ctx).Save(exportModel).Error
+}
+
+// GetExpiredDataExports gets data exports past their download window
+func (r *privacyRepository) GetExpiredDataExports(ctx context.Context) ([]*entity.DataExport, error) {
+	var exportModels []model.DataExportModel
+	if err := r.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Find(&exportModels).Error; err != nil {
+		return nil, err
+	}
+
+	exports := make([]*entity.DataExport, len(exportModels))
+	for i, exportModel := range exportModels {
+		exports[i] = exportModel.ToEntity()
+	}
+	return exports, nil
+}
+
+// DeleteDataExport deletes a data export
+func (r *privacyRepository) DeleteDataExport(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.DataExportModel{}, id).Error
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type rbacRepository struct {
+	db *gorm.DB
+}
+
+// NewRBACRepository creates a new RBAC repository
+func NewRBACRepository(db *gorm.DB) repository.RBACRepository {
+	return &rbacRepository{db: db}
+}
+
+// ListRoles lists all roles with their permissions
+func (r *rbacRepository) ListRoles(ctx context.Context) ([]*entity.Role, error) {
+	var roleModels []model.RoleModel
+	if err := r.db.WithContext(ctx).Preload("Permissions").Order("name").Find(&roleModels).Error; err != nil {
+		return nil, err
+	}
+
+	roles := make([]*entity.Role, len(roleModels))
+	for i := range roleModels {
+		roles[i] = roleModels[i].ToEntity()
+	}
+	return roles, nil
+}
+
+// GetRoleByID gets a role with its permissions by ID
+func (r *rbacRepository) GetRoleByID(ctx context.Context, id uint) (*entity.Role, error) {
+	return r.getRole(ctx, "id = ?", id)
+}
+
+// GetRoleByName gets a role with its permissions by name
+func (r *rbacRepository) GetRoleByName(ctx context.Context, name string) (*entity.Role, error) {
+	return r.getRole(ctx, "name = ?", name)
+}
+
+func (r *rbacRepository) getRole(ctx context.Context, query string, arg interface{}) (*entity.Role, error) {
+	var roleModel model.RoleModel
+	err := r.db.WithContext(ctx).Preload("Permissions").Where(query, arg).First(&roleModel).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return roleModel.ToEntity(), nil
+}
+
+// CreateRole creates a role without permissions
+func (r *rbacRepository) CreateRole(ctx context.Context, role *entity.Role) error {
+	roleModel := &model.RoleModel{}
+	roleModel.FromEntity(role)
+
+	if err := r.db.WithContext(ctx).Omit("Permissions").Create(roleModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return entity.ErrRoleAlreadyExists.Wrap(err)
+		}
+		return err
+	}
+
+	role.ID = roleModel.ID
+	return nil
+}
+
+// UpdateRole updates the name and description of a role
+func (r *rbacRepository) UpdateRole(ctx context.Context, role *entity.Role) error {
+	roleModel := &model.RoleModel{}
+	roleModel.FromEntity(role)
+
+	err := r.db.WithContext(ctx).Model(roleModel).Select("name", "description", "updated_at").Updates(roleModel).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return entity.ErrRoleAlreadyExists.Wrap(err)
+	}
+	return err
+}
+
+// DeleteRole deletes a role; assignments and grants cascade
+func (r *rbacRepository) DeleteRole(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.RoleModel{}, id).Error
+}
+
+// SetRolePermissions replaces the permissions of a role
+func (r *rbacRepository) SetRolePermissions(ctx context.Context, roleID uint, permissions []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var permissionModels []model.PermissionModel
+		if len(permissions) > 0 {
+			if err := tx.Where("name IN ?", permissions).Find(&permissionModels).Error; err != nil {
+				return err
+			}
+			if len(permissionModels) != len(uniqueStrings(permissions)) {
+				return entity.ErrPermissionNotFound
+			}
+		}
+
+		roleModel := &model.RoleModel{ID: roleID}
+		return tx.Model(roleModel).Association("Permissions").Replace(permissionModels)
+	})
+}
+
+// ListPermissions lists all permissions
+func (r *rbacRepository) ListPermissions(ctx context.Context) ([]*entity.Permission, error) {
+	var permissionModels []model.PermissionModel
+	if err := r.db.WithContext(ctx).Order("name").Find(&permissionModels).Error; err != nil {
+		return nil, err
+	}
+
+	permissions := make([]*entity.Permission, len(permissionModels))
+	for i := range permissionModels {
+		permissions[i] = permissionModels[i].ToEntity()
+	}
+	return permissions, nil
+}
+
+// GetUserRoles gets the roles held by a user
+func (r *rbacRepository) GetUserRoles(ctx context.Context, userID uint) ([]*entity.Role, error) {
+	var roleModels []model.RoleModel
+	err := r.db.WithContext(ctx).
+		Preload("Permissions").
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.name").
+		Find(&roleModels).Error
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]*entity.Role, len(roleModels))
+	for i := range roleModels {
+		roles[i] = roleModels[i].ToEntity()
+	}
+	return roles, nil
+}
+
+// AssignRole gives a role to a user; assigning a held role is a no-op
+func (r *rbacRepository) AssignRole(ctx context.Context, userID, roleID uint) error {
+	userRole := &model.UserRoleModel{UserID: userID, RoleID: roleID}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(userRole).Error
+}
+
+// RevokeRole removes a role from a user
+func (r *rbacRepository) RevokeRole(ctx context.Context, userID, roleID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ? AND role_id = ?", userID, roleID).Delete(&model.UserRoleModel{}).Error
+}
+
+// GetUserAccess gets the role and permission names of a user in one query
+func (r *rbacRepository) GetUserAccess(ctx context.Context, userID uint) (*entity.UserAccess, error) {
+	var rows []struct {
+		Role       string
+		Permission *string
+	}
+	err := r.db.WithContext(ctx).
+		Table("user_roles").
+		Select("roles.name AS role, permissions.name AS permission").
+		Joins("JOIN roles ON roles.id = user_roles.role_id").
+		Joins("LEFT JOIN role_permissions ON role_permissions.role_id = roles.id").
+		Joins("LEFT JOIN permissions ON permissions.id = role_permissions.permission_id").
+		Where("user_roles.user_id = ?", userID).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	access := &entity.UserAccess{Roles: []string{}, Permissions: []string{}}
+	seenRoles := map[string]bool{}
+	seenPermissions := map[string]bool{}
+	for _, row := range rows {
+		if !seenRoles[row.Role] {
+			seenRoles[row.Role] = true
+			access.Roles = append(access.Roles, row.Role)
+		}
+		if row.Permission != nil && !seenPermissions[*row.Permission] {
+			seenPermissions[*row.Permission] = true
+			access.Permissions = append(access.Permissions, *row.Permission)
+		}
+	}
+	return access, nil
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := values[:0:0]
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
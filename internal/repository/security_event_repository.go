@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type securityEventRepository struct {
+	db *gorm.DB
+}
+
+// NewSecurityEventRepository creates a new security event repository
+func NewSecurityEventRepository(db *gorm.DB) repository.SecurityEventRepository {
+	return &securityEventRepository{db: db}
+}
+
+// Create records a new security event
+func (r *securityEventRepository) Create(ctx context.Context, event *entity.SecurityEvent) error {
+	eventModel := &model.SecurityEventModel{}
+	eventModel.FromEntity(event)
+
+	if err := r.db.WithContext(ctx).Create(eventModel).Error; err != nil {
+		return err
+	}
+
+	event.ID = eventModel.ID
+	return nil
+}
+
+// GetByUserID gets security events by user ID with filtering
+func (r *securityEventRepository) GetByUserID(ctx context.Context, userID uint, filter repository.SecurityEventFilter) ([]*entity.SecurityEvent, error) {
+	var eventModels []model.SecurityEventModel
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+
+	// Apply filters
+	if filter.Event != "" {
+		query = query.Where("event = ?", filter.Event)
+	}
+
+	// Apply sorting
+	sortBy := "created_at"
+	if filter.SortDesc {
+		sortBy += " DESC"
+	}
+	query = query.Order(sortBy)
+
+	// Apply pagination
+	if filter.Page > 0 && filter.Limit > 0 {
+		offset := (filter.Page - 1) * filter.Limit
+		query = query.Offset(offset).Limit(filter.Limit)
+	}
+
+	if err := query.Find(&eventModels).Error; err != nil {
+		return nil, err
+	}
+
+	events := make([]*entity.SecurityEvent, len(eventModels))
+	for i, eventModel := range eventModels {
+		events[i] = eventModel.ToEntity()
+	}
+	return events, nil
+}
+
+// AnonymizeByUserID strips network identifiers from a user's security events
+func (r *securityEventRepository) AnonymizeByUserID(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Model(&model.SecurityEventModel{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+		"ip_address": "",
+		"user_agent": "",
+		"metadata":   nil,
+	}).Error
+}
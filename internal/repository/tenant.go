@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+
+	"boilerplate-go-fiber-v2/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// tenantScope limits queries on a tenant-scoped table to the organization
+// active in ctx. Personal sessions only see their own rows outside any
+// organization. Contexts with neither, such as background jobs, see nothing
+// unless they opt out with utils.WithAllTenants. Postgres row security
+// policies enforce the same rule.
+func tenantScope(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if orgID := utils.OrgIDFromContext(ctx); orgID != 0 {
+			return db.Where("org_id = ?", orgID)
+		}
+		if userID := utils.UserIDFromContext(ctx); userID != 0 {
+			return db.Where("org_id IS NULL AND user_id = ?", userID)
+		}
+		if utils.AllTenantsFromContext(ctx) {
+			return db
+		}
+		return db.Where("1 = 0")
+	}
+}
+
+// tenantID returns the organization a new tenant-scoped row belongs to. The
+// organization active in ctx wins, so a request cannot write into another
+// tenant; without one the row keeps orgID.
+func tenantID(ctx context.Context, orgID *uint) *uint {
+	if active := utils.OrgIDFromContext(ctx); active != 0 {
+		return &active
+	}
+	return orgID
+}
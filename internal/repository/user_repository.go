@@ -5,7 +5,10 @@ import (
 	"boilerplate-go-fiber-v2/internal/domain/repository"
 	"boilerplate-go-fiber-v2/internal/model"
 	"context"
+	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -22,7 +25,19 @@ func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
 	userModel := &model.UserModel{}
 	userModel.FromEntity(user)
 
-	if err := r.db.WithContext(ctx).Create(userModel).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(userModel).Error; err != nil {
+			return err
+		}
+
+		// New users hold their primary role
+		return tx.Exec("INSERT INTO user_roles (user_id, role_id) SELECT ?, id FROM roles WHERE name = ? ON CONFLICT DO NOTHING",
+			userModel.ID, userModel.Role).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return entity.ErrUserAlreadyExists.Wrap(err)
+		}
 		return err
 	}
 
@@ -34,6 +49,9 @@ func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
 func (r *userRepository) GetByID(ctx context.Context, id uint) (*entity.User, error) {
 	var userModel model.UserModel
 	if err := r.db.WithContext(ctx).First(&userModel, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrUserNotFound
+		}
 		return nil, err
 	}
 	return userModel.ToEntity(), nil
@@ -42,6 +60,9 @@ func (r *userRepository) GetByID(ctx context.Context, id uint) (*entity.User, er
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
 	var userModel model.UserModel
 	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&userModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrUserNotFound
+		}
 		return nil, err
 	}
 	return userModel.ToEntity(), nil
@@ -50,15 +71,48 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entity.
 func (r *userRepository) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
 	var userModel model.UserModel
 	if err := r.db.WithContext(ctx).Where("username = ?", username).First(&userModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrUserNotFound
+		}
 		return nil, err
 	}
 	return userModel.ToEntity(), nil
 }
 
+// uniqueUserFields lists the columns ExistsBy may query
+var uniqueUserFields = map[string]bool{
+	"email":    true,
+	"username": true,
+	"phone":    true,
+}
+
+func (r *userRepository) ExistsBy(ctx context.Context, field, value string, excludeID uint) (bool, error) {
+	if !uniqueUserFields[field] {
+		return false, fmt.Errorf("unsupported unique field: %s", field)
+	}
+
+	var count int64
+	query := r.db.WithContext(ctx).Model(&model.UserModel{}).Where(field+" = ?", value)
+	if excludeID != 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Limit(1).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
 	userModel := &model.UserModel{}
 	userModel.FromEntity(user)
-	return r.db.WithContext(ctx).Save(userModel).Error
+
+	if err := r.db.WithContext(ctx).Save(userModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return entity.ErrUserAlreadyExists.Wrap(err)
+		}
+		return err
+	}
+	return nil
 }
 
 func (r *userRepository) Delete(ctx context.Context, id uint) error {
@@ -142,6 +196,10 @@ func (r *userRepository) UpdateStatus(ctx context.Context, userID uint, status s
 	return r.db.WithContext(ctx).Model(&model.UserModel{}).Where("id = ?", userID).Update("status", status).Error
 }
 
+func (r *userRepository) UpdatePassword(ctx context.Context, userID uint, hash string) error {
+	return r.db.WithContext(ctx).Model(&model.UserModel{}).Where("id = ?", userID).Update("password", hash).Error
+}
+
 func (r *userRepository) UpdateTFA(ctx context.Context, userID uint, enabled bool, secret string, backupCodes []string) error {
 	return r.db.WithContext(ctx).Model(&model.UserModel{}).Where("id = ?", userID).Updates(map[string]interface{}{
 		"tfa_enabled":      enabled,
@@ -149,3 +207,60 @@ func (r *userRepository) UpdateTFA(ctx context.Context, userID uint, enabled boo
 		"tfa_backup_codes": backupCodes,
 	}).Error
 }
+
+func (r *userRepository) GetDueForDeletion(ctx context.Context, before time.Time) ([]*entity.User, error) {
+	var userModels []model.UserModel
+	if err := r.db.WithContext(ctx).Where("deletion_scheduled_at <= ? AND anonymized_at IS NULL", before).Find(&userModels).Error; err != nil {
+		return nil, err
+	}
+
+	users := make([]*entity.User, len(userModels))
+	for i, userModel := range userModels {
+		users[i] = userModel.ToEntity()
+	}
+	return users, nil
+}
+
+// Anonymize saves the erased user and removes everything that would outlive
+// the erasure: sessions, credentials, sign-in methods, role assignments and
+// organization memberships
+func (r *userRepository) Anonymize(ctx context.Context, user *entity.User) error {
+	userModel := &model.UserModel{}
+	userModel.FromEntity(user)
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Invitations are addressed by email, so match them before the
+		// stored address is overwritten
+		err := tx.Where("LOWER(email) = (SELECT LOWER(email) FROM users WHERE id = ?)", user.ID).
+			Delete(&model.InvitationModel{}).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Save(userModel).Error; err != nil {
+			return err
+		}
+
+		for _, owned := range []interface{}{
+			&entity.AuthSession{},
+			&entity.PasswordReset{},
+			&entity.TFACode{},
+			&entity.MagicLink{},
+			&entity.PendingLogin{},
+			&model.PasswordHistoryModel{},
+			&model.EmailVerificationModel{},
+			&model.APITokenModel{},
+			&model.UserIdentityModel{},
+			&model.OIDCAuthRequestModel{},
+			&model.WebAuthnCredentialModel{},
+			&model.WebAuthnChallengeModel{},
+			&model.UserRoleModel{},
+			&model.MembershipModel{},
+		} {
+			if err := tx.Where("user_id = ?", user.ID).Delete(owned).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type webAuthnRepository struct {
+	db *gorm.DB
+}
+
+// NewWebAuthnRepository creates a new WebAuthn repository
+func NewWebAuthnRepository(db *gorm.DB) repository.WebAuthnRepository {
+	return &webAuthnRepository{db: db}
+}
+
+// CreateCredential stores a new passkey
+func (r *webAuthnRepository) CreateCredential(ctx context.Context, credential *entity.WebAuthnCredential) error {
+	credentialModel := &model.WebAuthnCredentialModel{}
+	credentialModel.FromEntity(credential)
+
+	if err := r.db.WithContext(ctx).Create(credentialModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return entity.ErrPasskeyAlreadyRegistered.Wrap(err)
+		}
+		return err
+	}
+
+	credential.ID = credentialModel.ID
+	credential.CreatedAt = credentialModel.CreatedAt
+	credential.UpdatedAt = credentialModel.UpdatedAt
+	return nil
+}
+
+// GetCredentialByCredentialID gets a passkey by the ID the authenticator assigned
+func (r *webAuthnRepository) GetCredentialByCredentialID(ctx context.Context, credentialID []byte) (*entity.WebAuthnCredential, error) {
+	var credentialModel model.WebAuthnCredentialModel
+	err := r.db.WithContext(ctx).Where("credential_id = ?", credentialID).First(&credentialModel).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrPasskeyNotFound
+		}
+		return nil, err
+	}
+	return credentialModel.ToEntity(), nil
+}
+
+// ListCredentials lists the passkeys of a user
+func (r *webAuthnRepository) ListCredentials(ctx context.Context, userID uint) ([]*entity.WebAuthnCredential, error) {
+	var credentialModels []model.WebAuthnCredentialModel
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at").
+		Find(&credentialModels).Error
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := make([]*entity.WebAuthnCredential, len(credentialModels))
+	for i := range credentialModels {
+		credentials[i] = credentialModels[i].ToEntity()
+	}
+	return credentials, nil
+}
+
+// UpdateCredential updates a passkey
+func (r *webAuthnRepository) UpdateCredential(ctx context.Context, credential *entity.WebAuthnCredential) error {
+	credentialModel := &model.WebAuthnCredentialModel{}
+	credentialModel.FromEntity(credential)
+	return r.db.WithContext(ctx).Save(credentialModel).Error
+}
+
+// RenameCredential renames a passkey owned by the user
+func (r *webAuthnRepository) RenameCredential(ctx context.Context, id, userID uint, name string) (*entity.WebAuthnCredential, error) {
+	var credentialModels []model.WebAuthnCredentialModel
+	result := r.db.WithContext(ctx).
+		Model(&credentialModels).
+		Clauses(clause.Returning{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(map[string]interface{}{"name": name, "updated_at": time.Now()})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(credentialModels) == 0 {
+		return nil, entity.ErrPasskeyNotFound
+	}
+	return credentialModels[0].ToEntity(), nil
+}
+
+// DeleteCredential removes a passkey owned by the user
+func (r *webAuthnRepository) DeleteCredential(ctx context.Context, id, userID uint) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&model.WebAuthnCredentialModel{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrPasskeyNotFound
+	}
+	return nil
+}
+
+// CreateChallenge stores the challenge of a ceremony
+func (r *webAuthnRepository) CreateChallenge(ctx context.Context, challenge *entity.WebAuthnChallenge) error {
+	challengeModel := &model.WebAuthnChallengeModel{}
+	challengeModel.FromEntity(challenge)
+
+	if err := r.db.WithContext(ctx).Create(challengeModel).Error; err != nil {
+		return err
+	}
+
+	challenge.ID = challengeModel.ID
+	challenge.CreatedAt = challengeModel.CreatedAt
+	return nil
+}
+
+// ClaimChallenge deletes and returns a ceremony's challenge, so that each
+// challenge is answered at most once even by concurrent responses
+func (r *webAuthnRepository) ClaimChallenge(ctx context.Context, challengeHash string) (*entity.WebAuthnChallenge, error) {
+	var challengeModels []model.WebAuthnChallengeModel
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("challenge_hash = ?", challengeHash).
+		Delete(&challengeModels).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(challengeModels) == 0 {
+		return nil, entity.ErrInvalidWebAuthnChallenge
+	}
+	return challengeModels[0].ToEntity(), nil
+}
+
+// CleanExpiredChallenges removes abandoned ceremonies
+func (r *webAuthnRepository) CleanExpiredChallenges(ctx context.Context) error {
+	return r.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&model.WebAuthnChallengeModel{}).Error
+}
@@ -3,7 +3,12 @@ package route
 import (
 	"boilerplate-go-fiber-v2/config"
 	"boilerplate-go-fiber-v2/internal/container"
+	"boilerplate-go-fiber-v2/internal/handler"
 	v1Routes "boilerplate-go-fiber-v2/internal/route/v1"
+	"boilerplate-go-fiber-v2/pkg/mailer"
+	"boilerplate-go-fiber-v2/pkg/sms"
+	"boilerplate-go-fiber-v2/pkg/storage"
+	"context"
 	"log"
 
 	"github.com/gofiber/fiber/v2"
@@ -12,14 +17,22 @@ import (
 )
 
 // SetupRoutes configures all application routes
-func SetupRoutes(app *fiber.App, db *gorm.DB, redis *redis.Client, cfg *config.Config) {
+func SetupRoutes(app *fiber.App, db *gorm.DB, redis *redis.Client, store storage.Storage, mail mailer.Mailer, sender sms.SMSSender, cfg *config.Config) {
 	// Health check endpoint
 	app.Get("/health", healthCheck)
 
 	// Initialize dependency container
-	container := container.NewContainer(db, redis, cfg)
+	container := container.NewContainer(db, redis, store, mail, sender, cfg)
 	log.Println("Dependency container initialized successfully")
 
+	// Start background jobs
+	container.StartBackgroundJobs(context.Background())
+
+	// Uploaded files (local storage only; S3 serves its own URLs)
+	if localStorage, ok := store.(*storage.LocalStorage); ok {
+		app.Get("/files/*", handler.NewFileHandler(localStorage).Serve)
+	}
+
 	// API routes
 	api := app.Group("/api")
 
@@ -39,6 +52,9 @@ func setupV1Routes(router fiber.Router, container *container.Container, cfg *con
 	// Setup v1 route modules
 	v1Routes.SetupAuthRoutes(router, container, cfg, redis)
 	v1Routes.SetupUserRoutes(router, container, cfg, redis)
+	v1Routes.SetupAdminRoutes(router, container, cfg, redis)
+	v1Routes.SetupOAuthRoutes(router, container, cfg, redis)
+	v1Routes.SetupOrganizationRoutes(router, container, cfg, redis)
 
 	// v1 test endpoint
 	router.Get("/test", func(c *fiber.Ctx) error {
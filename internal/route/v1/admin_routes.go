@@ -0,0 +1,50 @@
+package v1
+
+import (
+	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/internal/container"
+	"boilerplate-go-fiber-v2/internal/middleware"
+	"boilerplate-go-fiber-v2/pkg/binder"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// SetupAdminRoutes configures administration routes
+func SetupAdminRoutes(router fiber.Router, container *container.Container, cfg *config.Config, redis *redis.Client) {
+	authMiddleware := middleware.NewAuthMiddleware(container.GetAuthService(), container.GetRBACService(), container.GetAPITokenService(), cfg)
+	admin := router.Group("/admin")
+
+	// Roles and permissions. Permissions are checked per route, since a
+	// group on "/" would apply to every /admin route. API tokens with the
+	// roles:manage scope may call them; sessions need the permission, and
+	// impersonation sessions are refused.
+	rolesScope := authMiddleware.RequireScope("roles:manage")
+	noImpersonation := authMiddleware.DenyImpersonation()
+	manageRoles := authMiddleware.RequirePermission("roles:manage")
+	admin.Get("/roles", rolesScope, noImpersonation, manageRoles, container.GetRBACHandler().ListRoles)
+	admin.Post("/roles", rolesScope, noImpersonation, manageRoles, binder.Handle(container.GetRBACHandler().CreateRole))
+	admin.Get("/roles/:id", rolesScope, noImpersonation, manageRoles, binder.Handle(container.GetRBACHandler().GetRole))
+	admin.Put("/roles/:id", rolesScope, noImpersonation, manageRoles, binder.Handle(container.GetRBACHandler().UpdateRole))
+	admin.Delete("/roles/:id", rolesScope, noImpersonation, manageRoles, binder.Handle(container.GetRBACHandler().DeleteRole))
+	admin.Put("/roles/:id/permissions", rolesScope, noImpersonation, manageRoles, binder.Handle(container.GetRBACHandler().SetRolePermissions))
+	admin.Get("/permissions", rolesScope, noImpersonation, manageRoles, container.GetRBACHandler().ListPermissions)
+	admin.Get("/users/:id/roles", rolesScope, noImpersonation, manageRoles, binder.Handle(container.GetRBACHandler().GetUserRoles))
+	admin.Post("/users/:id/roles", rolesScope, noImpersonation, manageRoles, binder.Handle(container.GetRBACHandler().AssignRole))
+	admin.Delete("/users/:id/roles/:roleId", rolesScope, noImpersonation, manageRoles, binder.Handle(container.GetRBACHandler().RevokeRole))
+
+	// OAuth clients
+	clientsScope := authMiddleware.RequireScope("oauth_clients:manage")
+	manageClients := authMiddleware.RequirePermission("oauth_clients:manage")
+	admin.Get("/oauth/clients", clientsScope, manageClients, container.GetOAuthHandler().ListClients)
+	admin.Post("/oauth/clients", clientsScope, manageClients, binder.Handle(container.GetOAuthHandler().CreateClient))
+	admin.Delete("/oauth/clients/:id", clientsScope, manageClients, binder.Handle(container.GetOAuthHandler().DisableClient))
+
+	// Impersonation acts for a person, so it takes sessions only; an
+	// impersonation session cannot start another one
+	authenticate := authMiddleware.Authenticate()
+	impersonate := authMiddleware.RequirePermission("users:impersonate")
+	admin.Post("/users/:id/impersonate", authenticate, impersonate, noImpersonation, binder.Handle(container.GetImpersonationHandler().Start))
+	admin.Get("/impersonations", authenticate, impersonate, container.GetImpersonationHandler().ListStarted)
+	admin.Delete("/impersonations/:id", authenticate, binder.Handle(container.GetImpersonationHandler().End))
+}
@@ -4,6 +4,7 @@ import (
 	"boilerplate-go-fiber-v2/config"
 	"boilerplate-go-fiber-v2/internal/container"
 	"boilerplate-go-fiber-v2/internal/middleware"
+	"boilerplate-go-fiber-v2/pkg/binder"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/redis/go-redis/v9"
@@ -14,18 +15,50 @@ func SetupAuthRoutes(router fiber.Router, container *container.Container, cfg *c
 	auth := router.Group("/auth")
 
 	// Public routes (no auth required)
-	auth.Post("/register", container.GetAuthHandler().Register)
-	auth.Post("/login", container.GetAuthHandler().Login)
-	auth.Post("/refresh-token", container.GetAuthHandler().RefreshToken)
-	auth.Post("/password-reset", container.GetAuthHandler().CreatePasswordReset)
-	auth.Post("/reset-password", container.GetAuthHandler().ResetPassword)
+	auth.Post("/register", binder.Handle(container.GetAuthHandler().Register))
+	auth.Post("/login", binder.Handle(container.GetAuthHandler().Login))
+	auth.Post("/refresh-token", binder.Handle(container.GetAuthHandler().RefreshToken))
+	auth.Post("/password-reset", binder.Handle(container.GetAuthHandler().CreatePasswordReset))
+	auth.Post("/reset-password", binder.Handle(container.GetAuthHandler().ResetPassword))
+
+	// Passwordless login with emailed links or passkeys, limited per IP address
+	passwordlessLimit := middleware.NewRateLimitMiddleware(redis).AuthRateLimit()
+	auth.Post("/magic-link", passwordlessLimit, binder.Handle(container.GetAuthHandler().RequestMagicLink))
+	auth.Post("/magic-link/login", passwordlessLimit, binder.Handle(container.GetAuthHandler().LoginWithMagicLink))
+
+	// Second factor of a login held by a TFA challenge, whatever the first was
+	auth.Post("/tfa/login", passwordlessLimit, binder.Handle(container.GetAuthHandler().LoginWithTFACode))
+	auth.Post("/tfa/login/code", passwordlessLimit, binder.Handle(container.GetAuthHandler().CreateTFALoginCode))
+
+	// Passkey login; the passkey picked by the user tells who signs in
+	auth.Post("/passkey/login/start", passwordlessLimit, container.GetWebAuthnHandler().StartLogin)
+	auth.Post("/passkey/login/finish", passwordlessLimit, binder.Handle(container.GetWebAuthnHandler().FinishLogin))
+	auth.Post("/passkey/tfa/start", passwordlessLimit, binder.Handle(container.GetWebAuthnHandler().StartTFA))
+	auth.Post("/passkey/tfa/finish", passwordlessLimit, binder.Handle(container.GetWebAuthnHandler().FinishTFA))
+
+	// Sign-in with OpenID Connect providers; the callback accepts the
+	// provider's redirect directly or the code and state posted by a frontend
+	auth.Get("/oidc/providers", container.GetOIDCHandler().Providers)
+	auth.Get("/oidc/:provider/start", binder.Handle(container.GetOIDCHandler().StartLogin))
+	auth.Get("/oidc/:provider/callback", binder.Handle(container.GetOIDCHandler().Callback))
+	auth.Post("/oidc/:provider/callback", binder.Handle(container.GetOIDCHandler().Callback))
 
 	// Protected routes (auth required)
-	authMiddleware := middleware.NewAuthMiddleware(container.GetAuthService(), cfg)
+	authMiddleware := middleware.NewAuthMiddleware(container.GetAuthService(), container.GetRBACService(), container.GetAPITokenService(), cfg)
 	protected := auth.Group("/", authMiddleware.Authenticate())
 	protected.Post("/logout", container.GetAuthHandler().Logout)
-	protected.Post("/tfa/create", container.GetAuthHandler().CreateTFACode)
-	protected.Post("/tfa/enable", container.GetAuthHandler().EnableTFA)
-	protected.Post("/tfa/disable", container.GetAuthHandler().DisableTFA)
-	protected.Post("/tfa/verify", container.GetAuthHandler().VerifyTFA)
+
+	// Proving the user's identity is closed to admins impersonating them
+	noImpersonation := authMiddleware.DenyImpersonation()
+	protected.Post("/tfa/create", noImpersonation, binder.Handle(container.GetAuthHandler().CreateTFACode))
+	protected.Post("/reauthenticate", noImpersonation, binder.Handle(container.GetAuthHandler().Reauthenticate))
+	protected.Post("/reauthenticate/code", noImpersonation, binder.Handle(container.GetAuthHandler().CreateStepUpCode))
+	protected.Post("/passkey/verify/start", noImpersonation, container.GetWebAuthnHandler().StartVerify)
+	protected.Post("/passkey/verify/finish", noImpersonation, binder.Handle(container.GetWebAuthnHandler().FinishVerify))
+
+	// Sensitive routes (recent authentication required)
+	recentAuth := authMiddleware.RequireRecentAuth(cfg.StepUp.MaxAge)
+	protected.Post("/tfa/enable", recentAuth, container.GetAuthHandler().EnableTFA)
+	protected.Post("/tfa/disable", recentAuth, container.GetAuthHandler().DisableTFA)
+	protected.Post("/tfa/verify", noImpersonation, binder.Handle(container.GetAuthHandler().VerifyTFA))
 }
@@ -0,0 +1,19 @@
+package v1
+
+import (
+	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/internal/container"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// SetupOAuthRoutes configures the OAuth token endpoints. Clients
+// authenticate with their own credentials, not with a user token.
+func SetupOAuthRoutes(router fiber.Router, container *container.Container, cfg *config.Config, redis *redis.Client) {
+	oauth := router.Group("/oauth")
+
+	oauth.Post("/token", container.GetOAuthHandler().Token)
+	oauth.Post("/introspect", container.GetOAuthHandler().Introspect)
+	oauth.Post("/revoke", container.GetOAuthHandler().Revoke)
+}
@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/internal/container"
+	"boilerplate-go-fiber-v2/internal/middleware"
+	"boilerplate-go-fiber-v2/pkg/binder"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// SetupOrganizationRoutes configures organization, membership and
+// invitation routes. Roles within an organization are checked by the
+// organization service, not by RequirePermission.
+func SetupOrganizationRoutes(router fiber.Router, container *container.Container, cfg *config.Config, redis *redis.Client) {
+	authMiddleware := middleware.NewAuthMiddleware(container.GetAuthService(), container.GetRBACService(), container.GetAPITokenService(), cfg)
+	orgs := router.Group("/organizations", authMiddleware.Authenticate())
+
+	orgs.Get("/", container.GetOrganizationHandler().ListOrganizations)
+	orgs.Post("/", binder.Handle(container.GetOrganizationHandler().CreateOrganization))
+	orgs.Post("/switch", binder.Handle(container.GetOrganizationHandler().SwitchOrganization))
+	orgs.Post("/invitations/accept", binder.Handle(container.GetOrganizationHandler().AcceptInvitation))
+
+	orgs.Get("/:id", binder.Handle(container.GetOrganizationHandler().GetOrganization))
+	orgs.Patch("/:id", binder.Handle(container.GetOrganizationHandler().UpdateOrganization))
+	orgs.Delete("/:id", authMiddleware.RequireRecentAuth(cfg.StepUp.MaxAge), binder.Handle(container.GetOrganizationHandler().DeleteOrganization))
+
+	// Members; any member can leave by removing themselves
+	orgs.Get("/:id/members", binder.Handle(container.GetOrganizationHandler().ListMembers))
+	orgs.Patch("/:id/members/:userId", binder.Handle(container.GetOrganizationHandler().UpdateMemberRole))
+	orgs.Delete("/:id/members/:userId", binder.Handle(container.GetOrganizationHandler().RemoveMember))
+
+	// Invitations
+	orgs.Get("/:id/invitations", binder.Handle(container.GetOrganizationHandler().ListInvitations))
+	orgs.Post("/:id/invitations", binder.Handle(container.GetOrganizationHandler().Invite))
+	orgs.Delete("/:id/invitations/:invitationId", binder.Handle(container.GetOrganizationHandler().RevokeInvitation))
+
+	// Service keys; like personal tokens, issuing and revoking need a recent
+	// login
+	orgs.Get("/:id/api-keys", binder.Handle(container.GetAPITokenHandler().ListOrganizationKeys))
+	orgs.Post("/:id/api-keys", authMiddleware.RequireRecentAuth(cfg.StepUp.MaxAge), binder.Handle(container.GetAPITokenHandler().CreateOrganizationKey))
+	orgs.Delete("/:id/api-keys/:keyId", authMiddleware.RequireRecentAuth(cfg.StepUp.MaxAge), binder.Handle(container.GetAPITokenHandler().RevokeOrganizationKey))
+}
@@ -3,6 +3,8 @@ package v1
 import (
 	"boilerplate-go-fiber-v2/config"
 	"boilerplate-go-fiber-v2/internal/container"
+	"boilerplate-go-fiber-v2/internal/middleware"
+	"boilerplate-go-fiber-v2/pkg/binder"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/redis/go-redis/v9"
@@ -10,8 +12,52 @@ import (
 
 // SetupUserRoutes configures user-related routes
 func SetupUserRoutes(router fiber.Router, container *container.Container, cfg *config.Config, redis *redis.Client) {
-	// TODO: Implement user routes when UserHandler is created
-	// user := router.Group("/users")
-	// authMiddleware := middleware.NewAuthMiddleware(container.GetAuthService(), cfg)
-	// protected := user.Group("/", authMiddleware.Authenticate())
+	user := router.Group("/users")
+
+	// Public routes, authorized by the emailed tokens
+	user.Post("/email/confirm", binder.Handle(container.GetUserHandler().ConfirmEmailChange))
+	user.Post("/email/cancel", binder.Handle(container.GetUserHandler().CancelEmailChange))
+
+	// Protected routes (auth required)
+	authMiddleware := middleware.NewAuthMiddleware(container.GetAuthService(), container.GetRBACService(), container.GetAPITokenService(), cfg)
+	protected := user.Group("/", authMiddleware.Authenticate())
+
+	// Current user routes
+	me := protected.Group("/me")
+	noImpersonation := authMiddleware.DenyImpersonation()
+	me.Post("/avatar", container.GetUserHandler().UploadAvatar)
+	me.Get("/avatar", container.GetUserHandler().GetAvatar)
+	me.Delete("/avatar", container.GetUserHandler().DeleteAvatar)
+	me.Post("/export", authMiddleware.RequireRecentAuth(cfg.StepUp.MaxAge), container.GetUserHandler().RequestDataExport)
+	me.Get("/export", container.GetUserHandler().ListDataExports)
+	me.Get("/export/:id", binder.Handle(container.GetUserHandler().GetDataExport))
+	me.Get("/export/:id/download", binder.Handle(container.GetUserHandler().DownloadDataExport))
+	me.Post("/delete", authMiddleware.RequireRecentAuth(cfg.StepUp.MaxAge), container.GetUserHandler().RequestAccountDeletion)
+	me.Post("/delete/cancel", noImpersonation, container.GetUserHandler().CancelAccountDeletion)
+	me.Post("/email", authMiddleware.RequireRecentAuth(cfg.StepUp.MaxAge), binder.Handle(container.GetUserHandler().RequestEmailChange))
+	me.Post("/phone/verify/start", noImpersonation, container.GetUserHandler().StartPhoneVerification)
+	me.Post("/phone/verify/confirm", noImpersonation, binder.Handle(container.GetUserHandler().ConfirmPhoneVerification))
+
+	// API tokens; issuing and revoking need a recent login, so a token
+	// cannot mint or revoke tokens
+	me.Get("/tokens", container.GetAPITokenHandler().ListTokens)
+	me.Post("/tokens", authMiddleware.RequireRecentAuth(cfg.StepUp.MaxAge), binder.Handle(container.GetAPITokenHandler().CreateToken))
+	me.Delete("/tokens/:id", authMiddleware.RequireRecentAuth(cfg.StepUp.MaxAge), binder.Handle(container.GetAPITokenHandler().RevokeToken))
+
+	// Linked sign-in providers; linking and unlinking need a recent login
+	me.Get("/identities", container.GetOIDCHandler().ListIdentities)
+	me.Post("/identities/:provider/start", authMiddleware.RequireRecentAuth(cfg.StepUp.MaxAge), binder.Handle(container.GetOIDCHandler().StartLink))
+	me.Post("/identities/:provider/callback", noImpersonation, binder.Handle(container.GetOIDCHandler().LinkCallback))
+	me.Delete("/identities/:id", authMiddleware.RequireRecentAuth(cfg.StepUp.MaxAge), binder.Handle(container.GetOIDCHandler().Unlink))
+
+	// Passkeys; registering and deleting need a recent login
+	me.Get("/passkeys", container.GetWebAuthnHandler().ListPasskeys)
+	me.Post("/passkeys/register/start", authMiddleware.RequireRecentAuth(cfg.StepUp.MaxAge), container.GetWebAuthnHandler().StartRegistration)
+	me.Post("/passkeys/register/finish", noImpersonation, binder.Handle(container.GetWebAuthnHandler().FinishRegistration))
+	me.Patch("/passkeys/:id", noImpersonation, binder.Handle(container.GetWebAuthnHandler().RenamePasskey))
+	me.Delete("/passkeys/:id", authMiddleware.RequireRecentAuth(cfg.StepUp.MaxAge), binder.Handle(container.GetWebAuthnHandler().DeletePasskey))
+
+	// Impersonation sessions on the user's account; the user can end them
+	me.Get("/impersonations", container.GetImpersonationHandler().ListMine)
+	me.Delete("/impersonations/:id", binder.Handle(container.GetImpersonationHandler().End))
 }
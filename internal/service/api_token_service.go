@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/pkg/utils"
+)
+
+type apiTokenService struct {
+	tokenRepo      repository.APITokenRepository
+	userRepo       repository.UserRepository
+	orgRepo        repository.OrganizationRepository
+	rbacService    service.RBACService
+	securityEvents service.SecurityEventService
+	config         *config.Config
+}
+
+// NewAPITokenService creates a new API token service
+func NewAPITokenService(
+	tokenRepo repository.APITokenRepository,
+	userRepo repository.UserRepository,
+	orgRepo repository.OrganizationRepository,
+	rbacService service.RBACService,
+	securityEvents service.SecurityEventService,
+	config *config.Config,
+) service.APITokenService {
+	return &apiTokenService{
+		tokenRepo:      tokenRepo,
+		userRepo:       userRepo,
+		orgRepo:        orgRepo,
+		rbacService:    rbacService,
+		securityEvents: securityEvents,
+		config:         config,
+	}
+}
+
+// Create issues a personal access token of the form <prefix>_<lookup>_<secret>.
+// Only the lookup part is stored in clear; the secret is stored as a SHA-256
+// digest.
+func (s *apiTokenService) Create(ctx context.Context, userID uint, name string, scopes []string, ttl time.Duration) (*entity.APIToken, string, error) {
+	granted, err := s.grantableScopes(ctx, userID, scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token := &entity.APIToken{
+		UserID: userID,
+		Kind:   entity.APITokenKindPersonal,
+		Name:   name,
+		Scopes: granted,
+	}
+	plaintext, err := s.issue(ctx, token, ttl)
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.securityEvents.Record(ctx, userID, entity.SecurityEventAPITokenCreated, map[string]interface{}{
+		"token_id": token.ID,
+		"kind":     token.Kind,
+		"prefix":   token.Prefix,
+		"scopes":   token.Scopes,
+	})
+
+	return token, plaintext, nil
+}
+
+// List lists the tokens of a user, including revoked and expired ones
+func (s *apiTokenService) List(ctx context.Context, userID uint) ([]*entity.APIToken, error) {
+	return s.tokenRepo.ListByUserID(ctx, userID)
+}
+
+// Revoke revokes one of the user's tokens
+func (s *apiTokenService) Revoke(ctx context.Context, userID, tokenID uint) error {
+	if err := s.tokenRepo.Revoke(ctx, tokenID, userID); err != nil {
+		return err
+	}
+
+	s.securityEvents.Record(ctx, userID, entity.SecurityEventAPITokenRevoked, map[string]interface{}{
+		"token_id": tokenID,
+	})
+	return nil
+}
+
+// CreateForOrganization issues a service key owned by the organization. The
+// key outlives the member who issued it, but its scopes must be permissions
+// that member holds.
+func (s *apiTokenService) CreateForOrganization(ctx context.Context, userID, orgID uint, name string, scopes []string, ttl time.Duration) (*entity.APIToken, string, error) {
+	if err := s.requireKeyManager(ctx, orgID, userID); err != nil {
+		return nil, "", err
+	}
+
+	granted, err := s.grantableScopes(ctx, userID, scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token := &entity.APIToken{
+		OrgID:     orgID,
+		CreatedBy: userID,
+		Kind:      entity.APITokenKindService,
+		Name:      name,
+		Scopes:    granted,
+	}
+	plaintext, err := s.issue(ctx, token, ttl)
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.securityEvents.Record(ctx, userID, entity.SecurityEventAPITokenCreated, map[string]interface{}{
+		"token_id": token.ID,
+		"org_id":   orgID,
+		"kind":     token.Kind,
+		"prefix":   token.Prefix,
+		"scopes":   token.Scopes,
+	})
+
+	return token, plaintext, nil
+}
+
+// ListForOrganization lists the service keys of an organization, including
+// revoked and expired ones
+func (s *apiTokenService) ListForOrganization(ctx context.Context, userID, orgID uint) ([]*entity.APIToken, error) {
+	if err := s.requireKeyManager(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+	return s.tokenRepo.ListByOrgID(ctx, orgID)
+}
+
+// RevokeForOrganization revokes one of the organization's service keys
+func (s *apiTokenService) RevokeForOrganization(ctx context.Context, userID, orgID, tokenID uint) error {
+	if err := s.requireKeyManager(ctx, orgID, userID); err != nil {
+		return err
+	}
+
+	if err := s.tokenRepo.RevokeForOrganization(ctx, tokenID, orgID); err != nil {
+		return err
+	}
+
+	s.securityEvents.Record(ctx, userID, entity.SecurityEventAPITokenRevoked, map[string]interface{}{
+		"token_id": tokenID,
+		"org_id":   orgID,
+	})
+	return nil
+}
+
+// Authenticate checks a raw token and returns it with its owner, or with a
+// nil user for service keys. Every failure maps to ErrInvalidAPIToken so callers cannot probe for prefixes.
+func (s *apiTokenService) Authenticate(ctx context.Context, raw, ip string) (*entity.APIToken, *entity.User, error) {
+	parts := strings.SplitN(raw, "_", 3)
+	if len(parts) != 3 || !entity.IsAPIToken(raw) {
+		return nil, nil, entity.ErrInvalidAPIToken
+	}
+
+	token, err := s.tokenRepo.GetByPrefix(ctx, parts[0]+"_"+parts[1])
+	if err != nil {
+		if errors.Is(err, entity.ErrAPITokenNotFound) {
+			return nil, nil, entity.ErrInvalidAPIToken
+		}
+		return nil, nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(utils.HashToken(parts[2])), []byte(token.SecretHash)) != 1 {
+		return nil, nil, entity.ErrInvalidAPIToken
+	}
+
+	if !token.IsActive() {
+		return nil, nil, entity.ErrInvalidAPIToken
+	}
+
+	// Service keys act for their organization, not for a user
+	if token.BelongsToOrganization() {
+		s.touchLastUsed(ctx, token, ip)
+		return token, nil, nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, token.UserID)
+	if err != nil {
+		return nil, nil, entity.ErrInvalidAPIToken.Wrap(err)
+	}
+
+	if !user.IsActive() {
+		return nil, nil, entity.ErrAccountInactive
+	}
+
+	s.touchLastUsed(ctx, token, ip)
+	return token, user, nil
+}
+
+// touchLastUsed records token usage at most once per LastUsedInterval
+func (s *apiTokenService) touchLastUsed(ctx context.Context, token *entity.APIToken, ip string) {
+	before := time.Now().Add(-s.config.APIToken.LastUsedInterval)
+	if err := s.tokenRepo.TouchLastUsed(ctx, token.ID, ip, before); err != nil {
+		log.Printf("Failed to record usage of API token %d: %v", token.ID, err)
+	}
+}
+
+// grantableScopes checks that the user holds every scope, since a token can
+// never do more than the member who issued it, and removes duplicates
+func (s *apiTokenService) grantableScopes(ctx context.Context, userID uint, scopes []string) ([]string, error) {
+	access, err := s.rbacService.GetUserAccess(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	granted := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !access.HasPermission(scope) {
+			return nil, entity.ErrInvalidTokenScope.WithMessage("Scope " + scope + " is not a permission you hold")
+		}
+		if !slices.Contains(granted, scope) {
+			granted = append(granted, scope)
+		}
+	}
+	return granted, nil
+}
+
+// issue generates the token's prefix and secret, sets its expiry and stores
+// it. It returns the plaintext token.
+func (s *apiTokenService) issue(ctx context.Context, token *entity.APIToken, ttl time.Duration) (string, error) {
+	if ttl <= 0 {
+		ttl = s.config.APIToken.DefaultTTL
+	}
+	if ttl > s.config.APIToken.MaxTTL {
+		ttl = s.config.APIToken.MaxTTL
+	}
+	expiresAt := time.Now().Add(ttl)
+
+	lookup, err := randomHex(4)
+	if err != nil {
+		return "", err
+	}
+	secret, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return "", err
+	}
+	secret = strings.TrimRight(secret, "=")
+
+	token.Prefix = entity.APITokenPrefix(token.Kind) + "_" + lookup
+	token.SecretHash = utils.HashToken(secret)
+	token.ExpiresAt = &expiresAt
+
+	if err := s.tokenRepo.Create(ctx, token); err != nil {
+		return "", err
+	}
+	return token.Prefix + "_" + secret, nil
+}
+
+// requireKeyManager checks that the user is an owner or admin of the
+// organization, hiding organizations they do not belong to
+func (s *apiTokenService) requireKeyManager(ctx context.Context, orgID, userID uint) error {
+	membership, err := s.orgRepo.GetMembership(ctx, orgID, userID)
+	if err != nil {
+		if errors.Is(err, entity.ErrMemberNotFound) {
+			return entity.ErrOrganizationNotFound
+		}
+		return err
+	}
+	if !membership.CanManageMembers() {
+		return entity.ErrOrgRoleRequired
+	}
+	return nil
+}
@@ -2,7 +2,12 @@ package service
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
 	"time"
 
 	"boilerplate-go-fiber-v2/config"
@@ -10,58 +15,114 @@ import (
 	"boilerplate-go-fiber-v2/internal/domain/repository"
 	"boilerplate-go-fiber-v2/internal/domain/service"
 	"boilerplate-go-fiber-v2/pkg/jwt"
+	"boilerplate-go-fiber-v2/pkg/mailer"
+	"boilerplate-go-fiber-v2/pkg/totp"
 	"boilerplate-go-fiber-v2/pkg/utils"
 )
 
 type authService struct {
-	userRepo    repository.UserRepository
-	authRepo    repository.AuthRepository
-	userService service.UserService
-	config      *config.Config
+	userRepo        repository.UserRepository
+	authRepo        repository.AuthRepository
+	userService     service.UserService
+	passwordService service.PasswordService
+	securityEvents  service.SecurityEventService
+	mailer          mailer.Mailer
+	codes           service.OneTimeCodeService
+	config          *config.Config
 }
 
 // NewAuthService creates a new auth service
-func NewAuthService(userRepo repository.UserRepository, authRepo repository.AuthRepository, userService service.UserService, config *config.Config) service.AuthService {
+func NewAuthService(userRepo repository.UserRepository, authRepo repository.AuthRepository, userService service.UserService, passwordService service.PasswordService, securityEvents service.SecurityEventService, mailer mailer.Mailer, codes service.OneTimeCodeService, config *config.Config) service.AuthService {
 	return &authService{
-		userRepo:    userRepo,
-		authRepo:    authRepo,
-		userService: userService,
-		config:      config,
+		userRepo:        userRepo,
+		authRepo:        authRepo,
+		userService:     userService,
+		passwordService: passwordService,
+		securityEvents:  securityEvents,
+		mailer:          mailer,
+		codes:           codes,
+		config:          config,
 	}
 }
 
-// Login authenticates a user
-func (s *authService) Login(ctx context.Context, email, password string) (*entity.User, *entity.AuthSession, error) {
+// Login authenticates a user. A user whose password is older than the
+// maximum age gets a password change challenge instead of a session.
+func (s *authService) Login(ctx context.Context, email, password string) (*entity.User, *entity.AuthSession, *entity.LoginChallenge, error) {
 	// Get user by email
 	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
-		return nil, nil, errors.New("invalid credentials")
+		if errors.Is(err, entity.ErrUserNotFound) {
+			return nil, nil, nil, entity.ErrInvalidCredentials
+		}
+		return nil, nil, nil, err
 	}
 
 	// Check if user is active
 	if !user.IsActive() {
-		return nil, nil, errors.New("account is not active")
+		return nil, nil, nil, entity.ErrAccountInactive
 	}
 
 	// Check if email is verified
 	if !user.IsEmailVerified() {
-		return nil, nil, errors.New("email not verified")
+		return nil, nil, nil, entity.ErrEmailNotVerified
 	}
 
 	// Verify password
 	if !utils.CheckPassword(password, user.Password) {
-		return nil, nil, errors.New("invalid credentials")
+		s.securityEvents.Record(ctx, user.ID, entity.SecurityEventLoginFailed, nil)
+		return nil, nil, nil, entity.ErrInvalidCredentials
 	}
 
-	// Generate tokens
-	accessToken, err := jwt.GenerateToken(user.ID, user.Email, user.Role, s.config.JWT.Secret, s.config.JWT.Expiry)
+	// Upgrade the stored hash while the plaintext password is at hand
+	if utils.PasswordNeedsRehash(user.Password) {
+		s.rehashPassword(ctx, user, password)
+	}
+
+	return s.CompleteLogin(ctx, user, entity.AuthMethodPassword)
+}
+
+// CompleteLogin finishes a login once the user proved their identity. Every
+// way of signing in goes through here: a user with TFA enabled who proved a
+// single factor gets a TFA challenge, and a user whose password is older than
+// the maximum age gets a password change challenge instead of a session.
+func (s *authService) CompleteLogin(ctx context.Context, user *entity.User, methods ...string) (*entity.User, *entity.AuthSession, *entity.LoginChallenge, error) {
+	if user.IsTFAEnabled() && !entity.IsMultiFactor(methods) {
+		challenge, err := s.tfaChallenge(ctx, user, methods)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return user, nil, challenge, nil
+	}
+
+	if user.IsPasswordExpired(s.config.Password.MaxAge) {
+		challenge, err := s.passwordChangeChallenge(ctx, user)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return user, nil, challenge, nil
+	}
+
+	session, err := s.StartSession(ctx, user, methods...)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
+	}
+
+	return user, session, nil, nil
+}
+
+// StartSession signs a user in after they proved their identity with methods.
+// Every way of signing in ends here, so sessions, last login and login events
+// are recorded the same way.
+func (s *authService) StartSession(ctx context.Context, user *entity.User, methods ...string) (*entity.AuthSession, error) {
+	// Generate tokens; sessions start on the personal account
+	accessToken, err := jwt.GenerateToken(user.ID, user.Email, user.Role, 0, s.config.JWT.Secret, s.config.JWT.Expiry)
+	if err != nil {
+		return nil, err
 	}
 
 	refreshToken, err := utils.GenerateSecureToken(32)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	// Create session
@@ -72,10 +133,13 @@ func (s *authService) Login(ctx context.Context, email, password string) (*entit
 		ExpiresAt:    time.Now().Add(s.config.JWT.Expiry),
 		CreatedAt:    time.Now(),
 	}
+	for _, method := range methods {
+		session.Authenticate(method)
+	}
 
 	err = s.authRepo.CreateSession(ctx, session)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	// Update last login
@@ -84,12 +148,195 @@ func (s *authService) Login(ctx context.Context, email, password string) (*entit
 		// Log error but don't fail login
 	}
 
-	return user, session, nil
+	s.securityEvents.Record(ctx, user.ID, entity.SecurityEventLogin, map[string]interface{}{
+		"session_id": session.ID,
+		"method":     session.AMR,
+	})
+
+	return session, nil
+}
+
+// rehashPassword replaces an outdated password hash. Failures are logged and
+// never fail the login; the upgrade is retried on the next login.
+func (s *authService) rehashPassword(ctx context.Context, user *entity.User, password string) {
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		log.Printf("Failed to rehash password for user %d: %v", user.ID, err)
+		return
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, hash); err != nil {
+		log.Printf("Failed to store rehashed password for user %d: %v", user.ID, err)
+		return
+	}
+
+	user.Password = hash
+}
+
+// tfaChallenge parks a login until the user proves a second factor and
+// returns the challenge that lets the client finish it
+func (s *authService) tfaChallenge(ctx context.Context, user *entity.User, methods []string) (*entity.LoginChallenge, error) {
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	login := &entity.PendingLogin{
+		UserID:    user.ID,
+		Token:     utils.HashToken(token),
+		AMR:       strings.Join(methods, ","),
+		ExpiresAt: now.Add(s.config.TFA.ChallengeTTL),
+		CreatedAt: now,
+	}
+	if err := s.authRepo.CreatePendingLogin(ctx, login); err != nil {
+		return nil, err
+	}
+
+	return &entity.LoginChallenge{
+		Type:      entity.LoginChallengeTFA,
+		Token:     token,
+		ExpiresAt: login.ExpiresAt,
+	}, nil
+}
+
+// GetPendingLogin returns the login waiting on a TFA challenge token without
+// using the token up
+func (s *authService) GetPendingLogin(ctx context.Context, token string) (*entity.PendingLogin, error) {
+	login, err := s.authRepo.GetPendingLoginByToken(ctx, utils.HashToken(token))
+	if err != nil {
+		if errors.Is(err, entity.ErrPendingLoginNotFound) {
+			return nil, entity.ErrInvalidTFAChallenge
+		}
+		return nil, err
+	}
+
+	if !login.IsValid() {
+		return nil, entity.ErrInvalidTFAChallenge
+	}
+
+	return login, nil
+}
+
+// CreateTFALoginCode sends the user of a pending login a sign-in code over
+// the requested channel, email by default
+func (s *authService) CreateTFALoginCode(ctx context.Context, token, channel string) error {
+	login, err := s.GetPendingLogin(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return s.CreateTFACode(ctx, login.UserID, channel)
+}
+
+// LoginWithTFACode finishes a pending login with a code from the user's
+// authenticator app or one sent by CreateTFALoginCode. The challenge works
+// once, so a wrong code means logging in again.
+func (s *authService) LoginWithTFACode(ctx context.Context, token, code string) (*entity.User, *entity.AuthSession, *entity.LoginChallenge, error) {
+	login, user, err := s.claimPendingLogin(ctx, token)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var method string
+	if s.verifyTOTP(user, code) {
+		method = entity.AuthMethodOTP
+	} else {
+		tfaCode, err := s.codes.Verify(ctx, user.ID, entity.TFACodePurposeLogin, code)
+		if err != nil {
+			s.securityEvents.Record(ctx, user.ID, entity.SecurityEventLoginFailed, map[string]interface{}{
+				"method": login.AMR,
+				"tfa":    true,
+			})
+			return nil, nil, nil, err
+		}
+		method = entity.AuthMethodEmail
+		if tfaCode.Channel == entity.TFAChannelSMS {
+			method = entity.AuthMethodSMS
+		}
+	}
+
+	return s.CompleteLogin(ctx, user, append(login.Methods(), method)...)
+}
+
+// ContinueLogin finishes a pending login with a second factor proven outside
+// of the auth service, such as a passkey
+func (s *authService) ContinueLogin(ctx context.Context, token string, methods ...string) (*entity.User, *entity.AuthSession, *entity.LoginChallenge, error) {
+	login, user, err := s.claimPendingLogin(ctx, token)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return s.CompleteLogin(ctx, user, append(login.Methods(), methods...)...)
+}
+
+// claimPendingLogin uses up a TFA challenge token and returns its login and
+// user. Claiming before the second factor is checked gives each challenge a
+// single attempt.
+func (s *authService) claimPendingLogin(ctx context.Context, token string) (*entity.PendingLogin, *entity.User, error) {
+	login, err := s.GetPendingLogin(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.authRepo.MarkPendingLoginUsed(ctx, login.Token); err != nil {
+		if errors.Is(err, entity.ErrPendingLoginNotFound) {
+			return nil, nil, entity.ErrInvalidTFAChallenge
+		}
+		return nil, nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, login.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !user.IsActive() {
+		return nil, nil, entity.ErrAccountInactive
+	}
+
+	return login, user, nil
+}
+
+// passwordChangeChallenge issues a short-lived reset token that lets a user
+// with an expired password set a new one through ResetPassword
+func (s *authService) passwordChangeChallenge(ctx context.Context, user *entity.User) (*entity.LoginChallenge, error) {
+	token, reset, err := s.createPasswordReset(ctx, user.ID, s.config.Password.ChangeTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	s.securityEvents.Record(ctx, user.ID, entity.SecurityEventPasswordExpired, nil)
+
+	return &entity.LoginChallenge{
+		Type:      entity.LoginChallengePasswordChange,
+		Token:     token,
+		ExpiresAt: reset.ExpiresAt,
+	}, nil
 }
 
 // Logout logs out a user
 func (s *authService) Logout(ctx context.Context, token string) error {
-	return s.authRepo.DeleteSession(ctx, token)
+	session, err := s.authRepo.GetSessionByToken(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	if err := s.authRepo.DeleteSession(ctx, token); err != nil {
+		return err
+	}
+
+	// Logging out of an impersonation ends it
+	if session.IsImpersonation() {
+		recordImpersonationEnded(ctx, s.securityEvents, session, *session.ActorID)
+		return nil
+	}
+
+	s.securityEvents.Record(ctx, session.UserID, entity.SecurityEventLogout, map[string]interface{}{
+		"session_id": session.ID,
+	})
+
+	return nil
 }
 
 // RefreshToken refreshes an access token
@@ -97,22 +344,30 @@ func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (*e
 	// Get session by refresh token
 	session, err := s.authRepo.GetSessionByRefreshToken(ctx, refreshToken)
 	if err != nil {
-		return nil, errors.New("invalid refresh token")
+		if errors.Is(err, entity.ErrSessionNotFound) {
+			return nil, entity.ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	// Impersonation tokens end when they expire
+	if session.IsImpersonation() {
+		return nil, entity.ErrInvalidRefreshToken
 	}
 
 	// Check if session is expired
 	if session.IsExpired() {
-		return nil, errors.New("session expired")
+		return nil, entity.ErrSessionExpired
 	}
 
 	// Get user
 	user, err := s.userRepo.GetByID(ctx, session.UserID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, err
 	}
 
-	// Generate new tokens
-	accessToken, err := jwt.GenerateToken(user.ID, user.Email, user.Role, s.config.JWT.Secret, s.config.JWT.Expiry)
+	// Generate new tokens, keeping the active organization
+	accessToken, err := jwt.GenerateToken(user.ID, user.Email, user.Role, session.ActiveOrgID(), s.config.JWT.Secret, s.config.JWT.Expiry)
 	if err != nil {
 		return nil, err
 	}
@@ -136,6 +391,44 @@ func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (*e
 	return session, nil
 }
 
+// SetActiveOrganization reissues a session's access token with orgID as its
+// active organization, or with none when orgID is 0. Callers check that the
+// user belongs to the organization. The refresh token is kept.
+func (s *authService) SetActiveOrganization(ctx context.Context, sessionID, orgID uint) (*entity.AuthSession, error) {
+	session, err := s.authRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The token of an impersonation carries the admin behind it
+	if session.IsImpersonation() {
+		return nil, entity.ErrImpersonationDenied
+	}
+
+	user, err := s.userRepo.GetByID(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := jwt.GenerateToken(user.ID, user.Email, user.Role, orgID, s.config.JWT.Secret, s.config.JWT.Expiry)
+	if err != nil {
+		return nil, err
+	}
+
+	session.Token = accessToken
+	session.OrgID = nil
+	if orgID != 0 {
+		session.OrgID = &orgID
+	}
+	session.ExpiresAt = time.Now().Add(s.config.JWT.Expiry)
+
+	if err := s.authRepo.UpdateSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
 // Register registers a new user
 func (s *authService) Register(ctx context.Context, user *entity.User) error {
 	return s.userService.Register(ctx, user)
@@ -143,55 +436,364 @@ func (s *authService) Register(ctx context.Context, user *entity.User) error {
 
 // ValidateToken validates a JWT token
 func (s *authService) ValidateToken(ctx context.Context, token string) (*jwt.Claims, error) {
+	claims, _, err := s.AuthenticateToken(ctx, token)
+	return claims, err
+}
+
+// AuthenticateToken validates a JWT token and returns its session
+func (s *authService) AuthenticateToken(ctx context.Context, token string) (*jwt.Claims, *entity.AuthSession, error) {
 	claims, err := jwt.ValidateToken(token, s.config.JWT.Secret)
 	if err != nil {
-		return nil, err
+		return nil, nil, entity.ErrInvalidToken.Wrap(err)
 	}
 
 	// Check if session exists
-	_, err = s.authRepo.GetSessionByToken(ctx, token)
+	session, err := s.authRepo.GetSessionByToken(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Client tokens are for resource servers, not for acting as a user
+	if session.IsClientSession() {
+		return nil, nil, entity.ErrInvalidToken
+	}
+
+	return claims, session, nil
+}
+
+// Reauthenticate confirms the user's identity within a session with their
+// password, a TOTP code or a step-up code, and marks the session as recently
+// authenticated
+func (s *authService) Reauthenticate(ctx context.Context, sessionID uint, password, code string) (*entity.AuthSession, error) {
+	session, err := s.authRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// An admin acting as the user cannot prove to be them
+	if session.IsImpersonation() {
+		return nil, entity.ErrImpersonationDenied
+	}
+
+	user, err := s.userRepo.GetByID(ctx, session.UserID)
 	if err != nil {
-		return nil, errors.New("session not found")
+		return nil, err
 	}
 
-	return claims, nil
+	var method string
+	switch {
+	case password != "":
+		if !utils.CheckPassword(password, user.Password) {
+			s.securityEvents.Record(ctx, user.ID, entity.SecurityEventLoginFailed, map[string]interface{}{
+				"session_id": session.ID,
+				"step_up":    true,
+			})
+			return nil, entity.ErrInvalidPassword
+		}
+		method = entity.AuthMethodPassword
+	case s.verifyTOTP(user, code):
+		method = entity.AuthMethodOTP
+	default:
+		tfaCode, err := s.codes.Verify(ctx, user.ID, entity.TFACodePurposeStepUp, code)
+		if err != nil {
+			return nil, err
+		}
+		method = entity.AuthMethodEmail
+		if tfaCode.Channel == entity.TFAChannelSMS {
+			method = entity.AuthMethodSMS
+		}
+	}
+
+	return s.stepUp(ctx, session, method)
+}
+
+// StepUp marks a session as recently authenticated after the user confirmed
+// their identity with methods outside of Reauthenticate, such as a passkey
+func (s *authService) StepUp(ctx context.Context, sessionID uint, methods ...string) (*entity.AuthSession, error) {
+	session, err := s.authRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.stepUp(ctx, session, methods...)
 }
 
-// CreatePasswordReset creates a password reset request
+func (s *authService) stepUp(ctx context.Context, session *entity.AuthSession, methods ...string) (*entity.AuthSession, error) {
+	if session.IsImpersonation() {
+		return nil, entity.ErrImpersonationDenied
+	}
+
+	for _, method := range methods {
+		session.Authenticate(method)
+	}
+	session.UpdatedAt = time.Now()
+	if err := s.authRepo.UpdateSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	s.securityEvents.Record(ctx, session.UserID, entity.SecurityEventReauthenticated, map[string]interface{}{
+		"session_id": session.ID,
+		"method":     strings.Join(methods, ","),
+	})
+
+	return session, nil
+}
+
+// CreateStepUpCode sends a code that can be used to re-authenticate
+func (s *authService) CreateStepUpCode(ctx context.Context, userID uint, channel string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.codes.Issue(ctx, user, entity.TFACodePurposeStepUp, channel)
+	return err
+}
+
+// verifyTOTP checks a code from the user's authenticator app
+func (s *authService) verifyTOTP(user *entity.User, code string) bool {
+	if !user.IsTFAEnabled() || user.TFASecret == nil {
+		return false
+	}
+	return totp.Validate(*user.TFASecret, code, time.Now(), s.totpOptions())
+}
+
+func (s *authService) totpOptions() totp.Options {
+	return totp.Options{
+		Issuer:    s.config.TFA.Issuer,
+		Algorithm: s.config.TFA.Algorithm,
+		Digits:    s.config.TFA.Digits,
+		Period:    s.config.TFA.Period,
+		Skew:      1,
+	}
+}
+
+// CreatePasswordReset creates a password reset request and emails the reset
+// link. Unknown, inactive and erased accounts succeed silently, so the result
+// never reveals whether an email is registered.
 func (s *authService) CreatePasswordReset(ctx context.Context, email string) error {
 	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
-		return errors.New("user not found")
+		if errors.Is(err, entity.ErrUserNotFound) {
+			return nil
+		}
+		return err
 	}
 
+	if !user.IsActive() || user.AnonymizedAt != nil {
+		return nil
+	}
+
+	token, reset, err := s.createPasswordReset(ctx, user.ID, s.config.Password.ResetTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	mailer.SendInBackground(s.mailer, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to choose a new password. It expires at %s and can be used once.\n\n%s%s\n\nIf you did not ask for a password reset, you can ignore this email.\n",
+			user.FirstName, reset.ExpiresAt.Format(time.RFC1123), s.config.Password.ResetURL, url.QueryEscape(token)),
+	})
+
+	s.securityEvents.Record(ctx, user.ID, entity.SecurityEventPasswordResetRequest, nil)
+
+	return nil
+}
+
+// RequestMagicLink emails a single-use login link. When the link is bound to
+// the requesting device, the returned device token must be presented with
+// it. Unknown, inactive and erased accounts, and accounts over the send
+// limits, succeed silently, so the result never reveals whether an email is
+// registered.
+func (s *authService) RequestMagicLink(ctx context.Context, email, ip string, bindDevice bool) (string, error) {
+	var deviceToken string
+	switch s.config.MagicLink.DeviceBinding {
+	case entity.MagicLinkBindingRequired:
+		bindDevice = true
+	case entity.MagicLinkBindingOff:
+		bindDevice = false
+	}
+	if bindDevice {
+		token, err := utils.GenerateSecureToken(32)
+		if err != nil {
+			return "", err
+		}
+		deviceToken = token
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, entity.ErrUserNotFound) {
+			return deviceToken, nil
+		}
+		return "", err
+	}
+
+	if !user.IsActive() || user.AnonymizedAt != nil {
+		return deviceToken, nil
+	}
+
+	now := time.Now()
+
+	latest, err := s.authRepo.GetLatestMagicLink(ctx, user.ID)
+	if err != nil && !errors.Is(err, entity.ErrMagicLinkNotFound) {
+		return "", err
+	}
+	if latest != nil && now.Sub(latest.CreatedAt) < s.config.MagicLink.ResendInterval {
+		return deviceToken, nil
+	}
+
+	sent, err := s.authRepo.CountMagicLinksSince(ctx, user.ID, now.Add(-time.Hour))
+	if err != nil {
+		return "", err
+	}
+	if sent >= int64(s.config.MagicLink.MaxPerHour) {
+		return deviceToken, nil
+	}
+
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.authRepo.InvalidateMagicLinks(ctx, user.ID); err != nil {
+		return "", err
+	}
+
+	link := &entity.MagicLink{
+		UserID:    user.ID,
+		Token:     utils.HashToken(token),
+		IPAddress: ip,
+		ExpiresAt: now.Add(s.config.MagicLink.TTL),
+		CreatedAt: now,
+	}
+	if deviceToken != "" {
+		link.DeviceHash = utils.HashToken(deviceToken)
+	}
+
+	if err := s.authRepo.CreateMagicLink(ctx, link); err != nil {
+		return "", err
+	}
+
+	mailer.SendInBackground(s.mailer, mailer.Message{
+		To:      user.Email,
+		Subject: "Your login link",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to log in. It expires at %s and can be used once.\n\n%s%s\n\nIf you did not ask to log in, you can ignore this email.\n",
+			user.FirstName, link.ExpiresAt.Format(time.RFC1123), s.config.MagicLink.URL, url.QueryEscape(token)),
+	})
+
+	s.securityEvents.Record(ctx, user.ID, entity.SecurityEventMagicLinkRequested, map[string]interface{}{
+		"ip_address":   ip,
+		"device_bound": link.IsDeviceBound(),
+	})
+
+	return deviceToken, nil
+}
+
+// LoginWithMagicLink logs a user in with an emailed login link. The session
+// is then issued like a password login, so users with TFA enabled get a TFA
+// challenge.
+func (s *authService) LoginWithMagicLink(ctx context.Context, token, deviceToken, ip string) (*entity.User, *entity.AuthSession, *entity.LoginChallenge, error) {
+	tokenHash := utils.HashToken(token)
+
+	link, err := s.authRepo.GetMagicLinkByToken(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, entity.ErrMagicLinkNotFound) {
+			return nil, nil, nil, entity.ErrInvalidMagicLink
+		}
+		return nil, nil, nil, err
+	}
+
+	if !link.IsValid() {
+		return nil, nil, nil, entity.ErrInvalidMagicLink
+	}
+
+	if link.IsDeviceBound() && subtle.ConstantTimeCompare([]byte(utils.HashToken(deviceToken)), []byte(link.DeviceHash)) != 1 {
+		return nil, nil, nil, entity.ErrMagicLinkDeviceMismatch
+	}
+	if s.config.MagicLink.BindIP && link.IPAddress != ip {
+		return nil, nil, nil, entity.ErrMagicLinkDeviceMismatch
+	}
+
+	user, err := s.userRepo.GetByID(ctx, link.UserID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if !user.IsActive() {
+		return nil, nil, nil, entity.ErrAccountInactive
+	}
+
+	// Claim the link so it works only once
+	if err := s.authRepo.MarkMagicLinkUsed(ctx, tokenHash); err != nil {
+		if errors.Is(err, entity.ErrMagicLinkNotFound) {
+			return nil, nil, nil, entity.ErrInvalidMagicLink
+		}
+		return nil, nil, nil, err
+	}
+
+	return s.CompleteLogin(ctx, user, entity.AuthMethodMagicLink)
+}
+
+// createPasswordReset stores a new reset token valid for ttl and returns the
+// plaintext token; only its hash is stored. Earlier unused tokens of the user
+// are invalidated.
+func (s *authService) createPasswordReset(ctx context.Context, userID uint, ttl time.Duration) (string, *entity.PasswordReset, error) {
 	// Generate reset token
 	token, err := utils.GenerateSecureToken(32)
 	if err != nil {
-		return err
+		return "", nil, err
+	}
+
+	if err := s.authRepo.InvalidatePasswordResets(ctx, userID); err != nil {
+		return "", nil, err
 	}
 
 	// Create password reset
 	reset := &entity.PasswordReset{
-		UserID:    user.ID,
-		Token:     token,
-		ExpiresAt: time.Now().Add(24 * time.Hour), // 24 hours
+		UserID:    userID,
+		Token:     utils.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
 		CreatedAt: time.Now(),
 	}
 
-	return s.authRepo.CreatePasswordReset(ctx, reset)
+	if err := s.authRepo.CreatePasswordReset(ctx, reset); err != nil {
+		return "", nil, err
+	}
+
+	return token, reset, nil
 }
 
-// ResetPassword resets user password
+// ResetPassword resets user password, signs the user out everywhere and
+// notifies them by email
 func (s *authService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	tokenHash := utils.HashToken(token)
+
 	// Get password reset
-	reset, err := s.authRepo.GetPasswordResetByToken(ctx, token)
+	reset, err := s.authRepo.GetPasswordResetByToken(ctx, tokenHash)
 	if err != nil {
-		return errors.New("invalid reset token")
+		if errors.Is(err, entity.ErrPasswordResetNotFound) {
+			return entity.ErrInvalidResetToken
+		}
+		return err
 	}
 
 	// Check if reset is valid
 	if !reset.IsValid() {
-		return errors.New("reset token expired or already used")
+		return entity.ErrResetTokenExpired
+	}
+
+	if err := s.passwordService.CheckCandidate(ctx, "new_password", newPassword); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, reset.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.passwordService.CheckReuse(ctx, user, "new_password", newPassword); err != nil {
+		return err
 	}
 
 	// Hash new password
@@ -200,78 +802,80 @@ func (s *authService) ResetPassword(ctx context.Context, token, newPassword stri
 		return err
 	}
 
-	// Update user password
-	user, err := s.userRepo.GetByID(ctx, reset.UserID)
-	if err != nil {
-		return errors.New("user not found")
+	// Claim the token before changing anything, so it works only once
+	if err := s.authRepo.MarkPasswordResetUsed(ctx, tokenHash); err != nil {
+		if errors.Is(err, entity.ErrPasswordResetNotFound) {
+			return entity.ErrInvalidResetToken
+		}
+		return err
 	}
 
+	// Update user password
+	now := time.Now()
 	user.Password = hashedPassword
-	user.UpdatedAt = time.Now()
+	user.PasswordChangedAt = &now
+	user.UpdatedAt = now
 
 	err = s.userRepo.Update(ctx, user)
 	if err != nil {
 		return err
 	}
 
-	// Mark reset as used
-	return s.authRepo.MarkPasswordResetUsed(ctx, token)
-}
-
-// CreateTFACode creates a TFA code for user
-func (s *authService) CreateTFACode(ctx context.Context, userID uint) error {
-	// Generate TFA code
-	code := utils.GenerateTFACode()
+	s.passwordService.Remember(ctx, user.ID, hashedPassword)
 
-	// Create TFA code
-	tfaCode := &entity.TFACode{
-		UserID:    userID,
-		Code:      code,
-		ExpiresAt: time.Now().Add(5 * time.Minute), // 5 minutes
-		CreatedAt: time.Now(),
+	// Whoever knew the old password must not stay signed in
+	if err := s.authRepo.DeleteSessionsByUserID(ctx, user.ID); err != nil {
+		return err
 	}
 
-	return s.authRepo.CreateTFACode(ctx, tfaCode)
+	s.securityEvents.Record(ctx, user.ID, entity.SecurityEventPasswordReset, nil)
+
+	mailer.SendInBackground(s.mailer, mailer.Message{
+		To:      user.Email,
+		Subject: "Your password was changed",
+		Body: fmt.Sprintf("Hi %s,\n\nThe password of your account was reset at %s and all sessions were signed out.\n\nIf this was not you, reset your password again right away and contact support.\n",
+			user.FirstName, now.Format(time.RFC1123)),
+	})
+
+	return nil
 }
 
-// VerifyTFACode verifies a TFA code
-func (s *authService) VerifyTFACode(ctx context.Context, userID uint, code string) error {
-	tfaCode, err := s.authRepo.GetTFACodeByCode(ctx, code)
+// CreateTFACode issues a sign-in code for user and delivers it over the
+// requested channel, email by default
+func (s *authService) CreateTFACode(ctx context.Context, userID uint, channel string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return errors.New("invalid TFA code")
-	}
-
-	// Check if code belongs to user
-	if tfaCode.UserID != userID {
-		return errors.New("invalid TFA code")
+		return err
 	}
 
-	// Check if code is valid
-	if !tfaCode.IsValid() {
-		return errors.New("TFA code expired or already used")
-	}
+	_, err = s.codes.Issue(ctx, user, entity.TFACodePurposeLogin, channel)
+	return err
+}
 
-	// Mark code as used
-	return s.authRepo.MarkTFACodeUsed(ctx, code)
+// VerifyTFACode verifies a sign-in code issued to user
+func (s *authService) VerifyTFACode(ctx context.Context, userID uint, code string) error {
+	_, err := s.codes.Verify(ctx, userID, entity.TFACodePurposeLogin, code)
+	return err
 }
 
-// EnableTFA enables TFA for user
-func (s *authService) EnableTFA(ctx context.Context, userID uint) (*entity.User, error) {
+// EnableTFA enables TFA for user and returns the otpauth:// URI for
+// authenticator apps
+func (s *authService) EnableTFA(ctx context.Context, userID uint) (*entity.User, string, error) {
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, "", err
 	}
 
-	// Generate TFA secret
-	secret, err := utils.GenerateSecureToken(32)
+	// Generate TOTP secret
+	secret, err := totp.GenerateSecret()
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	// Generate backup codes
 	backupCodes, err := utils.GenerateBackupCodes(8)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	// Enable TFA
@@ -280,36 +884,49 @@ func (s *authService) EnableTFA(ctx context.Context, userID uint) (*entity.User,
 
 	err = s.userRepo.Update(ctx, user)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return user, nil
+	s.securityEvents.Record(ctx, userID, entity.SecurityEventTFAEnabled, nil)
+
+	return user, totp.URL(secret, user.Email, s.totpOptions()), nil
 }
 
 // DisableTFA disables TFA for user
 func (s *authService) DisableTFA(ctx context.Context, userID uint) error {
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return errors.New("user not found")
+		return err
 	}
 
 	// Disable TFA
 	user.DisableTFA()
 	user.UpdatedAt = time.Now()
 
-	return s.userRepo.Update(ctx, user)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	s.securityEvents.Record(ctx, userID, entity.SecurityEventTFADisabled, nil)
+
+	return nil
 }
 
 // VerifyTFA verifies TFA for login
 func (s *authService) VerifyTFA(ctx context.Context, userID uint, code string) error {
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return errors.New("user not found")
+		return err
 	}
 
 	// Check if TFA is enabled
 	if !user.IsTFAEnabled() {
-		return errors.New("TFA not enabled")
+		return entity.ErrTFANotEnabled
+	}
+
+	// Accept authenticator app codes before delivered codes
+	if s.verifyTOTP(user, code) {
+		return nil
 	}
 
 	// Verify TFA code
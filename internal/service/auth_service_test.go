@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/pkg/totp"
+)
+
+func (r *fakeAuthRepo) CreatePendingLogin(ctx context.Context, login *entity.PendingLogin) error {
+	login.ID = uint(len(r.pending) + 1)
+	r.pending = append(r.pending, login)
+	return nil
+}
+
+func (r *fakeAuthRepo) GetPendingLoginByToken(ctx context.Context, tokenHash string) (*entity.PendingLogin, error) {
+	for _, login := range r.pending {
+		if login.Token == tokenHash {
+			return login, nil
+		}
+	}
+	return nil, entity.ErrPendingLoginNotFound
+}
+
+func (r *fakeAuthRepo) MarkPendingLoginUsed(ctx context.Context, tokenHash string) error {
+	login, err := r.GetPendingLoginByToken(ctx, tokenHash)
+	if err != nil || !login.IsValid() {
+		return entity.ErrPendingLoginNotFound
+	}
+	login.Used = true
+	return nil
+}
+
+func (s *fakeUserService) UpdateLastLogin(ctx context.Context, userID uint) error {
+	return nil
+}
+
+// fakeCodes accepts a single code for every user and channel
+type fakeCodes struct {
+	service.OneTimeCodeService
+	code    string
+	channel string
+	issued  int
+}
+
+func (c *fakeCodes) Issue(ctx context.Context, user *entity.User, purpose, channel string) (*entity.TFACode, error) {
+	c.issued++
+	c.channel = channel
+	return &entity.TFACode{UserID: user.ID, Purpose: purpose, Channel: channel}, nil
+}
+
+func (c *fakeCodes) Verify(ctx context.Context, userID uint, purpose, code string) (*entity.TFACode, error) {
+	if purpose != entity.TFACodePurposeLogin || code != c.code {
+		return nil, entity.ErrInvalidTFACode
+	}
+	return &entity.TFACode{UserID: userID, Purpose: purpose, Channel: c.channel}, nil
+}
+
+func TestTFAGate(t *testing.T) {
+	const plainID, tfaID = 1, 2
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+	tfaUser := &entity.User{ID: tfaID, Email: "tfa@example.com", Role: "user", Status: "active"}
+	tfaUser.EnableTFA(secret, nil)
+	users := &fakeUserRepo{users: map[uint]*entity.User{
+		plainID: {ID: plainID, Email: "plain@example.com", Role: "user", Status: "active"},
+		tfaID:   tfaUser,
+	}}
+
+	cfg := &config.Config{
+		JWT: config.JWTConfig{Secret: "secret", Expiry: time.Hour},
+		TFA: config.TFAConfig{Issuer: "Test", Algorithm: "SHA1", Digits: 6, Period: 30, ChallengeTTL: time.Minute},
+	}
+	newService := func() (*authService, *fakeCodes) {
+		codes := &fakeCodes{code: "424242"}
+		svc := NewAuthService(users, &fakeAuthRepo{}, &fakeUserService{repo: users}, nil, &fakeSecurityEvents{}, nil, codes, cfg)
+		return svc.(*authService), codes
+	}
+	ctx := context.Background()
+
+	t.Run("one factor without TFA starts a session", func(t *testing.T) {
+		svc, _ := newService()
+		_, session, challenge, err := svc.CompleteLogin(ctx, users.users[plainID], entity.AuthMethodPassword)
+		if err != nil || challenge != nil || session == nil {
+			t.Fatalf("CompleteLogin = %v, %v, %v; want a session", session, challenge, err)
+		}
+	})
+
+	t.Run("two factors pass the gate", func(t *testing.T) {
+		svc, _ := newService()
+		_, session, challenge, err := svc.CompleteLogin(ctx, tfaUser, entity.AuthMethodPasskey, entity.AuthMethodMFA)
+		if err != nil || challenge != nil || session == nil {
+			t.Fatalf("CompleteLogin = %v, %v, %v; want a session", session, challenge, err)
+		}
+	})
+
+	// Every first factor is held by the same challenge, and every second
+	// factor completes it
+	firstFactors := []string{entity.AuthMethodPassword, entity.AuthMethodMagicLink, entity.AuthMethodOIDC, entity.AuthMethodPasskey}
+	secondFactors := []struct {
+		name       string
+		complete   func(svc *authService, codes *fakeCodes, token string) (*entity.AuthSession, error)
+		wantMethod string
+	}{
+		{
+			name: "authenticator app",
+			complete: func(svc *authService, codes *fakeCodes, token string) (*entity.AuthSession, error) {
+				code, err := totp.Generate(secret, time.Now(), svc.totpOptions())
+				if err != nil {
+					return nil, err
+				}
+				_, session, _, err := svc.LoginWithTFACode(ctx, token, code)
+				return session, err
+			},
+			wantMethod: entity.AuthMethodOTP,
+		},
+		{
+			name: "emailed code",
+			complete: func(svc *authService, codes *fakeCodes, token string) (*entity.AuthSession, error) {
+				if err := svc.CreateTFALoginCode(ctx, token, entity.TFAChannelEmail); err != nil {
+					return nil, err
+				}
+				_, session, _, err := svc.LoginWithTFACode(ctx, token, codes.code)
+				return session, err
+			},
+			wantMethod: entity.AuthMethodEmail,
+		},
+		{
+			name: "SMS code",
+			complete: func(svc *authService, codes *fakeCodes, token string) (*entity.AuthSession, error) {
+				if err := svc.CreateTFALoginCode(ctx, token, entity.TFAChannelSMS); err != nil {
+					return nil, err
+				}
+				_, session, _, err := svc.LoginWithTFACode(ctx, token, codes.code)
+				return session, err
+			},
+			wantMethod: entity.AuthMethodSMS,
+		},
+		{
+			name: "passkey",
+			complete: func(svc *authService, codes *fakeCodes, token string) (*entity.AuthSession, error) {
+				_, session, _, err := svc.ContinueLogin(ctx, token, entity.AuthMethodPasskey)
+				return session, err
+			},
+			wantMethod: entity.AuthMethodPasskey,
+		},
+	}
+
+	for _, first := range firstFactors {
+		for _, second := range secondFactors {
+			if first == second.wantMethod {
+				continue
+			}
+			t.Run(first+" then "+second.name, func(t *testing.T) {
+				svc, codes := newService()
+
+				_, session, challenge, err := svc.CompleteLogin(ctx, tfaUser, first)
+				if err != nil {
+					t.Fatalf("CompleteLogin: %v", err)
+				}
+				if session != nil || challenge == nil || challenge.Type != entity.LoginChallengeTFA {
+					t.Fatalf("CompleteLogin = %v, %v; want a TFA challenge", session, challenge)
+				}
+
+				session, err = second.complete(svc, codes, challenge.Token)
+				if err != nil {
+					t.Fatalf("second factor: %v", err)
+				}
+				if want := []string{first, second.wantMethod}; !slices.Equal(session.Methods(), want) {
+					t.Errorf("session methods = %v, want %v", session.Methods(), want)
+				}
+
+				// The challenge works once
+				if _, _, _, err := svc.ContinueLogin(ctx, challenge.Token, entity.AuthMethodPasskey); !errors.Is(err, entity.ErrInvalidTFAChallenge) {
+					t.Errorf("reused challenge error = %v, want %v", err, entity.ErrInvalidTFAChallenge)
+				}
+			})
+		}
+	}
+
+	t.Run("wrong code uses the challenge up", func(t *testing.T) {
+		svc, codes := newService()
+
+		_, _, challenge, err := svc.CompleteLogin(ctx, tfaUser, entity.AuthMethodPassword)
+		if err != nil {
+			t.Fatalf("CompleteLogin: %v", err)
+		}
+		if _, _, _, err := svc.LoginWithTFACode(ctx, challenge.Token, "000000"); !errors.Is(err, entity.ErrInvalidTFACode) {
+			t.Errorf("wrong code error = %v, want %v", err, entity.ErrInvalidTFACode)
+		}
+		if _, _, _, err := svc.LoginWithTFACode(ctx, challenge.Token, codes.code); !errors.Is(err, entity.ErrInvalidTFAChallenge) {
+			t.Errorf("retry error = %v, want %v", err, entity.ErrInvalidTFAChallenge)
+		}
+	})
+}
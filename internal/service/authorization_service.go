@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+)
+
+type authorizationService struct {
+	rbacService service.RBACService
+	policies    map[string]service.Policy
+}
+
+// NewAuthorizationService creates a new authorization service. Later
+// policies for the same resource type replace earlier ones.
+func NewAuthorizationService(rbacService service.RBACService, policies ...service.Policy) service.AuthorizationService {
+	s := &authorizationService{
+		rbacService: rbacService,
+		policies:    make(map[string]service.Policy, len(policies)),
+	}
+	for _, policy := range policies {
+		s.policies[policy.ResourceType] = policy
+	}
+	return s
+}
+
+// Subject builds the subject of an authenticated user from their roles and
+// permissions
+func (s *authorizationService) Subject(ctx context.Context, userID uint) (*entity.Subject, error) {
+	access, err := s.rbacService.GetUserAccess(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Subject{UserID: userID, Access: access}, nil
+}
+
+// Authorize checks an action against the policy of the resource type
+func (s *authorizationService) Authorize(ctx context.Context, subject *entity.Subject, action string, resource entity.Resource) error {
+	if reason := s.evaluate(ctx, subject, action, resource); reason != "" {
+		return entity.ErrForbidden.Wrap(&entity.PolicyDenial{
+			Action:       action,
+			ResourceType: resource.ResourceType(),
+			Reason:       reason,
+		})
+	}
+	return nil
+}
+
+// Can reports whether an action is allowed
+func (s *authorizationService) Can(ctx context.Context, subject *entity.Subject, action string, resource entity.Resource) bool {
+	return s.evaluate(ctx, subject, action, resource) == ""
+}
+
+// evaluate returns why an action is denied, or "" when it is allowed
+func (s *authorizationService) evaluate(ctx context.Context, subject *entity.Subject, action string, resource entity.Resource) string {
+	if subject == nil {
+		return "no subject"
+	}
+
+	policy, ok := s.policies[resource.ResourceType()]
+	if !ok {
+		return "no policy for resource type"
+	}
+
+	condition, ok := policy.Rules[action]
+	if !ok {
+		return "no rule for action"
+	}
+
+	if !condition(ctx, subject, resource) {
+		return "condition not met"
+	}
+	return ""
+}
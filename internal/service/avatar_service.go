@@ -0,0 +1,176 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/pkg/imaging"
+	"boilerplate-go-fiber-v2/pkg/storage"
+	"boilerplate-go-fiber-v2/pkg/utils"
+)
+
+// allowedAvatarTypes lists accepted upload MIME types, sniffed from content
+var allowedAvatarTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+type avatarService struct {
+	userRepo repository.UserRepository
+	storage  storage.Storage
+	config   *config.Config
+}
+
+// NewAvatarService creates a new avatar service
+func NewAvatarService(userRepo repository.UserRepository, storage storage.Storage, config *config.Config) service.AvatarService {
+	return &avatarService{
+		userRepo: userRepo,
+		storage:  storage,
+		config:   config,
+	}
+}
+
+// Upload validates an image, stores its resized variants and returns their URLs
+func (s *avatarService) Upload(ctx context.Context, userID uint, src io.Reader) (map[string]string, error) {
+	// Read one byte past the limit to detect overflow without buffering the rest
+	data, err := io.ReadAll(io.LimitReader(src, s.config.Avatar.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.config.Avatar.MaxSize {
+		return nil, entity.ErrAvatarTooLarge.WithMessage(fmt.Sprintf("Avatar must not exceed %d bytes", s.config.Avatar.MaxSize))
+	}
+
+	// Sniff the content instead of trusting the client's Content-Type
+	if !allowedAvatarTypes[http.DetectContentType(data)] {
+		return nil, entity.ErrAvatarInvalidType
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	img, _, err := imaging.Decode(data, s.config.Avatar.MaxDimension)
+	if err != nil {
+		if errors.Is(err, imaging.ErrTooLarge) {
+			return nil, entity.ErrAvatarTooLarge.WithMessage(fmt.Sprintf("Avatar must not exceed %dx%d pixels", s.config.Avatar.MaxDimension, s.config.Avatar.MaxDimension))
+		}
+		return nil, entity.ErrAvatarInvalidImage.Wrap(err)
+	}
+
+	// Keep transparency when the source has it
+	format, extension, contentType := "jpeg", "jpg", "image/jpeg"
+	if imaging.HasAlpha(img) {
+		format, extension, contentType = "png", "png", "image/png"
+	}
+
+	// A fresh prefix per upload keeps cached URLs of the old avatar from serving the new one
+	token, err := utils.GenerateSecureToken(12)
+	if err != nil {
+		return nil, err
+	}
+	prefix := fmt.Sprintf("avatars/%d/%s", userID, token)
+
+	variants := make(map[string]string, len(s.config.Avatar.Sizes))
+	for _, size := range s.config.Avatar.Sizes {
+		var buf bytes.Buffer
+		if err := imaging.Encode(&buf, imaging.Square(img, size), format); err != nil {
+			s.deleteVariants(ctx, variants)
+			return nil, err
+		}
+
+		key := fmt.Sprintf("%s/%d.%s", prefix, size, extension)
+		if err := s.storage.Put(ctx, key, bytes.NewReader(buf.Bytes()), int64(buf.Len()), contentType); err != nil {
+			s.deleteVariants(ctx, variants)
+			return nil, err
+		}
+		variants[strconv.Itoa(size)] = key
+	}
+
+	previous := user.AvatarVariants
+	user.AvatarVariants = variants
+	user.UpdatedAt = time.Now()
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		s.deleteVariants(ctx, variants)
+		return nil, err
+	}
+
+	s.deleteVariants(ctx, previous)
+
+	return s.urls(ctx, variants)
+}
+
+// GetURLs returns fresh URLs of the user's avatar variants
+func (s *avatarService) GetURLs(ctx context.Context, userID uint) (map[string]string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.HasUploadedAvatar() {
+		return nil, entity.ErrAvatarNotFound
+	}
+
+	return s.urls(ctx, user.AvatarVariants)
+}
+
+// Delete removes the user's uploaded avatar
+func (s *avatarService) Delete(ctx context.Context, userID uint) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.HasUploadedAvatar() {
+		return nil
+	}
+
+	previous := user.AvatarVariants
+	user.AvatarVariants = nil
+	user.UpdatedAt = time.Now()
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	s.deleteVariants(ctx, previous)
+
+	return nil
+}
+
+// urls resolves variant storage keys to URLs
+func (s *avatarService) urls(ctx context.Context, variants map[string]string) (map[string]string, error) {
+	urls := make(map[string]string, len(variants))
+	for size, key := range variants {
+		url, err := s.storage.URL(ctx, key, s.config.Storage.URLExpiry)
+		if err != nil {
+			return nil, err
+		}
+		urls[size] = url
+	}
+	return urls, nil
+}
+
+// deleteVariants removes stored variants; orphaned files are logged, not fatal
+func (s *avatarService) deleteVariants(ctx context.Context, variants map[string]string) {
+	for _, key := range variants {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			log.Printf("Failed to delete avatar variant %s: %v", key, err)
+		}
+	}
+}
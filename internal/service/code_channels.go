@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/pkg/mailer"
+	"boilerplate-go-fiber-v2/pkg/sms"
+)
+
+// codeText describes what a code is for, per purpose
+var codeText = map[string]string{
+	entity.TFACodePurposeLogin:       "sign-in code",
+	entity.TFACodePurposeStepUp:      "confirmation code",
+	entity.TFACodePurposePhoneVerify: "verification code",
+}
+
+func describeCode(d service.CodeDelivery) string {
+	minutes := int(time.Until(d.ExpiresAt).Round(time.Minute).Minutes())
+	return fmt.Sprintf("Your %s is %s. It expires in %d minutes.", codeText[d.Purpose], d.Code, minutes)
+}
+
+type emailCodeChannel struct {
+	mailer mailer.Mailer
+}
+
+// NewEmailCodeChannel creates a channel that emails codes to the account address
+func NewEmailCodeChannel(mailer mailer.Mailer) service.CodeChannel {
+	return &emailCodeChannel{mailer: mailer}
+}
+
+func (c *emailCodeChannel) Name() string {
+	return entity.TFAChannelEmail
+}
+
+func (c *emailCodeChannel) Destination(user *entity.User, purpose string) (string, error) {
+	return user.Email, nil
+}
+
+func (c *emailCodeChannel) Deliver(ctx context.Context, d service.CodeDelivery) error {
+	mailer.SendInBackground(c.mailer, mailer.Message{
+		To:      d.Destination,
+		Subject: "Your " + codeText[d.Purpose],
+		Body: fmt.Sprintf("Hi %s,\n\n%s\n\nIf you did not request it, change your password.\n",
+			d.User.FirstName, describeCode(d)),
+	})
+	return nil
+}
+
+type smsCodeChannel struct {
+	sender sms.SMSSender
+}
+
+// NewSMSCodeChannel creates a channel that texts codes to the verified phone
+// number. Phone verification itself targets the unverified number.
+func NewSMSCodeChannel(sender sms.SMSSender) service.CodeChannel {
+	return &smsCodeChannel{sender: sender}
+}
+
+func (c *smsCodeChannel) Name() string {
+	return entity.TFAChannelSMS
+}
+
+func (c *smsCodeChannel) Destination(user *entity.User, purpose string) (string, error) {
+	if user.Phone == "" {
+		return "", entity.ErrPhoneRequired
+	}
+	if purpose != entity.TFACodePurposePhoneVerify && !user.IsPhoneVerified() {
+		return "", entity.ErrPhoneNotVerified
+	}
+	return user.Phone, nil
+}
+
+func (c *smsCodeChannel) Deliver(ctx context.Context, d service.CodeDelivery) error {
+	return c.sender.Send(ctx, d.Destination, describeCode(d))
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/pkg/mailer"
+	"boilerplate-go-fiber-v2/pkg/utils"
+	"boilerplate-go-fiber-v2/pkg/validator"
+)
+
+type emailChangeService struct {
+	userRepo         repository.UserRepository
+	verificationRepo repository.EmailVerificationRepository
+	authRepo         repository.AuthRepository
+	securityEvents   service.SecurityEventService
+	mailer           mailer.Mailer
+	config           *config.Config
+}
+
+// NewEmailChangeService creates a new email change service
+func NewEmailChangeService(
+	userRepo repository.UserRepository,
+	verificationRepo repository.EmailVerificationRepository,
+	authRepo repository.AuthRepository,
+	securityEvents service.SecurityEventService,
+	mailer mailer.Mailer,
+	config *config.Config,
+) service.EmailChangeService {
+	return &emailChangeService{
+		userRepo:         userRepo,
+		verificationRepo: verificationRepo,
+		authRepo:         authRepo,
+		securityEvents:   securityEvents,
+		mailer:           mailer,
+		config:           config,
+	}
+}
+
+// RequestChange starts an email change. A confirmation link goes to the new
+// address and a cancel link to the current one; the email is only swapped
+// once the new address is confirmed. A newer request replaces a pending one.
+func (s *emailChangeService) RequestChange(ctx context.Context, userID uint, newEmail string) (*entity.EmailVerification, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	newEmail = strings.TrimSpace(newEmail)
+	if strings.EqualFold(newEmail, user.Email) {
+		return nil, entity.ErrEmailUnchanged
+	}
+
+	if err := s.checkAvailable(ctx, user.ID, newEmail); err != nil {
+		return nil, err
+	}
+
+	if err := s.verificationRepo.CancelPending(ctx, user.ID, entity.EmailVerificationPurposeChange); err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	cancelToken, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	verification := &entity.EmailVerification{
+		UserID:        user.ID,
+		Email:         newEmail,
+		Purpose:       entity.EmailVerificationPurposeChange,
+		PreviousEmail: user.Email,
+		Token:         utils.HashToken(token),
+		CancelToken:   utils.HashToken(cancelToken),
+		ExpiresAt:     time.Now().Add(s.config.Email.ChangeTokenTTL),
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	if err := s.verificationRepo.Create(ctx, verification); err != nil {
+		return nil, err
+	}
+
+	expiresAt := verification.ExpiresAt.Format(time.RFC1123)
+	mailer.SendInBackground(s.mailer, mailer.Message{
+		To:      newEmail,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf("Hi %s,\n\nConfirm that you want to use this address for your account. The link expires at %s.\n\n%s%s\n\nIf you did not ask for this change, ignore this email.\n",
+			user.FirstName, expiresAt, s.config.Email.ChangeConfirmURL, url.QueryEscape(token)),
+	})
+	mailer.SendInBackground(s.mailer, mailer.Message{
+		To:      user.Email,
+		Subject: "Your email address is being changed",
+		Body: fmt.Sprintf("Hi %s,\n\nSomeone asked to change the email address of your account to %s.\n\nIf this was not you, cancel the change with the link below. Until %s it also restores this address if the change was already confirmed.\n\n%s%s\n",
+			user.FirstName, newEmail, expiresAt, s.config.Email.ChangeCancelURL, url.QueryEscape(cancelToken)),
+	})
+
+	s.securityEvents.Record(ctx, user.ID, entity.SecurityEventEmailChangeRequested, map[string]interface{}{
+		"new_email": newEmail,
+	})
+
+	return verification, nil
+}
+
+// ConfirmChange swaps the email once the new address is confirmed and signs
+// the user out everywhere
+func (s *emailChangeService) ConfirmChange(ctx context.Context, token string) error {
+	verification, err := s.getChange(ctx, s.verificationRepo.GetByToken, token)
+	if err != nil {
+		return err
+	}
+
+	if !verification.IsValid() {
+		return entity.ErrInvalidEmailChangeToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, verification.UserID)
+	if err != nil {
+		return err
+	}
+
+	// The email changed some other way since the request
+	if user.Email != verification.PreviousEmail {
+		return entity.ErrInvalidEmailChangeToken
+	}
+
+	if err := s.checkAvailable(ctx, user.ID, verification.Email); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	user.Email = verification.Email
+	user.EmailVerifiedAt = &now
+	user.UpdatedAt = now
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	verification.MarkVerified()
+	verification.UpdatedAt = now
+	if err := s.verificationRepo.Update(ctx, verification); err != nil {
+		return err
+	}
+
+	if err := s.authRepo.DeleteSessionsByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	s.securityEvents.Record(ctx, user.ID, entity.SecurityEventEmailChanged, map[string]interface{}{
+		"previous_email": verification.PreviousEmail,
+		"new_email":      verification.Email,
+	})
+
+	return nil
+}
+
+// CancelChange cancels a pending email change. A change that was already
+// confirmed is reverted to the previous address while the token is valid,
+// and the user is signed out everywhere.
+func (s *emailChangeService) CancelChange(ctx context.Context, token string) error {
+	verification, err := s.getChange(ctx, s.verificationRepo.GetByCancelToken, token)
+	if err != nil {
+		return err
+	}
+
+	if verification.IsCancelled() || verification.IsExpired() {
+		return entity.ErrInvalidEmailChangeToken
+	}
+
+	now := time.Now()
+	event := entity.SecurityEventEmailChangeCancelled
+
+	if verification.VerifiedAt != nil {
+		user, err := s.userRepo.GetByID(ctx, verification.UserID)
+		if err != nil {
+			return err
+		}
+
+		// The email changed again since; there is nothing to revert
+		if user.Email != verification.Email {
+			return entity.ErrInvalidEmailChangeToken
+		}
+
+		user.Email = verification.PreviousEmail
+		user.EmailVerifiedAt = &now
+		user.UpdatedAt = now
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+
+		if err := s.authRepo.DeleteSessionsByUserID(ctx, user.ID); err != nil {
+			return err
+		}
+
+		event = entity.SecurityEventEmailChangeReverted
+	}
+
+	verification.MarkCancelled()
+	verification.UpdatedAt = now
+	if err := s.verificationRepo.Update(ctx, verification); err != nil {
+		return err
+	}
+
+	s.securityEvents.Record(ctx, verification.UserID, event, map[string]interface{}{
+		"previous_email": verification.PreviousEmail,
+		"new_email":      verification.Email,
+	})
+
+	return nil
+}
+
+// getChange looks up an email change by one of its tokens
+func (s *emailChangeService) getChange(ctx context.Context, lookup func(context.Context, string) (*entity.EmailVerification, error), token string) (*entity.EmailVerification, error) {
+	verification, err := lookup(ctx, utils.HashToken(token))
+	if err != nil {
+		if errors.Is(err, entity.ErrEmailVerificationNotFound) {
+			return nil, entity.ErrInvalidEmailChangeToken
+		}
+		return nil, err
+	}
+
+	if verification.Purpose != entity.EmailVerificationPurposeChange {
+		return nil, entity.ErrInvalidEmailChangeToken
+	}
+
+	return verification, nil
+}
+
+// checkAvailable reports a taken address as a unique field error on new_email
+func (s *emailChangeService) checkAvailable(ctx context.Context, userID uint, email string) error {
+	exists, err := s.userRepo.ExistsBy(ctx, "email", email, userID)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return entity.ErrUserAlreadyExists.WithFields(
+			validator.NewFieldError("new_email", "unique", "", utils.LanguageFromContext(ctx)))
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/pkg/jwt"
+)
+
+type impersonationService struct {
+	userRepo       repository.UserRepository
+	authRepo       repository.AuthRepository
+	rbacService    service.RBACService
+	securityEvents service.SecurityEventService
+	config         *config.Config
+}
+
+// NewImpersonationService creates a new impersonation service
+func NewImpersonationService(userRepo repository.UserRepository, authRepo repository.AuthRepository, rbacService service.RBACService, securityEvents service.SecurityEventService, config *config.Config) service.ImpersonationService {
+	return &impersonationService{
+		userRepo:       userRepo,
+		authRepo:       authRepo,
+		rbacService:    rbacService,
+		securityEvents: securityEvents,
+		config:         config,
+	}
+}
+
+// Start issues an impersonation session. Admins, and users holding any
+// permission the impersonator lacks, cannot be impersonated, so impersonation
+// never grants more than the impersonator already holds. The
+// session has no refresh token and no authentication time, so it expires
+// after the configured TTL and never passes RequireRecentAuth.
+func (s *impersonationService) Start(ctx context.Context, actorID, userID uint, reason string) (*entity.User, *entity.AuthSession, error) {
+	if actorID == userID {
+		return nil, nil, entity.ErrCannotImpersonate
+	}
+
+	actor, err := s.userRepo.GetByID(ctx, actorID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if user.IsAdmin() {
+		return nil, nil, entity.ErrCannotImpersonate
+	}
+	if err := s.requireWiderAccess(ctx, actor.ID, user.ID); err != nil {
+		return nil, nil, err
+	}
+	if !user.IsActive() {
+		return nil, nil, entity.ErrAccountInactive
+	}
+
+	tokenID, err := randomHex(16)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accessToken, err := jwt.GenerateImpersonationToken(user.ID, user.Email, user.Role, jwt.Actor{
+		Subject: strconv.FormatUint(uint64(actor.ID), 10),
+		UserID:  actor.ID,
+		Email:   actor.Email,
+	}, tokenID, s.config.JWT.Secret, s.config.Impersonation.TTL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	session := &entity.AuthSession{
+		UserID:    user.ID,
+		Token:     accessToken,
+		ActorID:   &actor.ID,
+		ExpiresAt: now.Add(s.config.Impersonation.TTL),
+		CreatedAt: now,
+	}
+
+	if err := s.authRepo.CreateSession(ctx, session); err != nil {
+		return nil, nil, err
+	}
+
+	// Both accounts keep a record: the user's so they can see who acted on
+	// their behalf, the admin's so their own activity is traceable
+	s.securityEvents.Record(ctx, user.ID, entity.SecurityEventImpersonationStarted, map[string]interface{}{
+		"session_id": session.ID,
+		"actor_id":   actor.ID,
+		"reason":     reason,
+	})
+	s.securityEvents.Record(ctx, actor.ID, entity.SecurityEventImpersonationStarted, map[string]interface{}{
+		"session_id": session.ID,
+		"user_id":    user.ID,
+		"reason":     reason,
+	})
+
+	return user, session, nil
+}
+
+// requireWiderAccess checks that the actor holds every permission the user
+// holds through RBAC roles, which users.role does not reflect
+func (s *impersonationService) requireWiderAccess(ctx context.Context, actorID, userID uint) error {
+	actorAccess, err := s.rbacService.GetUserAccess(ctx, actorID)
+	if err != nil {
+		return err
+	}
+	userAccess, err := s.rbacService.GetUserAccess(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, permission := range userAccess.Permissions {
+		if !actorAccess.HasPermission(permission) {
+			return entity.ErrCannotImpersonate
+		}
+	}
+	return nil
+}
+
+// ListByActor lists the active impersonation sessions an admin holds
+func (s *impersonationService) ListByActor(ctx context.Context, actorID uint) ([]*entity.AuthSession, error) {
+	sessions, err := s.authRepo.GetSessionsByActorID(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	return activeImpersonations(sessions), nil
+}
+
+// ListByUser lists the active impersonation sessions on a user's account
+func (s *impersonationService) ListByUser(ctx context.Context, userID uint) ([]*entity.AuthSession, error) {
+	sessions, err := s.authRepo.GetSessionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return activeImpersonations(sessions), nil
+}
+
+// End terminates an impersonation session on behalf of either the admin
+// holding it or the impersonated user
+func (s *impersonationService) End(ctx context.Context, partyID, sessionID uint) error {
+	session, err := s.authRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, entity.ErrSessionNotFound) {
+			return entity.ErrImpersonationNotFound
+		}
+		return err
+	}
+
+	if !session.IsImpersonation() || (session.UserID != partyID && *session.ActorID != partyID) {
+		return entity.ErrImpersonationNotFound
+	}
+
+	if err := s.authRepo.DeleteSession(ctx, session.Token); err != nil {
+		return err
+	}
+
+	recordImpersonationEnded(ctx, s.securityEvents, session, partyID)
+	return nil
+}
+
+// recordImpersonationEnded records the end of an impersonation session on
+// both accounts
+func recordImpersonationEnded(ctx context.Context, securityEvents service.SecurityEventService, session *entity.AuthSession, endedBy uint) {
+	securityEvents.Record(ctx, session.UserID, entity.SecurityEventImpersonationEnded, map[string]interface{}{
+		"session_id": session.ID,
+		"actor_id":   *session.ActorID,
+		"ended_by":   endedBy,
+	})
+	securityEvents.Record(ctx, *session.ActorID, entity.SecurityEventImpersonationEnded, map[string]interface{}{
+		"session_id": session.ID,
+		"user_id":    session.UserID,
+		"ended_by":   endedBy,
+	})
+}
+
+// activeImpersonations keeps the unexpired impersonation sessions
+func activeImpersonations(sessions []*entity.AuthSession) []*entity.AuthSession {
+	active := make([]*entity.AuthSession, 0, len(sessions))
+	for _, session := range sessions {
+		if session.IsImpersonation() && !session.IsExpired() {
+			active = append(active, session)
+		}
+	}
+	return active
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+)
+
+type fakeRBACService struct {
+	service.RBACService
+	access map[uint]*entity.UserAccess
+}
+
+func (s *fakeRBACService) GetUserAccess(ctx context.Context, userID uint) (*entity.UserAccess, error) {
+	if access, ok := s.access[userID]; ok {
+		return access, nil
+	}
+	return &entity.UserAccess{Roles: []string{}, Permissions: []string{}}, nil
+}
+
+type fakeAuthRepo struct {
+	repository.AuthRepository
+	sessions []*entity.AuthSession
+	pending  []*entity.PendingLogin
+}
+
+func (r *fakeAuthRepo) CreateSession(ctx context.Context, session *entity.AuthSession) error {
+	session.ID = uint(len(r.sessions) + 1)
+	r.sessions = append(r.sessions, session)
+	return nil
+}
+
+func TestImpersonationRequiresWiderAccess(t *testing.T) {
+	const supportID, customerID, agentID, roleAdminID = 1, 2, 3, 4
+
+	users := &fakeUserRepo{users: map[uint]*entity.User{
+		supportID:   {ID: supportID, Email: "support@example.com", Role: "user", Status: "active"},
+		customerID:  {ID: customerID, Email: "customer@example.com", Role: "user", Status: "active"},
+		agentID:     {ID: agentID, Email: "agent@example.com", Role: "user", Status: "active"},
+		roleAdminID: {ID: roleAdminID, Email: "role-admin@example.com", Role: "user", Status: "active"},
+	}}
+	// Roles granted through RBAC only; users.role stays "user" for everyone
+	rbac := &fakeRBACService{access: map[uint]*entity.UserAccess{
+		supportID:   {Roles: []string{"support"}, Permissions: []string{"users:impersonate", "users:read", "orders:read"}},
+		agentID:     {Roles: []string{"agent"}, Permissions: []string{"orders:refund"}},
+		roleAdminID: {Roles: []string{"admin"}, Permissions: []string{entity.PermissionAll}},
+	}}
+	cfg := &config.Config{
+		JWT:           config.JWTConfig{Secret: "secret"},
+		Impersonation: config.ImpersonationConfig{TTL: time.Minute},
+	}
+	svc := NewImpersonationService(users, &fakeAuthRepo{}, rbac, &fakeSecurityEvents{}, cfg)
+
+	tests := []struct {
+		name    string
+		userID  uint
+		wantErr error
+	}{
+		{name: "user without extra permissions", userID: customerID},
+		{name: "user with a permission the actor lacks", userID: agentID, wantErr: entity.ErrCannotImpersonate},
+		{name: "admin through RBAC only", userID: roleAdminID, wantErr: entity.ErrCannotImpersonate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, session, err := svc.Start(context.Background(), supportID, tt.userID, "ticket")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Start error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+			if session.ActorID == nil || *session.ActorID != supportID {
+				t.Errorf("session actor = %v, want %d", session.ActorID, supportID)
+			}
+		})
+	}
+}
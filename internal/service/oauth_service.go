@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/pkg/jwt"
+	"boilerplate-go-fiber-v2/pkg/utils"
+)
+
+type oauthService struct {
+	clientRepo     repository.OAuthClientRepository
+	authRepo       repository.AuthRepository
+	securityEvents service.SecurityEventService
+	config         *config.Config
+}
+
+// NewOAuthService creates a new OAuth service
+func NewOAuthService(
+	clientRepo repository.OAuthClientRepository,
+	authRepo repository.AuthRepository,
+	securityEvents service.SecurityEventService,
+	config *config.Config,
+) service.OAuthService {
+	return &oauthService{
+		clientRepo:     clientRepo,
+		authRepo:       authRepo,
+		securityEvents: securityEvents,
+		config:         config,
+	}
+}
+
+// IssueToken implements the client_credentials grant (RFC 6749 section 4.4).
+// The token is a JWT recorded in auth_sessions, so it can be introspected
+// and revoked like a user session.
+func (s *oauthService) IssueToken(ctx context.Context, grantType, clientID, clientSecret, scope string) (*entity.AuthSession, error) {
+	if grantType != entity.GrantTypeClientCredentials {
+		return nil, entity.ErrUnsupportedGrantType
+	}
+
+	client, err := s.authenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes, ok := client.GrantScopes(scope)
+	if !ok {
+		return nil, entity.ErrInvalidScope
+	}
+	grantedScope := strings.Join(scopes, " ")
+
+	tokenID, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := jwt.GenerateClientToken(client.ClientID, grantedScope, tokenID, s.config.JWT.Secret, s.config.OAuth.TokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &entity.AuthSession{
+		Token:     accessToken,
+		ClientID:  &client.ID,
+		Scope:     grantedScope,
+		AuthTime:  now,
+		ExpiresAt: now.Add(s.config.OAuth.TokenTTL),
+	}
+
+	if err := s.authRepo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	if err := s.clientRepo.TouchLastUsed(ctx, client.ID); err != nil {
+		log.Printf("Failed to record usage of OAuth client %d: %v", client.ID, err)
+	}
+
+	return session, nil
+}
+
+// Introspect describes a token to an authenticated client (RFC 7662).
+// Anything that is not a live access token is reported as inactive.
+func (s *oauthService) Introspect(ctx context.Context, clientID, clientSecret, token string) (*entity.TokenIntrospection, error) {
+	if _, err := s.authenticateClient(ctx, clientID, clientSecret); err != nil {
+		return nil, err
+	}
+
+	inactive := &entity.TokenIntrospection{Active: false}
+
+	claims, err := jwt.ValidateToken(token, s.config.JWT.Secret)
+	if err != nil {
+		return inactive, nil
+	}
+
+	session, err := s.authRepo.GetSessionByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, entity.ErrSessionNotFound) {
+			return inactive, nil
+		}
+		return nil, err
+	}
+
+	if !session.IsValid() {
+		return inactive, nil
+	}
+
+	introspection := &entity.TokenIntrospection{
+		Active:    true,
+		TokenType: "Bearer",
+		ExpiresAt: session.ExpiresAt,
+	}
+	if claims.IssuedAt != nil {
+		introspection.IssuedAt = claims.IssuedAt.Time
+	}
+
+	if session.IsClientSession() {
+		introspection.ClientID = claims.ClientID
+		introspection.Subject = claims.ClientID
+		introspection.Scope = session.Scope
+	} else {
+		introspection.Subject = strconv.FormatUint(uint64(session.UserID), 10)
+		introspection.Username = claims.Email
+	}
+
+	return introspection, nil
+}
+
+// Revoke revokes a token issued to the calling client (RFC 7009). Unknown
+// tokens and tokens of other clients are ignored, as the RFC requires.
+// Client tokens have no refresh token, so the hint never narrows the search.
+func (s *oauthService) Revoke(ctx context.Context, clientID, clientSecret, token, tokenTypeHint string) error {
+	client, err := s.authenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return err
+	}
+
+	session, err := s.authRepo.GetSessionByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, entity.ErrSessionNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if session.ClientID == nil || *session.ClientID != client.ID {
+		return nil
+	}
+
+	return s.authRepo.DeleteSession(ctx, token)
+}
+
+// CreateClient registers a client and returns its secret, which is never
+// shown again
+func (s *oauthService) CreateClient(ctx context.Context, actorID uint, name string, scopes []string) (*entity.OAuthClient, string, error) {
+	clientID, err := randomHex(16)
+	if err != nil {
+		return nil, "", err
+	}
+
+	secret, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+	secret = strings.TrimRight(secret, "=")
+
+	client := &entity.OAuthClient{
+		ClientID:   clientID,
+		Name:       name,
+		SecretHash: utils.HashToken(secret),
+		Scopes:     scopes,
+		CreatedBy:  actorID,
+	}
+
+	if err := s.clientRepo.Create(ctx, client); err != nil {
+		return nil, "", err
+	}
+
+	s.securityEvents.Record(ctx, actorID, entity.SecurityEventOAuthClientCreated, map[string]interface{}{
+		"client_id": client.ClientID,
+		"scopes":    client.Scopes,
+	})
+
+	return client, secret, nil
+}
+
+// ListClients lists all OAuth clients
+func (s *oauthService) ListClients(ctx context.Context) ([]*entity.OAuthClient, error) {
+	return s.clientRepo.List(ctx)
+}
+
+// DisableClient disables a client and revokes its outstanding tokens
+func (s *oauthService) DisableClient(ctx context.Context, actorID, id uint) error {
+	client, err := s.clientRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if client.IsActive() {
+		client.Disable()
+		if err := s.clientRepo.Update(ctx, client); err != nil {
+			return err
+		}
+	}
+
+	if err := s.authRepo.DeleteSessionsByClientID(ctx, client.ID); err != nil {
+		return err
+	}
+
+	s.securityEvents.Record(ctx, actorID, entity.SecurityEventOAuthClientDisabled, map[string]interface{}{
+		"client_id": client.ClientID,
+	})
+	return nil
+}
+
+// authenticateClient checks client credentials. Every failure is
+// invalid_client so callers cannot probe for client IDs.
+func (s *oauthService) authenticateClient(ctx context.Context, clientID, clientSecret string) (*entity.OAuthClient, error) {
+	if clientID == "" || clientSecret == "" {
+		return nil, entity.ErrInvalidClient
+	}
+
+	client, err := s.clientRepo.GetByClientID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, entity.ErrOAuthClientNotFound) {
+			return nil, entity.ErrInvalidClient
+		}
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(utils.HashToken(clientSecret)), []byte(client.SecretHash)) != 1 {
+		return nil, entity.ErrInvalidClient
+	}
+
+	if !client.IsActive() {
+		return nil, entity.ErrInvalidClient
+	}
+
+	return client, nil
+}
+
+// randomHex returns n random bytes as a hex string
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
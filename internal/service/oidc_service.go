@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/pkg/oidc"
+	"boilerplate-go-fiber-v2/pkg/utils"
+)
+
+type oidcService struct {
+	providers      map[string]*oidc.Provider
+	identityRepo   repository.IdentityRepository
+	webauthnRepo   repository.WebAuthnRepository
+	userRepo       repository.UserRepository
+	userService    service.UserService
+	authService    service.AuthService
+	securityEvents service.SecurityEventService
+	config         *config.Config
+}
+
+// NewOIDCService creates a new OpenID Connect sign-in service
+func NewOIDCService(
+	identityRepo repository.IdentityRepository,
+	webauthnRepo repository.WebAuthnRepository,
+	userRepo repository.UserRepository,
+	userService service.UserService,
+	authService service.AuthService,
+	securityEvents service.SecurityEventService,
+	config *config.Config,
+) service.OIDCService {
+	providers := make(map[string]*oidc.Provider, len(config.OIDC.Providers))
+	for _, p := range config.OIDC.Providers {
+		providers[p.Name] = oidc.NewProvider(oidc.Config{
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  strings.ReplaceAll(config.OIDC.RedirectURL, "{provider}", p.Name),
+			Scopes:       p.Scopes,
+		})
+	}
+
+	return &oidcService{
+		providers:      providers,
+		identityRepo:   identityRepo,
+		webauthnRepo:   webauthnRepo,
+		userRepo:       userRepo,
+		userService:    userService,
+		authService:    authService,
+		securityEvents: securityEvents,
+		config:         config,
+	}
+}
+
+// Providers lists the names of the configured providers
+func (s *oidcService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AuthorizationURL stores the state, nonce and PKCE verifier of a new
+// authorization request and returns the provider URL that starts it. Only a
+// hash of the state is stored, so a database read cannot complete a sign-in.
+func (s *oidcService) AuthorizationURL(ctx context.Context, provider, purpose string, userID uint) (string, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return "", entity.ErrUnknownOIDCProvider
+	}
+
+	state, err := oidc.RandomString()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := oidc.RandomString()
+	if err != nil {
+		return "", err
+	}
+	verifier, err := oidc.RandomString()
+	if err != nil {
+		return "", err
+	}
+
+	request := &entity.OIDCAuthRequest{
+		StateHash:    utils.HashToken(state),
+		Provider:     provider,
+		Purpose:      purpose,
+		UserID:       userID,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(s.config.OIDC.StateTTL),
+	}
+
+	if err := s.identityRepo.CreateAuthRequest(ctx, request); err != nil {
+		return "", err
+	}
+
+	authURL, err := p.AuthCodeURL(ctx, state, nonce, oidc.S256Challenge(verifier))
+	if err != nil {
+		return "", entity.ErrOIDCLoginFailed.Wrap(err)
+	}
+
+	return authURL, nil
+}
+
+// Login completes a sign-in. A known identity signs its user in; an unknown
+// one creates a new account, unless its email already belongs to an account,
+// which must link the provider itself. Like every login, it can end in a
+// challenge instead of a session.
+func (s *oidcService) Login(ctx context.Context, provider, code, state string) (*entity.User, *entity.AuthSession, *entity.LoginChallenge, error) {
+	idToken, err := s.complete(ctx, provider, entity.OIDCPurposeLogin, 0, code, state)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	identity, err := s.identityRepo.GetByProviderSubject(ctx, provider, idToken.Subject)
+	switch {
+	case err == nil:
+		return s.loginIdentity(ctx, identity, idToken)
+	case errors.Is(err, entity.ErrIdentityNotFound):
+		return s.register(ctx, provider, idToken)
+	default:
+		return nil, nil, nil, err
+	}
+}
+
+// loginIdentity signs in the user of an already linked identity
+func (s *oidcService) loginIdentity(ctx context.Context, identity *entity.UserIdentity, idToken *oidc.IDToken) (*entity.User, *entity.AuthSession, *entity.LoginChallenge, error) {
+	user, err := s.userRepo.GetByID(ctx, identity.UserID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if !user.IsActive() {
+		return nil, nil, nil, entity.ErrAccountInactive
+	}
+
+	now := time.Now()
+	identity.Email = strings.ToLower(idToken.Email)
+	identity.EmailVerified = idToken.EmailVerified
+	identity.LastLoginAt = &now
+	identity.UpdatedAt = now
+	if err := s.identityRepo.Update(ctx, identity); err != nil {
+		log.Printf("Failed to update identity %d after sign-in: %v", identity.ID, err)
+	}
+
+	return s.authService.CompleteLogin(ctx, user, entity.AuthMethodOIDC)
+}
+
+// register creates an account for a first-time provider sign-in. The
+// provider must vouch for the email address, as it becomes the account's
+// verified email.
+func (s *oidcService) register(ctx context.Context, provider string, idToken *oidc.IDToken) (*entity.User, *entity.AuthSession, *entity.LoginChallenge, error) {
+	email := strings.ToLower(strings.TrimSpace(idToken.Email))
+	if email == "" || !idToken.EmailVerified {
+		return nil, nil, nil, entity.ErrOIDCEmailRequired
+	}
+
+	if _, err := s.userRepo.GetByEmail(ctx, email); err == nil {
+		return nil, nil, nil, entity.ErrIdentityEmailInUse
+	} else if !errors.Is(err, entity.ErrUserNotFound) {
+		return nil, nil, nil, err
+	}
+
+	user := &entity.User{
+		Email:     email,
+		FirstName: idToken.GivenName,
+		LastName:  idToken.FamilyName,
+	}
+	if user.FirstName == "" && user.LastName == "" {
+		user.FirstName = idToken.Name
+	}
+	user.MarkEmailVerified()
+
+	if err := s.userService.RegisterExternal(ctx, user); err != nil {
+		return nil, nil, nil, err
+	}
+
+	now := time.Now()
+	identity := &entity.UserIdentity{
+		UserID:        user.ID,
+		Provider:      provider,
+		Subject:       idToken.Subject,
+		Email:         email,
+		EmailVerified: true,
+		LastLoginAt:   &now,
+	}
+
+	if err := s.identityRepo.Create(ctx, identity); err != nil {
+		// A concurrent sign-in linked the identity first; drop the account
+		// created for this one
+		if deleteErr := s.userRepo.Delete(ctx, user.ID); deleteErr != nil {
+			log.Printf("Failed to delete user %d after identity conflict: %v", user.ID, deleteErr)
+		}
+		return nil, nil, nil, err
+	}
+
+	s.securityEvents.Record(ctx, user.ID, entity.SecurityEventIdentityLinked, map[string]interface{}{
+		"provider": provider,
+		"signup":   true,
+	})
+
+	return s.authService.CompleteLogin(ctx, user, entity.AuthMethodOIDC)
+}
+
+// Link completes an account link started by the same user
+func (s *oidcService) Link(ctx context.Context, userID uint, provider, code, state string) (*entity.UserIdentity, error) {
+	idToken, err := s.complete(ctx, provider, entity.OIDCPurposeLink, userID, code, state)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &entity.UserIdentity{
+		UserID:        userID,
+		Provider:      provider,
+		Subject:       idToken.Subject,
+		Email:         strings.ToLower(idToken.Email),
+		EmailVerified: idToken.EmailVerified,
+	}
+
+	if err := s.identityRepo.Create(ctx, identity); err != nil {
+		return nil, err
+	}
+
+	s.securityEvents.Record(ctx, userID, entity.SecurityEventIdentityLinked, map[string]interface{}{
+		"provider": provider,
+	})
+
+	return identity, nil
+}
+
+// ListIdentities lists the identities linked to a user
+func (s *oidcService) ListIdentities(ctx context.Context, userID uint) ([]*entity.UserIdentity, error) {
+	return s.identityRepo.ListByUserID(ctx, userID)
+}
+
+// Unlink removes a linked identity, unless it is the user's only way to sign in
+func (s *oidcService) Unlink(ctx context.Context, userID, id uint) error {
+	identities, err := s.identityRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	var identity *entity.UserIdentity
+	for _, i := range identities {
+		if i.ID == id {
+			identity = i
+			break
+		}
+	}
+	if identity == nil {
+		return entity.ErrIdentityNotFound
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.HasPassword() && len(identities) == 1 {
+		passkeys, err := s.webauthnRepo.ListCredentials(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if len(passkeys) == 0 {
+			return entity.ErrLastSignInMethod
+		}
+	}
+
+	if err := s.identityRepo.Delete(ctx, id, userID); err != nil {
+		return err
+	}
+
+	s.securityEvents.Record(ctx, userID, entity.SecurityEventIdentityUnlinked, map[string]interface{}{
+		"provider": identity.Provider,
+	})
+
+	return nil
+}
+
+// complete claims the authorization request behind state, exchanges the code
+// and verifies the returned ID token. The request is consumed even when a
+// later step fails, so every state works once.
+func (s *oidcService) complete(ctx context.Context, provider, purpose string, userID uint, code, state string) (*oidc.IDToken, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, entity.ErrUnknownOIDCProvider
+	}
+
+	request, err := s.identityRepo.ClaimAuthRequest(ctx, utils.HashToken(state))
+	if err != nil {
+		return nil, err
+	}
+
+	if request.IsExpired() || request.Provider != provider || request.Purpose != purpose || request.UserID != userID {
+		return nil, entity.ErrInvalidOIDCState
+	}
+
+	token, err := p.Exchange(ctx, code, request.CodeVerifier)
+	if err != nil {
+		return nil, entity.ErrOIDCLoginFailed.Wrap(err)
+	}
+
+	idToken, err := p.VerifyIDToken(ctx, token.IDToken, request.Nonce)
+	if err != nil {
+		return nil, entity.ErrOIDCLoginFailed.Wrap(err)
+	}
+
+	return idToken, nil
+}
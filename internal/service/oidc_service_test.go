@@ -0,0 +1,351 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/pkg/oidc/oidctest"
+)
+
+// The fakes embed their interface and implement only what the OIDC service
+// calls, so any other call panics
+
+type fakeIdentityRepo struct {
+	repository.IdentityRepository
+	identities []*entity.UserIdentity
+	requests   map[string]*entity.OIDCAuthRequest
+}
+
+func (r *fakeIdentityRepo) Create(ctx context.Context, identity *entity.UserIdentity) error {
+	for _, i := range r.identities {
+		if i.Provider == identity.Provider && i.Subject == identity.Subject {
+			return entity.ErrIdentityAlreadyLinked
+		}
+	}
+	identity.ID = uint(len(r.identities) + 1)
+	r.identities = append(r.identities, identity)
+	return nil
+}
+
+func (r *fakeIdentityRepo) GetByProviderSubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error) {
+	for _, i := range r.identities {
+		if i.Provider == provider && i.Subject == subject {
+			return i, nil
+		}
+	}
+	return nil, entity.ErrIdentityNotFound
+}
+
+func (r *fakeIdentityRepo) ListByUserID(ctx context.Context, userID uint) ([]*entity.UserIdentity, error) {
+	var identities []*entity.UserIdentity
+	for _, i := range r.identities {
+		if i.UserID == userID {
+			identities = append(identities, i)
+		}
+	}
+	return identities, nil
+}
+
+func (r *fakeIdentityRepo) Update(ctx context.Context, identity *entity.UserIdentity) error {
+	return nil
+}
+
+func (r *fakeIdentityRepo) Delete(ctx context.Context, id, userID uint) error {
+	for n, i := range r.identities {
+		if i.ID == id && i.UserID == userID {
+			r.identities = slices.Delete(r.identities, n, n+1)
+			return nil
+		}
+	}
+	return entity.ErrIdentityNotFound
+}
+
+func (r *fakeIdentityRepo) CreateAuthRequest(ctx context.Context, request *entity.OIDCAuthRequest) error {
+	r.requests[request.StateHash] = request
+	return nil
+}
+
+func (r *fakeIdentityRepo) ClaimAuthRequest(ctx context.Context, stateHash string) (*entity.OIDCAuthRequest, error) {
+	request, ok := r.requests[stateHash]
+	if !ok {
+		return nil, entity.ErrInvalidOIDCState
+	}
+	delete(r.requests, stateHash)
+	return request, nil
+}
+
+type fakeUserRepo struct {
+	repository.UserRepository
+	users map[uint]*entity.User
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id uint) (*entity.User, error) {
+	if user, ok := r.users[id]; ok {
+		return user, nil
+	}
+	return nil, entity.ErrUserNotFound
+}
+
+func (r *fakeUserRepo) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, entity.ErrUserNotFound
+}
+
+func (r *fakeUserRepo) Delete(ctx context.Context, id uint) error {
+	delete(r.users, id)
+	return nil
+}
+
+type fakeUserService struct {
+	service.UserService
+	repo *fakeUserRepo
+}
+
+func (s *fakeUserService) RegisterExternal(ctx context.Context, user *entity.User) error {
+	user.ID = uint(len(s.repo.users) + 1)
+	user.Status = "active"
+	s.repo.users[user.ID] = user
+	return nil
+}
+
+type fakeWebAuthnRepo struct {
+	repository.WebAuthnRepository
+	credentials map[uint][]*entity.WebAuthnCredential
+}
+
+func (r *fakeWebAuthnRepo) ListCredentials(ctx context.Context, userID uint) ([]*entity.WebAuthnCredential, error) {
+	return r.credentials[userID], nil
+}
+
+// fakeAuthService records logins. It does not implement StartSession, so a
+// sign-in that skips CompleteLogin panics.
+type fakeAuthService struct {
+	service.AuthService
+	logins    [][]string
+	challenge *entity.LoginChallenge
+}
+
+func (s *fakeAuthService) CompleteLogin(ctx context.Context, user *entity.User, methods ...string) (*entity.User, *entity.AuthSession, *entity.LoginChallenge, error) {
+	s.logins = append(s.logins, methods)
+	if s.challenge != nil {
+		return user, nil, s.challenge, nil
+	}
+	return user, &entity.AuthSession{UserID: user.ID}, nil, nil
+}
+
+type fakeSecurityEvents struct {
+	service.SecurityEventService
+	events []string
+}
+
+func (s *fakeSecurityEvents) Record(ctx context.Context, userID uint, event string, metadata map[string]interface{}) {
+	s.events = append(s.events, event)
+}
+
+type oidcFixture struct {
+	server     *oidctest.Server
+	service    service.OIDCService
+	identities *fakeIdentityRepo
+	users      *fakeUserRepo
+	passkeys   *fakeWebAuthnRepo
+	auth       *fakeAuthService
+}
+
+func newOIDCFixture(t *testing.T) *oidcFixture {
+	t.Helper()
+
+	server := oidctest.NewServer("client", "secret")
+	t.Cleanup(server.Close)
+
+	cfg := &config.Config{OIDC: config.OIDCConfig{
+		RedirectURL: "http://localhost/auth/callback/{provider}",
+		StateTTL:    time.Minute,
+		Providers: []config.OIDCProviderConfig{{
+			Name:         "test",
+			Issuer:       server.Issuer(),
+			ClientID:     "client",
+			ClientSecret: "secret",
+		}},
+	}}
+
+	f := &oidcFixture{
+		server:     server,
+		identities: &fakeIdentityRepo{requests: map[string]*entity.OIDCAuthRequest{}},
+		users:      &fakeUserRepo{users: map[uint]*entity.User{}},
+		passkeys:   &fakeWebAuthnRepo{credentials: map[uint][]*entity.WebAuthnCredential{}},
+		auth:       &fakeAuthService{},
+	}
+	f.service = NewOIDCService(f.identities, f.passkeys, f.users, &fakeUserService{repo: f.users}, f.auth, &fakeSecurityEvents{}, cfg)
+	return f
+}
+
+// authorize starts a sign-in or link and returns the provider's callback
+// code and state
+func (f *oidcFixture) authorize(t *testing.T, purpose string, userID uint) (string, string) {
+	t.Helper()
+
+	authURL, err := f.service.AuthorizationURL(context.Background(), "test", purpose, userID)
+	if err != nil {
+		t.Fatalf("AuthorizationURL: %v", err)
+	}
+	code, state, err := f.server.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+	return code, state
+}
+
+func (f *oidcFixture) addUser(user *entity.User) {
+	user.Status = "active"
+	f.users.users[user.ID] = user
+}
+
+func TestOIDCLoginCompletesLogin(t *testing.T) {
+	f := newOIDCFixture(t)
+	ctx := context.Background()
+
+	// First sign-in registers the account
+	code, state := f.authorize(t, entity.OIDCPurposeLogin, 0)
+	user, session, challenge, err := f.service.Login(ctx, "test", code, state)
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if user.Email != "oidctest@example.com" || !user.IsEmailVerified() || session == nil || challenge != nil {
+		t.Fatalf("Login = %+v, %+v, %+v; want a verified account and a session", user, session, challenge)
+	}
+
+	// Later sign-ins find the linked identity
+	code, state = f.authorize(t, entity.OIDCPurposeLogin, 0)
+	again, _, _, err := f.service.Login(ctx, "test", code, state)
+	if err != nil {
+		t.Fatalf("second Login: %v", err)
+	}
+	if again.ID != user.ID {
+		t.Errorf("second Login signed in user %d, want %d", again.ID, user.ID)
+	}
+
+	want := [][]string{{entity.AuthMethodOIDC}, {entity.AuthMethodOIDC}}
+	if !slices.EqualFunc(f.auth.logins, want, slices.Equal[[]string]) {
+		t.Errorf("CompleteLogin calls = %v, want %v", f.auth.logins, want)
+	}
+
+	// A challenge from CompleteLogin, e.g. an expired password, is returned
+	// instead of a session
+	f.auth.challenge = &entity.LoginChallenge{Type: "password_change", Token: "challenge"}
+	code, state = f.authorize(t, entity.OIDCPurposeLogin, 0)
+	_, session, challenge, err = f.service.Login(ctx, "test", code, state)
+	if err != nil {
+		t.Fatalf("third Login: %v", err)
+	}
+	if session != nil || challenge != f.auth.challenge {
+		t.Errorf("third Login = %+v, %+v; want only the challenge", session, challenge)
+	}
+}
+
+func TestOIDCLoginRejectsExistingEmail(t *testing.T) {
+	f := newOIDCFixture(t)
+	f.addUser(&entity.User{ID: 1, Email: "oidctest@example.com", Password: "hash"})
+
+	code, state := f.authorize(t, entity.OIDCPurposeLogin, 0)
+	if _, _, _, err := f.service.Login(context.Background(), "test", code, state); !errors.Is(err, entity.ErrIdentityEmailInUse) {
+		t.Errorf("Login error = %v, want ErrIdentityEmailInUse", err)
+	}
+}
+
+func TestOIDCStateWorksOnceForItsPurpose(t *testing.T) {
+	f := newOIDCFixture(t)
+	ctx := context.Background()
+	f.addUser(&entity.User{ID: 1, Email: "user@example.com", Password: "hash"})
+
+	// A sign-in state cannot complete a link
+	code, state := f.authorize(t, entity.OIDCPurposeLogin, 0)
+	if _, err := f.service.Link(ctx, 1, "test", code, state); !errors.Is(err, entity.ErrInvalidOIDCState) {
+		t.Errorf("Link with a sign-in state error = %v, want ErrInvalidOIDCState", err)
+	}
+
+	// The failed attempt consumed the state
+	if _, _, _, err := f.service.Login(ctx, "test", code, state); !errors.Is(err, entity.ErrInvalidOIDCState) {
+		t.Errorf("Login with a used state error = %v, want ErrInvalidOIDCState", err)
+	}
+
+	// A link started by one user cannot be completed by another
+	code, state = f.authorize(t, entity.OIDCPurposeLink, 1)
+	if _, err := f.service.Link(ctx, 2, "test", code, state); !errors.Is(err, entity.ErrInvalidOIDCState) {
+		t.Errorf("Link by another user error = %v, want ErrInvalidOIDCState", err)
+	}
+}
+
+func TestOIDCLinkAndUnlink(t *testing.T) {
+	f := newOIDCFixture(t)
+	ctx := context.Background()
+	f.addUser(&entity.User{ID: 1, Email: "user@example.com", Password: "hash"})
+	f.addUser(&entity.User{ID: 2, Email: "other@example.com", Password: "hash"})
+
+	code, state := f.authorize(t, entity.OIDCPurposeLink, 1)
+	identity, err := f.service.Link(ctx, 1, "test", code, state)
+	if err != nil {
+		t.Fatalf("Link: %v", err)
+	}
+	if identity.UserID != 1 || identity.Subject != "oidctest-user" {
+		t.Errorf("Link = %+v, want the oidctest user linked to user 1", identity)
+	}
+
+	// The provider account can only be linked once
+	code, state = f.authorize(t, entity.OIDCPurposeLink, 2)
+	if _, err := f.service.Link(ctx, 2, "test", code, state); !errors.Is(err, entity.ErrIdentityAlreadyLinked) {
+		t.Errorf("second Link error = %v, want ErrIdentityAlreadyLinked", err)
+	}
+
+	// Other users cannot unlink it
+	if err := f.service.Unlink(ctx, 2, identity.ID); !errors.Is(err, entity.ErrIdentityNotFound) {
+		t.Errorf("Unlink by another user error = %v, want ErrIdentityNotFound", err)
+	}
+
+	if err := f.service.Unlink(ctx, 1, identity.ID); err != nil {
+		t.Fatalf("Unlink: %v", err)
+	}
+	identities, err := f.service.ListIdentities(ctx, 1)
+	if err != nil {
+		t.Fatalf("ListIdentities: %v", err)
+	}
+	if len(identities) != 0 {
+		t.Errorf("ListIdentities after Unlink = %v, want none", identities)
+	}
+}
+
+func TestOIDCUnlinkKeepsASignInMethod(t *testing.T) {
+	f := newOIDCFixture(t)
+	ctx := context.Background()
+
+	// An account registered through the provider has no password
+	code, state := f.authorize(t, entity.OIDCPurposeLogin, 0)
+	user, _, _, err := f.service.Login(ctx, "test", code, state)
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	identities, err := f.service.ListIdentities(ctx, user.ID)
+	if err != nil || len(identities) != 1 {
+		t.Fatalf("ListIdentities = %v, %v; want one identity", identities, err)
+	}
+
+	if err := f.service.Unlink(ctx, user.ID, identities[0].ID); !errors.Is(err, entity.ErrLastSignInMethod) {
+		t.Errorf("Unlink of the last sign-in method error = %v, want ErrLastSignInMethod", err)
+	}
+
+	// A passkey is another way to sign in
+	f.passkeys.credentials[user.ID] = []*entity.WebAuthnCredential{{ID: 1, UserID: user.ID}}
+	if err := f.service.Unlink(ctx, user.ID, identities[0].ID); err != nil {
+		t.Errorf("Unlink with a passkey: %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"time"
+
+	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/pkg/utils"
+)
+
+type oneTimeCodeService struct {
+	authRepo repository.AuthRepository
+	channels map[string]service.CodeChannel
+	config   *config.Config
+}
+
+// NewOneTimeCodeService creates a new one-time code service. The first
+// channel is the default when none is requested.
+func NewOneTimeCodeService(authRepo repository.AuthRepository, config *config.Config, channels ...service.CodeChannel) service.OneTimeCodeService {
+	s := &oneTimeCodeService{
+		authRepo: authRepo,
+		channels: make(map[string]service.CodeChannel, len(channels)+1),
+		config:   config,
+	}
+	for i, channel := range channels {
+		if i == 0 {
+			s.channels[""] = channel
+		}
+		s.channels[channel.Name()] = channel
+	}
+	return s
+}
+
+// Issue creates a code for a user and purpose and hands it to a channel.
+// Requests are limited by a resend cooldown and an hourly cap, and a new
+// code invalidates the older ones of the same purpose.
+func (s *oneTimeCodeService) Issue(ctx context.Context, user *entity.User, purpose, channelName string) (*entity.TFACode, error) {
+	channel, ok := s.channels[channelName]
+	if !ok {
+		return nil, entity.ErrUnknownCodeChannel
+	}
+
+	destination, err := channel.Destination(user, purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	latest, err := s.authRepo.GetLatestTFACode(ctx, user.ID, purpose)
+	if err != nil && !errors.Is(err, entity.ErrTFACodeNotFound) {
+		return nil, err
+	}
+	if latest != nil && now.Sub(latest.CreatedAt) < s.config.OTP.ResendInterval {
+		return nil, entity.ErrOTPResendTooSoon
+	}
+
+	sent, err := s.authRepo.CountTFACodesSince(ctx, user.ID, purpose, now.Add(-time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	if sent >= int64(s.config.OTP.MaxPerHour) {
+		return nil, entity.ErrOTPRateLimited
+	}
+
+	if err := s.authRepo.InvalidateTFACodes(ctx, user.ID, purpose); err != nil {
+		return nil, err
+	}
+
+	code := utils.GenerateTFACode()
+	tfaCode := &entity.TFACode{
+		UserID:      user.ID,
+		Code:        utils.HashCode(code, s.config.OTP.Secret),
+		Purpose:     purpose,
+		Channel:     channel.Name(),
+		Destination: destination,
+		ExpiresAt:   now.Add(s.ttl(purpose)),
+		CreatedAt:   now,
+	}
+	if err := s.authRepo.CreateTFACode(ctx, tfaCode); err != nil {
+		return nil, err
+	}
+
+	err = channel.Deliver(ctx, service.CodeDelivery{
+		User:        user,
+		Destination: destination,
+		Code:        code,
+		Purpose:     purpose,
+		ExpiresAt:   tfaCode.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tfaCode, nil
+}
+
+// Verify checks a code against the latest one issued to the user for the
+// purpose and consumes it. Every attempt counts against the code until it is
+// locked.
+func (s *oneTimeCodeService) Verify(ctx context.Context, userID uint, purpose, code string) (*entity.TFACode, error) {
+	tfaCode, err := s.authRepo.GetLatestTFACode(ctx, userID, purpose)
+	if err != nil {
+		if errors.Is(err, entity.ErrTFACodeNotFound) {
+			return nil, entity.ErrInvalidTFACode
+		}
+		return nil, err
+	}
+
+	if !tfaCode.IsValid() {
+		return nil, entity.ErrTFACodeExpired
+	}
+	if tfaCode.AttemptsExhausted(s.config.OTP.MaxAttempts) {
+		return nil, entity.ErrOTPAttemptsExceeded
+	}
+
+	// Reserve the attempt before comparing so parallel guesses cannot all
+	// pass the check above
+	if err := s.authRepo.ReserveTFACodeAttempt(ctx, tfaCode.ID, s.config.OTP.MaxAttempts); err != nil {
+		return nil, err
+	}
+
+	hash := utils.HashCode(code, s.config.OTP.Secret)
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(tfaCode.Code)) != 1 {
+		return nil, entity.ErrInvalidTFACode
+	}
+
+	if err := s.authRepo.ClaimTFACode(ctx, tfaCode.ID); err != nil {
+		if errors.Is(err, entity.ErrTFACodeNotFound) {
+			return nil, entity.ErrTFACodeExpired
+		}
+		return nil, err
+	}
+
+	tfaCode.MarkAsUsed()
+	return tfaCode, nil
+}
+
+func (s *oneTimeCodeService) ttl(purpose string) time.Duration {
+	if purpose == entity.TFACodePurposePhoneVerify {
+		return s.config.OTP.TTL
+	}
+	return s.config.OTP.LoginTTL
+}
@@ -0,0 +1,368 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/pkg/mailer"
+	"boilerplate-go-fiber-v2/pkg/utils"
+)
+
+type organizationService struct {
+	orgRepo        repository.OrganizationRepository
+	userRepo       repository.UserRepository
+	authRepo       repository.AuthRepository
+	authService    service.AuthService
+	securityEvents service.SecurityEventService
+	mailer         mailer.Mailer
+	config         *config.Config
+}
+
+// NewOrganizationService creates a new organization service
+func NewOrganizationService(
+	orgRepo repository.OrganizationRepository,
+	userRepo repository.UserRepository,
+	authRepo repository.AuthRepository,
+	authService service.AuthService,
+	securityEvents service.SecurityEventService,
+	mailer mailer.Mailer,
+	config *config.Config,
+) service.OrganizationService {
+	return &organizationService{
+		orgRepo:        orgRepo,
+		userRepo:       userRepo,
+		authRepo:       authRepo,
+		authService:    authService,
+		securityEvents: securityEvents,
+		mailer:         mailer,
+		config:         config,
+	}
+}
+
+// Create creates an organization owned by the user
+func (s *organizationService) Create(ctx context.Context, userID uint, name string) (*entity.Organization, error) {
+	org := &entity.Organization{Name: strings.TrimSpace(name)}
+	owner := &entity.Membership{UserID: userID, Role: entity.OrgRoleOwner}
+
+	if err := s.orgRepo.CreateOrganization(ctx, org, owner); err != nil {
+		return nil, err
+	}
+
+	s.securityEvents.Record(ctx, userID, entity.SecurityEventOrgJoined, map[string]interface{}{
+		"org_id": org.ID,
+		"role":   owner.Role,
+	})
+
+	return org, nil
+}
+
+// ListForUser lists the organizations a user belongs to, with their role
+func (s *organizationService) ListForUser(ctx context.Context, userID uint) ([]*entity.Membership, error) {
+	return s.orgRepo.ListMembershipsByUser(ctx, userID)
+}
+
+// Get gets an organization and the user's membership in it
+func (s *organizationService) Get(ctx context.Context, userID, orgID uint) (*entity.Organization, *entity.Membership, error) {
+	membership, err := s.membership(ctx, orgID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	org, err := s.orgRepo.GetOrganization(ctx, orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return org, membership, nil
+}
+
+// Update renames an organization; admins and owners may
+func (s *organizationService) Update(ctx context.Context, userID, orgID uint, name string) (*entity.Organization, *entity.Membership, error) {
+	org, membership, err := s.Get(ctx, userID, orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !membership.HasRole(entity.OrgRoleAdmin) {
+		return nil, nil, entity.ErrOrgRoleRequired
+	}
+
+	org.Name = strings.TrimSpace(name)
+	org.UpdatedAt = time.Now()
+	if err := s.orgRepo.UpdateOrganization(ctx, org); err != nil {
+		return nil, nil, err
+	}
+
+	return org, membership, nil
+}
+
+// Delete deletes an organization; only owners may. Organizations that own
+// orders or payments are kept, since those records cannot be reassigned.
+func (s *organizationService) Delete(ctx context.Context, userID, orgID uint) error {
+	membership, err := s.membership(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if !membership.IsOwner() {
+		return entity.ErrOrgRoleRequired
+	}
+
+	return s.orgRepo.DeleteOrganization(ctx, orgID)
+}
+
+// ListMembers lists the members of an organization; any member may
+func (s *organizationService) ListMembers(ctx context.Context, userID, orgID uint) ([]*entity.Membership, error) {
+	if _, err := s.membership(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+	return s.orgRepo.ListMembers(ctx, orgID)
+}
+
+// UpdateMemberRole changes a member's role. Admins manage admins and
+// members; only owners grant or take away ownership, and the last owner
+// cannot step down.
+func (s *organizationService) UpdateMemberRole(ctx context.Context, userID, orgID, memberID uint, role string) error {
+	if !entity.IsOrgRole(role) {
+		return entity.ErrInvalidOrgRole
+	}
+
+	actor, err := s.membership(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+
+	member, err := s.orgRepo.GetMembership(ctx, orgID, memberID)
+	if err != nil {
+		return err
+	}
+
+	if !actor.CanGrant(role) || !actor.CanGrant(member.Role) {
+		return entity.ErrOrgRoleRequired
+	}
+	if member.Role == role {
+		return nil
+	}
+
+	if err := s.orgRepo.UpdateMemberRole(ctx, orgID, memberID, role); err != nil {
+		return err
+	}
+
+	s.securityEvents.Record(ctx, memberID, entity.SecurityEventOrgRoleChanged, map[string]interface{}{
+		"org_id":     orgID,
+		"role":       role,
+		"old_role":   member.Role,
+		"changed_by": userID,
+	})
+
+	return nil
+}
+
+// RemoveMember removes a member from an organization. Members may leave on
+// their own; removing someone else follows the same rules as changing their
+// role. The removed member's sessions in the organization go back to their
+// personal account.
+func (s *organizationService) RemoveMember(ctx context.Context, userID, orgID, memberID uint) error {
+	actor, err := s.membership(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+
+	member := actor
+	if memberID != userID {
+		member, err = s.orgRepo.GetMembership(ctx, orgID, memberID)
+		if err != nil {
+			return err
+		}
+		if !actor.CanGrant(member.Role) {
+			return entity.ErrOrgRoleRequired
+		}
+	}
+
+	if err := s.orgRepo.DeleteMembership(ctx, orgID, memberID); err != nil {
+		return err
+	}
+
+	if err := s.authRepo.ClearSessionOrganization(ctx, memberID, orgID); err != nil {
+		return err
+	}
+
+	s.securityEvents.Record(ctx, memberID, entity.SecurityEventOrgLeft, map[string]interface{}{
+		"org_id":     orgID,
+		"removed_by": userID,
+	})
+
+	return nil
+}
+
+// Invite emails an invitation to join an organization with role. A newer
+// invitation to the same address replaces a pending one.
+func (s *organizationService) Invite(ctx context.Context, userID, orgID uint, email, role string) (*entity.Invitation, error) {
+	if !entity.IsOrgRole(role) {
+		return nil, entity.ErrInvalidOrgRole
+	}
+
+	org, actor, err := s.Get(ctx, userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.CanGrant(role) {
+		return nil, entity.ErrOrgRoleRequired
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	invitee, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil && !errors.Is(err, entity.ErrUserNotFound) {
+		return nil, err
+	}
+	if invitee != nil {
+		if _, err := s.orgRepo.GetMembership(ctx, orgID, invitee.ID); err == nil {
+			return nil, entity.ErrAlreadyMember
+		} else if !errors.Is(err, entity.ErrMemberNotFound) {
+			return nil, err
+		}
+	}
+
+	inviter, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation := &entity.Invitation{
+		OrgID:     orgID,
+		Email:     email,
+		Role:      role,
+		Token:     utils.HashToken(token),
+		InvitedBy: userID,
+		ExpiresAt: time.Now().Add(s.config.Organization.InvitationTTL),
+	}
+
+	if err := s.orgRepo.CreateInvitation(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	mailer.SendInBackground(s.mailer, mailer.Message{
+		To:      email,
+		Subject: fmt.Sprintf("Join %s", org.Name),
+		Body: fmt.Sprintf("Hi,\n\n%s invited you to join %s as %s. Sign in or create an account with this email address, then open the link below. It expires at %s.\n\n%s%s\n\nIf you were not expecting this invitation, you can ignore this email.\n",
+			inviter.GetFullName(), org.Name, role, invitation.ExpiresAt.Format(time.RFC1123), s.config.Organization.InvitationURL, url.QueryEscape(token)),
+	})
+
+	s.securityEvents.Record(ctx, userID, entity.SecurityEventOrgInvitationSent, map[string]interface{}{
+		"org_id":        orgID,
+		"invitation_id": invitation.ID,
+		"email":         email,
+		"role":          role,
+	})
+
+	return invitation, nil
+}
+
+// ListInvitations lists the pending invitations of an organization; admins
+// and owners may
+func (s *organizationService) ListInvitations(ctx context.Context, userID, orgID uint) ([]*entity.Invitation, error) {
+	actor, err := s.membership(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.CanManageMembers() {
+		return nil, entity.ErrOrgRoleRequired
+	}
+	return s.orgRepo.ListPendingInvitations(ctx, orgID)
+}
+
+// RevokeInvitation revokes a pending invitation; admins and owners may
+func (s *organizationService) RevokeInvitation(ctx context.Context, userID, orgID, invitationID uint) error {
+	actor, err := s.membership(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if !actor.CanManageMembers() {
+		return entity.ErrOrgRoleRequired
+	}
+	return s.orgRepo.DeleteInvitation(ctx, invitationID, orgID)
+}
+
+// AcceptInvitation adds the user to the organization of an invitation. The
+// invitation must have been sent to the user's verified email address.
+func (s *organizationService) AcceptInvitation(ctx context.Context, userID uint, token string) (*entity.Membership, error) {
+	invitation, err := s.orgRepo.GetInvitationByToken(ctx, utils.HashToken(token))
+	if err != nil {
+		if errors.Is(err, entity.ErrInvitationNotFound) {
+			return nil, entity.ErrInvalidInvitation
+		}
+		return nil, err
+	}
+
+	if invitation.IsAccepted() || invitation.IsExpired() {
+		return nil, entity.ErrInvalidInvitation
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !invitation.IsFor(user.Email) {
+		return nil, entity.ErrInvitationEmail
+	}
+	if !user.IsEmailVerified() {
+		return nil, entity.ErrEmailNotVerified
+	}
+
+	membership := &entity.Membership{
+		OrgID:  invitation.OrgID,
+		UserID: userID,
+		Role:   invitation.Role,
+	}
+
+	if err := s.orgRepo.AcceptInvitation(ctx, invitation, membership); err != nil {
+		return nil, err
+	}
+
+	s.securityEvents.Record(ctx, userID, entity.SecurityEventOrgJoined, map[string]interface{}{
+		"org_id":        invitation.OrgID,
+		"role":          membership.Role,
+		"invitation_id": invitation.ID,
+		"invited_by":    invitation.InvitedBy,
+	})
+
+	return membership, nil
+}
+
+// Switch makes an organization the active one of a session. The new access
+// token carries it in the org_id claim, and repositories scope tenant data
+// to it.
+func (s *organizationService) Switch(ctx context.Context, userID, sessionID, orgID uint) (*entity.AuthSession, error) {
+	if orgID != 0 {
+		if _, err := s.membership(ctx, orgID, userID); err != nil {
+			return nil, err
+		}
+	}
+	return s.authService.SetActiveOrganization(ctx, sessionID, orgID)
+}
+
+// membership gets the user's membership, hiding organizations they do not
+// belong to
+func (s *organizationService) membership(ctx context.Context, orgID, userID uint) (*entity.Membership, error) {
+	membership, err := s.orgRepo.GetMembership(ctx, orgID, userID)
+	if err != nil {
+		if errors.Is(err, entity.ErrMemberNotFound) {
+			return nil, entity.ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+	return membership, nil
+}
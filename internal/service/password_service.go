@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/pkg/pwned"
+	"boilerplate-go-fiber-v2/pkg/utils"
+	"boilerplate-go-fiber-v2/pkg/validator"
+)
+
+type passwordService struct {
+	checker     *pwned.Checker
+	historyRepo repository.PasswordHistoryRepository
+	historySize int
+}
+
+// NewPasswordService creates a new password service. historySize is the
+// number of recent passwords, including the current one, that cannot be
+// reused; 0 disables the check.
+func NewPasswordService(checker *pwned.Checker, historyRepo repository.PasswordHistoryRepository, historySize int) service.PasswordService {
+	return &passwordService{
+		checker:     checker,
+		historyRepo: historyRepo,
+		historySize: historySize,
+	}
+}
+
+// CheckCandidate rejects a new password that is common or known from data
+// breaches, reporting it as a field error on field. Dataset read failures are
+// logged and the password is allowed, so a broken dataset never blocks signups.
+func (s *passwordService) CheckCandidate(ctx context.Context, field, password string) error {
+	result, err := s.checker.Check(password)
+	if err != nil {
+		log.Printf("Failed to check password against breach dataset: %v", err)
+	}
+
+	language := utils.LanguageFromContext(ctx)
+	switch {
+	case result.Breached:
+		return validator.ErrValidationFailed.WithFields(validator.NewFieldError(field, "breached", "", language))
+	case result.Common:
+		return validator.ErrValidationFailed.WithFields(validator.NewFieldError(field, "common", "", language))
+	}
+
+	return nil
+}
+
+// CheckReuse rejects a new password that matches the user's current password
+// or one of their recent ones. Every hash is verified on its own, since
+// salted hashes cannot be compared directly.
+func (s *passwordService) CheckReuse(ctx context.Context, user *entity.User, field, password string) error {
+	if s.historySize <= 0 {
+		return nil
+	}
+
+	reused := utils.CheckPassword(password, user.Password)
+	if !reused {
+		entries, err := s.historyRepo.ListRecent(ctx, user.ID, s.historySize)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			if utils.CheckPassword(password, entry.PasswordHash) {
+				reused = true
+				break
+			}
+		}
+	}
+
+	if reused {
+		return validator.ErrValidationFailed.WithFields(
+			validator.NewFieldError(field, "reused", strconv.Itoa(s.historySize), utils.LanguageFromContext(ctx)))
+	}
+
+	return nil
+}
+
+// Remember adds a newly set password hash to the user's history and drops
+// entries beyond the history size. Failures are logged; the password change
+// itself has already succeeded.
+func (s *passwordService) Remember(ctx context.Context, userID uint, hash string) {
+	if s.historySize <= 0 {
+		return
+	}
+
+	entry := &entity.PasswordHistory{
+		UserID:       userID,
+		PasswordHash: hash,
+		CreatedAt:    time.Now(),
+	}
+
+	if err := s.historyRepo.Create(ctx, entry); err != nil {
+		log.Printf("Failed to record password history for user %d: %v", userID, err)
+		return
+	}
+
+	if err := s.historyRepo.Prune(ctx, userID, s.historySize); err != nil {
+		log.Printf("Failed to prune password history for user %d: %v", userID, err)
+	}
+}
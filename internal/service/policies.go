@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+)
+
+// DefaultPolicies returns the built-in resource policies
+func DefaultPolicies() []service.Policy {
+	return []service.Policy{OrderPolicy, PaymentPolicy, UserPolicy}
+}
+
+// OrderPolicy lets customers manage their own orders and staff act on any
+// order through permissions
+var OrderPolicy = service.Policy{
+	ResourceType: "order",
+	Rules: map[string]service.Condition{
+		"read": Any(Owner, Permission("orders:read")),
+		"cancel": Any(
+			All(Owner, Attr(func(o *entity.Order) bool { return o.CanBeCancelled() })),
+			Permission("orders:write"),
+		),
+		"refund": All(Permission("orders:refund"), Attr(func(o *entity.Order) bool { return o.IsPaid() || o.IsCompleted() })),
+	},
+}
+
+// PaymentPolicy lets customers see their own payments and staff see all
+var PaymentPolicy = service.Policy{
+	ResourceType: "payment",
+	Rules: map[string]service.Condition{
+		"read": Any(Owner, Permission("payments:read")),
+	},
+}
+
+// UserPolicy lets users manage their own account and staff read or manage
+// others
+var UserPolicy = service.Policy{
+	ResourceType: "user",
+	Rules: map[string]service.Condition{
+		"read":                 Any(Owner, Permission("users:read")),
+		"update":               Any(Owner, Permission("users:write")),
+		"read_security_events": Any(Owner, Permission("security_events:read")),
+	},
+}
+
+// Owner allows subjects acting on a resource they own
+func Owner(ctx context.Context, subject *entity.Subject, resource entity.Resource) bool {
+	owned, ok := resource.(entity.OwnedResource)
+	return ok && subject.UserID != 0 && owned.OwnerID() == subject.UserID
+}
+
+// Permission allows subjects holding a permission
+func Permission(permission string) service.Condition {
+	return func(ctx context.Context, subject *entity.Subject, resource entity.Resource) bool {
+		return subject.Access != nil && subject.Access.HasPermission(permission)
+	}
+}
+
+// Role allows subjects holding a role
+func Role(role string) service.Condition {
+	return func(ctx context.Context, subject *entity.Subject, resource entity.Resource) bool {
+		return subject.Access != nil && subject.Access.HasRole(role)
+	}
+}
+
+// Attr allows when a check on the concrete resource passes. Resources of
+// another type never match.
+func Attr[T entity.Resource](check func(T) bool) service.Condition {
+	return func(ctx context.Context, subject *entity.Subject, resource entity.Resource) bool {
+		typed, ok := resource.(T)
+		return ok && check(typed)
+	}
+}
+
+// Any allows when at least one condition allows
+func Any(conditions ...service.Condition) service.Condition {
+	return func(ctx context.Context, subject *entity.Subject, resource entity.Resource) bool {
+		for _, condition := range conditions {
+			if condition(ctx, subject, resource) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// All allows when every condition allows
+func All(conditions ...service.Condition) service.Condition {
+	return func(ctx context.Context, subject *entity.Subject, resource entity.Resource) bool {
+		for _, condition := range conditions {
+			if !condition(ctx, subject, resource) {
+				return false
+			}
+		}
+		return len(conditions) > 0
+	}
+}
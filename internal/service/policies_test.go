@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/service/policytest"
+)
+
+const (
+	ownerID uint = iota + 1
+	supportID
+	adminID
+	otherID
+)
+
+// policySubjects mirrors the seeded roles: customers hold no permissions, support
+// reads everything and admins also write
+func policySubjects() map[string]*entity.Subject {
+	return map[string]*entity.Subject{
+		"owner":   policytest.Subject(ownerID, []string{"user"}),
+		"support": policytest.Subject(supportID, []string{"support"}, "users:read", "orders:read", "payments:read", "security_events:read"),
+		"admin":   policytest.Subject(adminID, []string{"admin"}, "orders:read", "orders:write", "orders:refund", "payments:read"),
+		"other":   policytest.Subject(otherID, []string{"user"}),
+	}
+}
+
+func TestOrderPolicy(t *testing.T) {
+	authz := NewAuthorizationService(nil, DefaultPolicies()...)
+	actions := []string{"read", "cancel", "refund", "delete"}
+
+	t.Run("pending", func(t *testing.T) {
+		order := &entity.Order{ID: 1, UserID: ownerID, Status: "pending"}
+		policytest.AssertMatrix(t, authz, policytest.Grid(order, policySubjects(), actions, map[string][]string{
+			"owner":   {"read", "cancel"},
+			"support": {"read"},
+			"admin":   {"read", "cancel"},
+		}))
+	})
+
+	t.Run("paid", func(t *testing.T) {
+		order := &entity.Order{ID: 2, UserID: ownerID, Status: "paid"}
+		policytest.AssertMatrix(t, authz, policytest.Grid(order, policySubjects(), actions, map[string][]string{
+			"owner":   {"read"},
+			"support": {"read"},
+			"admin":   {"read", "cancel", "refund"},
+		}))
+	})
+
+	t.Run("completed", func(t *testing.T) {
+		order := &entity.Order{ID: 3, UserID: ownerID, Status: "completed"}
+		policytest.AssertMatrix(t, authz, policytest.Grid(order, policySubjects(), actions, map[string][]string{
+			"owner":   {"read"},
+			"support": {"read"},
+			"admin":   {"read", "cancel", "refund"},
+		}))
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		order := &entity.Order{ID: 4, UserID: ownerID, Status: "cancelled"}
+		policytest.AssertMatrix(t, authz, policytest.Grid(order, policySubjects(), actions, map[string][]string{
+			"owner":   {"read"},
+			"support": {"read"},
+			"admin":   {"read", "cancel"},
+		}))
+	})
+}
+
+func TestPaymentPolicy(t *testing.T) {
+	authz := NewAuthorizationService(nil, DefaultPolicies()...)
+	payment := &entity.Payment{ID: 1, UserID: ownerID, Status: "pending"}
+
+	policytest.AssertMatrix(t, authz, policytest.Grid(payment, policySubjects(), []string{"read", "refund"}, map[string][]string{
+		"owner":   {"read"},
+		"support": {"read"},
+		"admin":   {"read"},
+	}))
+}
+
+func TestAuthorizeDenialReasons(t *testing.T) {
+	authz := NewAuthorizationService(nil, OrderPolicy)
+	order := &entity.Order{ID: 1, UserID: ownerID, Status: "pending"}
+	owner := policySubjects()["owner"]
+
+	tests := []struct {
+		name     string
+		subject  *entity.Subject
+		action   string
+		resource entity.Resource
+		reason   string
+	}{
+		{"no subject", nil, "read", order, "no subject"},
+		{"no policy", owner, "read", &entity.Payment{UserID: ownerID}, "no policy for resource type"},
+		{"no rule", owner, "delete", order, "no rule for action"},
+		{"condition", policySubjects()["other"], "read", order, "condition not met"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authz.Authorize(context.Background(), tt.subject, tt.action, tt.resource)
+			policytest.AssertDenied(t, tt.name, err, tt.action, tt.resource.ResourceType())
+
+			var denial *entity.PolicyDenial
+			if errors.As(err, &denial) && denial.Reason != tt.reason {
+				t.Errorf("reason = %q, want %q", denial.Reason, tt.reason)
+			}
+			if authz.Can(context.Background(), tt.subject, tt.action, tt.resource) {
+				t.Error("Can allowed a denied action")
+			}
+		})
+	}
+}
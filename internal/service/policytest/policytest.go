@@ -0,0 +1,95 @@
+// Package policytest asserts authorization policy matrices. Use it from
+// tests that exercise service.AuthorizationService.
+package policytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+)
+
+// Case is one row of a policy matrix
+type Case struct {
+	Name     string
+	Subject  *entity.Subject
+	Action   string
+	Resource entity.Resource
+	Allow    bool
+}
+
+// Subject builds a subject holding the given roles and permissions
+func Subject(userID uint, roles []string, permissions ...string) *entity.Subject {
+	return &entity.Subject{
+		UserID: userID,
+		Access: &entity.UserAccess{Roles: roles, Permissions: permissions},
+	}
+}
+
+// AssertMatrix checks every case, reporting all mismatches. Denials must be
+// entity.ErrForbidden carrying a PolicyDenial for the case's action and
+// resource type.
+func AssertMatrix(t testing.TB, authz service.AuthorizationService, cases []Case) {
+	t.Helper()
+
+	for _, c := range cases {
+		name := c.Name
+		if name == "" {
+			name = fmt.Sprintf("user %d %s %s", c.Subject.UserID, c.Action, c.Resource.ResourceType())
+		}
+
+		err := authz.Authorize(context.Background(), c.Subject, c.Action, c.Resource)
+		switch {
+		case c.Allow && err != nil:
+			t.Errorf("%s: expected allow, got %v", name, err)
+		case !c.Allow && err == nil:
+			t.Errorf("%s: expected deny, got allow", name)
+		case !c.Allow:
+			AssertDenied(t, name, err, c.Action, c.Resource.ResourceType())
+		}
+	}
+}
+
+// AssertDenied checks that err is a policy denial of action on resourceType
+func AssertDenied(t testing.TB, name string, err error, action, resourceType string) {
+	t.Helper()
+
+	if !errors.Is(err, entity.ErrForbidden) {
+		t.Errorf("%s: expected %v, got %v", name, entity.ErrForbidden, err)
+		return
+	}
+
+	var denial *entity.PolicyDenial
+	if !errors.As(err, &denial) {
+		t.Errorf("%s: expected a policy denial cause, got %v", name, err)
+		return
+	}
+	if denial.Action != action || denial.ResourceType != resourceType {
+		t.Errorf("%s: denial is for %s on %s", name, denial.Action, denial.ResourceType)
+	}
+}
+
+// Grid expands subjects × actions into cases for one resource. expected maps
+// a subject name to the actions it may perform; other actions must be denied.
+func Grid(resource entity.Resource, subjects map[string]*entity.Subject, actions []string, expected map[string][]string) []Case {
+	var cases []Case
+	for name, subject := range subjects {
+		allowed := make(map[string]bool, len(expected[name]))
+		for _, action := range expected[name] {
+			allowed[action] = true
+		}
+		for _, action := range actions {
+			cases = append(cases, Case{
+				Name:     fmt.Sprintf("%s %s %s", name, action, resource.ResourceType()),
+				Subject:  subject,
+				Action:   action,
+				Resource: resource,
+				Allow:    allowed[action],
+			})
+		}
+	}
+	return cases
+}
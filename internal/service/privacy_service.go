@@ -0,0 +1,505 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/pkg/utils"
+)
+
+// errExportTimedOut is recorded on exports that stopped without finishing
+var errExportTimedOut = errors.New("export timed out")
+
+type privacyService struct {
+	userRepo          repository.UserRepository
+	authRepo          repository.AuthRepository
+	orderRepo         repository.OrderRepository
+	paymentRepo       repository.PaymentRepository
+	privacyRepo       repository.PrivacyRepository
+	securityEventRepo repository.SecurityEventRepository
+	securityEvents    service.SecurityEventService
+	avatarService     service.AvatarService
+	rbacService       service.RBACService
+	config            *config.Config
+}
+
+// Export archive documents. Entities carry credentials (password hashes, TFA
+// secrets, session tokens) that must never leave the system, so each export
+// document lists its fields explicitly.
+type exportedProfile struct {
+	ID              uint       `json:"id"`
+	Email           string     `json:"email"`
+	Username        string     `json:"username"`
+	FirstName       string     `json:"first_name"`
+	LastName        string     `json:"last_name"`
+	Phone           string     `json:"phone"`
+	Avatar          string     `json:"avatar"`
+	Role            string     `json:"role"`
+	Status          string     `json:"status"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at"`
+	LastLoginAt     *time.Time `json:"last_login_at"`
+	TFAEnabled      bool       `json:"tfa_enabled"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+type exportedSession struct {
+	ID        uint      `json:"id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type exportedOrder struct {
+	ID          uint      `json:"id"`
+	OrderNumber string    `json:"order_number"`
+	TotalAmount float64   `json:"total_amount"`
+	Status      string    `json:"status"`
+	Note        string    `json:"note"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type exportedPayment struct {
+	ID            uint       `json:"id"`
+	OrderID       uint       `json:"order_id"`
+	Amount        float64    `json:"amount"`
+	Currency      string     `json:"currency"`
+	PaymentMethod string     `json:"payment_method"`
+	Status        string     `json:"status"`
+	Gateway       string     `json:"gateway"`
+	GatewayRef    string     `json:"gateway_ref"`
+	PaidAt        *time.Time `json:"paid_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type exportedSecurityEvent struct {
+	Event     string                 `json:"event"`
+	IPAddress string                 `json:"ip_address"`
+	UserAgent string                 `json:"user_agent"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// NewPrivacyService creates a new privacy service
+func NewPrivacyService(
+	userRepo repository.UserRepository,
+	authRepo repository.AuthRepository,
+	orderRepo repository.OrderRepository,
+	paymentRepo repository.PaymentRepository,
+	privacyRepo repository.PrivacyRepository,
+	securityEventRepo repository.SecurityEventRepository,
+	securityEvents service.SecurityEventService,
+	avatarService service.AvatarService,
+	rbacService service.RBACService,
+	config *config.Config,
+) service.PrivacyService {
+	return &privacyService{
+		userRepo:          userRepo,
+		authRepo:          authRepo,
+		orderRepo:         orderRepo,
+		paymentRepo:       paymentRepo,
+		privacyRepo:       privacyRepo,
+		securityEventRepo: securityEventRepo,
+		securityEvents:    securityEvents,
+		avatarService:     avatarService,
+		rbacService:       rbacService,
+		config:            config,
+	}
+}
+
+// RequestDataExport queues a personal data export and builds it in the background
+func (s *privacyService) RequestDataExport(ctx context.Context, userID uint) (*entity.DataExport, error) {
+	exports, err := s.privacyRepo.GetDataExportsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only one export may be in progress at a time. A build that stopped
+	// without finishing, e.g. on a restart, is failed so it does not block
+	// the user forever.
+	for _, export := range exports {
+		if export.IsStale(s.config.Privacy.ExportTimeout) {
+			export.MarkAsFailed(errExportTimedOut)
+			export.UpdatedAt = time.Now()
+			if err := s.privacyRepo.UpdateDataExport(ctx, export); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if export.IsPending() {
+			return nil, entity.ErrDataExportInProgress
+		}
+	}
+
+	export := &entity.DataExport{
+		UserID:    userID,
+		Status:    "pending",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := s.privacyRepo.CreateDataExport(ctx, export); err != nil {
+		return nil, err
+	}
+
+	s.securityEvents.Record(ctx, userID, entity.SecurityEventDataExportRequested, map[string]interface{}{
+		"export_id": export.ID,
+	})
+
+	// The request context is recycled once the handler returns. The export
+	// covers orders and payments the user made in any organization.
+	go s.buildDataExport(utils.WithAllTenants(context.Background()), *export)
+
+	return export, nil
+}
+
+// GetDataExport gets a data export owned by the user
+func (s *privacyService) GetDataExport(ctx context.Context, userID, exportID uint) (*entity.DataExport, error) {
+	export, err := s.privacyRepo.GetDataExportByID(ctx, exportID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if export belongs to user
+	if export.UserID != userID {
+		return nil, entity.ErrDataExportNotFound
+	}
+
+	return export, nil
+}
+
+// ListDataExports lists the user's data exports
+func (s *privacyService) ListDataExports(ctx context.Context, userID uint) ([]*entity.DataExport, error) {
+	return s.privacyRepo.GetDataExportsByUserID(ctx, userID)
+}
+
+// RequestAccountDeletion schedules account erasure after the grace period.
+// Callers confirm the user's identity with a recent authentication.
+func (s *privacyService) RequestAccountDeletion(ctx context.Context, userID uint) (*entity.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsPendingDeletion() {
+		return nil, entity.ErrDeletionAlreadyScheduled
+	}
+
+	user.ScheduleDeletion(s.config.Privacy.DeletionGracePeriod)
+	user.UpdatedAt = time.Now()
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	// Sign out everywhere; the user can still log in to cancel during the grace period
+	if err := s.authRepo.DeleteSessionsByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	s.securityEvents.Record(ctx, userID, entity.SecurityEventDeletionRequested, map[string]interface{}{
+		"scheduled_at": user.DeletionScheduledAt,
+	})
+
+	return user, nil
+}
+
+// CancelAccountDeletion cancels a scheduled account erasure
+func (s *privacyService) CancelAccountDeletion(ctx context.Context, userID uint) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.IsPendingDeletion() {
+		return entity.ErrDeletionNotScheduled
+	}
+
+	user.CancelDeletion()
+	user.UpdatedAt = time.Now()
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	s.securityEvents.Record(ctx, userID, entity.SecurityEventDeletionCancelled, nil)
+
+	return nil
+}
+
+// ProcessScheduledDeletions anonymizes accounts whose grace period has ended.
+// Orders and payments reference the user ID only and are kept for accounting.
+func (s *privacyService) ProcessScheduledDeletions(ctx context.Context) (int, error) {
+	users, err := s.userRepo.GetDueForDeletion(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for _, user := range users {
+		if err := s.eraseUser(ctx, user); err != nil {
+			log.Printf("Failed to erase user %d: %v", user.ID, err)
+			continue
+		}
+		processed++
+	}
+
+	return processed, nil
+}
+
+// CleanExpiredDataExports removes export archives past their download window
+func (s *privacyService) CleanExpiredDataExports(ctx context.Context) error {
+	exports, err := s.privacyRepo.GetExpiredDataExports(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, export := range exports {
+		if err := s.removeDataExport(ctx, export); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// eraseUser removes a user's exports and anonymizes their personal data
+func (s *privacyService) eraseUser(ctx context.Context, user *entity.User) error {
+	exports, err := s.privacyRepo.GetDataExportsByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, export := range exports {
+		if err := s.removeDataExport(ctx, export); err != nil {
+			return err
+		}
+	}
+
+	// Remove uploaded avatar files before the keys are cleared
+	if err := s.avatarService.Delete(ctx, user.ID); err != nil {
+		return err
+	}
+
+	user.Anonymize()
+	if err := s.userRepo.Anonymize(ctx, user); err != nil {
+		return err
+	}
+
+	// Role assignments are gone; drop the permissions cached from them
+	s.rbacService.InvalidateUserAccess(ctx, user.ID)
+
+	// Keep the audit trail but drop network identifiers
+	if err := s.securityEventRepo.AnonymizeByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	s.securityEvents.Record(ctx, user.ID, entity.SecurityEventAccountAnonymized, nil)
+
+	return nil
+}
+
+// removeDataExport deletes the export archive and its record
+func (s *privacyService) removeDataExport(ctx context.Context, export *entity.DataExport) error {
+	if export.FilePath != "" {
+		if err := os.Remove(export.FilePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return s.privacyRepo.DeleteDataExport(ctx, export.ID)
+}
+
+// buildDataExport collects the user's data and writes it to a ZIP archive. It
+// runs in its own goroutine, so it recovers from panics, and it gives up after
+// the export timeout, when the export would count as stale anyway.
+func (s *privacyService) buildDataExport(ctx context.Context, export entity.DataExport) {
+	export.MarkAsProcessing()
+	export.UpdatedAt = time.Now()
+	if err := s.privacyRepo.UpdateDataExport(ctx, &export); err != nil {
+		log.Printf("Failed to update data export %d: %v", export.ID, err)
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic while building data export %d: %v", export.ID, r)
+			s.finishDataExport(ctx, &export, "", errors.New("export failed unexpectedly"))
+		}
+	}()
+
+	buildCtx, cancel := context.WithTimeout(ctx, s.config.Privacy.ExportTimeout)
+	defer cancel()
+
+	filePath, err := s.writeDataExport(buildCtx, &export)
+	if err != nil {
+		log.Printf("Failed to build data export %d: %v", export.ID, err)
+	}
+	s.finishDataExport(ctx, &export, filePath, err)
+}
+
+// finishDataExport records the outcome of a build
+func (s *privacyService) finishDataExport(ctx context.Context, export *entity.DataExport, filePath string, err error) {
+	if err != nil {
+		export.MarkAsFailed(err)
+	} else {
+		export.MarkAsCompleted(filePath, s.config.Privacy.ExportTTL)
+	}
+
+	export.UpdatedAt = time.Now()
+	if err := s.privacyRepo.UpdateDataExport(ctx, export); err != nil {
+		log.Printf("Failed to update data export %d: %v", export.ID, err)
+	}
+}
+
+// writeDataExport writes the export archive and returns its path
+func (s *privacyService) writeDataExport(ctx context.Context, export *entity.DataExport) (string, error) {
+	documents, err := s.collectExportDocuments(ctx, export.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(s.config.Privacy.ExportDir, fmt.Sprintf("user-%d", export.UserID))
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", err
+	}
+
+	filePath := filepath.Join(dir, fmt.Sprintf("export-%d.zip", export.ID))
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o640)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	for _, name := range []string{"profile.json", "sessions.json", "orders.json", "payments.json", "security_events.json"} {
+		writer, err := archive.Create(name)
+		if err != nil {
+			return "", err
+		}
+
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(documents[name]); err != nil {
+			return "", err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return "", err
+	}
+
+	return filePath, nil
+}
+
+// collectExportDocuments gathers the export documents keyed by file name
+func (s *privacyService) collectExportDocuments(ctx context.Context, userID uint) (map[string]interface{}, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.authRepo.GetSessionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := s.orderRepo.GetByUserID(ctx, userID, repository.OrderFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.paymentRepo.GetByUserID(ctx, userID, repository.PaymentFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.securityEventRepo.GetByUserID(ctx, userID, repository.SecurityEventFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	exportedSessions := make([]exportedSession, len(sessions))
+	for i, session := range sessions {
+		exportedSessions[i] = exportedSession{
+			ID:        session.ID,
+			ExpiresAt: session.ExpiresAt,
+			CreatedAt: session.CreatedAt,
+		}
+	}
+
+	exportedOrders := make([]exportedOrder, len(orders))
+	for i, order := range orders {
+		exportedOrders[i] = exportedOrder{
+			ID:          order.ID,
+			OrderNumber: order.OrderNumber,
+			TotalAmount: order.TotalAmount,
+			Status:      order.Status,
+			Note:        order.Note,
+			CreatedAt:   order.CreatedAt,
+			UpdatedAt:   order.UpdatedAt,
+		}
+	}
+
+	exportedPayments := make([]exportedPayment, len(payments))
+	for i, payment := range payments {
+		exportedPayments[i] = exportedPayment{
+			ID:            payment.ID,
+			OrderID:       payment.OrderID,
+			Amount:        payment.Amount,
+			Currency:      payment.Currency,
+			PaymentMethod: payment.PaymentMethod,
+			Status:        payment.Status,
+			Gateway:       payment.Gateway,
+			GatewayRef:    payment.GatewayRef,
+			PaidAt:        payment.PaidAt,
+			CreatedAt:     payment.CreatedAt,
+		}
+	}
+
+	exportedEvents := make([]exportedSecurityEvent, len(events))
+	for i, event := range events {
+		exportedEvents[i] = exportedSecurityEvent{
+			Event:     event.Event,
+			IPAddress: event.IPAddress,
+			UserAgent: event.UserAgent,
+			Metadata:  event.Metadata,
+			CreatedAt: event.CreatedAt,
+		}
+	}
+
+	return map[string]interface{}{
+		"profile.json": exportedProfile{
+			ID:              user.ID,
+			Email:           user.Email,
+			Username:        user.Username,
+			FirstName:       user.FirstName,
+			LastName:        user.LastName,
+			Phone:           user.Phone,
+			Avatar:          user.Avatar,
+			Role:            user.Role,
+			Status:          user.Status,
+			EmailVerifiedAt: user.EmailVerifiedAt,
+			PhoneVerifiedAt: user.PhoneVerifiedAt,
+			LastLoginAt:     user.LastLoginAt,
+			TFAEnabled:      user.TFAEnabled,
+			CreatedAt:       user.CreatedAt,
+			UpdatedAt:       user.UpdatedAt,
+		},
+		"sessions.json":        exportedSessions,
+		"orders.json":          exportedOrders,
+		"payments.json":        exportedPayments,
+		"security_events.json": exportedEvents,
+	}, nil
+}
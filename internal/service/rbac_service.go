@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// rbacVersionKey is bumped whenever a role changes, which orphans every
+// cached permission set at once
+const rbacVersionKey = "rbac:version"
+
+type rbacService struct {
+	rbacRepo       repository.RBACRepository
+	userRepo       repository.UserRepository
+	securityEvents service.SecurityEventService
+	redis          *redis.Client
+	config         *config.Config
+}
+
+// NewRBACService creates a new RBAC service. Without Redis, every check
+// reads from the database.
+func NewRBACService(
+	rbacRepo repository.RBACRepository,
+	userRepo repository.UserRepository,
+	securityEvents service.SecurityEventService,
+	redis *redis.Client,
+	config *config.Config,
+) service.RBACService {
+	return &rbacService{
+		rbacRepo:       rbacRepo,
+		userRepo:       userRepo,
+		securityEvents: securityEvents,
+		redis:          redis,
+		config:         config,
+	}
+}
+
+// GetUserAccess returns the roles and permissions of a user, cached in Redis
+func (s *rbacService) GetUserAccess(ctx context.Context, userID uint) (*entity.UserAccess, error) {
+	key := s.cacheKey(ctx, userID)
+	if key != "" {
+		if data, err := s.redis.Get(ctx, key).Bytes(); err == nil {
+			var access entity.UserAccess
+			if json.Unmarshal(data, &access) == nil {
+				return &access, nil
+			}
+		}
+	}
+
+	access, err := s.rbacRepo.GetUserAccess(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if key != "" {
+		if data, err := json.Marshal(access); err == nil {
+			if err := s.redis.Set(ctx, key, data, s.config.RBAC.CacheTTL).Err(); err != nil {
+				log.Printf("Failed to cache permissions of user %d: %v", userID, err)
+			}
+		}
+	}
+
+	return access, nil
+}
+
+// HasPermission checks if a user holds a permission
+func (s *rbacService) HasPermission(ctx context.Context, userID uint, permission string) (bool, error) {
+	access, err := s.GetUserAccess(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return access.HasPermission(permission), nil
+}
+
+// ListRoles lists all roles
+func (s *rbacService) ListRoles(ctx context.Context) ([]*entity.Role, error) {
+	return s.rbacRepo.ListRoles(ctx)
+}
+
+// GetRole gets a role by ID
+func (s *rbacService) GetRole(ctx context.Context, id uint) (*entity.Role, error) {
+	return s.rbacRepo.GetRoleByID(ctx, id)
+}
+
+// CreateRole creates a role with its permissions
+func (s *rbacService) CreateRole(ctx context.Context, role *entity.Role, permissions []string) (*entity.Role, error) {
+	now := time.Now()
+	role.IsSystem = false
+	role.CreatedAt = now
+	role.UpdatedAt = now
+
+	if err := s.rbacRepo.CreateRole(ctx, role); err != nil {
+		return nil, err
+	}
+
+	if err := s.rbacRepo.SetRolePermissions(ctx, role.ID, permissions); err != nil {
+		// Do not leave a half-created role behind
+		if delErr := s.rbacRepo.DeleteRole(ctx, role.ID); delErr != nil {
+			log.Printf("Failed to remove role %d after a failed create: %v", role.ID, delErr)
+		}
+		return nil, err
+	}
+
+	return s.rbacRepo.GetRoleByID(ctx, role.ID)
+}
+
+// UpdateRole renames or describes a role. System roles keep their name.
+func (s *rbacService) UpdateRole(ctx context.Context, id uint, name, description string) (*entity.Role, error) {
+	role, err := s.rbacRepo.GetRoleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name != "" && name != role.Name {
+		if role.IsSystem {
+			return nil, entity.ErrSystemRole
+		}
+		role.Name = name
+	}
+	role.Description = description
+	role.UpdatedAt = time.Now()
+
+	if err := s.rbacRepo.UpdateRole(ctx, role); err != nil {
+		return nil, err
+	}
+
+	s.invalidateAll(ctx)
+	return role, nil
+}
+
+// DeleteRole deletes a custom role
+func (s *rbacService) DeleteRole(ctx context.Context, id uint) error {
+	role, err := s.rbacRepo.GetRoleByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if role.IsSystem {
+		return entity.ErrSystemRole
+	}
+
+	if err := s.rbacRepo.DeleteRole(ctx, id); err != nil {
+		return err
+	}
+
+	s.invalidateAll(ctx)
+	return nil
+}
+
+// SetRolePermissions replaces the permissions of a role
+func (s *rbacService) SetRolePermissions(ctx context.Context, id uint, permissions []string) (*entity.Role, error) {
+	if _, err := s.rbacRepo.GetRoleByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if err := s.rbacRepo.SetRolePermissions(ctx, id, permissions); err != nil {
+		return nil, err
+	}
+
+	s.invalidateAll(ctx)
+	return s.rbacRepo.GetRoleByID(ctx, id)
+}
+
+// ListPermissions lists all permissions
+func (s *rbacService) ListPermissions(ctx context.Context) ([]*entity.Permission, error) {
+	return s.rbacRepo.ListPermissions(ctx)
+}
+
+// GetUserRoles gets the roles held by a user
+func (s *rbacService) GetUserRoles(ctx context.Context, userID uint) ([]*entity.Role, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.rbacRepo.GetUserRoles(ctx, userID)
+}
+
+// AssignRole gives a role to a user
+func (s *rbacService) AssignRole(ctx context.Context, actorID, userID uint, roleName string) error {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return err
+	}
+
+	role, err := s.rbacRepo.GetRoleByName(ctx, roleName)
+	if err != nil {
+		return err
+	}
+
+	if err := s.rbacRepo.AssignRole(ctx, userID, role.ID); err != nil {
+		return err
+	}
+
+	s.invalidateUser(ctx, userID)
+	s.securityEvents.Record(ctx, userID, entity.SecurityEventRoleAssigned, map[string]interface{}{
+		"role":     role.Name,
+		"actor_id": actorID,
+	})
+
+	return nil
+}
+
+// RevokeRole removes a role from a user
+func (s *rbacService) RevokeRole(ctx context.Context, actorID, userID, roleID uint) error {
+	role, err := s.rbacRepo.GetRoleByID(ctx, roleID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.rbacRepo.RevokeRole(ctx, userID, role.ID); err != nil {
+		return err
+	}
+
+	s.invalidateUser(ctx, userID)
+	s.securityEvents.Record(ctx, userID, entity.SecurityEventRoleRevoked, map[string]interface{}{
+		"role":     role.Name,
+		"actor_id": actorID,
+	})
+
+	return nil
+}
+
+// cacheKey returns the cache key of a user's permissions, or "" when
+// caching is unavailable
+func (s *rbacService) cacheKey(ctx context.Context, userID uint) string {
+	if s.redis == nil {
+		return ""
+	}
+
+	version, err := s.redis.Get(ctx, rbacVersionKey).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return ""
+	}
+	return fmt.Sprintf("rbac:access:%d:%d", version, userID)
+}
+
+// InvalidateUserAccess drops the cached permissions of a user whose roles
+// changed outside this service, such as on account erasure
+func (s *rbacService) InvalidateUserAccess(ctx context.Context, userID uint) {
+	s.invalidateUser(ctx, userID)
+}
+
+func (s *rbacService) invalidateUser(ctx context.Context, userID uint) {
+	key := s.cacheKey(ctx, userID)
+	if key == "" {
+		return
+	}
+	if err := s.redis.Del(ctx, key).Err(); err != nil {
+		log.Printf("Failed to invalidate cached permissions of user %d: %v", userID, err)
+	}
+}
+
+func (s *rbacService) invalidateAll(ctx context.Context) {
+	if s.redis == nil {
+		return
+	}
+	if err := s.redis.Incr(ctx, rbacVersionKey).Err(); err != nil {
+		log.Printf("Failed to invalidate cached permissions: %v", err)
+	}
+}
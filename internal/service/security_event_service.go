@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/pkg/utils"
+)
+
+type securityEventService struct {
+	securityEventRepo repository.SecurityEventRepository
+}
+
+// NewSecurityEventService creates a new security event service
+func NewSecurityEventService(securityEventRepo repository.SecurityEventRepository) service.SecurityEventService {
+	return &securityEventService{
+		securityEventRepo: securityEventRepo,
+	}
+}
+
+// Record stores a security event for a user. Failures are logged rather than
+// returned so that auditing never breaks the operation being audited.
+// Events recorded during an impersonation carry impersonator_id.
+func (s *securityEventService) Record(ctx context.Context, userID uint, event string, metadata map[string]interface{}) {
+	if actorID := utils.ActorIDFromContext(ctx); actorID != 0 {
+		flagged := make(map[string]interface{}, len(metadata)+1)
+		for k, v := range metadata {
+			flagged[k] = v
+		}
+		flagged["impersonator_id"] = actorID
+		metadata = flagged
+	}
+
+	securityEvent := &entity.SecurityEvent{
+		UserID:    userID,
+		Event:     event,
+		IPAddress: utils.ClientIPFromContext(ctx),
+		UserAgent: utils.UserAgentFromContext(ctx),
+		Metadata:  metadata,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.securityEventRepo.Create(ctx, securityEvent); err != nil {
+		log.Printf("Failed to record security event %s for user %d: %v", event, userID, err)
+	}
+}
+
+// GetByUserID gets security events for a user
+func (s *securityEventService) GetByUserID(ctx context.Context, userID uint, filter repository.SecurityEventFilter) ([]*entity.SecurityEvent, error) {
+	return s.securityEventRepo.GetByUserID(ctx, userID, filter)
+}
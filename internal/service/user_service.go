@@ -3,27 +3,54 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
+	"unicode"
 
 	"boilerplate-go-fiber-v2/internal/domain/entity"
 	"boilerplate-go-fiber-v2/internal/domain/repository"
 	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/pkg/apperror"
 	"boilerplate-go-fiber-v2/pkg/utils"
+	"boilerplate-go-fiber-v2/pkg/validator"
+
+	"golang.org/x/sync/errgroup"
 )
 
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo        repository.UserRepository
+	passwordService service.PasswordService
+	securityEvents  service.SecurityEventService
 }
 
 // NewUserService creates a new user service
-func NewUserService(userRepo repository.UserRepository) service.UserService {
+func NewUserService(userRepo repository.UserRepository, passwordService service.PasswordService, securityEvents service.SecurityEventService) service.UserService {
 	return &userService{
-		userRepo: userRepo,
+		userRepo:        userRepo,
+		passwordService: passwordService,
+		securityEvents:  securityEvents,
 	}
 }
 
 // Register registers a new user
 func (s *userService) Register(ctx context.Context, user *entity.User) error {
+	if user.Phone != "" {
+		phone, err := validator.NormalizePhone(user.Phone)
+		if err != nil {
+			return validator.ErrValidationFailed.WithFields(
+				validator.NewFieldError("phone", "phone", "", utils.LanguageFromContext(ctx)))
+		}
+		user.Phone = phone
+	}
+
+	if err := s.checkUnique(ctx, user); err != nil {
+		return err
+	}
+
+	if err := s.passwordService.CheckCandidate(ctx, "password", user.Password); err != nil {
+		return err
+	}
+
 	// Hash password
 	hashedPassword, err := utils.HashPassword(user.Password)
 	if err != nil {
@@ -32,15 +59,134 @@ func (s *userService) Register(ctx context.Context, user *entity.User) error {
 	user.Password = hashedPassword
 
 	// Set default values
+	now := time.Now()
 	user.Role = "user"
 	user.Status = "active"
-	user.CreatedAt = time.Now()
-	user.UpdatedAt = time.Now()
+	user.PasswordChangedAt = &now
+	user.CreatedAt = now
+	user.UpdatedAt = now
 
 	// Create user
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		// A concurrent registration may have claimed a value after the check
+		if errors.Is(err, entity.ErrUserAlreadyExists) {
+			if uniqueErr := s.checkUnique(ctx, user); uniqueErr != nil {
+				return uniqueErr
+			}
+		}
+		return err
+	}
+
+	s.passwordService.Remember(ctx, user.ID, user.Password)
+
+	return nil
+}
+
+// RegisterExternal registers a user who signed in through a sign-in provider.
+// The user has no password, and a username is derived from the email address
+// when none is given.
+func (s *userService) RegisterExternal(ctx context.Context, user *entity.User) error {
+	if user.Username == "" {
+		username, err := s.availableUsername(ctx, user.Email)
+		if err != nil {
+			return err
+		}
+		user.Username = username
+	}
+
+	if err := s.checkUnique(ctx, user); err != nil {
+		return err
+	}
+
+	// Set default values
+	now := time.Now()
+	user.Password = ""
+	user.Role = "user"
+	user.Status = "active"
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
 	return s.userRepo.Create(ctx, user)
 }
 
+// availableUsername derives a free username from the local part of an email
+// address, adding a random suffix when it is taken
+func (s *userService) availableUsername(ctx context.Context, email string) (string, error) {
+	base := strings.Map(func(r rune) rune {
+		r = unicode.ToLower(r)
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, strings.SplitN(email, "@", 2)[0])
+	if len(base) < 3 || base[0] < 'a' {
+		base = "user" + base
+	}
+	if len(base) > 13 {
+		base = base[:13]
+	}
+
+	candidate := base
+	for attempt := 0; attempt < 5; attempt++ {
+		exists, err := s.userRepo.ExistsBy(ctx, "username", candidate, 0)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+
+		suffix, err := randomHex(3)
+		if err != nil {
+			return "", err
+		}
+		candidate = base + "_" + suffix
+	}
+	return "", entity.ErrUserAlreadyExists
+}
+
+// checkUnique looks up the user's unique fields concurrently and reports the
+// taken ones as field errors
+func (s *userService) checkUnique(ctx context.Context, user *entity.User) error {
+	checks := []struct {
+		field string
+		value string
+	}{
+		{"email", user.Email},
+		{"username", user.Username},
+		{"phone", user.Phone},
+	}
+	taken := make([]bool, len(checks))
+
+	g, gctx := errgroup.WithContext(ctx)
+	for i, check := range checks {
+		if check.value == "" {
+			continue
+		}
+		g.Go(func() error {
+			exists, err := s.userRepo.ExistsBy(gctx, check.field, check.value, user.ID)
+			taken[i] = exists
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	language := utils.LanguageFromContext(ctx)
+	var fields []apperror.FieldError
+	for i, check := range checks {
+		if taken[i] {
+			fields = append(fields, validator.NewFieldError(check.field, "unique", "", language))
+		}
+	}
+
+	if len(fields) > 0 {
+		return entity.ErrUserAlreadyExists.WithFields(fields...)
+	}
+	return nil
+}
+
 // GetByID gets a user by ID
 func (s *userService) GetByID(ctx context.Context, id uint) (*entity.User, error) {
 	return s.userRepo.GetByID(ctx, id)
@@ -86,8 +232,26 @@ func (s *userService) UpdateProfile(ctx context.Context, userID uint, updates ma
 	if lastName, ok := updates["last_name"].(string); ok {
 		user.LastName = lastName
 	}
-	if phone, ok := updates["phone"].(string); ok {
-		user.Phone = phone
+	if phone, ok := updates["phone"].(string); ok && phone != user.Phone {
+		normalized, err := validator.NormalizePhone(phone)
+		if err != nil {
+			return validator.ErrValidationFailed.WithFields(
+				validator.NewFieldError("phone", "phone", "", utils.LanguageFromContext(ctx)))
+		}
+
+		exists, err := s.userRepo.ExistsBy(ctx, "phone", normalized, user.ID)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return entity.ErrUserAlreadyExists.WithFields(
+				validator.NewFieldError("phone", "unique", "", utils.LanguageFromContext(ctx)))
+		}
+		// A new number has to be verified again
+		if normalized != user.Phone {
+			user.PhoneVerifiedAt = nil
+		}
+		user.Phone = normalized
 	}
 	if avatar, ok := updates["avatar"].(string); ok {
 		user.Avatar = avatar
@@ -106,7 +270,15 @@ func (s *userService) ChangePassword(ctx context.Context, userID uint, oldPasswo
 
 	// Verify old password
 	if !utils.CheckPassword(oldPassword, user.Password) {
-		return errors.New("invalid old password")
+		return entity.ErrInvalidOldPassword
+	}
+
+	if err := s.passwordService.CheckCandidate(ctx, "new_password", newPassword); err != nil {
+		return err
+	}
+
+	if err := s.passwordService.CheckReuse(ctx, user, "new_password", newPassword); err != nil {
+		return err
 	}
 
 	// Hash new password
@@ -115,9 +287,19 @@ func (s *userService) ChangePassword(ctx context.Context, userID uint, oldPasswo
 		return err
 	}
 
+	now := time.Now()
 	user.Password = hashedPassword
-	user.UpdatedAt = time.Now()
-	return s.userRepo.Update(ctx, user)
+	user.PasswordChangedAt = &now
+	user.UpdatedAt = now
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	s.passwordService.Remember(ctx, userID, hashedPassword)
+
+	s.securityEvents.Record(ctx, userID, entity.SecurityEventPasswordChanged, nil)
+
+	return nil
 }
 
 // UpdateStatus updates user status
@@ -132,7 +314,7 @@ func (s *userService) UpdateStatus(ctx context.Context, userID uint, status stri
 		}
 	}
 	if !isValid {
-		return errors.New("invalid status")
+		return entity.ErrInvalidUserStatus
 	}
 
 	return s.userRepo.UpdateStatus(ctx, userID, status)
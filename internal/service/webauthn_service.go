@@ -0,0 +1,404 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"log"
+	"time"
+
+	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/internal/domain/entity"
+	"boilerplate-go-fiber-v2/internal/domain/repository"
+	"boilerplate-go-fiber-v2/internal/domain/service"
+	"boilerplate-go-fiber-v2/pkg/utils"
+	"boilerplate-go-fiber-v2/pkg/webauthn"
+)
+
+// defaultPasskeyName names passkeys registered without a name
+const defaultPasskeyName = "Passkey"
+
+type webAuthnService struct {
+	rp             *webauthn.RelyingParty
+	webauthnRepo   repository.WebAuthnRepository
+	userRepo       repository.UserRepository
+	identityRepo   repository.IdentityRepository
+	authService    service.AuthService
+	securityEvents service.SecurityEventService
+	config         *config.Config
+}
+
+// NewWebAuthnService creates a new passkey service
+func NewWebAuthnService(
+	webauthnRepo repository.WebAuthnRepository,
+	userRepo repository.UserRepository,
+	identityRepo repository.IdentityRepository,
+	authService service.AuthService,
+	securityEvents service.SecurityEventService,
+	config *config.Config,
+) service.WebAuthnService {
+	return &webAuthnService{
+		rp: webauthn.New(webauthn.Config{
+			RPID:             config.WebAuthn.RPID,
+			RPName:           config.WebAuthn.RPName,
+			Origins:          config.WebAuthn.Origins,
+			Timeout:          config.WebAuthn.Timeout,
+			UserVerification: config.WebAuthn.UserVerification,
+			Attestation:      config.WebAuthn.Attestation,
+		}),
+		webauthnRepo:   webauthnRepo,
+		userRepo:       userRepo,
+		identityRepo:   identityRepo,
+		authService:    authService,
+		securityEvents: securityEvents,
+		config:         config,
+	}
+}
+
+// BeginRegistration returns the options that create a passkey for the user.
+// All passkeys of a user share one user handle, so an authenticator keeps a
+// single passkey per account.
+func (s *webAuthnService) BeginRegistration(ctx context.Context, userID uint) (*webauthn.CreationOptions, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := s.webauthnRepo.ListCredentials(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var userHandle []byte
+	exclude := make([]webauthn.CredentialDescriptor, len(credentials))
+	for i, credential := range credentials {
+		exclude[i] = webauthn.NewCredentialDescriptor(credential.CredentialID, credential.Transports)
+		userHandle = credential.UserHandle
+	}
+	if userHandle == nil {
+		userHandle = make([]byte, 32)
+		if _, err := rand.Read(userHandle); err != nil {
+			return nil, err
+		}
+	}
+
+	challenge, err := s.createChallenge(ctx, entity.WebAuthnPurposeRegister, userID, userHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	displayName := user.GetFullName()
+	if displayName == "" {
+		displayName = user.Email
+	}
+	return s.rp.CreationOptions(challenge, webauthn.User{
+		ID:          userHandle,
+		Name:        user.Email,
+		DisplayName: displayName,
+	}, exclude), nil
+}
+
+// FinishRegistration verifies the authenticator's response and stores the
+// new passkey
+func (s *webAuthnService) FinishRegistration(ctx context.Context, userID uint, name string, resp *webauthn.RegistrationResponse) (*entity.WebAuthnCredential, error) {
+	challenge, err := resp.Challenge()
+	if err != nil {
+		return nil, entity.ErrPasskeyVerificationFailed.Wrap(err)
+	}
+
+	stored, err := s.claimChallenge(ctx, challenge, entity.WebAuthnPurposeRegister, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	verified, err := s.rp.VerifyRegistration(resp, challenge)
+	if err != nil {
+		return nil, entity.ErrPasskeyVerificationFailed.Wrap(err)
+	}
+
+	if name == "" {
+		name = defaultPasskeyName
+	}
+	credential := &entity.WebAuthnCredential{
+		UserID:            userID,
+		Name:              name,
+		CredentialID:      verified.ID,
+		PublicKey:         verified.PublicKey,
+		Algorithm:         verified.Algorithm,
+		SignCount:         verified.SignCount,
+		AAGUID:            verified.AAGUID,
+		UserHandle:        stored.UserHandle,
+		AttestationFormat: verified.AttestationFormat,
+		Transports:        verified.Transports,
+		BackupEligible:    verified.BackupEligible,
+		BackedUp:          verified.BackedUp,
+	}
+
+	if err := s.webauthnRepo.CreateCredential(ctx, credential); err != nil {
+		return nil, err
+	}
+
+	s.securityEvents.Record(ctx, userID, entity.SecurityEventPasskeyRegistered, map[string]interface{}{
+		"passkey_id":         credential.ID,
+		"attestation_format": verified.AttestationFormat,
+		"attestation_type":   verified.AttestationType,
+		"backup_eligible":    verified.BackupEligible,
+	})
+
+	return credential, nil
+}
+
+// BeginLogin returns the options of a passkey login. The allow list is empty,
+// so the user picks any of their passkeys and no account is revealed before
+// they do.
+func (s *webAuthnService) BeginLogin(ctx context.Context) (*webauthn.RequestOptions, error) {
+	challenge, err := s.createChallenge(ctx, entity.WebAuthnPurposeLogin, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.rp.RequestOptions(challenge, nil), nil
+}
+
+// FinishLogin signs in the owner of the passkey that answered a login
+// challenge. The passkey is a first factor, and both factors when the
+// authenticator verified the user; users with TFA enabled get a TFA challenge
+// for a passkey that did not.
+func (s *webAuthnService) FinishLogin(ctx context.Context, resp *webauthn.AssertionResponse) (*entity.User, *entity.AuthSession, *entity.LoginChallenge, error) {
+	credential, assertion, err := s.verifyAssertion(ctx, resp, entity.WebAuthnPurposeLogin, 0)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, credential.UserID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if !user.IsActive() {
+		return nil, nil, nil, entity.ErrAccountInactive
+	}
+
+	return s.authService.CompleteLogin(ctx, user, passkeyMethods(assertion)...)
+}
+
+// BeginTFA returns the options that answer a TFA challenge with one of the
+// passkeys of the user logging in
+func (s *webAuthnService) BeginTFA(ctx context.Context, token string) (*webauthn.RequestOptions, error) {
+	login, err := s.authService.GetPendingLogin(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return s.userRequestOptions(ctx, entity.WebAuthnPurposeTFA, login.UserID)
+}
+
+// FinishTFA finishes a login held by a TFA challenge with a passkey of the
+// user logging in
+func (s *webAuthnService) FinishTFA(ctx context.Context, token string, resp *webauthn.AssertionResponse) (*entity.User, *entity.AuthSession, *entity.LoginChallenge, error) {
+	login, err := s.authService.GetPendingLogin(ctx, token)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	_, assertion, err := s.verifyAssertion(ctx, resp, entity.WebAuthnPurposeTFA, login.UserID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return s.authService.ContinueLogin(ctx, token, passkeyMethods(assertion)...)
+}
+
+// BeginVerify returns the options that confirm the current session with one
+// of the user's passkeys
+func (s *webAuthnService) BeginVerify(ctx context.Context, userID uint) (*webauthn.RequestOptions, error) {
+	return s.userRequestOptions(ctx, entity.WebAuthnPurposeVerify, userID)
+}
+
+// FinishVerify marks the session as recently authenticated with a passkey,
+// as step-up for sensitive operations. Logins waiting for a second factor go
+// through FinishTFA instead.
+func (s *webAuthnService) FinishVerify(ctx context.Context, userID, sessionID uint, resp *webauthn.AssertionResponse) (*entity.AuthSession, error) {
+	_, assertion, err := s.verifyAssertion(ctx, resp, entity.WebAuthnPurposeVerify, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.authService.StepUp(ctx, sessionID, passkeyMethods(assertion)...)
+}
+
+// ListCredentials lists the passkeys of a user
+func (s *webAuthnService) ListCredentials(ctx context.Context, userID uint) ([]*entity.WebAuthnCredential, error) {
+	return s.webauthnRepo.ListCredentials(ctx, userID)
+}
+
+// RenameCredential renames one of the user's passkeys
+func (s *webAuthnService) RenameCredential(ctx context.Context, userID, id uint, name string) (*entity.WebAuthnCredential, error) {
+	return s.webauthnRepo.RenameCredential(ctx, id, userID, name)
+}
+
+// DeleteCredential removes one of the user's passkeys, unless it is their
+// only way to sign in
+func (s *webAuthnService) DeleteCredential(ctx context.Context, userID, id uint) error {
+	credentials, err := s.webauthnRepo.ListCredentials(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	var credential *entity.WebAuthnCredential
+	for _, c := range credentials {
+		if c.ID == id {
+			credential = c
+			break
+		}
+	}
+	if credential == nil {
+		return entity.ErrPasskeyNotFound
+	}
+
+	if len(credentials) == 1 {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		identities, err := s.identityRepo.ListByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if !user.HasPassword() && len(identities) == 0 {
+			return entity.ErrLastSignInMethod
+		}
+	}
+
+	if err := s.webauthnRepo.DeleteCredential(ctx, id, userID); err != nil {
+		return err
+	}
+
+	s.securityEvents.Record(ctx, userID, entity.SecurityEventPasskeyRemoved, map[string]interface{}{
+		"passkey_id": id,
+		"name":       credential.Name,
+	})
+
+	return nil
+}
+
+// userRequestOptions starts a ceremony that only the passkeys of userID can
+// answer
+func (s *webAuthnService) userRequestOptions(ctx context.Context, purpose string, userID uint) (*webauthn.RequestOptions, error) {
+	credentials, err := s.webauthnRepo.ListCredentials(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(credentials) == 0 {
+		return nil, entity.ErrPasskeyNotFound
+	}
+
+	allow := make([]webauthn.CredentialDescriptor, len(credentials))
+	for i, credential := range credentials {
+		allow[i] = webauthn.NewCredentialDescriptor(credential.CredentialID, credential.Transports)
+	}
+
+	challenge, err := s.createChallenge(ctx, purpose, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.rp.RequestOptions(challenge, allow), nil
+}
+
+// createChallenge stores the challenge of a new ceremony. Only a hash is
+// stored, so a database read cannot answer a ceremony.
+func (s *webAuthnService) createChallenge(ctx context.Context, purpose string, userID uint, userHandle []byte) (string, error) {
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return "", err
+	}
+
+	err = s.webauthnRepo.CreateChallenge(ctx, &entity.WebAuthnChallenge{
+		ChallengeHash: utils.HashToken(challenge),
+		Purpose:       purpose,
+		UserID:        userID,
+		UserHandle:    userHandle,
+		ExpiresAt:     time.Now().Add(s.config.WebAuthn.ChallengeTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return challenge, nil
+}
+
+// claimChallenge consumes the ceremony a response answers. The challenge is
+// gone even when verification fails later, so every challenge works once.
+func (s *webAuthnService) claimChallenge(ctx context.Context, challenge, purpose string, userID uint) (*entity.WebAuthnChallenge, error) {
+	stored, err := s.webauthnRepo.ClaimChallenge(ctx, utils.HashToken(challenge))
+	if err != nil {
+		return nil, err
+	}
+
+	if stored.IsExpired() || stored.Purpose != purpose || stored.UserID != userID {
+		return nil, entity.ErrInvalidWebAuthnChallenge
+	}
+
+	return stored, nil
+}
+
+// verifyAssertion checks an authentication response for a ceremony and
+// records the use of the passkey. A passkey of another user than userID (or
+// any user for a login) is rejected.
+func (s *webAuthnService) verifyAssertion(ctx context.Context, resp *webauthn.AssertionResponse, purpose string, userID uint) (*entity.WebAuthnCredential, *webauthn.Assertion, error) {
+	challenge, err := resp.Challenge()
+	if err != nil {
+		return nil, nil, entity.ErrPasskeyVerificationFailed.Wrap(err)
+	}
+
+	if _, err := s.claimChallenge(ctx, challenge, purpose, userID); err != nil {
+		return nil, nil, err
+	}
+
+	credentialID, err := resp.CredentialID()
+	if err != nil {
+		return nil, nil, entity.ErrPasskeyVerificationFailed.Wrap(err)
+	}
+
+	credential, err := s.webauthnRepo.GetCredentialByCredentialID(ctx, credentialID)
+	if err != nil {
+		if errors.Is(err, entity.ErrPasskeyNotFound) {
+			return nil, nil, entity.ErrPasskeyVerificationFailed
+		}
+		return nil, nil, err
+	}
+	if userID != 0 && credential.UserID != userID {
+		return nil, nil, entity.ErrPasskeyVerificationFailed
+	}
+
+	userHandle, err := resp.UserHandle()
+	if err != nil {
+		return nil, nil, entity.ErrPasskeyVerificationFailed.Wrap(err)
+	}
+	if userHandle != nil && !bytes.Equal(userHandle, credential.UserHandle) {
+		return nil, nil, entity.ErrPasskeyVerificationFailed
+	}
+
+	assertion, err := s.rp.VerifyAssertion(resp, challenge, credential.PublicKey, credential.SignCount)
+	if err != nil {
+		return nil, nil, entity.ErrPasskeyVerificationFailed.Wrap(err)
+	}
+
+	now := time.Now()
+	credential.SignCount = assertion.SignCount
+	credential.BackedUp = assertion.BackedUp
+	credential.LastUsedAt = &now
+	credential.UpdatedAt = now
+	if err := s.webauthnRepo.UpdateCredential(ctx, credential); err != nil {
+		log.Printf("Failed to update passkey %d after use: %v", credential.ID, err)
+	}
+
+	return credential, assertion, nil
+}
+
+// passkeyMethods returns the authentication methods proven by an assertion
+func passkeyMethods(assertion *webauthn.Assertion) []string {
+	if assertion.UserVerified {
+		return []string{entity.AuthMethodPasskey, entity.AuthMethodMFA}
+	}
+	return []string{entity.AuthMethodPasskey}
+}
@@ -0,0 +1,5 @@
+-- Migration 00004: create_orders_and_payments
+-- Down migration
+DROP TABLE IF EXISTS payments;
+
+DROP TABLE IF EXISTS orders;
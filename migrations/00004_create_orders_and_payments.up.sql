@@ -0,0 +1,44 @@
+-- Migration 00004: create_orders_and_payments
+-- Up migration
+-- Create orders table
+CREATE TABLE orders (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    order_number VARCHAR(50) UNIQUE NOT NULL,
+    total_amount NUMERIC(15, 2) NOT NULL,
+    status VARCHAR(20) DEFAULT 'pending',
+    note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create payments table
+CREATE TABLE payments (
+    id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL REFERENCES orders(id),
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    amount NUMERIC(15, 2) NOT NULL,
+    currency VARCHAR(3) DEFAULT 'IDR',
+    payment_method VARCHAR(50) NOT NULL,
+    status VARCHAR(20) DEFAULT 'pending',
+    gateway VARCHAR(50) NOT NULL,
+    gateway_ref VARCHAR(255),
+    gateway_data JSONB,
+    expires_at TIMESTAMP,
+    paid_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX idx_orders_user_id ON orders(user_id);
+
+CREATE INDEX idx_orders_status ON orders(status);
+
+CREATE INDEX idx_payments_order_id ON payments(order_id);
+
+CREATE INDEX idx_payments_user_id ON payments(user_id);
+
+CREATE INDEX idx_payments_gateway_ref ON payments(gateway_ref);
+
+CREATE INDEX idx_payments_status ON payments(status);
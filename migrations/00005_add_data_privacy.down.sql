@@ -0,0 +1,11 @@
+-- Migration 00005: add_data_privacy
+-- Down migration
+DROP TABLE IF EXISTS security_events CASCADE;
+
+DROP TABLE IF EXISTS data_exports CASCADE;
+
+-- Remove account deletion fields from users table
+ALTER TABLE
+    users DROP COLUMN IF EXISTS deletion_requested_at,
+    DROP COLUMN IF EXISTS deletion_scheduled_at,
+    DROP COLUMN IF EXISTS anonymized_at;
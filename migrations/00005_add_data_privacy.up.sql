@@ -0,0 +1,57 @@
+-- Migration 00005: add_data_privacy
+-- Up migration
+-- Add account deletion fields to users table
+ALTER TABLE
+    users
+ADD
+    COLUMN deletion_requested_at TIMESTAMP,
+ADD
+    COLUMN deletion_scheduled_at TIMESTAMP,
+ADD
+    COLUMN anonymized_at TIMESTAMP;
+
+-- Create data_exports table
+CREATE TABLE data_exports (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    file_path VARCHAR(500),
+    error TEXT,
+    expires_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create security_events table
+CREATE TABLE security_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event VARCHAR(50) NOT NULL,
+    ip_address VARCHAR(45),
+    user_agent VARCHAR(500),
+    metadata JSONB,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX idx_users_deletion_scheduled_at ON users(deletion_scheduled_at);
+
+CREATE INDEX idx_data_exports_user_id ON data_exports(user_id);
+
+CREATE INDEX idx_data_exports_expires_at ON data_exports(expires_at);
+
+CREATE INDEX idx_security_events_user_id ON security_events(user_id);
+
+CREATE INDEX idx_security_events_event ON security_events(event);
+
+CREATE INDEX idx_security_events_created_at ON security_events(created_at);
+
+-- Add comment for documentation
+COMMENT ON TABLE data_exports IS 'User-initiated personal data export archives';
+
+COMMENT ON TABLE security_events IS 'Security-relevant account activity';
+
+COMMENT ON COLUMN users.deletion_scheduled_at IS 'When the account will be anonymized';
+
+COMMENT ON COLUMN users.anonymized_at IS 'When personal data was erased';
@@ -0,0 +1,4 @@
+-- Migration 00006: add_avatar_variants
+-- Down migration
+ALTER TABLE
+    users DROP COLUMN IF EXISTS avatar_variants;
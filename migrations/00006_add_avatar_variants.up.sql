@@ -0,0 +1,9 @@
+-- Migration 00006: add_avatar_variants
+-- Up migration
+-- Storage keys of uploaded avatar variants, keyed by size
+ALTER TABLE
+    users
+ADD
+    COLUMN avatar_variants JSONB;
+
+COMMENT ON COLUMN users.avatar_variants IS 'Storage keys of uploaded avatar variants keyed by pixel size';
@@ -0,0 +1,4 @@
+-- Migration 00007: add_users_phone_unique
+-- Down migration
+-- Normalized and deduplicated phone numbers are not restored
+DROP INDEX IF EXISTS idx_users_phone_unique;
@@ -0,0 +1,66 @@
+-- Migration 00007: add_users_phone_unique
+-- Up migration
+-- Phone numbers are stored in E.164 and must be unique; accounts without a
+-- phone (including anonymized ones) store an empty string
+
+-- Bring numbers stored before validation closer to E.164 the way
+-- NormalizePhone does: drop separators and turn a 00 prefix into +. National
+-- numbers with a 0 trunk prefix need the configured country code and are
+-- left as they are.
+UPDATE
+    users
+SET
+    phone = regexp_replace(btrim(phone), '[ .()-]', '', 'g')
+WHERE
+    phone ~ '[ .()-]';
+
+UPDATE
+    users
+SET
+    phone = '+' || substr(phone, 3)
+WHERE
+    phone ~ '^00[1-9]';
+
+UPDATE
+    users
+SET
+    phone = '+' || phone
+WHERE
+    phone ~ '^[1-9]';
+
+-- Keep each duplicated number on one account only, preferring a verified
+-- account, then the oldest; the others lose the number and have to add it again
+UPDATE
+    users
+SET
+    phone = NULL,
+    phone_verified_at = NULL
+WHERE
+    id IN (
+        SELECT
+            id
+        FROM
+            (
+                SELECT
+                    id,
+                    ROW_NUMBER() OVER (
+                        PARTITION BY phone
+                        ORDER BY
+                            phone_verified_at IS NULL,
+                            deleted_at IS NOT NULL,
+                            id
+                    ) AS rank
+                FROM
+                    users
+                WHERE
+                    phone IS NOT NULL
+                    AND phone <> ''
+            ) AS ranked
+        WHERE
+            rank > 1
+    );
+
+CREATE UNIQUE INDEX idx_users_phone_unique ON users(phone)
+WHERE
+    phone IS NOT NULL
+    AND phone <> '';
@@ -0,0 +1,7 @@
+-- Migration 00008: create_password_history
+-- Down migration
+DROP TABLE IF EXISTS password_history CASCADE;
+
+-- Remove password age field from users table
+ALTER TABLE
+    users DROP COLUMN IF EXISTS password_changed_at;
@@ -0,0 +1,29 @@
+-- Migration 00008: create_password_history
+-- Up migration
+-- Track when the password was last set, for the maximum password age
+ALTER TABLE
+    users
+ADD
+    COLUMN password_changed_at TIMESTAMP;
+
+-- Existing users get a full maximum age window from now
+UPDATE
+    users
+SET
+    password_changed_at = CURRENT_TIMESTAMP;
+
+-- Create password_history table
+CREATE TABLE password_history (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX idx_password_history_user_id_created_at ON password_history(user_id, created_at DESC);
+
+-- Add comment for documentation
+COMMENT ON TABLE password_history IS 'Recent password hashes per user, used to prevent reuse';
+
+COMMENT ON COLUMN users.password_changed_at IS 'When the password was last set';
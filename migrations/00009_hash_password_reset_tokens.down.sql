@@ -0,0 +1,10 @@
+-- Migration 00009: hash_password_reset_tokens
+-- Down migration
+-- Hashed tokens cannot be restored; drop them
+DELETE FROM
+    password_resets;
+
+DROP INDEX IF EXISTS idx_password_resets_user_id_used;
+
+ALTER TABLE
+    password_resets DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,20 @@
+-- Migration 00009: hash_password_reset_tokens
+-- Up migration
+-- Reset tokens are stored as SHA-256 hex digests; hash the outstanding ones
+-- so links that were already sent keep working
+UPDATE
+    password_resets
+SET
+    token = encode(sha256(convert_to(token, 'UTF8')), 'hex');
+
+-- The entity tracks updates, e.g. when older tokens are invalidated
+ALTER TABLE
+    password_resets
+ADD
+    COLUMN IF NOT EXISTS updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+
+-- Create indexes
+CREATE INDEX idx_password_resets_user_id_used ON password_resets(user_id, used);
+
+-- Add comment for documentation
+COMMENT ON COLUMN password_resets.token IS 'SHA-256 hex digest of the reset token';
@@ -0,0 +1,15 @@
+-- Migration 00010: add_email_change
+-- Down migration
+DELETE FROM
+    email_verifications
+WHERE
+    purpose = 'change';
+
+DROP INDEX IF EXISTS idx_email_verifications_user_id_purpose;
+
+-- Remove email change fields from email_verifications table
+ALTER TABLE
+    email_verifications DROP COLUMN IF EXISTS purpose,
+    DROP COLUMN IF EXISTS previous_email,
+    DROP COLUMN IF EXISTS cancel_token,
+    DROP COLUMN IF EXISTS cancelled_at;
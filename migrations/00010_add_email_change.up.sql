@@ -0,0 +1,25 @@
+-- Migration 00010: add_email_change
+-- Up migration
+-- Email verifications also track pending email changes
+ALTER TABLE
+    email_verifications
+ADD
+    COLUMN purpose VARCHAR(20) NOT NULL DEFAULT 'verify',
+ADD
+    COLUMN previous_email VARCHAR(255),
+ADD
+    COLUMN cancel_token VARCHAR(255) UNIQUE,
+ADD
+    COLUMN cancelled_at TIMESTAMP;
+
+-- Create indexes
+CREATE INDEX idx_email_verifications_user_id_purpose ON email_verifications(user_id, purpose);
+
+-- Add comment for documentation
+COMMENT ON COLUMN email_verifications.purpose IS 'verify for sign-up verification, change for email changes';
+
+COMMENT ON COLUMN email_verifications.previous_email IS 'Address the change started from, restored on cancel';
+
+COMMENT ON COLUMN email_verifications.cancel_token IS 'SHA-256 hex digest of the token sent to the previous address';
+
+COMMENT ON COLUMN email_verifications.cancelled_at IS 'When the change was cancelled or reverted';
@@ -0,0 +1,22 @@
+-- Migration 00011: add_phone_verification
+-- Down migration
+DELETE FROM
+    tfa_codes
+WHERE
+    purpose <> 'login'
+    OR LENGTH(code) > 10;
+
+DROP INDEX IF EXISTS idx_tfa_codes_user_id_purpose;
+
+-- Remove phone verification fields from tfa_codes table
+ALTER TABLE
+    tfa_codes DROP COLUMN IF EXISTS purpose,
+    DROP COLUMN IF EXISTS channel,
+    DROP COLUMN IF EXISTS destination,
+    DROP COLUMN IF EXISTS attempts,
+    DROP COLUMN IF EXISTS updated_at;
+
+ALTER TABLE
+    tfa_codes
+ALTER COLUMN
+    code TYPE VARCHAR(10);
@@ -0,0 +1,33 @@
+-- Migration 00011: add_phone_verification
+-- Up migration
+-- One-time codes carry a purpose and a destination so that the same table
+-- serves login codes and phone verification codes
+ALTER TABLE
+    tfa_codes
+ALTER COLUMN
+    code TYPE VARCHAR(64);
+
+ALTER TABLE
+    tfa_codes
+ADD
+    COLUMN purpose VARCHAR(20) NOT NULL DEFAULT 'login',
+ADD
+    COLUMN channel VARCHAR(20) NOT NULL DEFAULT 'email',
+ADD
+    COLUMN destination VARCHAR(255),
+ADD
+    COLUMN attempts INTEGER NOT NULL DEFAULT 0,
+ADD
+    COLUMN updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+
+-- Create indexes
+CREATE INDEX idx_tfa_codes_user_id_purpose ON tfa_codes(user_id, purpose, created_at);
+
+-- Add comment for documentation
+COMMENT ON COLUMN tfa_codes.purpose IS 'login for second-factor codes, phone_verify for phone verification';
+
+COMMENT ON COLUMN tfa_codes.channel IS 'Delivery channel: email or sms';
+
+COMMENT ON COLUMN tfa_codes.destination IS 'Email address or E.164 phone number the code was sent to';
+
+COMMENT ON COLUMN tfa_codes.attempts IS 'Failed verification attempts against this code';
@@ -0,0 +1,5 @@
+-- Migration 00012: scope_tfa_codes
+-- Down migration
+COMMENT ON COLUMN tfa_codes.code IS NULL;
+
+CREATE INDEX idx_tfa_codes_code ON tfa_codes(code);
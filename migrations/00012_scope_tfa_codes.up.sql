@@ -0,0 +1,13 @@
+-- Migration 00012: scope_tfa_codes
+-- Up migration
+-- Codes are now stored as HMAC digests and looked up per user and purpose.
+-- Plaintext codes cannot be converted and live for minutes, so drop them.
+DELETE FROM
+    tfa_codes
+WHERE
+    LENGTH(code) <= 10;
+
+DROP INDEX IF EXISTS idx_tfa_codes_code;
+
+-- Add comment for documentation
+COMMENT ON COLUMN tfa_codes.code IS 'HMAC-SHA256 hex digest of the code, keyed by OTP_SECRET';
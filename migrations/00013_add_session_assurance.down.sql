@@ -0,0 +1,7 @@
+-- Migration 00013: add_session_assurance
+-- Down migration
+-- Remove assurance fields from auth_sessions table
+ALTER TABLE
+    auth_sessions DROP COLUMN IF EXISTS auth_time,
+    DROP COLUMN IF EXISTS amr,
+    DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,23 @@
+-- Migration 00013: add_session_assurance
+-- Up migration
+-- Sessions remember when and how the user last proved their identity, so
+-- sensitive operations can require a recent re-authentication
+ALTER TABLE
+    auth_sessions
+ADD
+    COLUMN auth_time TIMESTAMP,
+ADD
+    COLUMN amr VARCHAR(100) NOT NULL DEFAULT '',
+ADD
+    COLUMN updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+
+UPDATE
+    auth_sessions
+SET
+    auth_time = created_at,
+    amr = 'pwd';
+
+-- Add comment for documentation
+COMMENT ON COLUMN auth_sessions.auth_time IS 'Last time the user authenticated in this session (login or step-up)';
+
+COMMENT ON COLUMN auth_sessions.amr IS 'Comma-separated authentication methods used (RFC 8176), e.g. pwd,otp';
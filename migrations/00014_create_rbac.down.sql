@@ -0,0 +1,9 @@
+-- Migration 00014: create_rbac
+-- Down migration
+DROP TABLE IF EXISTS user_roles;
+
+DROP TABLE IF EXISTS role_permissions;
+
+DROP TABLE IF EXISTS permissions;
+
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,89 @@
+-- Migration 00014: create_rbac
+-- Up migration
+-- Create roles table
+CREATE TABLE roles (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(50) UNIQUE NOT NULL,
+    description VARCHAR(255),
+    is_system BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create permissions table
+CREATE TABLE permissions (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) UNIQUE NOT NULL,
+    description VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create role_permissions table
+CREATE TABLE role_permissions (
+    role_id BIGINT NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    permission_id BIGINT NOT NULL REFERENCES permissions(id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+-- Create user_roles table
+CREATE TABLE user_roles (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role_id BIGINT NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, role_id)
+);
+
+-- Create indexes
+CREATE INDEX idx_user_roles_role_id ON user_roles(role_id);
+
+-- Seed default roles and permissions
+INSERT INTO
+    roles (name, description, is_system)
+VALUES
+    ('admin', 'Full access', TRUE),
+    ('support', 'Read access to customers, orders and payments', TRUE),
+    ('user', 'Default role for registered users', TRUE);
+
+INSERT INTO
+    permissions (name, description)
+VALUES
+    ('*', 'Every permission'),
+    ('users:read', 'View user accounts'),
+    ('users:write', 'Update user accounts and statuses'),
+    ('roles:manage', 'Manage roles, permissions and role assignments'),
+    ('orders:read', 'View all orders'),
+    ('orders:refund', 'Refund orders'),
+    ('payments:read', 'View all payments'),
+    ('security_events:read', 'View security events of any user');
+
+INSERT INTO
+    role_permissions (role_id, permission_id)
+SELECT
+    r.id,
+    p.id
+FROM
+    roles r
+    JOIN permissions p ON (
+        (r.name = 'admin' AND p.name = '*')
+        OR (
+            r.name = 'support'
+            AND p.name IN ('users:read', 'orders:read', 'payments:read', 'security_events:read')
+        )
+    );
+
+-- Existing users keep their role
+INSERT INTO
+    user_roles (user_id, role_id)
+SELECT
+    u.id,
+    r.id
+FROM
+    users u
+    JOIN roles r ON r.name = u.role;
+
+-- Add comment for documentation
+COMMENT ON TABLE roles IS 'Named sets of permissions; system roles cannot be deleted';
+
+COMMENT ON TABLE permissions IS 'Permission names in resource:action form; * grants everything';
+
+COMMENT ON TABLE user_roles IS 'Roles held by each user; users.role is kept as the primary role shown in tokens';
@@ -0,0 +1,8 @@
+-- Migration 00015: create_api_tokens
+-- Down migration
+DELETE FROM
+    permissions
+WHERE
+    name = 'api_keys:manage';
+
+DROP TABLE IF EXISTS api_tokens;
@@ -0,0 +1,36 @@
+-- Migration 00015: create_api_tokens
+-- Up migration
+-- Create api_tokens table for personal access tokens and service API keys
+CREATE TABLE api_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL DEFAULT 'personal',
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(32) UNIQUE NOT NULL,
+    secret_hash VARCHAR(64) NOT NULL,
+    scopes VARCHAR(1000) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    last_used_ip VARCHAR(45),
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX idx_api_tokens_user_id ON api_tokens(user_id);
+
+-- Service keys need their own permission
+INSERT INTO
+    permissions (name, description)
+VALUES
+    ('api_keys:manage', 'Create and revoke service API keys');
+
+-- Add comment for documentation
+COMMENT ON COLUMN api_tokens.kind IS 'personal for personal access tokens, service for service API keys';
+
+COMMENT ON COLUMN api_tokens.prefix IS 'Visible part of the token, e.g. pat_1a2b3c4d, used for lookup';
+
+COMMENT ON COLUMN api_tokens.secret_hash IS 'SHA-256 hex digest of the secret part of the token';
+
+COMMENT ON COLUMN api_tokens.scopes IS 'Space-separated permissions the token is limited to; empty means no permissions';
@@ -0,0 +1,26 @@
+-- Migration 00016: create_oauth_clients
+-- Down migration
+DELETE FROM
+    auth_sessions
+WHERE
+    client_id IS NOT NULL;
+
+ALTER TABLE
+    auth_sessions DROP CONSTRAINT IF EXISTS chk_auth_sessions_owner,
+    DROP COLUMN IF EXISTS scope,
+    DROP COLUMN IF EXISTS client_id,
+ALTER COLUMN
+    refresh_token
+SET
+    NOT NULL,
+ALTER COLUMN
+    user_id
+SET
+    NOT NULL;
+
+DELETE FROM
+    permissions
+WHERE
+    name = 'oauth_clients:manage';
+
+DROP TABLE IF EXISTS oauth_clients;
@@ -0,0 +1,55 @@
+-- Migration 00016: create_oauth_clients
+-- Up migration
+-- Create oauth_clients table for machine-to-machine clients
+CREATE TABLE oauth_clients (
+    id BIGSERIAL PRIMARY KEY,
+    client_id VARCHAR(64) UNIQUE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    secret_hash VARCHAR(64) NOT NULL,
+    scopes VARCHAR(1000) NOT NULL DEFAULT '',
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    disabled_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Client tokens are sessions without a user and without a refresh token
+ALTER TABLE
+    auth_sessions
+ALTER COLUMN
+    user_id DROP NOT NULL,
+ALTER COLUMN
+    refresh_token DROP NOT NULL,
+ADD
+    COLUMN client_id BIGINT REFERENCES oauth_clients(id) ON DELETE CASCADE,
+ADD
+    COLUMN scope VARCHAR(1000) NOT NULL DEFAULT '',
+ADD
+    CONSTRAINT chk_auth_sessions_owner CHECK (
+        user_id IS NOT NULL
+        OR client_id IS NOT NULL
+    );
+
+-- Create indexes
+CREATE INDEX idx_auth_sessions_client_id ON auth_sessions(client_id);
+
+-- Clients are managed by administrators
+INSERT INTO
+    permissions (name, description)
+VALUES
+    (
+        'oauth_clients:manage',
+        'Register and disable OAuth clients'
+    );
+
+-- Add comment for documentation
+COMMENT ON COLUMN oauth_clients.client_id IS 'Public client identifier sent with the client_credentials grant';
+
+COMMENT ON COLUMN oauth_clients.secret_hash IS 'SHA-256 hex digest of the client secret';
+
+COMMENT ON COLUMN oauth_clients.scopes IS 'Space-separated scopes the client may request';
+
+COMMENT ON COLUMN auth_sessions.client_id IS 'OAuth client a client_credentials token was issued to; NULL for user sessions';
+
+COMMENT ON COLUMN auth_sessions.scope IS 'Space-separated scopes granted to a client token';
@@ -0,0 +1,7 @@
+-- Migration 00017: create_user_identities
+-- Down migration
+DROP TABLE IF EXISTS oidc_auth_requests;
+
+DROP TABLE IF EXISTS user_identities;
+
+COMMENT ON COLUMN users.password IS NULL;
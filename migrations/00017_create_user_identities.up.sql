@@ -0,0 +1,43 @@
+-- Migration 00017: create_user_identities
+-- Up migration
+-- Create user_identities table linking users to OpenID Connect accounts
+CREATE TABLE user_identities (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    email_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    last_login_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (provider, subject),
+    UNIQUE (user_id, provider)
+);
+
+-- Create oidc_auth_requests table for sign-ins in progress
+CREATE TABLE oidc_auth_requests (
+    id BIGSERIAL PRIMARY KEY,
+    state_hash VARCHAR(64) UNIQUE NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    purpose VARCHAR(20) NOT NULL,
+    user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    nonce VARCHAR(100) NOT NULL,
+    code_verifier VARCHAR(100) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
+
+CREATE INDEX idx_oidc_auth_requests_expires_at ON oidc_auth_requests(expires_at);
+
+-- Add comment for documentation
+COMMENT ON COLUMN user_identities.subject IS 'Stable user identifier (sub claim) at the provider';
+
+COMMENT ON COLUMN oidc_auth_requests.state_hash IS 'SHA-256 hex digest of the state parameter';
+
+COMMENT ON COLUMN oidc_auth_requests.purpose IS 'login to sign in or sign up, link to attach the identity to user_id';
+
+COMMENT ON COLUMN users.password IS 'Password hash; empty for accounts created through a sign-in provider';
@@ -0,0 +1,3 @@
+-- Migration 00018: create_magic_links
+-- Down migration
+DROP TABLE IF EXISTS magic_links;
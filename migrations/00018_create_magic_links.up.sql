@@ -0,0 +1,24 @@
+-- Migration 00018: create_magic_links
+-- Up migration
+-- Create magic_links table for passwordless login links
+CREATE TABLE magic_links (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token VARCHAR(64) UNIQUE NOT NULL,
+    device_hash VARCHAR(64),
+    ip_address VARCHAR(45),
+    expires_at TIMESTAMP NOT NULL,
+    used BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX idx_magic_links_user_id_created_at ON magic_links(user_id, created_at);
+
+CREATE INDEX idx_magic_links_expires_at ON magic_links(expires_at);
+
+-- Add comment for documentation
+COMMENT ON COLUMN magic_links.token IS 'SHA-256 hex digest of the emailed login token';
+
+COMMENT ON COLUMN magic_links.device_hash IS 'SHA-256 hex digest of the device token; links with one only work on the requesting device';
@@ -0,0 +1,5 @@
+-- Migration 00019: create_webauthn_credentials
+-- Down migration
+DROP TABLE IF EXISTS webauthn_challenges;
+
+DROP TABLE IF EXISTS webauthn_credentials;
@@ -0,0 +1,48 @@
+-- Migration 00019: create_webauthn_credentials
+-- Up migration
+-- Create webauthn_credentials table for passkeys
+CREATE TABLE webauthn_credentials (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    credential_id BYTEA UNIQUE NOT NULL,
+    public_key BYTEA NOT NULL,
+    algorithm INTEGER NOT NULL,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    aaguid BYTEA,
+    user_handle BYTEA NOT NULL,
+    attestation_format VARCHAR(20) NOT NULL,
+    transports VARCHAR(255),
+    backup_eligible BOOLEAN NOT NULL DEFAULT FALSE,
+    backed_up BOOLEAN NOT NULL DEFAULT FALSE,
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create webauthn_challenges table for ceremonies in progress
+CREATE TABLE webauthn_challenges (
+    id BIGSERIAL PRIMARY KEY,
+    challenge_hash VARCHAR(64) UNIQUE NOT NULL,
+    purpose VARCHAR(20) NOT NULL,
+    user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    user_handle BYTEA,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX idx_webauthn_credentials_user_id ON webauthn_credentials(user_id);
+
+CREATE INDEX idx_webauthn_challenges_expires_at ON webauthn_challenges(expires_at);
+
+-- Add comment for documentation
+COMMENT ON COLUMN webauthn_credentials.public_key IS 'Credential public key in COSE_Key form';
+
+COMMENT ON COLUMN webauthn_credentials.user_handle IS 'Opaque WebAuthn user.id, the same for all credentials of a user';
+
+COMMENT ON COLUMN webauthn_credentials.transports IS 'Space-separated authenticator transports, e.g. usb nfc internal';
+
+COMMENT ON COLUMN webauthn_challenges.challenge_hash IS 'SHA-256 hex digest of the ceremony challenge';
+
+COMMENT ON COLUMN webauthn_challenges.purpose IS 'register, login or verify (confirm the current session)';
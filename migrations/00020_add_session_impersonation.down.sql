@@ -0,0 +1,14 @@
+-- Migration 00020: add_session_impersonation
+-- Down migration
+DELETE FROM
+    auth_sessions
+WHERE
+    actor_id IS NOT NULL;
+
+ALTER TABLE
+    auth_sessions DROP COLUMN IF EXISTS actor_id;
+
+DELETE FROM
+    permissions
+WHERE
+    name = 'users:impersonate';
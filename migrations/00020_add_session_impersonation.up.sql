@@ -0,0 +1,23 @@
+-- Migration 00020: add_session_impersonation
+-- Up migration
+-- Impersonation sessions belong to the impersonated user and remember the
+-- admin acting on their behalf
+ALTER TABLE
+    auth_sessions
+ADD
+    COLUMN actor_id BIGINT REFERENCES users(id) ON DELETE CASCADE;
+
+-- Create indexes
+CREATE INDEX idx_auth_sessions_actor_id ON auth_sessions(actor_id);
+
+-- Impersonation is granted to administrators
+INSERT INTO
+    permissions (name, description)
+VALUES
+    (
+        'users:impersonate',
+        'Sign in as another user for support'
+    );
+
+-- Add comment for documentation
+COMMENT ON COLUMN auth_sessions.actor_id IS 'Admin impersonating user_id; NULL for sessions the user signed in to';
@@ -0,0 +1,16 @@
+-- Migration 00021: create_organizations
+-- Down migration
+ALTER TABLE
+    payments DROP COLUMN IF EXISTS org_id;
+
+ALTER TABLE
+    orders DROP COLUMN IF EXISTS org_id;
+
+ALTER TABLE
+    auth_sessions DROP COLUMN IF EXISTS org_id;
+
+DROP TABLE IF EXISTS organization_invitations;
+
+DROP TABLE IF EXISTS organization_members;
+
+DROP TABLE IF EXISTS organizations;
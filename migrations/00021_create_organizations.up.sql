@@ -0,0 +1,78 @@
+-- Migration 00021: create_organizations
+-- Up migration
+-- Create organizations table
+CREATE TABLE organizations (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create organization_members table
+CREATE TABLE organization_members (
+    id BIGSERIAL PRIMARY KEY,
+    org_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL DEFAULT 'member',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (org_id, user_id),
+    CHECK (role IN ('owner', 'admin', 'member'))
+);
+
+-- Create organization_invitations table
+CREATE TABLE organization_invitations (
+    id BIGSERIAL PRIMARY KEY,
+    org_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'member',
+    token VARCHAR(64) UNIQUE NOT NULL,
+    invited_by BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (role IN ('owner', 'admin', 'member'))
+);
+
+-- Sessions remember the organization they act in
+ALTER TABLE
+    auth_sessions
+ADD
+    COLUMN org_id BIGINT REFERENCES organizations(id) ON DELETE SET NULL;
+
+-- Orders and payments can belong to an organization; organizations that own
+-- any cannot be deleted
+ALTER TABLE
+    orders
+ADD
+    COLUMN org_id BIGINT REFERENCES organizations(id);
+
+ALTER TABLE
+    payments
+ADD
+    COLUMN org_id BIGINT REFERENCES organizations(id);
+
+-- Create indexes
+CREATE INDEX idx_organization_members_user_id ON organization_members(user_id);
+
+CREATE INDEX idx_organization_invitations_org_id_email ON organization_invitations(org_id, LOWER(email));
+
+CREATE INDEX idx_organization_invitations_expires_at ON organization_invitations(expires_at);
+
+CREATE INDEX idx_auth_sessions_org_id ON auth_sessions(org_id);
+
+CREATE INDEX idx_orders_org_id ON orders(org_id);
+
+CREATE INDEX idx_payments_org_id ON payments(org_id);
+
+-- Add comment for documentation
+COMMENT ON TABLE organization_members IS 'Users in each organization with their organization role: owner, admin or member';
+
+COMMENT ON COLUMN organization_invitations.token IS 'SHA-256 hex digest of the emailed invitation token';
+
+COMMENT ON COLUMN auth_sessions.org_id IS 'Active organization of the session; NULL for the personal account';
+
+COMMENT ON COLUMN orders.org_id IS 'Owning organization; NULL for personal orders';
+
+COMMENT ON COLUMN payments.org_id IS 'Owning organization; NULL for personal payments';
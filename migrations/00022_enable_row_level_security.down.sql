@@ -0,0 +1,17 @@
+-- Migration 00022: enable_row_level_security
+-- Down migration
+DROP POLICY IF EXISTS payments_tenant_isolation ON payments;
+
+ALTER TABLE
+    payments NO FORCE ROW LEVEL SECURITY;
+
+ALTER TABLE
+    payments DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS orders_tenant_isolation ON orders;
+
+ALTER TABLE
+    orders NO FORCE ROW LEVEL SECURITY;
+
+ALTER TABLE
+    orders DISABLE ROW LEVEL SECURITY;
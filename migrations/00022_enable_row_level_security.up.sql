@@ -0,0 +1,51 @@
+-- Migration 00022: enable_row_level_security
+-- Up migration
+-- Isolate orders by tenant
+ALTER TABLE
+    orders ENABLE ROW LEVEL SECURITY;
+
+ALTER TABLE
+    orders FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY orders_tenant_isolation ON orders USING (
+    NULLIF(current_setting('app.rls_bypass', true), '') = 'on'
+    OR org_id = NULLIF(current_setting('app.current_tenant', true), '') :: BIGINT
+    OR (
+        NULLIF(current_setting('app.current_tenant', true), '') IS NULL
+        AND org_id IS NULL
+        AND user_id = NULLIF(current_setting('app.current_user_id', true), '') :: BIGINT
+    )
+) WITH CHECK (
+    NULLIF(current_setting('app.rls_bypass', true), '') = 'on'
+    OR org_id = NULLIF(current_setting('app.current_tenant', true), '') :: BIGINT
+    OR (
+        NULLIF(current_setting('app.current_tenant', true), '') IS NULL
+        AND org_id IS NULL
+        AND user_id = NULLIF(current_setting('app.current_user_id', true), '') :: BIGINT
+    )
+);
+
+-- Isolate payments by tenant
+ALTER TABLE
+    payments ENABLE ROW LEVEL SECURITY;
+
+ALTER TABLE
+    payments FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY payments_tenant_isolation ON payments USING (
+    NULLIF(current_setting('app.rls_bypass', true), '') = 'on'
+    OR org_id = NULLIF(current_setting('app.current_tenant', true), '') :: BIGINT
+    OR (
+        NULLIF(current_setting('app.current_tenant', true), '') IS NULL
+        AND org_id IS NULL
+        AND user_id = NULLIF(current_setting('app.current_user_id', true), '') :: BIGINT
+    )
+) WITH CHECK (
+    NULLIF(current_setting('app.rls_bypass', true), '') = 'on'
+    OR org_id = NULLIF(current_setting('app.current_tenant', true), '') :: BIGINT
+    OR (
+        NULLIF(current_setting('app.current_tenant', true), '') IS NULL
+        AND org_id IS NULL
+        AND user_id = NULLIF(current_setting('app.current_user_id', true), '') :: BIGINT
+    )
+);
@@ -0,0 +1,27 @@
+-- Migration 00023: add_organization_api_keys
+-- Down migration
+INSERT INTO
+    permissions (name, description)
+VALUES
+    ('api_keys:manage', 'Create and revoke service API keys');
+
+DELETE FROM
+    api_tokens
+WHERE
+    org_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_api_tokens_org_id;
+
+ALTER TABLE
+    api_tokens DROP CONSTRAINT IF EXISTS api_tokens_single_owner;
+
+ALTER TABLE
+    api_tokens
+ALTER COLUMN
+    user_id
+SET
+    NOT NULL;
+
+ALTER TABLE
+    api_tokens DROP COLUMN IF EXISTS created_by,
+    DROP COLUMN IF EXISTS org_id;
@@ -0,0 +1,46 @@
+-- Migration 00023: add_organization_api_keys
+-- Up migration
+-- Service keys belong to an organization instead of a user
+ALTER TABLE
+    api_tokens
+ADD
+    COLUMN org_id BIGINT REFERENCES organizations(id) ON DELETE CASCADE,
+ADD
+    COLUMN created_by BIGINT REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE
+    api_tokens
+ALTER COLUMN
+    user_id DROP NOT NULL;
+
+ALTER TABLE
+    api_tokens
+ADD
+    CONSTRAINT api_tokens_single_owner CHECK ((user_id IS NULL) <> (org_id IS NULL));
+
+-- Create indexes
+CREATE INDEX idx_api_tokens_org_id ON api_tokens(org_id);
+
+-- Service keys issued to users before this migration have no organization;
+-- revoke them so they are reissued under one
+UPDATE
+    api_tokens
+SET
+    revoked_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    kind = 'service'
+    AND revoked_at IS NULL;
+
+-- Organization owners and admins manage service keys instead
+DELETE FROM
+    permissions
+WHERE
+    name = 'api_keys:manage';
+
+-- Add comment for documentation
+COMMENT ON COLUMN api_tokens.user_id IS 'Owner of a personal access token; NULL for service keys';
+
+COMMENT ON COLUMN api_tokens.org_id IS 'Organization that owns a service key; NULL for personal access tokens';
+
+COMMENT ON COLUMN api_tokens.created_by IS 'Member who issued a service key';
@@ -0,0 +1,3 @@
+-- Migration 00024: create_pending_logins
+-- Down migration
+DROP TABLE IF EXISTS pending_logins;
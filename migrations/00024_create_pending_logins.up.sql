@@ -0,0 +1,23 @@
+-- Migration 00024: create_pending_logins
+-- Up migration
+-- Create pending_logins table for logins waiting for a second factor
+CREATE TABLE pending_logins (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token VARCHAR(64) UNIQUE NOT NULL,
+    amr VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP NOT NULL,
+    used BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX idx_pending_logins_user_id ON pending_logins(user_id);
+
+CREATE INDEX idx_pending_logins_expires_at ON pending_logins(expires_at);
+
+-- Add comment for documentation
+COMMENT ON COLUMN pending_logins.token IS 'SHA-256 hex digest of the TFA challenge token';
+
+COMMENT ON COLUMN pending_logins.amr IS 'Comma-separated authentication methods proven before the challenge';
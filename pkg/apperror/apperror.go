@@ -0,0 +1,137 @@
+package apperror
+
+import (
+	"errors"
+)
+
+// Category groups errors by how callers should react to them
+type Category string
+
+const (
+	CategoryNotFound     Category = "not_found"
+	CategoryConflict     Category = "conflict"
+	CategoryValidation   Category = "validation"
+	CategoryUnauthorized Category = "unauthorized"
+	CategoryForbidden    Category = "forbidden"
+	CategoryRateLimited  Category = "rate_limited"
+	CategoryInternal     Category = "internal"
+)
+
+// FieldError describes a problem with a single request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Error is a typed application error. Code is a stable machine-readable
+// identifier and Message is safe to show to clients; the wrapped cause is
+// for logs only.
+type Error struct {
+	Code     string
+	Category Category
+	Message  string
+	Fields   []FieldError
+	Err      error
+}
+
+// New creates a new application error
+func New(category Category, code, message string) *Error {
+	return &Error{
+		Code:     code,
+		Category: category,
+		Message:  message,
+	}
+}
+
+// NotFound creates a not found error
+func NotFound(code, message string) *Error {
+	return New(CategoryNotFound, code, message)
+}
+
+// Conflict creates a conflict error
+func Conflict(code, message string) *Error {
+	return New(CategoryConflict, code, message)
+}
+
+// Validation creates a validation error
+func Validation(code, message string) *Error {
+	return New(CategoryValidation, code, message)
+}
+
+// Unauthorized creates an unauthorized error
+func Unauthorized(code, message string) *Error {
+	return New(CategoryUnauthorized, code, message)
+}
+
+// Forbidden creates a forbidden error
+func Forbidden(code, message string) *Error {
+	return New(CategoryForbidden, code, message)
+}
+
+// RateLimited creates a rate limited error
+func RateLimited(code, message string) *Error {
+	return New(CategoryRateLimited, code, message)
+}
+
+// Internal wraps an unexpected error without exposing its details
+func Internal(err error) *Error {
+	return &Error{
+		Code:     "internal_error",
+		Category: CategoryInternal,
+		Message:  "An unexpected error occurred",
+		Err:      err,
+	}
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Code + ": " + e.Message + ": " + e.Err.Error()
+	}
+	return e.Code + ": " + e.Message
+}
+
+// Unwrap returns the wrapped cause
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches application errors by code, so a wrapped or re-worded copy of a
+// sentinel still satisfies errors.Is
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Wrap returns a copy of the error with a cause attached
+func (e *Error) Wrap(err error) *Error {
+	wrapped := *e
+	wrapped.Err = err
+	return &wrapped
+}
+
+// WithMessage returns a copy of the error with a different client message
+func (e *Error) WithMessage(message string) *Error {
+	wrapped := *e
+	wrapped.Message = message
+	return &wrapped
+}
+
+// WithFields returns a copy of the error with per-field details
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	wrapped := *e
+	wrapped.Fields = fields
+	return &wrapped
+}
+
+// From extracts the application error from an error chain. Errors that are
+// not application errors are treated as internal.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return Internal(err)
+}
@@ -0,0 +1,131 @@
+package binder
+
+import (
+	"reflect"
+	"sync"
+
+	"boilerplate-go-fiber-v2/pkg/apperror"
+	"boilerplate-go-fiber-v2/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Binding errors
+var (
+	ErrInvalidBody    = apperror.Validation("invalid_body", "Invalid request body")
+	ErrInvalidQuery   = apperror.Validation("invalid_query", "Invalid query parameters")
+	ErrInvalidParams  = apperror.Validation("invalid_params", "Invalid URL parameters")
+	ErrInvalidHeaders = apperror.Validation("invalid_headers", "Invalid request headers")
+)
+
+// Handler is a route handler that receives a bound and validated request
+type Handler[T any] func(c *fiber.Ctx, req *T) error
+
+// sources records which parts of a request a type binds from
+type sources struct {
+	body    bool
+	query   bool
+	params  bool
+	headers bool
+}
+
+// sourceCache caches sources per request type
+var sourceCache sync.Map
+
+// Handle adapts a typed handler to a fiber.Handler. Every request is bound
+// into a fresh T, so concurrent requests never share state.
+func Handle[T any](handler Handler[T]) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		req, err := Bind[T](c)
+		if err != nil {
+			return err
+		}
+		return handler(c, req)
+	}
+}
+
+// Bind parses the body, query string, route params and headers of a request
+// into a new T according to its struct tags, then validates it. Route params
+// and headers are bound last, so they cannot be overridden from the body.
+func Bind[T any](c *fiber.Ctx) (*T, error) {
+	req := new(T)
+	src := sourcesOf(reflect.TypeOf(req).Elem())
+
+	if src.body && len(c.Body()) > 0 {
+		if err := c.BodyParser(req); err != nil {
+			return nil, ErrInvalidBody.Wrap(err)
+		}
+	}
+
+	if src.query {
+		if err := c.QueryParser(req); err != nil {
+			return nil, ErrInvalidQuery.Wrap(err)
+		}
+	}
+
+	if src.params {
+		if err := c.ParamsParser(req); err != nil {
+			return nil, ErrInvalidParams.Wrap(err)
+		}
+	}
+
+	if src.headers {
+		if err := c.ReqHeaderParser(req); err != nil {
+			return nil, ErrInvalidHeaders.Wrap(err)
+		}
+	}
+
+	if err := validator.Validate(req, c.Get(fiber.HeaderAcceptLanguage)); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// sourcesOf inspects the struct tags of a request type
+func sourcesOf(t reflect.Type) sources {
+	if cached, ok := sourceCache.Load(t); ok {
+		return cached.(sources)
+	}
+
+	var src sources
+	if t.Kind() == reflect.Struct {
+		collectSources(t, &src)
+	}
+
+	sourceCache.Store(t, src)
+	return src
+}
+
+func collectSources(t reflect.Type, src *sources) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			collectSources(field.Type, src)
+			continue
+		}
+
+		if hasTag(field, "json", "form", "xml") {
+			src.body = true
+		}
+		if hasTag(field, "query") {
+			src.query = true
+		}
+		if hasTag(field, "params") {
+			src.params = true
+		}
+		if hasTag(field, "reqHeader") {
+			src.headers = true
+		}
+	}
+}
+
+func hasTag(field reflect.StructField, keys ...string) bool {
+	for _, key := range keys {
+		if _, ok := field.Tag.Lookup(key); ok {
+			return true
+		}
+	}
+	return false
+}
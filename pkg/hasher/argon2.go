@@ -0,0 +1,140 @@
+package hasher
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Argon2Params are the argon2id cost parameters
+type Argon2Params struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params follow the OWASP recommendation of 64 MiB, 3 passes
+var DefaultArgon2Params = Argon2Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// Argon2id hashes passwords with argon2id. Hashes look like
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash> with unpadded base64.
+type Argon2id struct {
+	params Argon2Params
+}
+
+// NewArgon2id creates an argon2id hasher; zero fields take the defaults
+func NewArgon2id(params Argon2Params) *Argon2id {
+	if params.Memory == 0 {
+		params.Memory = DefaultArgon2Params.Memory
+	}
+	if params.Iterations == 0 {
+		params.Iterations = DefaultArgon2Params.Iterations
+	}
+	if params.Parallelism == 0 {
+		params.Parallelism = DefaultArgon2Params.Parallelism
+	}
+	if params.SaltLength == 0 {
+		params.SaltLength = DefaultArgon2Params.SaltLength
+	}
+	if params.KeyLength == 0 {
+		params.KeyLength = DefaultArgon2Params.KeyLength
+	}
+	return &Argon2id{params: params}
+}
+
+func (a *Argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, a.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, a.params.Iterations, a.params.Memory, a.params.Parallelism, a.params.KeyLength)
+
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		AlgorithmArgon2id,
+		argon2.Version,
+		a.params.Memory,
+		a.params.Iterations,
+		a.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (a *Argon2id) Verify(password, encoded string) (bool, error) {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	candidate := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	return subtle.ConstantTimeCompare(key, candidate) == 1, nil
+}
+
+func (a *Argon2id) Matches(encoded string) bool {
+	fields := phcFields(encoded)
+	return len(fields) > 0 && fields[0] == AlgorithmArgon2id
+}
+
+func (a *Argon2id) NeedsRehash(encoded string) bool {
+	params, salt, _, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return params.Memory != a.params.Memory ||
+		params.Iterations != a.params.Iterations ||
+		params.Parallelism != a.params.Parallelism ||
+		params.KeyLength != a.params.KeyLength ||
+		uint32(len(salt)) < a.params.SaltLength
+}
+
+// decodeArgon2id parses an argon2id PHC string
+func decodeArgon2id(encoded string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	fields := phcFields(encoded)
+	if len(fields) != 5 || fields[0] != AlgorithmArgon2id {
+		return params, nil, nil, ErrMalformedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(fields[1], "v=%d", &version); err != nil {
+		return params, nil, nil, ErrMalformedHash
+	}
+	if version != argon2.Version {
+		return params, nil, nil, ErrUnknownAlgorithm
+	}
+
+	if _, err := fmt.Sscanf(fields[2], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, ErrMalformedHash
+	}
+	// argon2 panics on zero passes or threads
+	if params.Iterations == 0 || params.Parallelism == 0 {
+		return params, nil, nil, ErrMalformedHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(fields[3])
+	if err != nil {
+		return params, nil, nil, ErrMalformedHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(fields[4])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrMalformedHash
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
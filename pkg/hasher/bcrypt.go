@@ -0,0 +1,51 @@
+package hasher
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Bcrypt hashes passwords with bcrypt. Only the first 72 bytes of a password
+// are used, so prefer argon2id for new deployments.
+type Bcrypt struct {
+	cost int
+}
+
+// NewBcrypt creates a bcrypt hasher; a cost of 0 uses bcrypt.DefaultCost
+func NewBcrypt(cost int) *Bcrypt {
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	return &Bcrypt{cost: cost}
+}
+
+func (b *Bcrypt) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), b.cost)
+	return string(hash), err
+}
+
+func (b *Bcrypt) Verify(password, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (b *Bcrypt) Matches(encoded string) bool {
+	fields := phcFields(encoded)
+	if len(fields) == 0 {
+		return false
+	}
+	switch fields[0] {
+	case "2a", "2b", "2y":
+		return true
+	}
+	return false
+}
+
+func (b *Bcrypt) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != b.cost
+}
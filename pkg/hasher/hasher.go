@@ -0,0 +1,132 @@
+package hasher
+
+import (
+	"errors"
+	"strings"
+)
+
+// Algorithm identifiers, as used in PHC strings
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+var (
+	ErrUnknownAlgorithm = errors.New("unknown password hash algorithm")
+	ErrMalformedHash    = errors.New("malformed password hash")
+)
+
+// Hasher hashes and verifies passwords for one algorithm. Hashes are encoded
+// as PHC strings ($<id>$<params>$<salt>$<hash>); bcrypt keeps its native
+// modular crypt format, which has the same shape.
+type Hasher interface {
+	// Hash returns the encoded hash of a password
+	Hash(password string) (string, error)
+	// Verify reports whether a password matches an encoded hash
+	Verify(password, encoded string) (bool, error)
+	// Matches reports whether an encoded hash was produced by this algorithm
+	Matches(encoded string) bool
+	// NeedsRehash reports whether an encoded hash uses outdated parameters
+	NeedsRehash(encoded string) bool
+}
+
+// Options configures the package-level hasher
+type Options struct {
+	Algorithm  string // algorithm used for new hashes
+	Argon2     Argon2Params
+	BcryptCost int
+}
+
+// Manager hashes new passwords with the preferred algorithm and verifies
+// hashes produced by any supported algorithm, so existing hashes keep working
+// while they are upgraded.
+type Manager struct {
+	preferred Hasher
+	hashers   []Hasher
+}
+
+// NewManager creates a manager from options
+func NewManager(opts Options) (*Manager, error) {
+	argon := NewArgon2id(opts.Argon2)
+	bcryptHasher := NewBcrypt(opts.BcryptCost)
+
+	m := &Manager{hashers: []Hasher{argon, bcryptHasher}}
+	switch opts.Algorithm {
+	case "", AlgorithmArgon2id:
+		m.preferred = argon
+	case AlgorithmBcrypt:
+		m.preferred = bcryptHasher
+	default:
+		return nil, ErrUnknownAlgorithm
+	}
+
+	return m, nil
+}
+
+// Hash hashes a password with the preferred algorithm
+func (m *Manager) Hash(password string) (string, error) {
+	return m.preferred.Hash(password)
+}
+
+// Verify checks a password against a hash of any supported algorithm
+func (m *Manager) Verify(password, encoded string) (bool, error) {
+	h := m.find(encoded)
+	if h == nil {
+		return false, ErrUnknownAlgorithm
+	}
+	return h.Verify(password, encoded)
+}
+
+// NeedsRehash reports whether a hash should be replaced, either because it
+// uses another algorithm than the preferred one or outdated parameters
+func (m *Manager) NeedsRehash(encoded string) bool {
+	if !m.preferred.Matches(encoded) {
+		return true
+	}
+	return m.preferred.NeedsRehash(encoded)
+}
+
+func (m *Manager) find(encoded string) Hasher {
+	for _, h := range m.hashers {
+		if h.Matches(encoded) {
+			return h
+		}
+	}
+	return nil
+}
+
+var defaultManager, _ = NewManager(Options{})
+
+// Configure replaces the package-level manager. It should be called once
+// during startup, before any password is hashed.
+func Configure(opts Options) error {
+	m, err := NewManager(opts)
+	if err != nil {
+		return err
+	}
+	defaultManager = m
+	return nil
+}
+
+// Hash hashes a password with the configured algorithm
+func Hash(password string) (string, error) {
+	return defaultManager.Hash(password)
+}
+
+// Verify checks a password against an encoded hash
+func Verify(password, encoded string) (bool, error) {
+	return defaultManager.Verify(password, encoded)
+}
+
+// NeedsRehash reports whether an encoded hash should be upgraded
+func NeedsRehash(encoded string) bool {
+	return defaultManager.NeedsRehash(encoded)
+}
+
+// phcFields splits a PHC string into its $-separated fields
+func phcFields(encoded string) []string {
+	if !strings.HasPrefix(encoded, "$") {
+		return nil
+	}
+	return strings.Split(encoded[1:], "$")
+}
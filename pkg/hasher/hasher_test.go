@@ -0,0 +1,200 @@
+package hasher
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Cheap parameters keep the tests fast; the encoding does not depend on them
+var testArgon2Params = Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func newTestManager(t *testing.T, opts Options) *Manager {
+	t.Helper()
+	if opts.Argon2 == (Argon2Params{}) {
+		opts.Argon2 = testArgon2Params
+	}
+	if opts.BcryptCost == 0 {
+		opts.BcryptCost = bcrypt.MinCost
+	}
+	m, err := NewManager(opts)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	return m
+}
+
+func TestHashRoundTrip(t *testing.T) {
+	for _, algorithm := range []string{AlgorithmArgon2id, AlgorithmBcrypt} {
+		t.Run(algorithm, func(t *testing.T) {
+			m := newTestManager(t, Options{Algorithm: algorithm})
+
+			const password = "correct horse battery staple"
+			encoded, err := m.Hash(password)
+			if err != nil {
+				t.Fatalf("Hash: %v", err)
+			}
+			if encoded == password || !strings.HasPrefix(encoded, "$") {
+				t.Fatalf("Hash = %q, want an encoded hash", encoded)
+			}
+
+			again, err := m.Hash(password)
+			if err != nil {
+				t.Fatalf("Hash: %v", err)
+			}
+			if again == encoded {
+				t.Errorf("two hashes of the same password are equal; the salt is not random")
+			}
+
+			if ok, err := m.Verify(password, encoded); err != nil || !ok {
+				t.Errorf("Verify(correct password) = %v, %v; want true", ok, err)
+			}
+			if ok, err := m.Verify("Correct horse battery staple", encoded); err != nil || ok {
+				t.Errorf("Verify(wrong password) = %v, %v; want false", ok, err)
+			}
+			if ok, err := m.Verify("", encoded); err != nil || ok {
+				t.Errorf("Verify(empty password) = %v, %v; want false", ok, err)
+			}
+			if m.NeedsRehash(encoded) {
+				t.Errorf("NeedsRehash of a fresh hash = true")
+			}
+		})
+	}
+}
+
+func TestVerifyOtherAlgorithm(t *testing.T) {
+	legacy := newTestManager(t, Options{Algorithm: AlgorithmBcrypt})
+	encoded, err := legacy.Hash("secret")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+
+	// Existing bcrypt hashes keep working after switching to argon2id
+	m := newTestManager(t, Options{Algorithm: AlgorithmArgon2id})
+	if ok, err := m.Verify("secret", encoded); err != nil || !ok {
+		t.Errorf("Verify(bcrypt hash) = %v, %v; want true", ok, err)
+	}
+	if !m.NeedsRehash(encoded) {
+		t.Errorf("NeedsRehash(bcrypt hash) = false with argon2id preferred")
+	}
+}
+
+func TestVerifyMalformedHash(t *testing.T) {
+	m := newTestManager(t, Options{})
+
+	valid, err := m.Hash("secret")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	fields := strings.Split(valid, "$")
+	salt, key := fields[4], fields[5]
+
+	tests := []struct {
+		name    string
+		encoded string
+		wantErr error
+	}{
+		{name: "empty", encoded: "", wantErr: ErrUnknownAlgorithm},
+		{name: "plaintext", encoded: "secret", wantErr: ErrUnknownAlgorithm},
+		{name: "unknown algorithm", encoded: "$scrypt$ln=15,r=8,p=1$" + salt + "$" + key, wantErr: ErrUnknownAlgorithm},
+		{name: "argon2i", encoded: "$argon2i$v=19$m=64,t=1,p=1$" + salt + "$" + key, wantErr: ErrUnknownAlgorithm},
+		{name: "other argon2 version", encoded: "$argon2id$v=16$m=64,t=1,p=1$" + salt + "$" + key, wantErr: ErrUnknownAlgorithm},
+		{name: "missing hash", encoded: "$argon2id$v=19$m=64,t=1,p=1$" + salt, wantErr: ErrMalformedHash},
+		{name: "extra field", encoded: valid + "$extra", wantErr: ErrMalformedHash},
+		{name: "bad version", encoded: "$argon2id$version$m=64,t=1,p=1$" + salt + "$" + key, wantErr: ErrMalformedHash},
+		{name: "bad parameters", encoded: "$argon2id$v=19$m=64;t=1;p=1$" + salt + "$" + key, wantErr: ErrMalformedHash},
+		{name: "zero iterations", encoded: "$argon2id$v=19$m=64,t=0,p=1$" + salt + "$" + key, wantErr: ErrMalformedHash},
+		{name: "zero parallelism", encoded: "$argon2id$v=19$m=64,t=1,p=0$" + salt + "$" + key, wantErr: ErrMalformedHash},
+		{name: "parallelism overflow", encoded: "$argon2id$v=19$m=64,t=1,p=256$" + salt + "$" + key, wantErr: ErrMalformedHash},
+		{name: "padded salt", encoded: "$argon2id$v=19$m=64,t=1,p=1$" + salt + "==$" + key, wantErr: ErrMalformedHash},
+		{name: "bad base64 hash", encoded: "$argon2id$v=19$m=64,t=1,p=1$" + salt + "$not*base64", wantErr: ErrMalformedHash},
+		{name: "empty hash", encoded: "$argon2id$v=19$m=64,t=1,p=1$" + salt + "$", wantErr: ErrMalformedHash},
+		{name: "truncated bcrypt", encoded: "$2a$04$short"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := m.Verify("secret", tt.encoded)
+			if ok || err == nil {
+				t.Fatalf("Verify = %v, %v; want an error", ok, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify error = %v, want %v", err, tt.wantErr)
+			}
+			if !m.NeedsRehash(tt.encoded) {
+				t.Errorf("NeedsRehash = false, want true")
+			}
+		})
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	argon2Hash := func(t *testing.T, params Argon2Params) string {
+		t.Helper()
+		encoded, err := NewArgon2id(params).Hash("secret")
+		if err != nil {
+			t.Fatalf("Hash: %v", err)
+		}
+		return encoded
+	}
+	bcryptHash := func(t *testing.T, cost int) string {
+		t.Helper()
+		encoded, err := NewBcrypt(cost).Hash("secret")
+		if err != nil {
+			t.Fatalf("Hash: %v", err)
+		}
+		return encoded
+	}
+	with := func(change func(p *Argon2Params)) Argon2Params {
+		p := testArgon2Params
+		change(&p)
+		return p
+	}
+
+	tests := []struct {
+		name    string
+		opts    Options
+		encoded func(t *testing.T) string
+		want    bool
+	}{
+		{name: "same argon2id parameters", opts: Options{Argon2: testArgon2Params},
+			encoded: func(t *testing.T) string { return argon2Hash(t, testArgon2Params) }},
+		{name: "more memory", opts: Options{Argon2: with(func(p *Argon2Params) { p.Memory = 128 })},
+			encoded: func(t *testing.T) string { return argon2Hash(t, testArgon2Params) }, want: true},
+		{name: "more iterations", opts: Options{Argon2: with(func(p *Argon2Params) { p.Iterations = 2 })},
+			encoded: func(t *testing.T) string { return argon2Hash(t, testArgon2Params) }, want: true},
+		{name: "more parallelism", opts: Options{Argon2: with(func(p *Argon2Params) { p.Parallelism = 2 })},
+			encoded: func(t *testing.T) string { return argon2Hash(t, testArgon2Params) }, want: true},
+		{name: "longer key", opts: Options{Argon2: with(func(p *Argon2Params) { p.KeyLength = 64 })},
+			encoded: func(t *testing.T) string { return argon2Hash(t, testArgon2Params) }, want: true},
+		{name: "longer salt", opts: Options{Argon2: with(func(p *Argon2Params) { p.SaltLength = 32 })},
+			encoded: func(t *testing.T) string { return argon2Hash(t, testArgon2Params) }, want: true},
+		{name: "fewer iterations than stored", opts: Options{Argon2: testArgon2Params},
+			encoded: func(t *testing.T) string { return argon2Hash(t, with(func(p *Argon2Params) { p.Iterations = 2 })) }, want: true},
+		{name: "same bcrypt cost", opts: Options{Algorithm: AlgorithmBcrypt, BcryptCost: bcrypt.MinCost},
+			encoded: func(t *testing.T) string { return bcryptHash(t, bcrypt.MinCost) }},
+		{name: "higher bcrypt cost", opts: Options{Algorithm: AlgorithmBcrypt, BcryptCost: bcrypt.MinCost + 1},
+			encoded: func(t *testing.T) string { return bcryptHash(t, bcrypt.MinCost) }, want: true},
+		{name: "bcrypt to argon2id", opts: Options{Algorithm: AlgorithmArgon2id},
+			encoded: func(t *testing.T) string { return bcryptHash(t, bcrypt.MinCost) }, want: true},
+		{name: "argon2id to bcrypt", opts: Options{Algorithm: AlgorithmBcrypt},
+			encoded: func(t *testing.T) string { return argon2Hash(t, testArgon2Params) }, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t, tt.opts)
+			if got := m.NeedsRehash(tt.encoded(t)); got != tt.want {
+				t.Errorf("NeedsRehash = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewManagerUnknownAlgorithm(t *testing.T) {
+	if _, err := NewManager(Options{Algorithm: "md5"}); !errors.Is(err, ErrUnknownAlgorithm) {
+		t.Errorf("NewManager error = %v, want %v", err, ErrUnknownAlgorithm)
+	}
+}
@@ -0,0 +1,204 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	// Register decoders for the accepted upload formats
+	_ "image/gif"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+var (
+	// ErrUnsupportedFormat is returned for images that cannot be decoded
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	// ErrTooLarge is returned for images exceeding the pixel limit
+	ErrTooLarge = errors.New("image dimensions too large")
+)
+
+// Decode decodes an image after checking its dimensions, and applies the EXIF
+// orientation of JPEG files. Decoding drops all metadata, so re-encoding the
+// result strips EXIF data such as GPS coordinates.
+func Decode(data []byte, maxDimension int) (image.Image, string, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrUnsupportedFormat
+	}
+
+	// Check dimensions before allocating the full image
+	if cfg.Width > maxDimension || cfg.Height > maxDimension {
+		return nil, "", ErrTooLarge
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+
+	if format == "jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+
+	return img, format, nil
+}
+
+// Square crops the center square of an image and scales it to size x size
+func Square(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+
+	crop := image.Rect(0, 0, side, side).Add(image.Point{
+		X: bounds.Min.X + (bounds.Dx()-side)/2,
+		Y: bounds.Min.Y + (bounds.Dy()-side)/2,
+	})
+
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), img, crop, draw.Src, nil)
+	return dst
+}
+
+// HasAlpha reports whether an image has transparent pixels
+func HasAlpha(img image.Image) bool {
+	if opaque, ok := img.(interface{ Opaque() bool }); ok {
+		return !opaque.Opaque()
+	}
+	return true
+}
+
+// Encode writes an image as "jpeg" or "png"
+func Encode(w io.Writer, img image.Image, format string) error {
+	switch format {
+	case "jpeg":
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: 85})
+	case "png":
+		return png.Encode(w, img)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
+
+// jpegOrientation reads the EXIF orientation tag of a JPEG, defaulting to 1
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	offset := 2
+	for offset+4 <= len(data) {
+		if data[offset] != 0xFF {
+			return 1
+		}
+		marker := data[offset+1]
+		length := int(binary.BigEndian.Uint16(data[offset+2:]))
+		if length < 2 || offset+2+length > len(data) {
+			return 1
+		}
+
+		// APP1 segment with an Exif header
+		segment := data[offset+4 : offset+2+length]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(segment[6:])
+		}
+
+		// Start of scan; no metadata follows
+		if marker == 0xDA {
+			return 1
+		}
+		offset += 2 + length
+	}
+
+	return 1
+}
+
+// tiffOrientation reads the orientation tag from IFD0 of a TIFF header
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+
+	return 1
+}
+
+// orient transforms an image according to an EXIF orientation value
+func orient(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	// Orientations 5-8 swap width and height
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // flip horizontal
+				sx, sy = w-1-x, y
+			case 3: // rotate 180
+				sx, sy = w-1-x, h-1-y
+			case 4: // flip vertical
+				sx, sy = x, h-1-y
+			case 5: // transpose
+				sx, sy = y, x
+			case 6: // rotate 90 clockwise
+				sx, sy = y, h-1-x
+			case 7: // transverse
+				sx, sy = w-1-y, h-1-x
+			case 8: // rotate 270 clockwise
+				sx, sy = w-1-y, x
+			}
+			dst.Set(x, y, img.At(bounds.Min.X+sx, bounds.Min.Y+sy))
+		}
+	}
+
+	return dst
+}
@@ -8,18 +8,38 @@ import (
 )
 
 type Claims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID   uint   `json:"user_id"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+	OrgID    uint   `json:"org_id,omitempty"` // active organization, 0 for the personal account
+	ClientID string `json:"client_id,omitempty"`
+	Scope    string `json:"scope,omitempty"`
+	Actor    *Actor `json:"act,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken generates a JWT token
-func GenerateToken(userID uint, email, role, secret string, expiresIn time.Duration) (string, error) {
+// Actor identifies the party acting on behalf of the token's user, such as
+// an admin impersonating a customer (RFC 8693 section 4.1)
+type Actor struct {
+	Subject string `json:"sub"`
+	UserID  uint   `json:"user_id"`
+	Email   string `json:"email,omitempty"`
+}
+
+// IsImpersonation reports whether the token was issued to someone acting as
+// the user
+func (c *Claims) IsImpersonation() bool {
+	return c.Actor != nil
+}
+
+// GenerateToken generates a JWT token. orgID is the active organization, or
+// 0 for none.
+func GenerateToken(userID uint, email, role string, orgID uint, secret string, expiresIn time.Duration) (string, error) {
 	claims := Claims{
 		UserID: userID,
 		Email:  email,
 		Role:   role,
+		OrgID:  orgID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -31,6 +51,45 @@ func GenerateToken(userID uint, email, role, secret string, expiresIn time.Durat
 	return token.SignedString([]byte(secret))
 }
 
+// GenerateImpersonationToken generates a JWT for a user that carries the
+// actor behind it in the act claim. jti makes every token unique.
+func GenerateImpersonationToken(userID uint, email, role string, actor Actor, tokenID, secret string, expiresIn time.Duration) (string, error) {
+	claims := Claims{
+		UserID: userID,
+		Email:  email,
+		Role:   role,
+		Actor:  &actor,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(secret))
+}
+
+// GenerateClientToken generates a JWT for an OAuth client. The subject is the
+// client ID and jti makes every token unique.
+func GenerateClientToken(clientID, scope, tokenID, secret string, expiresIn time.Duration) (string, error) {
+	claims := Claims{
+		ClientID: clientID,
+		Scope:    scope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Subject:   clientID,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(secret))
+}
+
 // ValidateToken validates a JWT token and returns claims
 func ValidateToken(tokenString, secret string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
@@ -58,11 +117,17 @@ func RefreshToken(tokenString, secret string, expiresIn time.Duration) (string,
 		return "", err
 	}
 
+	// Impersonation stays as short as it was granted
+	if claims.IsImpersonation() {
+		return "", errors.New("impersonation tokens cannot be refreshed")
+	}
+
 	// Create new claims with extended expiry
 	newClaims := Claims{
 		UserID: claims.UserID,
 		Email:  claims.Email,
 		Role:   claims.Role,
+		OrgID:  claims.OrgID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -0,0 +1,21 @@
+package mailer
+
+import (
+	"context"
+	"log"
+)
+
+// LogMailer writes messages to the log instead of sending them. Use it in
+// development, where no SMTP server is available.
+type LogMailer struct{}
+
+// NewLogMailer creates a new log mailer
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+// Send logs a message
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	log.Printf("Email to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
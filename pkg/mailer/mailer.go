@@ -0,0 +1,28 @@
+package mailer
+
+import (
+	"context"
+	"log"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers email
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SendInBackground delivers a message without blocking the caller, so request
+// timing does not depend on the mail server. Failures are logged.
+func SendInBackground(m Mailer, msg Message) {
+	go func() {
+		if err := m.Send(context.Background(), msg); err != nil {
+			log.Printf("Failed to send email %q to %s: %v", msg.Subject, msg.To, err)
+		}
+	}()
+}
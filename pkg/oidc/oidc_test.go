@@ -0,0 +1,116 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"boilerplate-go-fiber-v2/pkg/oidc"
+	"boilerplate-go-fiber-v2/pkg/oidc/oidctest"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newProvider(t *testing.T) (*oidctest.Server, *oidc.Provider) {
+	t.Helper()
+
+	server := oidctest.NewServer("client", "secret")
+	t.Cleanup(server.Close)
+
+	provider := oidc.NewProvider(oidc.Config{
+		Issuer:       server.Issuer(),
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/callback",
+	})
+	return server, provider
+}
+
+// authorize runs the browser part of the flow and returns the code
+func authorize(t *testing.T, server *oidctest.Server, provider *oidc.Provider, nonce, verifier string) string {
+	t.Helper()
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state", nonce, oidc.S256Challenge(verifier))
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+
+	code, state, err := server.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+	if state != "state" {
+		t.Fatalf("state = %q, want %q", state, "state")
+	}
+	return code
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	server, provider := newProvider(t)
+	ctx := context.Background()
+
+	code := authorize(t, server, provider, "nonce", "verifier")
+
+	token, err := provider.Exchange(ctx, code, "verifier")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+
+	idToken, err := provider.VerifyIDToken(ctx, token.IDToken, "nonce")
+	if err != nil {
+		t.Fatalf("VerifyIDToken: %v", err)
+	}
+	if idToken.Subject != "oidctest-user" || idToken.Email != "oidctest@example.com" || !idToken.EmailVerified {
+		t.Errorf("ID token = %+v, want the oidctest user with a verified email", idToken)
+	}
+
+	// Codes are single use
+	if _, err := provider.Exchange(ctx, code, "verifier"); !errors.Is(err, oidc.ErrExchange) {
+		t.Errorf("second Exchange error = %v, want ErrExchange", err)
+	}
+}
+
+func TestExchangeRequiresCodeVerifier(t *testing.T) {
+	server, provider := newProvider(t)
+
+	code := authorize(t, server, provider, "nonce", "verifier")
+
+	if _, err := provider.Exchange(context.Background(), code, "another-verifier"); !errors.Is(err, oidc.ErrExchange) {
+		t.Errorf("Exchange error = %v, want ErrExchange", err)
+	}
+}
+
+func TestVerifyIDTokenRejects(t *testing.T) {
+	server, provider := newProvider(t)
+	user := oidctest.User{Subject: "subject", Email: "user@example.com", EmailVerified: true}
+
+	tests := []struct {
+		name   string
+		nonce  string
+		modify func(claims jwt.MapClaims)
+	}{
+		{name: "nonce mismatch", nonce: "other-nonce"},
+		{name: "wrong audience", nonce: "nonce", modify: func(claims jwt.MapClaims) { claims["aud"] = "other-client" }},
+		{name: "wrong issuer", nonce: "nonce", modify: func(claims jwt.MapClaims) { claims["iss"] = "https://issuer.example.com" }},
+		{name: "expired", nonce: "nonce", modify: func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{name: "missing subject", nonce: "nonce", modify: func(claims jwt.MapClaims) { delete(claims, "sub") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := server.Claims(user, "nonce", time.Hour)
+			if tt.modify != nil {
+				tt.modify(claims)
+			}
+			raw, err := server.Sign(claims)
+			if err != nil {
+				t.Fatalf("Sign: %v", err)
+			}
+
+			if _, err := provider.VerifyIDToken(context.Background(), raw, tt.nonce); !errors.Is(err, oidc.ErrInvalidIDToken) {
+				t.Errorf("VerifyIDToken error = %v, want ErrInvalidIDToken", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+// Package oidctest runs a local OpenID Connect provider for tests. It serves
+// discovery, an authorization endpoint that signs the configured user in
+// without a login page, a token endpoint enforcing PKCE and a JWKS.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// keyID is the key ID of the provider's only signing key
+const keyID = "oidctest"
+
+// User is the identity the provider signs in
+type User struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+}
+
+// Server is a fake OpenID Connect provider
+type Server struct {
+	*httptest.Server
+
+	ClientID     string
+	ClientSecret string
+
+	key *rsa.PrivateKey
+
+	mu    sync.Mutex
+	user  User
+	codes map[string]authRequest
+}
+
+// authRequest is what the provider remembers between authorize and token
+type authRequest struct {
+	user          User
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+}
+
+// NewServer starts a provider that accepts the given client credentials.
+// Call Close when done.
+func NewServer(clientID, clientSecret string) *Server {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(fmt.Sprintf("oidctest: generating key: %v", err))
+	}
+
+	s := &Server{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		key:          key,
+		codes:        make(map[string]authRequest),
+		user: User{
+			Subject:       "oidctest-user",
+			Email:         "oidctest@example.com",
+			EmailVerified: true,
+			GivenName:     "Test",
+			FamilyName:    "User",
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/authorize", s.authorize)
+	mux.HandleFunc("/token", s.token)
+	mux.HandleFunc("/jwks", s.jwks)
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// Issuer returns the issuer URL to configure the provider with
+func (s *Server) Issuer() string {
+	return s.URL
+}
+
+// SetUser changes the identity signed in by later authorizations
+func (s *Server) SetUser(user User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.user = user
+}
+
+// Authorize plays the browser: it follows an authorization URL and returns
+// the code and state the provider redirects back with
+func (s *Server) Authorize(authURL string) (code, state string, err error) {
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Get(authURL)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		return "", "", fmt.Errorf("oidctest: authorize returned status %d", resp.StatusCode)
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return "", "", err
+	}
+	query := location.Query()
+	if e := query.Get("error"); e != "" {
+		return "", "", errors.New("oidctest: authorize failed: " + e)
+	}
+	return query.Get("code"), query.Get("state"), nil
+}
+
+func (s *Server) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                s.URL,
+		"authorization_endpoint":                s.URL + "/authorize",
+		"token_endpoint":                        s.URL + "/token",
+		"jwks_uri":                              s.URL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || query.Get("redirect_uri") == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	back := redirectURI.Query()
+	back.Set("state", query.Get("state"))
+
+	switch {
+	case query.Get("client_id") != s.ClientID:
+		back.Set("error", "unauthorized_client")
+	case query.Get("response_type") != "code":
+		back.Set("error", "unsupported_response_type")
+	case query.Get("code_challenge") == "" || query.Get("code_challenge_method") != "S256":
+		back.Set("error", "invalid_request")
+	default:
+		code := randomString()
+		s.mu.Lock()
+		s.codes[code] = authRequest{
+			user:          s.user,
+			redirectURI:   query.Get("redirect_uri"),
+			nonce:         query.Get("nonce"),
+			codeChallenge: query.Get("code_challenge"),
+		}
+		s.mu.Unlock()
+		back.Set("code", code)
+	}
+
+	redirectURI.RawQuery = back.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != s.ClientID || clientSecret != s.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	// Codes are single use
+	code := r.PostForm.Get("code")
+	s.mu.Lock()
+	req, found := s.codes[code]
+	delete(s.codes, code)
+	s.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	switch {
+	case !found, req.redirectURI != r.PostForm.Get("redirect_uri"),
+		base64.RawURLEncoding.EncodeToString(sum[:]) != req.codeChallenge:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	idToken, err := s.IDToken(req.user, req.nonce, time.Hour)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+// IDToken signs an ID token for a user, e.g. to test expired tokens directly
+func (s *Server) IDToken(user User, nonce string, expiresIn time.Duration) (string, error) {
+	return s.Sign(s.Claims(user, nonce, expiresIn))
+}
+
+// Claims returns the claims of an ID token for a user. Change them and call
+// Sign to test tokens with a wrong audience, issuer or nonce.
+func (s *Server) Claims(user User, nonce string, expiresIn time.Duration) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":            s.URL,
+		"sub":            user.Subject,
+		"aud":            s.ClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(expiresIn).Unix(),
+		"nonce":          nonce,
+		"email":          user.Email,
+		"email_verified": user.EmailVerified,
+		"given_name":     user.GivenName,
+		"family_name":    user.FamilyName,
+		"name":           user.GivenName + " " + user.FamilyName,
+	}
+}
+
+// Sign signs claims with the provider's key
+func (s *Server) Sign(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keyID
+	return token.SignedString(s.key)
+}
+
+func (s *Server) jwks(w http.ResponseWriter, r *http.Request) {
+	public := s.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": keyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		}},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func randomString() string {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
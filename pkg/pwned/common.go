@@ -0,0 +1,45 @@
+package pwned
+
+import (
+	"bufio"
+	_ "embed"
+	"strings"
+	"unicode"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+// commonPasswords holds the lowercased built-in common password list
+var commonPasswords = func() map[string]struct{} {
+	passwords := make(map[string]struct{})
+	scanner := bufio.NewScanner(strings.NewReader(commonPasswordsFile))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords[strings.ToLower(line)] = struct{}{}
+	}
+	return passwords
+}()
+
+// IsCommon reports whether a password is on the common password list. Case
+// is ignored, and so are trailing digits and symbols, so "Password123!" is
+// caught as well as "password".
+func IsCommon(password string) bool {
+	lowered := strings.ToLower(password)
+	if _, ok := commonPasswords[lowered]; ok {
+		return true
+	}
+
+	base := strings.TrimRightFunc(lowered, func(r rune) bool {
+		return unicode.IsDigit(r) || unicode.IsPunct(r) || unicode.IsSymbol(r)
+	})
+	if len(base) < 4 {
+		return false
+	}
+
+	_, ok := commonPasswords[base]
+	return ok
+}
@@ -0,0 +1,248 @@
+package pwned
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ImportStats summarizes an import
+type ImportStats struct {
+	Prefixes int
+	Hashes   int
+}
+
+var (
+	prefixFileRegex = regexp.MustCompile(`^[0-9A-Fa-f]{5}\.txt$`)
+	fullHashRegex   = regexp.MustCompile(`^[0-9A-Fa-f]{40}:[0-9]+$`)
+)
+
+// Import replaces the dataset in dir with the contents of source. Source is
+// either a directory of range files (<PREFIX>.txt, as written by the official
+// downloader) or a single "HASH:COUNT" file ordered by hash. The new dataset
+// is built next to dir and swapped in at the end, so running checkers never
+// see a half-written dataset.
+func Import(source, dir string) (ImportStats, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return ImportStats{}, err
+	}
+
+	staging := fmt.Sprintf("%s.import-%d", strings.TrimRight(dir, string(os.PathSeparator)), time.Now().Unix())
+	if err := os.MkdirAll(staging, 0o750); err != nil {
+		return ImportStats{}, err
+	}
+	defer os.RemoveAll(staging)
+
+	var stats ImportStats
+	if info.IsDir() {
+		stats, err = importRangeFiles(source, staging)
+	} else {
+		stats, err = importHashFile(source, staging)
+	}
+	if err != nil {
+		return stats, err
+	}
+
+	if stats.Prefixes == 0 {
+		return stats, fmt.Errorf("no password hashes found in %s", source)
+	}
+
+	return stats, swap(staging, dir)
+}
+
+// importRangeFiles copies range files from a directory
+func importRangeFiles(source, staging string) (ImportStats, error) {
+	var stats ImportStats
+
+	entries, err := os.ReadDir(source)
+	if err != nil {
+		return stats, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !prefixFileRegex.MatchString(entry.Name()) {
+			continue
+		}
+
+		hashes, err := copyRangeFile(filepath.Join(source, entry.Name()), filepath.Join(staging, strings.ToUpper(entry.Name())))
+		if err != nil {
+			return stats, err
+		}
+		stats.Prefixes++
+		stats.Hashes += hashes
+	}
+
+	return stats, nil
+}
+
+// copyRangeFile copies one range file, counting its entries
+func copyRangeFile(src, dst string) (int, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return 0, err
+	}
+
+	hashes := 0
+	writer := bufio.NewWriter(out)
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fmt.Fprintln(writer, line)
+		hashes++
+	}
+
+	if err := scanner.Err(); err != nil {
+		out.Close()
+		return 0, err
+	}
+	if err := writer.Flush(); err != nil {
+		out.Close()
+		return 0, err
+	}
+	return hashes, out.Close()
+}
+
+// importHashFile splits a full-hash file ordered by hash into range files
+func importHashFile(source, staging string) (ImportStats, error) {
+	var stats ImportStats
+
+	in, err := os.Open(source)
+	if err != nil {
+		return stats, err
+	}
+	defer in.Close()
+
+	var (
+		current string
+		out     *os.File
+		writer  *bufio.Writer
+	)
+
+	closeCurrent := func() error {
+		if out == nil {
+			return nil
+		}
+		if err := writer.Flush(); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	}
+
+	scanner := bufio.NewScanner(in)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !fullHashRegex.MatchString(line) {
+			closeCurrent()
+			return stats, fmt.Errorf("line %d: expected HASH:COUNT", lineNumber)
+		}
+
+		line = strings.ToUpper(line)
+		prefix := line[:PrefixLength]
+		if prefix != current {
+			if prefix < current {
+				closeCurrent()
+				return stats, fmt.Errorf("line %d: file must be ordered by hash", lineNumber)
+			}
+			if err := closeCurrent(); err != nil {
+				return stats, err
+			}
+
+			out, err = os.Create(filepath.Join(staging, prefix+".txt"))
+			if err != nil {
+				return stats, err
+			}
+			writer = bufio.NewWriter(out)
+			current = prefix
+			stats.Prefixes++
+		}
+
+		fmt.Fprintln(writer, line[PrefixLength:])
+		stats.Hashes++
+	}
+
+	if err := scanner.Err(); err != nil {
+		closeCurrent()
+		return stats, err
+	}
+	return stats, closeCurrent()
+}
+
+// swap replaces dir with staging
+func swap(staging, dir string) error {
+	if err := os.MkdirAll(filepath.Dir(dir), 0o750); err != nil {
+		return err
+	}
+
+	previous := staging + ".previous"
+	if _, err := os.Stat(dir); err == nil {
+		if err := os.Rename(dir, previous); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Rename(staging, dir); err != nil {
+		// Put the previous dataset back
+		os.Rename(previous, dir)
+		return err
+	}
+
+	return os.RemoveAll(previous)
+}
+
+// Stats counts the range files and hashes of the dataset in dir
+func Stats(dir string) (ImportStats, error) {
+	var stats ImportStats
+
+	matches, err := filepath.Glob(filepath.Join(dir, "?????.txt"))
+	if err != nil {
+		return stats, err
+	}
+
+	for _, path := range matches {
+		file, err := os.Open(path)
+		if err != nil {
+			return stats, err
+		}
+		lines, err := countLines(file)
+		file.Close()
+		if err != nil {
+			return stats, err
+		}
+		stats.Prefixes++
+		stats.Hashes += lines
+	}
+
+	return stats, nil
+}
+
+func countLines(r io.Reader) (int, error) {
+	lines := 0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) != "" {
+			lines++
+		}
+	}
+	return lines, scanner.Err()
+}
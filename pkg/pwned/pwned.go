@@ -0,0 +1,99 @@
+package pwned
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// PrefixLength is the length of the SHA-1 prefix that names a range file
+const PrefixLength = 5
+
+// Result describes why a password was rejected
+type Result struct {
+	Breached bool // found in the breach dataset at least threshold times
+	Count    int  // number of times the password appeared in breaches
+	Common   bool // found in the built-in common password list
+}
+
+// Rejected reports whether the password must not be used
+func (r Result) Rejected() bool {
+	return r.Breached || r.Common
+}
+
+// Checker checks passwords against a local copy of the Have I Been Pwned
+// Pwned Passwords dataset and a built-in common password list. The dataset
+// is stored in the k-anonymity range format: one <PREFIX>.txt file per
+// 5-character SHA-1 prefix, each line holding "SUFFIX:COUNT". No network
+// calls are made.
+type Checker struct {
+	dir       string
+	threshold int
+}
+
+// NewChecker creates a checker reading range files from dir. Passwords seen
+// at least threshold times are rejected; a threshold of 0 disables the
+// breach lookup and leaves only the common password check.
+func NewChecker(dir string, threshold int) *Checker {
+	return &Checker{
+		dir:       dir,
+		threshold: threshold,
+	}
+}
+
+// Check looks a password up in the common list and the breach dataset
+func (c *Checker) Check(password string) (Result, error) {
+	result := Result{Common: IsCommon(password)}
+
+	if c.threshold <= 0 {
+		return result, nil
+	}
+
+	count, err := c.Count(password)
+	if err != nil {
+		return result, err
+	}
+
+	result.Count = count
+	result.Breached = count >= c.threshold
+	return result, nil
+}
+
+// Count returns how often a password appears in the breach dataset. Missing
+// range files are treated as empty so partial datasets keep working.
+func (c *Checker) Count(password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:PrefixLength], hash[PrefixLength:]
+
+	file, err := os.Open(filepath.Join(c.dir, prefix+".txt"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		entrySuffix, count, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !ok || !strings.EqualFold(entrySuffix, suffix) {
+			continue
+		}
+		return strconv.Atoi(count)
+	}
+
+	return 0, scanner.Err()
+}
+
+// Available reports whether the dataset directory contains range files
+func (c *Checker) Available() bool {
+	matches, _ := filepath.Glob(filepath.Join(c.dir, "?????.txt"))
+	return len(matches) > 0
+}
@@ -0,0 +1,47 @@
+package response
+
+import (
+	"errors"
+	"log"
+
+	"boilerplate-go-fiber-v2/pkg/apperror"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// categoryStatus maps error categories to HTTP status codes
+var categoryStatus = map[apperror.Category]int{
+	apperror.CategoryNotFound:     fiber.StatusNotFound,
+	apperror.CategoryConflict:     fiber.StatusConflict,
+	apperror.CategoryValidation:   fiber.StatusBadRequest,
+	apperror.CategoryUnauthorized: fiber.StatusUnauthorized,
+	apperror.CategoryForbidden:    fiber.StatusForbidden,
+	apperror.CategoryRateLimited:  fiber.StatusTooManyRequests,
+	apperror.CategoryInternal:     fiber.StatusInternalServerError,
+}
+
+// StatusCode returns the HTTP status code for an error category
+func StatusCode(category apperror.Category) int {
+	if status, ok := categoryStatus[category]; ok {
+		return status
+	}
+	return fiber.StatusInternalServerError
+}
+
+// ErrorHandler is the application-wide Fiber error handler. Handlers return
+// errors instead of writing them; typed errors are mapped to their status and
+// code, and anything else is logged and reported as a generic internal error.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	// Errors raised by Fiber itself (unknown route, body too large, ...)
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return writeError(c, fiberErr.Code, "", fiberErr.Message, nil)
+	}
+
+	appErr := apperror.From(err)
+	if appErr.Category == apperror.CategoryInternal {
+		log.Printf("Internal error on %s %s: %v", c.Method(), c.Path(), err)
+	}
+
+	return writeError(c, StatusCode(appErr.Category), appErr.Code, appErr.Message, appErr.Fields)
+}
@@ -0,0 +1,63 @@
+package response
+
+import (
+	"log"
+
+	"boilerplate-go-fiber-v2/pkg/apperror"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// oauthErrorCodes are the error codes defined by RFC 6749 section 5.2 and
+// RFC 7009 section 2.2.1
+var oauthErrorCodes = map[string]bool{
+	"invalid_request":        true,
+	"invalid_client":         true,
+	"invalid_grant":          true,
+	"unauthorized_client":    true,
+	"unsupported_grant_type": true,
+	"invalid_scope":          true,
+	"unsupported_token_type": true,
+}
+
+// OAuthErrorBody is an RFC 6749 error response
+type OAuthErrorBody struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+// OAuth writes a token endpoint response. Token responses must not be cached.
+func OAuth(c *fiber.Ctx, data interface{}) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Set(fiber.HeaderPragma, "no-cache")
+	return c.JSON(data)
+}
+
+// OAuthError writes an error in the RFC 6749 format instead of the API's own.
+// Codes the RFC does not define are reported as invalid_request, and
+// internal errors as server_error.
+func OAuthError(c *fiber.Ctx, err error) error {
+	appErr := apperror.From(err)
+
+	status := fiber.StatusBadRequest
+	body := OAuthErrorBody{Error: appErr.Code, Description: appErr.Message}
+
+	switch {
+	case appErr.Category == apperror.CategoryInternal:
+		log.Printf("Internal error on %s %s: %v", c.Method(), c.Path(), err)
+		status = fiber.StatusInternalServerError
+		body.Error = "server_error"
+	case appErr.Category == apperror.CategoryRateLimited:
+		status = fiber.StatusTooManyRequests
+		body.Error = "invalid_request"
+	case appErr.Code == "invalid_client":
+		status = fiber.StatusUnauthorized
+		c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="oauth"`)
+	case !oauthErrorCodes[appErr.Code]:
+		body.Error = "invalid_request"
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Set(fiber.HeaderPragma, "no-cache")
+	return c.Status(status).JSON(body)
+}
@@ -0,0 +1,129 @@
+package response
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"boilerplate-go-fiber-v2/pkg/apperror"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Error formats
+const (
+	FormatEnvelope = "envelope"
+	FormatProblem  = "problem"
+)
+
+// MIMEProblemJSON is the RFC 9457 media type
+const MIMEProblemJSON = "application/problem+json"
+
+// Problem is an RFC 9457 problem details object. Code and Errors are
+// extension members carrying the stable error code and per-field details.
+type Problem struct {
+	Type     string                `json:"type"`
+	Title    string                `json:"title"`
+	Status   int                   `json:"status"`
+	Detail   string                `json:"detail,omitempty"`
+	Instance string                `json:"instance,omitempty"`
+	Code     string                `json:"code,omitempty"`
+	Errors   []apperror.FieldError `json:"errors,omitempty"`
+}
+
+// Options configures error rendering
+type Options struct {
+	ErrorFormat        string // default format when the client does not ask for one
+	ProblemTypeBaseURL string // problem type URIs are built as base + code; "about:blank" when empty
+}
+
+var options = Options{ErrorFormat: FormatEnvelope}
+
+// Configure sets the error rendering options. It must be called before the
+// server starts handling requests.
+func Configure(opts Options) {
+	if opts.ErrorFormat != FormatProblem {
+		opts.ErrorFormat = FormatEnvelope
+	}
+	options = opts
+}
+
+// wantsProblem reports whether the error should be rendered as problem+json.
+// An Accept header naming application/problem+json or application/json picks
+// that format, the higher quality value winning when it names both. A type
+// with q=0 is not acceptable, so problem+json;q=0 always gets the envelope.
+// Wildcards, ties and a missing header fall back to the configured default.
+func wantsProblem(c *fiber.Ctx) bool {
+	problemQ, jsonQ := acceptQuality(c.Get(fiber.HeaderAccept))
+	switch {
+	case problemQ == 0:
+		return false
+	case jsonQ == 0:
+		return problemQ > 0 || options.ErrorFormat == FormatProblem
+	case problemQ > jsonQ:
+		return true
+	case jsonQ > problemQ:
+		return false
+	default:
+		return options.ErrorFormat == FormatProblem
+	}
+}
+
+// acceptQuality returns the quality values an Accept header gives
+// application/problem+json and application/json, or -1 for a type it does
+// not name
+func acceptQuality(accept string) (problemQ, jsonQ float64) {
+	problemQ, jsonQ = -1, -1
+	for _, mediaRange := range strings.Split(accept, ",") {
+		params := strings.Split(mediaRange, ";")
+		q := 1.0
+		for _, param := range params[1:] {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(name, "q") {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil && parsed >= 0 && parsed <= 1 {
+					q = parsed
+				}
+			}
+		}
+
+		switch strings.ToLower(strings.TrimSpace(params[0])) {
+		case MIMEProblemJSON:
+			problemQ = max(problemQ, q)
+		case fiber.MIMEApplicationJSON:
+			jsonQ = max(jsonQ, q)
+		}
+	}
+	return problemQ, jsonQ
+}
+
+// writeError renders an error in the negotiated format
+func writeError(c *fiber.Ctx, status int, code, message string, fields []apperror.FieldError) error {
+	if !wantsProblem(c) {
+		return c.Status(status).JSON(Response{
+			Success: false,
+			Error:   message,
+			Code:    code,
+			Errors:  fields,
+		})
+	}
+
+	problem := Problem{
+		Type:     problemType(code),
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   message,
+		Instance: c.OriginalURL(),
+		Code:     code,
+		Errors:   fields,
+	}
+
+	return c.Status(status).JSON(problem, MIMEProblemJSON)
+}
+
+// problemType builds the problem type URI for an error code
+func problemType(code string) string {
+	if options.ProblemTypeBaseURL == "" || code == "" {
+		return "about:blank"
+	}
+	return strings.TrimRight(options.ProblemTypeBaseURL, "/") + "/" + code
+}
@@ -0,0 +1,187 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"boilerplate-go-fiber-v2/pkg/apperror"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestAcceptQuality(t *testing.T) {
+	tests := []struct {
+		accept   string
+		problemQ float64
+		jsonQ    float64
+	}{
+		{accept: "", problemQ: -1, jsonQ: -1},
+		{accept: "application/problem+json", problemQ: 1, jsonQ: -1},
+		{accept: "application/json", problemQ: -1, jsonQ: 1},
+		{accept: "application/problem+json;q=0.9, application/json;q=0.5", problemQ: 0.9, jsonQ: 0.5},
+		{accept: "Application/Problem+JSON ; Q=0.4", problemQ: 0.4, jsonQ: -1},
+		{accept: "application/problem+json;q=0", problemQ: 0, jsonQ: -1},
+		{accept: "application/json;q=0.2, application/json;q=0.7", problemQ: -1, jsonQ: 0.7},
+		{accept: "application/json;q=abc", problemQ: -1, jsonQ: 1},
+		{accept: "application/json;q=2", problemQ: -1, jsonQ: 1},
+		{accept: "*/*, application/*;q=0.8", problemQ: -1, jsonQ: -1},
+		{accept: "text/html, application/xhtml+xml", problemQ: -1, jsonQ: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			problemQ, jsonQ := acceptQuality(tt.accept)
+			if problemQ != tt.problemQ || jsonQ != tt.jsonQ {
+				t.Errorf("acceptQuality(%q) = %v, %v, want %v, %v", tt.accept, problemQ, jsonQ, tt.problemQ, tt.jsonQ)
+			}
+		})
+	}
+}
+
+func TestErrorHandlerNegotiation(t *testing.T) {
+	t.Cleanup(func() { Configure(Options{}) })
+
+	errThingNotFound := apperror.NotFound("thing_not_found", "Thing not found")
+
+	tests := []struct {
+		name        string
+		format      string
+		accept      string
+		wantProblem bool
+	}{
+		{name: "no Accept header", wantProblem: false},
+		{name: "no Accept header, problem default", format: FormatProblem, wantProblem: true},
+		{name: "problem+json", accept: "application/problem+json", wantProblem: true},
+		{name: "json", accept: "application/json", format: FormatProblem, wantProblem: false},
+		{name: "problem+json preferred", accept: "application/json;q=0.5, application/problem+json", wantProblem: true},
+		{name: "json preferred", accept: "application/problem+json;q=0.5, application/json", format: FormatProblem, wantProblem: false},
+		{name: "tie uses the default", accept: "application/problem+json, application/json", format: FormatProblem, wantProblem: true},
+		{name: "problem+json refused", accept: "application/problem+json;q=0", format: FormatProblem, wantProblem: false},
+		{name: "problem+json refused, json named", accept: "application/problem+json;q=0, application/json;q=0.1", format: FormatProblem, wantProblem: false},
+		{name: "json refused", accept: "application/json;q=0, application/problem+json;q=0.1", wantProblem: true},
+		{name: "json refused, problem not named", accept: "application/json;q=0", format: FormatProblem, wantProblem: true},
+		{name: "wildcard uses the default", accept: "*/*", wantProblem: false},
+		{name: "wildcard uses the problem default", accept: "*/*", format: FormatProblem, wantProblem: true},
+		{name: "application wildcard uses the default", accept: "application/*", wantProblem: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Configure(Options{ErrorFormat: tt.format, ProblemTypeBaseURL: "https://errors.example.com/"})
+
+			app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+			app.Get("/things/:id", func(c *fiber.Ctx) error { return errThingNotFound })
+
+			req := httptest.NewRequest(fiber.MethodGet, "/things/7?expand=1", nil)
+			if tt.accept != "" {
+				req.Header.Set(fiber.HeaderAccept, tt.accept)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			if resp.StatusCode != fiber.StatusNotFound {
+				t.Errorf("status = %d, want %d", resp.StatusCode, fiber.StatusNotFound)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			contentType := resp.Header.Get(fiber.HeaderContentType)
+
+			if !tt.wantProblem {
+				var envelope Response
+				if err := json.Unmarshal(body, &envelope); err != nil {
+					t.Fatalf("decode envelope %s: %v", body, err)
+				}
+				if contentType != fiber.MIMEApplicationJSON || envelope.Success || envelope.Code != "thing_not_found" || envelope.Error != "Thing not found" {
+					t.Errorf("response = %s %s, want the JSON envelope", contentType, body)
+				}
+				return
+			}
+
+			var problem Problem
+			if err := json.Unmarshal(body, &problem); err != nil {
+				t.Fatalf("decode problem %s: %v", body, err)
+			}
+			want := Problem{
+				Type:     "https://errors.example.com/thing_not_found",
+				Title:    "Not Found",
+				Status:   fiber.StatusNotFound,
+				Detail:   "Thing not found",
+				Instance: "/things/7?expand=1",
+				Code:     "thing_not_found",
+			}
+			if contentType != MIMEProblemJSON || problem.Type != want.Type || problem.Title != want.Title ||
+				problem.Status != want.Status || problem.Detail != want.Detail || problem.Instance != want.Instance || problem.Code != want.Code {
+				t.Errorf("response = %s %+v, want %s %+v", contentType, problem, MIMEProblemJSON, want)
+			}
+		})
+	}
+}
+
+func TestErrorHandlerProblemDetails(t *testing.T) {
+	t.Cleanup(func() { Configure(Options{}) })
+	Configure(Options{ErrorFormat: FormatProblem})
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Post("/validate", func(c *fiber.Ctx) error {
+		return apperror.Validation("validation_failed", "Validation failed").
+			WithFields(apperror.FieldError{Field: "email", Message: "must be a valid email"})
+	})
+	app.Get("/internal", func(c *fiber.Ctx) error { return errors.New("database is down") })
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   Problem
+	}{
+		{
+			name:   "field errors",
+			method: fiber.MethodPost,
+			path:   "/validate",
+			want: Problem{Type: "about:blank", Title: "Bad Request", Status: fiber.StatusBadRequest, Detail: "Validation failed", Code: "validation_failed",
+				Errors: []apperror.FieldError{{Field: "email", Message: "must be a valid email"}}},
+		},
+		{
+			name:   "internal errors are not leaked",
+			method: fiber.MethodGet,
+			path:   "/internal",
+			want: Problem{Type: "about:blank", Title: "Internal Server Error", Status: fiber.StatusInternalServerError,
+				Detail: "An unexpected error occurred", Code: "internal_error"},
+		},
+		{
+			name:   "fiber errors have no code",
+			method: fiber.MethodGet,
+			path:   "/missing",
+			want:   Problem{Type: "about:blank", Title: "Not Found", Status: fiber.StatusNotFound, Detail: "Cannot GET /missing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(tt.method, tt.path, nil))
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			var problem Problem
+			if err := json.NewDecoder(resp.Body).Decode(&problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+
+			if resp.StatusCode != tt.want.Status || problem.Status != tt.want.Status || problem.Title != tt.want.Title || problem.Type != tt.want.Type {
+				t.Errorf("problem = %d %+v, want %+v", resp.StatusCode, problem, tt.want)
+			}
+			if problem.Code != tt.want.Code || problem.Detail != tt.want.Detail {
+				t.Errorf("problem = %+v, want code %q and detail %q", problem, tt.want.Code, tt.want.Detail)
+			}
+			if len(problem.Errors) != len(tt.want.Errors) || (len(problem.Errors) > 0 && problem.Errors[0] != tt.want.Errors[0]) {
+				t.Errorf("errors = %+v, want %+v", problem.Errors, tt.want.Errors)
+			}
+			if problem.Instance != tt.path {
+				t.Errorf("instance = %q, want %q", problem.Instance, tt.path)
+			}
+		})
+	}
+}
@@ -1,15 +1,19 @@
 package response
 
 import (
+	"boilerplate-go-fiber-v2/pkg/apperror"
+
 	"github.com/gofiber/fiber/v2"
 )
 
 type Response struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
-	Meta    *Meta       `json:"meta,omitempty"`
+	Success bool                  `json:"success"`
+	Message string                `json:"message"`
+	Data    interface{}           `json:"data,omitempty"`
+	Error   string                `json:"error,omitempty"`
+	Code    string                `json:"code,omitempty"`
+	Errors  []apperror.FieldError `json:"errors,omitempty"`
+	Meta    *Meta                 `json:"meta,omitempty"`
 }
 
 type Meta struct {
@@ -30,10 +34,7 @@ func Success(c *fiber.Ctx, message string, data interface{}) error {
 
 // Error returns an error response
 func Error(c *fiber.Ctx, message string, statusCode int) error {
-	return c.Status(statusCode).JSON(Response{
-		Success: false,
-		Error:   message,
-	})
+	return writeError(c, statusCode, "", message, nil)
 }
 
 // SuccessWithMeta returns a success response with pagination metadata
@@ -48,48 +49,30 @@ func SuccessWithMeta(c *fiber.Ctx, message string, data interface{}, meta *Meta)
 
 // ValidationError returns a validation error response
 func ValidationError(c *fiber.Ctx, message string) error {
-	return c.Status(fiber.StatusBadRequest).JSON(Response{
-		Success: false,
-		Error:   message,
-	})
+	return writeError(c, fiber.StatusBadRequest, "validation_failed", message, nil)
 }
 
 // Unauthorized returns an unauthorized error response
 func Unauthorized(c *fiber.Ctx, message string) error {
-	return c.Status(fiber.StatusUnauthorized).JSON(Response{
-		Success: false,
-		Error:   message,
-	})
+	return writeError(c, fiber.StatusUnauthorized, "unauthorized", message, nil)
 }
 
 // Forbidden returns a forbidden error response
 func Forbidden(c *fiber.Ctx, message string) error {
-	return c.Status(fiber.StatusForbidden).JSON(Response{
-		Success: false,
-		Error:   message,
-	})
+	return writeError(c, fiber.StatusForbidden, "forbidden", message, nil)
 }
 
 // NotFound returns a not found error response
 func NotFound(c *fiber.Ctx, message string) error {
-	return c.Status(fiber.StatusNotFound).JSON(Response{
-		Success: false,
-		Error:   message,
-	})
+	return writeError(c, fiber.StatusNotFound, "not_found", message, nil)
 }
 
 // InternalServerError returns an internal server error response
 func InternalServerError(c *fiber.Ctx, message string) error {
-	return c.Status(fiber.StatusInternalServerError).JSON(Response{
-		Success: false,
-		Error:   message,
-	})
+	return writeError(c, fiber.StatusInternalServerError, "internal_error", message, nil)
 }
 
 // RateLimitExceeded returns a rate limit exceeded error response
 func RateLimitExceeded(c *fiber.Ctx, message string) error {
-	return c.Status(fiber.StatusTooManyRequests).JSON(Response{
-		Success: false,
-		Error:   message,
-	})
+	return writeError(c, fiber.StatusTooManyRequests, "rate_limited", message, nil)
 }
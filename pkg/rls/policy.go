@@ -0,0 +1,76 @@
+package rls
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var identifierPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// setting reads a setting set by the plugin, or NULL when none is set. After
+// a transaction-local set_config a setting reads as an empty string rather
+// than NULL for the rest of the session, so both mean unset.
+func setting(name string) string {
+	return "NULLIF(current_setting('" + name + "', true), '')"
+}
+
+// PolicyName returns the name of the tenant policy on table
+func PolicyName(table string) string {
+	return table + "_tenant_isolation"
+}
+
+// EnableSQL returns the statements that isolate the rows of table by the
+// tenant in tenantColumn. The policy fails closed and mirrors the
+// application's tenant scope: a tenant sees and writes only its own rows, a
+// user without a tenant only their rows in no tenant (matched on
+// userColumn), and statements with neither see nothing unless BypassSetting
+// is on. Row security is forced so the table owner is limited too, but
+// superusers and BYPASSRLS roles always bypass it.
+func EnableSQL(table, tenantColumn, userColumn string) (string, error) {
+	if err := validate(table, tenantColumn, userColumn); err != nil {
+		return "", err
+	}
+
+	condition := fmt.Sprintf("%s = 'on'\n"+
+		"    OR %s = %s :: BIGINT\n"+
+		"    OR (\n"+
+		"        %s IS NULL\n"+
+		"        AND %s IS NULL\n"+
+		"        AND %s = %s :: BIGINT\n"+
+		"    )",
+		setting(BypassSetting),
+		tenantColumn, setting(Setting),
+		setting(Setting),
+		tenantColumn,
+		userColumn, setting(UserSetting))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- Isolate %s by tenant\n", table)
+	fmt.Fprintf(&b, "ALTER TABLE\n    %s ENABLE ROW LEVEL SECURITY;\n\n", table)
+	fmt.Fprintf(&b, "ALTER TABLE\n    %s FORCE ROW LEVEL SECURITY;\n\n", table)
+	fmt.Fprintf(&b, "CREATE POLICY %s ON %s USING (\n    %s\n) WITH CHECK (\n    %s\n);\n", PolicyName(table), table, condition, condition)
+	return b.String(), nil
+}
+
+// DisableSQL returns the statements that undo EnableSQL
+func DisableSQL(table string) (string, error) {
+	if err := validate(table); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "DROP POLICY IF EXISTS %s ON %s;\n\n", PolicyName(table), table)
+	fmt.Fprintf(&b, "ALTER TABLE\n    %s NO FORCE ROW LEVEL SECURITY;\n\n", table)
+	fmt.Fprintf(&b, "ALTER TABLE\n    %s DISABLE ROW LEVEL SECURITY;\n", table)
+	return b.String(), nil
+}
+
+func validate(identifiers ...string) error {
+	for _, identifier := range identifiers {
+		if !identifierPattern.MatchString(identifier) {
+			return fmt.Errorf("rls: invalid identifier %q", identifier)
+		}
+	}
+	return nil
+}
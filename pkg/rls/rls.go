@@ -0,0 +1,183 @@
+package rls
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// Postgres settings tenant policies read. Any role can change a custom
+// setting, so they only guard against application bugs, not hostile SQL.
+const (
+	// Setting is the tenant policies compare org_id against
+	Setting = "app.current_tenant"
+
+	// UserSetting is the user whose rows outside any tenant are visible
+	UserSetting = "app.current_user_id"
+
+	// BypassSetting set to "on" lifts tenant policies for trusted code,
+	// such as migrations and background jobs that work across tenants
+	BypassSetting = "app.rls_bypass"
+)
+
+const startedTransactionKey = "rls:started_transaction"
+
+// ErrTransactionRequired is returned for Row and Rows calls made with a scope
+// outside a transaction; their rows outlive the callback, so the plugin
+// cannot scope a transaction around them
+var ErrTransactionRequired = errors.New("rls: scoped row queries must run inside a transaction")
+
+// Scope is what the statements of a context may see
+type Scope struct {
+	TenantID uint // active tenant; its rows only
+	UserID   uint // without a tenant, the user's rows in no tenant
+	All      bool // every row; for trusted code only
+}
+
+// IsZero reports whether the scope sees nothing
+func (s Scope) IsZero() bool {
+	return s == Scope{}
+}
+
+// ScopeFunc returns the scope of a context
+type ScopeFunc func(ctx context.Context) Scope
+
+// Plugin sets Setting, UserSetting and BypassSetting from the scope of each
+// statement's context. Values are set with set_config(..., true), so they are
+// local to the transaction and never leak to the next user of a pooled
+// connection. Statements that run outside a transaction are wrapped in one.
+// Statements without a scope leave every setting empty, so policies hide
+// every row from them.
+type Plugin struct {
+	scope ScopeFunc
+}
+
+// NewPlugin creates a plugin that reads the scope with scope
+func NewPlugin(scope ScopeFunc) *Plugin {
+	return &Plugin{scope: scope}
+}
+
+// Name implements gorm.Plugin
+func (p *Plugin) Name() string {
+	return "rls"
+}
+
+// Initialize implements gorm.Plugin
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+
+	// Create, update and delete already run in gorm's default transaction
+	// unless SkipDefaultTransaction is set; queries and raw statements get one
+	// here. Row and Rows return before their rows are read, so they can only
+	// use a transaction the caller opened.
+	return errors.Join(
+		cb.Create().After("gorm:begin_transaction").Before("gorm:before_create").Register("rls:begin_transaction", p.begin),
+		cb.Create().After("rls:begin_transaction").Before("gorm:before_create").Register("rls:set_tenant", p.setTenant),
+		cb.Create().After("gorm:commit_or_rollback_transaction").Register("rls:commit_or_rollback_transaction", commitOrRollback),
+
+		cb.Update().After("gorm:begin_transaction").Before("gorm:setup_reflect_value").Register("rls:begin_transaction", p.begin),
+		cb.Update().After("rls:begin_transaction").Before("gorm:setup_reflect_value").Register("rls:set_tenant", p.setTenant),
+		cb.Update().After("gorm:commit_or_rollback_transaction").Register("rls:commit_or_rollback_transaction", commitOrRollback),
+
+		cb.Delete().After("gorm:begin_transaction").Before("gorm:before_delete").Register("rls:begin_transaction", p.begin),
+		cb.Delete().After("rls:begin_transaction").Before("gorm:before_delete").Register("rls:set_tenant", p.setTenant),
+		cb.Delete().After("gorm:commit_or_rollback_transaction").Register("rls:commit_or_rollback_transaction", commitOrRollback),
+
+		cb.Query().Before("gorm:query").Register("rls:begin_transaction", p.begin),
+		cb.Query().After("rls:begin_transaction").Before("gorm:query").Register("rls:set_tenant", p.setTenant),
+		cb.Query().After("gorm:after_query").Register("rls:commit_or_rollback_transaction", commitOrRollback),
+
+		cb.Raw().Before("gorm:raw").Register("rls:begin_transaction", p.begin),
+		cb.Raw().After("rls:begin_transaction").Before("gorm:raw").Register("rls:set_tenant", p.setTenant),
+		cb.Raw().After("gorm:raw").Register("rls:commit_or_rollback_transaction", commitOrRollback),
+
+		cb.Row().Before("gorm:row").Register("rls:set_tenant", p.setRowTenant),
+	)
+}
+
+// Bypasses reports whether the connected role ignores row security
+// policies, as superusers and BYPASSRLS roles do
+func Bypasses(db *gorm.DB) (bool, error) {
+	var bypass bool
+	err := db.Raw("SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user").Scan(&bypass).Error
+	return bypass, err
+}
+
+// begin starts a transaction for a scoped statement that is not in one
+func (p *Plugin) begin(db *gorm.DB) {
+	if db.Error != nil || db.DryRun || p.scope(db.Statement.Context).IsZero() {
+		return
+	}
+
+	if tx := db.Begin(); tx.Error == nil {
+		db.Statement.ConnPool = tx.Statement.ConnPool
+		db.InstanceSet(startedTransactionKey, true)
+	} else if tx.Error == gorm.ErrInvalidTransaction {
+		// Already in a transaction
+		tx.Error = nil
+	} else {
+		db.AddError(tx.Error)
+	}
+}
+
+// setTenant sets the scope for the rest of the current transaction. Every
+// setting is written, so an earlier statement's scope never carries over.
+func (p *Plugin) setTenant(db *gorm.DB) {
+	if db.Error != nil || db.DryRun {
+		return
+	}
+
+	// Inside a transaction an unscoped statement still clears the scope of
+	// earlier ones, so it fails closed instead of inheriting it
+	scope := p.scope(db.Statement.Context)
+	if _, inTransaction := db.Statement.ConnPool.(gorm.TxCommitter); scope.IsZero() && !inTransaction {
+		return
+	}
+
+	var tenant, user, bypass string
+	if scope.TenantID != 0 {
+		tenant = strconv.FormatUint(uint64(scope.TenantID), 10)
+	}
+	if scope.UserID != 0 {
+		user = strconv.FormatUint(uint64(scope.UserID), 10)
+	}
+	if scope.All {
+		bypass = "on"
+	}
+
+	_, err := db.Statement.ConnPool.ExecContext(db.Statement.Context,
+		"SELECT set_config($1, $2, true), set_config($3, $4, true), set_config($5, $6, true)",
+		Setting, tenant, UserSetting, user, BypassSetting, bypass)
+	if err != nil {
+		db.AddError(err)
+	}
+}
+
+// setRowTenant sets the scope for Row and Rows, which must already be in a
+// transaction
+func (p *Plugin) setRowTenant(db *gorm.DB) {
+	if db.Error != nil || db.DryRun {
+		return
+	}
+
+	_, inTransaction := db.Statement.ConnPool.(gorm.TxCommitter)
+	if !inTransaction && !p.scope(db.Statement.Context).IsZero() {
+		db.AddError(ErrTransactionRequired)
+		return
+	}
+	p.setTenant(db)
+}
+
+// commitOrRollback ends a transaction started by begin
+func commitOrRollback(db *gorm.DB) {
+	if _, ok := db.InstanceGet(startedTransactionKey); ok {
+		if db.Error != nil {
+			db.Rollback()
+		} else {
+			db.Commit()
+		}
+		db.Statement.ConnPool = db.ConnPool
+	}
+}
@@ -0,0 +1,211 @@
+package rls_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"boilerplate-go-fiber-v2/pkg/rls"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// databaseURLEnv names a migrated database to run against. The role must not
+// be a superuser or have BYPASSRLS, or policies would not apply.
+const databaseURLEnv = "RLS_TEST_DATABASE_URL"
+
+// insufficientPrivilege is the SQLSTATE of a row that violates a policy
+const insufficientPrivilege = "42501"
+
+type scopeKey struct{}
+
+func withScope(scope rls.Scope) context.Context {
+	return context.WithValue(context.Background(), scopeKey{}, scope)
+}
+
+func scopeFromContext(ctx context.Context) rls.Scope {
+	scope, _ := ctx.Value(scopeKey{}).(rls.Scope)
+	return scope
+}
+
+// openDatabase connects with the plugin, or skips the test without a database
+func openDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv(databaseURLEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", databaseURLEnv)
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	if err := db.Use(rls.NewPlugin(scopeFromContext)); err != nil {
+		t.Fatalf("use plugin: %v", err)
+	}
+
+	bypass, err := rls.Bypasses(db)
+	if err != nil {
+		t.Fatalf("check role: %v", err)
+	}
+	if bypass {
+		t.Fatalf("the role of %s bypasses row level security; use a role without SUPERUSER or BYPASSRLS", databaseURLEnv)
+	}
+	return db
+}
+
+// fixtures are two organizations with an order each, and a personal order
+// of the same user. They are written with the bypass, which trusted code
+// such as background jobs uses.
+type fixtures struct {
+	userID, otherUserID uint
+	orgIDs              [2]uint
+	orderIDs            [2]uint
+	personalOrderID     uint
+}
+
+func createFixtures(t *testing.T, tx *gorm.DB) fixtures {
+	t.Helper()
+
+	suffix := strconv.FormatInt(time.Now().UnixNano(), 36)
+	all := tx.WithContext(withScope(rls.Scope{All: true}))
+	var f fixtures
+
+	for i, userID := range []*uint{&f.userID, &f.otherUserID} {
+		err := all.Raw("INSERT INTO users (email, username, password, first_name, last_name) VALUES (?, ?, '-', 'RLS', 'Test') RETURNING id",
+			fmt.Sprintf("rls-%s-%d@example.com", suffix, i), fmt.Sprintf("rls_%s_%d", suffix, i)).Scan(userID).Error
+		if err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+	}
+
+	for i := range f.orgIDs {
+		if err := all.Raw("INSERT INTO organizations (name) VALUES (?) RETURNING id", fmt.Sprintf("RLS test %d", i)).Scan(&f.orgIDs[i]).Error; err != nil {
+			t.Fatalf("create organization: %v", err)
+		}
+		f.orderIDs[i] = createOrder(t, all, f.userID, &f.orgIDs[i], fmt.Sprintf("RLS-%s-%d", suffix, i))
+	}
+
+	f.personalOrderID = createOrder(t, all, f.userID, nil, "RLS-"+suffix+"-personal")
+	createOrder(t, all, f.otherUserID, nil, "RLS-"+suffix+"-other")
+	return f
+}
+
+func createOrder(t *testing.T, db *gorm.DB, userID uint, orgID *uint, number string) uint {
+	t.Helper()
+
+	var id uint
+	err := db.Raw("INSERT INTO orders (user_id, org_id, order_number, total_amount) VALUES (?, ?, ?, 0) RETURNING id",
+		userID, orgID, number).Scan(&id).Error
+	if err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	return id
+}
+
+// visibleOrders reads the fixture users' orders without any tenant filter
+func visibleOrders(t *testing.T, tx *gorm.DB, scope rls.Scope, f fixtures) []uint {
+	t.Helper()
+
+	var ids []uint
+	err := tx.WithContext(withScope(scope)).Table("orders").
+		Where("user_id IN ?", []uint{f.userID, f.otherUserID}).
+		Order("id").Pluck("id", &ids).Error
+	if err != nil {
+		t.Fatalf("read orders: %v", err)
+	}
+	return ids
+}
+
+// assertPolicyViolation checks that err is Postgres rejecting a row under a
+// policy, rather than any other failure
+func assertPolicyViolation(t *testing.T, err error) {
+	t.Helper()
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != insufficientPrivilege {
+		t.Errorf("error = %v, want SQLSTATE %s", err, insufficientPrivilege)
+	}
+}
+
+// insertOrder tries an insert under a savepoint, so the failed statement
+// does not abort the test transaction
+func insertOrder(t *testing.T, tx *gorm.DB, scope rls.Scope, userID uint, orgID *uint) error {
+	t.Helper()
+
+	if err := tx.SavePoint("rls_insert").Error; err != nil {
+		t.Fatalf("create savepoint: %v", err)
+	}
+	err := tx.WithContext(withScope(scope)).Exec("INSERT INTO orders (user_id, org_id, order_number, total_amount) VALUES (?, ?, ?, 0)",
+		userID, orgID, "RLS-insert-"+strconv.FormatInt(time.Now().UnixNano(), 36)).Error
+	if err := tx.RollbackTo("rls_insert").Error; err != nil {
+		t.Fatalf("roll back savepoint: %v", err)
+	}
+	return err
+}
+
+func TestTenantIsolation(t *testing.T) {
+	db := openDatabase(t)
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		t.Fatalf("begin: %v", tx.Error)
+	}
+	defer tx.Rollback()
+
+	f := createFixtures(t, tx)
+	tenant := rls.Scope{TenantID: f.orgIDs[0], UserID: f.userID}
+
+	t.Run("tenant reads only its rows", func(t *testing.T) {
+		got := visibleOrders(t, tx, tenant, f)
+		if len(got) != 1 || got[0] != f.orderIDs[0] {
+			t.Errorf("tenant %d read orders %v, want only %d", f.orgIDs[0], got, f.orderIDs[0])
+		}
+	})
+
+	t.Run("tenant cannot update another tenant", func(t *testing.T) {
+		result := tx.WithContext(withScope(tenant)).Table("orders").Where("id = ?", f.orderIDs[1]).Update("note", "rls test")
+		if result.Error != nil {
+			t.Fatalf("update: %v", result.Error)
+		}
+		if result.RowsAffected != 0 {
+			t.Errorf("tenant %d updated order %d of tenant %d", f.orgIDs[0], f.orderIDs[1], f.orgIDs[1])
+		}
+	})
+
+	t.Run("tenant cannot insert into another tenant", func(t *testing.T) {
+		assertPolicyViolation(t, insertOrder(t, tx, tenant, f.userID, &f.orgIDs[1]))
+	})
+
+	t.Run("user without tenant reads only personal rows", func(t *testing.T) {
+		got := visibleOrders(t, tx, rls.Scope{UserID: f.userID}, f)
+		if len(got) != 1 || got[0] != f.personalOrderID {
+			t.Errorf("user %d read orders %v, want only %d", f.userID, got, f.personalOrderID)
+		}
+	})
+
+	t.Run("user without tenant cannot insert for another user", func(t *testing.T) {
+		assertPolicyViolation(t, insertOrder(t, tx, rls.Scope{UserID: f.userID}, f.otherUserID, nil))
+	})
+
+	t.Run("no scope fails closed", func(t *testing.T) {
+		if got := visibleOrders(t, tx, rls.Scope{}, f); len(got) != 0 {
+			t.Errorf("unscoped read returned orders %v, want none", got)
+		}
+		assertPolicyViolation(t, insertOrder(t, tx, rls.Scope{}, f.userID, nil))
+	})
+
+	t.Run("bypass reads every row", func(t *testing.T) {
+		if got := visibleOrders(t, tx, rls.Scope{All: true}, f); len(got) != 4 {
+			t.Errorf("bypass read orders %v, want all 4", got)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package sms
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Message is a text message recorded by CaptureSender
+type Message struct {
+	To     string
+	Body   string
+	SentAt time.Time
+}
+
+// CaptureSender keeps messages in memory and logs them instead of sending
+// them. Use it in development and in integration tests.
+type CaptureSender struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewCaptureSender creates a new capture sender
+func NewCaptureSender() *CaptureSender {
+	return &CaptureSender{}
+}
+
+// Send records a message
+func (s *CaptureSender) Send(ctx context.Context, to, body string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = append(s.messages, Message{To: to, Body: body, SentAt: time.Now()})
+	log.Printf("SMS to %s: %s", to, body)
+	return nil
+}
+
+// Messages returns the recorded messages, oldest first
+func (s *CaptureSender) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Message(nil), s.messages...)
+}
+
+// Last returns the most recent message sent to a number
+func (s *CaptureSender) Last(to string) (Message, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := len(s.messages) - 1; i >= 0; i-- {
+		if s.messages[i].To == to {
+			return s.messages[i], true
+		}
+	}
+	return Message{}, false
+}
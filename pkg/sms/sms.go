@@ -0,0 +1,8 @@
+package sms
+
+import "context"
+
+// SMSSender delivers text messages to E.164 phone numbers
+type SMSSender interface {
+	Send(ctx context.Context, to, body string) error
+}
@@ -0,0 +1,70 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TwilioConfig holds the credentials of a Twilio-compatible messaging API
+type TwilioConfig struct {
+	BaseURL    string // e.g. https://api.twilio.com
+	AccountSID string
+	AuthToken  string
+	From       string
+}
+
+// TwilioSender sends messages through the Twilio Messages API, or any
+// provider exposing the same endpoint
+type TwilioSender struct {
+	config TwilioConfig
+	client *http.Client
+}
+
+// NewTwilioSender creates a new Twilio sender
+func NewTwilioSender(config TwilioConfig) *TwilioSender {
+	return &TwilioSender{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Send delivers a message
+func (s *TwilioSender) Send(ctx context.Context, to, body string) error {
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json",
+		strings.TrimRight(s.config.BaseURL, "/"), url.PathEscape(s.config.AccountSID))
+
+	form := url.Values{
+		"To":   {to},
+		"From": {s.config.From},
+		"Body": {body},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.config.AccountSID, s.config.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("sms provider returned %d: %s (code %d)", resp.StatusCode, apiErr.Message, apiErr.Code)
+	}
+
+	return nil
+}
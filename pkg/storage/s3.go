@@ -0,0 +1,283 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3TimeFormat      = "20060102T150405Z"
+	s3DateFormat      = "20060102"
+)
+
+// S3Config configures an S3-compatible storage (AWS S3, MinIO, R2, ...)
+type S3Config struct {
+	Endpoint      string // e.g. https://s3.amazonaws.com or http://localhost:9000
+	Region        string
+	Bucket        string
+	AccessKey     string
+	SecretKey     string
+	UsePathStyle  bool   // required by MinIO and most self-hosted stores
+	PublicBaseURL string // optional CDN or public bucket URL; objects are presigned when empty
+}
+
+// S3Storage stores objects in an S3-compatible bucket using SigV4-signed
+// requests, so it works against MinIO as well as AWS.
+type S3Storage struct {
+	config   S3Config
+	endpoint *url.URL
+	client   *http.Client
+}
+
+// NewS3Storage creates a new S3-compatible storage
+func NewS3Storage(config S3Config) (*S3Storage, error) {
+	endpoint, err := url.Parse(strings.TrimRight(config.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %w", err)
+	}
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint: %q", config.Endpoint)
+	}
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("S3 bucket is required")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+
+	return &S3Storage{
+		config:   config,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Put uploads an object
+func (s *S3Storage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key).String(), body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	s.signRequest(req, time.Now().UTC())
+	return s.do(req, http.StatusOK)
+}
+
+// Delete removes an object
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key).String(), nil)
+	if err != nil {
+		return err
+	}
+
+	s.signRequest(req, time.Now().UTC())
+	return s.do(req, http.StatusNoContent, http.StatusOK, http.StatusNotFound)
+}
+
+// URL returns the public URL of an object, or a presigned GET URL
+func (s *S3Storage) URL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+
+	if s.config.PublicBaseURL != "" {
+		return strings.TrimRight(s.config.PublicBaseURL, "/") + "/" + key, nil
+	}
+
+	return s.presign(http.MethodGet, key, expiry, time.Now().UTC()), nil
+}
+
+// objectURL builds the URL of an object in path or virtual-hosted style
+func (s *S3Storage) objectURL(key string) *url.URL {
+	objectURL := *s.endpoint
+	if s.config.UsePathStyle {
+		objectURL.Path = s.endpoint.Path + "/" + s.config.Bucket + "/" + key
+	} else {
+		objectURL.Host = s.config.Bucket + "." + s.endpoint.Host
+		objectURL.Path = s.endpoint.Path + "/" + key
+	}
+	objectURL.RawPath = s3EscapePath(objectURL.Path)
+	return &objectURL
+}
+
+// signRequest adds SigV4 authorization headers to a request
+func (s *S3Storage) signRequest(req *http.Request, now time.Time) {
+	amzDate := now.Format(s3TimeFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedPayload)
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": s3UnsignedPayload,
+		"x-amz-date":           amzDate,
+	}
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		headers["content-type"] = contentType
+	}
+
+	canonicalHeaders, signedHeaders := s3CanonicalHeaders(headers)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s3EscapePath(req.URL.Path),
+		"",
+		canonicalHeaders,
+		signedHeaders,
+		s3UnsignedPayload,
+	}, "\n")
+
+	scope := s.scope(now)
+	signature := s.signature(now, s.stringToSign(amzDate, scope, canonicalRequest))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.config.AccessKey, scope, signedHeaders, signature))
+}
+
+// presign returns a SigV4 query-signed URL
+func (s *S3Storage) presign(method, key string, expiry time.Duration, now time.Time) string {
+	objectURL := s.objectURL(key)
+	amzDate := now.Format(s3TimeFormat)
+	scope := s.scope(now)
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", s.config.AccessKey+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expiry.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalQuery := s3CanonicalQuery(query)
+	canonicalRequest := strings.Join([]string{
+		method,
+		objectURL.RawPath,
+		canonicalQuery,
+		"host:" + objectURL.Host + "\n",
+		"host",
+		s3UnsignedPayload,
+	}, "\n")
+
+	signature := s.signature(now, s.stringToSign(amzDate, scope, canonicalRequest))
+	objectURL.RawQuery = canonicalQuery + "&X-Amz-Signature=" + signature
+
+	return objectURL.String()
+}
+
+// scope returns the credential scope for a request time
+func (s *S3Storage) scope(now time.Time) string {
+	return fmt.Sprintf("%s/%s/s3/aws4_request", now.Format(s3DateFormat), s.config.Region)
+}
+
+// stringToSign builds the SigV4 string to sign
+func (s *S3Storage) stringToSign(amzDate, scope, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	return strings.Join([]string{s3Algorithm, amzDate, scope, hex.EncodeToString(hash[:])}, "\n")
+}
+
+// signature derives the signing key and signs the string to sign
+func (s *S3Storage) signature(now time.Time, stringToSign string) string {
+	key := s3HMAC([]byte("AWS4"+s.config.SecretKey), now.Format(s3DateFormat))
+	key = s3HMAC(key, s.config.Region)
+	key = s3HMAC(key, "s3")
+	key = s3HMAC(key, "aws4_request")
+	return hex.EncodeToString(s3HMAC(key, stringToSign))
+}
+
+// do sends a request and checks the response status
+func (s *S3Storage) do(req *http.Request, expected ...int) error {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	for _, status := range expected {
+		if resp.StatusCode == status {
+			return nil
+		}
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("s3 %s %s: unexpected status %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+func s3HMAC(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3CanonicalHeaders returns the canonical header block and signed header list
+func s3CanonicalHeaders(headers map[string]string) (string, string) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonical strings.Builder
+	for _, name := range names {
+		canonical.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	return canonical.String(), strings.Join(names, ";")
+}
+
+// s3CanonicalQuery encodes query parameters sorted by key as SigV4 requires
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		for _, value := range query[key] {
+			pairs = append(pairs, s3Escape(key, true)+"="+s3Escape(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// s3EscapePath URI-encodes a path, keeping the slashes between segments
+func s3EscapePath(path string) string {
+	return s3Escape(path, false)
+}
+
+// s3Escape URI-encodes everything except unreserved characters
+func s3Escape(value string, encodeSlash bool) string {
+	var escaped strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			escaped.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			escaped.WriteByte(c)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", c)
+		}
+	}
+	return escaped.String()
+}
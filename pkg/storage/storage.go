@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrInvalidKey is returned for keys that are empty or escape the storage root
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// Storage is an object store for user-uploaded files
+type Storage interface {
+	// Put stores an object under key, replacing any existing object
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Delete removes an object; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+	// URL returns a URL clients can fetch the object from. Private storages
+	// return a signed URL that is valid for expiry.
+	URL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// validateKey rejects keys that could address files outside the storage root
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Options configures code generation (RFC 6238)
+type Options struct {
+	Issuer    string
+	Algorithm string // SHA1, SHA256 or SHA512
+	Digits    int
+	Period    int // seconds
+	Skew      int // periods accepted before and after the current one
+}
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a random base32 secret for authenticator apps
+func GenerateSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(b), nil
+}
+
+// Generate returns the code of a secret at a point in time
+func Generate(secret string, t time.Time, opts Options) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return "", fmt.Errorf("invalid totp secret: %w", err)
+	}
+
+	return code(key, uint64(t.Unix())/uint64(period(opts)), opts), nil
+}
+
+// Validate checks a code against the secret, accepting opts.Skew periods of
+// clock drift
+func Validate(secret, input string, t time.Time, opts Options) bool {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil || len(input) != digits(opts) {
+		return false
+	}
+
+	counter := int64(t.Unix()) / int64(period(opts))
+	for i := -opts.Skew; i <= opts.Skew; i++ {
+		if counter+int64(i) < 0 {
+			continue
+		}
+		expected := code(key, uint64(counter+int64(i)), opts)
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(input)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// URL returns the otpauth:// provisioning URI, usually rendered as a QR code
+func URL(secret, account string, opts Options) string {
+	label := url.PathEscape(account)
+	if opts.Issuer != "" {
+		label = url.PathEscape(opts.Issuer) + ":" + label
+	}
+
+	query := url.Values{
+		"secret":    {secret},
+		"algorithm": {algorithm(opts)},
+		"digits":    {strconv.Itoa(digits(opts))},
+		"period":    {strconv.Itoa(period(opts))},
+	}
+	if opts.Issuer != "" {
+		query.Set("issuer", opts.Issuer)
+	}
+
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+func code(key []byte, counter uint64, opts Options) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	mac := hmac.New(hashFunc(opts), key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation (RFC 4226 section 5.3)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits(opts); i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits(opts), value%mod)
+}
+
+func hashFunc(opts Options) func() hash.Hash {
+	switch algorithm(opts) {
+	case "SHA256":
+		return sha256.New
+	case "SHA512":
+		return sha512.New
+	default:
+		return sha1.New
+	}
+}
+
+func algorithm(opts Options) string {
+	switch strings.ToUpper(opts.Algorithm) {
+	case "SHA256", "SHA512":
+		return strings.ToUpper(opts.Algorithm)
+	default:
+		return "SHA1"
+	}
+}
+
+func digits(opts Options) int {
+	if opts.Digits == 6 || opts.Digits == 8 {
+		return opts.Digits
+	}
+	return 6
+}
+
+func period(opts Options) int {
+	if opts.Period > 0 {
+		return opts.Period
+	}
+	return 30
+}
@@ -1,10 +1,20 @@
 package utils
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"os"
 
 	"boilerplate-go-fiber-v2/config"
+	"boilerplate-go-fiber-v2/pkg/hasher"
+	"boilerplate-go-fiber-v2/pkg/mailer"
+	"boilerplate-go-fiber-v2/pkg/pwned"
+	"boilerplate-go-fiber-v2/pkg/response"
+	"boilerplate-go-fiber-v2/pkg/rls"
+	"boilerplate-go-fiber-v2/pkg/sms"
+	"boilerplate-go-fiber-v2/pkg/storage"
+	"boilerplate-go-fiber-v2/pkg/validator"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/joho/godotenv"
@@ -22,10 +32,43 @@ func InitializeApp() (*fiber.App, *config.Config, error) {
 	// Load configuration
 	cfg := config.Load()
 
+	// Configure error rendering
+	response.Configure(response.Options{
+		ErrorFormat:        cfg.Response.ErrorFormat,
+		ProblemTypeBaseURL: cfg.Response.ProblemTypeBaseURL,
+	})
+
+	// Configure custom validation rules
+	validator.Configure(validator.Options{
+		DefaultCountryCode: cfg.Validation.DefaultCountryCode,
+		PasswordPolicy: validator.PasswordPolicy{
+			MinLength:     cfg.Validation.PasswordMinLength,
+			MaxLength:     cfg.Validation.PasswordMaxLength,
+			RequireUpper:  cfg.Validation.PasswordRequireUpper,
+			RequireLower:  cfg.Validation.PasswordRequireLower,
+			RequireDigit:  cfg.Validation.PasswordRequireDigit,
+			RequireSymbol: cfg.Validation.PasswordRequireSymbol,
+			MinEntropy:    float64(cfg.Validation.PasswordMinEntropy),
+		},
+	})
+
+	// Configure password hashing
+	if err := hasher.Configure(hasher.Options{
+		Algorithm: cfg.Hashing.Algorithm,
+		Argon2: hasher.Argon2Params{
+			Memory:      uint32(cfg.Hashing.Argon2Memory),
+			Iterations:  uint32(cfg.Hashing.Argon2Iterations),
+			Parallelism: uint8(cfg.Hashing.Argon2Parallelism),
+		},
+		BcryptCost: cfg.Hashing.BcryptCost,
+	}); err != nil {
+		return nil, nil, fmt.Errorf("invalid password hashing config: %w", err)
+	}
+
 	// Create Fiber app
 	app := fiber.New(fiber.Config{
 		AppName:      "Boilerplate Go Fiber v2",
-		ErrorHandler: nil, // Will be set in main.go
+		ErrorHandler: response.ErrorHandler,
 	})
 
 	return app, cfg, nil
@@ -34,15 +77,108 @@ func InitializeApp() (*fiber.App, *config.Config, error) {
 // InitializeDatabase initializes database connection
 func InitializeDatabase(cfg *config.Config) *gorm.DB {
 	db := config.NewDatabase(cfg)
+
+	// Scope every statement to the request's organization and user. Migration
+	// 00022 enables row security policies that see nothing without a scope,
+	// so the plugin is always on.
+	if err := db.Use(rls.NewPlugin(RLSScope)); err != nil {
+		log.Fatal("Failed to enable row level security:", err)
+	}
+	if bypass, err := rls.Bypasses(db); err != nil {
+		log.Fatal("Failed to check database role:", err)
+	} else if bypass {
+		log.Println("Warning: database role bypasses row level security; connect as a role without SUPERUSER or BYPASSRLS")
+	}
+
 	return db
 }
 
+// RLSScope returns the row security scope of a context, mirroring the tenant
+// scope of repositories: the active organization, else the user, else every
+// row for contexts from WithAllTenants
+func RLSScope(ctx context.Context) rls.Scope {
+	return rls.Scope{
+		TenantID: OrgIDFromContext(ctx),
+		UserID:   UserIDFromContext(ctx),
+		All:      AllTenantsFromContext(ctx),
+	}
+}
+
 // InitializeRedis initializes Redis connection
 func InitializeRedis(cfg *config.Config) *redis.Client {
 	redis := config.NewRedis(cfg)
 	return redis
 }
 
+// InitializeStorage initializes the object storage backend
+func InitializeStorage(cfg *config.Config) (storage.Storage, error) {
+	switch cfg.Storage.Driver {
+	case "local":
+		return storage.NewLocalStorage(
+			cfg.Storage.LocalDir,
+			cfg.Storage.LocalBaseURL,
+			cfg.Storage.SigningKey,
+			cfg.Storage.Public,
+		), nil
+	case "s3":
+		return storage.NewS3Storage(storage.S3Config{
+			Endpoint:      cfg.Storage.S3Endpoint,
+			Region:        cfg.Storage.S3Region,
+			Bucket:        cfg.Storage.S3Bucket,
+			AccessKey:     cfg.Storage.S3AccessKey,
+			SecretKey:     cfg.Storage.S3SecretKey,
+			UsePathStyle:  cfg.Storage.S3PathStyle,
+			PublicBaseURL: cfg.Storage.PublicBaseURL,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %s", cfg.Storage.Driver)
+	}
+}
+
+// InitializeMailer initializes the email delivery backend
+func InitializeMailer(cfg *config.Config) (mailer.Mailer, error) {
+	switch cfg.Email.Driver {
+	case "log":
+		return mailer.NewLogMailer(), nil
+	case "smtp":
+		return mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:     cfg.Email.SMTPHost,
+			Port:     cfg.Email.SMTPPort,
+			Username: cfg.Email.SMTPUsername,
+			Password: cfg.Email.SMTPPassword,
+			From:     cfg.Email.From,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown email driver: %s", cfg.Email.Driver)
+	}
+}
+
+// InitializeSMSSender initializes the SMS delivery backend
+func InitializeSMSSender(cfg *config.Config) (sms.SMSSender, error) {
+	switch cfg.SMS.Driver {
+	case "capture":
+		return sms.NewCaptureSender(), nil
+	case "twilio":
+		return sms.NewTwilioSender(sms.TwilioConfig{
+			BaseURL:    cfg.SMS.TwilioBaseURL,
+			AccountSID: cfg.SMS.TwilioAccountSID,
+			AuthToken:  cfg.SMS.TwilioAuthToken,
+			From:       cfg.SMS.From,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown sms driver: %s", cfg.SMS.Driver)
+	}
+}
+
+// InitializePasswordChecker initializes the breached password checker
+func InitializePasswordChecker(cfg *config.Config) *pwned.Checker {
+	checker := pwned.NewChecker(cfg.Validation.BreachDatasetDir, cfg.Validation.BreachThreshold)
+	if cfg.Validation.BreachThreshold > 0 && !checker.Available() {
+		log.Printf("Warning: breached password dataset not found in %s, only common passwords are rejected", cfg.Validation.BreachDatasetDir)
+	}
+	return checker
+}
+
 // GetPort returns the port from environment or default
 func GetPort() string {
 	port := os.Getenv("PORT")
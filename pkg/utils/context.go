@@ -0,0 +1,70 @@
+package utils
+
+import "context"
+
+// Context keys populated by middleware.RequestContext. Fiber stores locals as
+// fasthttp user values, which are exposed through the request context.
+const (
+	ClientIPKey  = "client_ip"
+	UserAgentKey = "user_agent"
+	LanguageKey  = "accept_language"
+	UserIDKey    = "user_id"  // set by middleware.AuthMiddleware for sessions and personal tokens
+	ActorIDKey   = "actor_id" // set by middleware.AuthMiddleware for impersonation sessions
+	OrgIDKey     = "org_id"   // set by middleware.AuthMiddleware when an organization is active
+)
+
+// allTenantsKey marks contexts of trusted code that reads across tenants. It
+// is unexported so only WithAllTenants can set it, never a request local.
+type allTenantsKey struct{}
+
+// ClientIPFromContext returns the client IP address of the current request
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(ClientIPKey).(string)
+	return ip
+}
+
+// UserAgentFromContext returns the user agent of the current request
+func UserAgentFromContext(ctx context.Context) string {
+	userAgent, _ := ctx.Value(UserAgentKey).(string)
+	return userAgent
+}
+
+// LanguageFromContext returns the Accept-Language header of the current request
+func LanguageFromContext(ctx context.Context) string {
+	language, _ := ctx.Value(LanguageKey).(string)
+	return language
+}
+
+// UserIDFromContext returns the authenticated user of the current request, or
+// 0 for anonymous requests, service keys and background jobs
+func UserIDFromContext(ctx context.Context) uint {
+	userID, _ := ctx.Value(UserIDKey).(uint)
+	return userID
+}
+
+// ActorIDFromContext returns the admin impersonating the user of the current
+// request, or 0
+func ActorIDFromContext(ctx context.Context) uint {
+	actorID, _ := ctx.Value(ActorIDKey).(uint)
+	return actorID
+}
+
+// OrgIDFromContext returns the active organization of the current request, or
+// 0 when the request is not scoped to one
+func OrgIDFromContext(ctx context.Context) uint {
+	orgID, _ := ctx.Value(OrgIDKey).(uint)
+	return orgID
+}
+
+// WithAllTenants returns a context whose queries are not limited to a tenant
+// or user. Only background jobs and other trusted code that work across
+// tenants, such as data exports, may use it; request handlers never should.
+func WithAllTenants(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allTenantsKey{}, true)
+}
+
+// AllTenantsFromContext reports whether ctx was created by WithAllTenants
+func AllTenantsFromContext(ctx context.Context) bool {
+	all, _ := ctx.Value(allTenantsKey{}).(bool)
+	return all
+}
@@ -1,23 +1,30 @@
 package utils
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 
-	"golang.org/x/crypto/bcrypt"
+	"boilerplate-go-fiber-v2/pkg/hasher"
 )
 
-// HashPassword hashes a password using bcrypt
+// HashPassword hashes a password using the configured algorithm
 func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	return string(bytes), err
+	return hasher.Hash(password)
 }
 
 // CheckPassword compares a password with its hash
 func CheckPassword(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+	ok, err := hasher.Verify(password, hash)
+	return err == nil && ok
+}
+
+// PasswordNeedsRehash reports whether a hash uses an outdated algorithm or parameters
+func PasswordNeedsRehash(hash string) bool {
+	return hasher.NeedsRehash(hash)
 }
 
 // GenerateSecureToken generates a secure random token
@@ -30,6 +37,21 @@ func GenerateSecureToken(length int) (string, error) {
 	return base64.URLEncoding.EncodeToString(b), nil
 }
 
+// HashToken returns the SHA-256 hex digest of a token, for storing tokens
+// that are looked up but must not be readable from the database
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// HashCode returns the HMAC-SHA256 hex digest of a short one-time code. Unlike
+// HashToken it is keyed, since codes are too short to resist brute force.
+func HashCode(code, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 // GenerateTFACode generates a 6-digit TFA code
 func GenerateTFACode() string {
 	b := make([]byte, 3)
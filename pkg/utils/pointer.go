@@ -22,3 +22,17 @@ func SafePtr[T any](ptr *T, defaultValue T) T {
 	}
 	return *ptr
 }
+
+// NilIfZero returns nil for the zero value and a pointer otherwise, e.g. to
+// store empty strings as NULL
+// Usage examples:
+//
+//	utils.NilIfZero("")        // returns nil
+//	utils.NilIfZero("token")   // returns *string
+func NilIfZero[T comparable](value T) *T {
+	var zero T
+	if value == zero {
+		return nil
+	}
+	return &value
+}
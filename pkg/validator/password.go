@@ -0,0 +1,189 @@
+package validator
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordPolicy describes the passwords accepted by the "password" tag
+type PasswordPolicy struct {
+	MinLength     int
+	MaxLength     int // bcrypt ignores everything past 72 bytes
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	MinEntropy    float64 // estimated bits, see EstimateEntropy
+}
+
+// Password policy violations, in the order they are checked
+const (
+	PasswordTooShort      = "min_length"
+	PasswordTooLong       = "max_length"
+	PasswordMissingUpper  = "upper"
+	PasswordMissingLower  = "lower"
+	PasswordMissingDigit  = "digit"
+	PasswordMissingSymbol = "symbol"
+	PasswordTooWeak       = "entropy"
+)
+
+// DefaultPasswordPolicy follows NIST SP 800-63B: a minimum length and a
+// strength estimate rather than mandatory character classes
+var DefaultPasswordPolicy = PasswordPolicy{
+	MinLength:  8,
+	MaxLength:  72,
+	MinEntropy: 36,
+}
+
+// keyboardRows are checked for adjacent-key runs such as "qwerty" or "asdf"
+var keyboardRows = []string{
+	"`1234567890-=",
+	"qwertyuiop[]\\",
+	"asdfghjkl;'",
+	"zxcvbnm,./",
+}
+
+// Check returns the first policy violation of a password, or "" if it is accepted
+func (p PasswordPolicy) Check(password string) string {
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		return PasswordTooShort
+	}
+	if p.MaxLength > 0 && len(password) > p.MaxLength {
+		return PasswordTooLong
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	switch {
+	case p.RequireUpper && !upper:
+		return PasswordMissingUpper
+	case p.RequireLower && !lower:
+		return PasswordMissingLower
+	case p.RequireDigit && !digit:
+		return PasswordMissingDigit
+	case p.RequireSymbol && !symbol:
+		return PasswordMissingSymbol
+	}
+
+	if EstimateEntropy(password) < p.MinEntropy {
+		return PasswordTooWeak
+	}
+
+	return ""
+}
+
+// EstimateEntropy estimates the strength of a password in bits, in the spirit
+// of zxcvbn: repeated characters, alphabetical or numeric sequences and
+// keyboard runs are scored as a single guessable pattern, and everything else
+// is scored as brute force over the character classes in use.
+func EstimateEntropy(password string) float64 {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return 0
+	}
+
+	bitsPerChar := math.Log2(float64(poolSize(runes)))
+	lowered := []rune(strings.ToLower(password))
+
+	var entropy float64
+	for i := 0; i < len(runes); {
+		if n := patternLength(lowered[i:]); n >= 3 {
+			// Guessing a pattern means guessing its start, direction and length
+			entropy += math.Log2(float64(poolSize(runes[i:i+n]))) + math.Log2(float64(n)) + 1
+			i += n
+			continue
+		}
+		entropy += bitsPerChar
+		i++
+	}
+
+	return entropy
+}
+
+// poolSize returns the number of characters an attacker must try per position
+func poolSize(runes []rune) int {
+	var upper, lower, digit, symbol, other bool
+	for _, r := range runes {
+		switch {
+		case r > unicode.MaxASCII:
+			other = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	pool := 0
+	if upper {
+		pool += 26
+	}
+	if lower {
+		pool += 26
+	}
+	if digit {
+		pool += 10
+	}
+	if symbol {
+		pool += 33
+	}
+	if other {
+		pool += 100
+	}
+	return pool
+}
+
+// patternLength returns the length of the longest repeat, sequence or
+// keyboard run at the start of s
+func patternLength(s []rune) int {
+	longest := 1
+	for _, step := range []func(a, b rune) bool{sameRune, nextRune, prevRune, keyboardAdjacent} {
+		n := 1
+		for n < len(s) && step(s[n-1], s[n]) {
+			n++
+		}
+		if n > longest {
+			longest = n
+		}
+	}
+	return longest
+}
+
+func sameRune(a, b rune) bool { return a == b }
+
+func nextRune(a, b rune) bool { return b == a+1 }
+
+func prevRune(a, b rune) bool { return b == a-1 }
+
+// keyboardAdjacent reports whether b sits next to a on a QWERTY row
+func keyboardAdjacent(a, b rune) bool {
+	for _, row := range keyboardRows {
+		i := strings.IndexRune(row, a)
+		if i < 0 {
+			continue
+		}
+		if (i > 0 && rune(row[i-1]) == b) || (i+1 < len(row) && rune(row[i+1]) == b) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package validator
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidPhone is returned for numbers that cannot be normalized to E.164
+var ErrInvalidPhone = errors.New("invalid phone number")
+
+var e164Regex = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+// NormalizePhone converts a phone number to E.164. Spaces, dashes, dots and
+// parentheses are dropped, a 00 international prefix becomes +, and national
+// numbers with a leading 0 trunk prefix get the default country code.
+func NormalizePhone(raw string) (string, error) {
+	phone := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(raw))
+
+	switch {
+	case strings.HasPrefix(phone, "+"):
+	case strings.HasPrefix(phone, "00"):
+		phone = "+" + phone[2:]
+	case strings.HasPrefix(phone, "0"):
+		phone = "+" + options.DefaultCountryCode + phone[1:]
+	default:
+		phone = "+" + phone
+	}
+
+	if !e164Regex.MatchString(phone) {
+		return "", ErrInvalidPhone
+	}
+	return phone, nil
+}
@@ -0,0 +1,152 @@
+package validator
+
+import (
+	"regexp"
+	"strconv"
+
+	"boilerplate-go-fiber-v2/pkg/apperror"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+)
+
+// Options configures the custom validation rules
+type Options struct {
+	DefaultCountryCode string // used to normalize national phone numbers, e.g. "62"
+	PasswordPolicy     PasswordPolicy
+}
+
+var options = Options{
+	DefaultCountryCode: "62",
+	PasswordPolicy:     DefaultPasswordPolicy,
+}
+
+// Configure sets the custom rule options. It must be called before the
+// server starts handling requests.
+func Configure(opts Options) {
+	options = opts
+}
+
+// usernameRegex allows letters and digits separated by single dots or
+// underscores, starting with a letter
+var usernameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9]*(?:[._][a-zA-Z0-9]+)*$`)
+
+// messages holds the translations of the custom rules and of field errors
+// raised outside struct validation, such as "unique"
+var messages = map[string]map[string]string{
+	"en": {
+		"phone":               "{0} must be a valid phone number in international format",
+		"username":            "{0} may only contain letters, digits and single dots or underscores, and must start with a letter",
+		"unique":              "{0} is already taken",
+		"password_min_length": "{0} must be at least {1} characters long",
+		"password_max_length": "{0} must be at most {1} bytes long",
+		"password_upper":      "{0} must contain an uppercase letter",
+		"password_lower":      "{0} must contain a lowercase letter",
+		"password_digit":      "{0} must contain a digit",
+		"password_symbol":     "{0} must contain a symbol",
+		"password_entropy":    "{0} is too easy to guess",
+		"breached":            "{0} has appeared in a data breach and cannot be used",
+		"common":              "{0} is too common",
+		"reused":              "{0} must not match any of your last {1} passwords",
+	},
+	"id": {
+		"phone":               "{0} harus berupa nomor telepon yang valid dalam format internasional",
+		"username":            "{0} hanya boleh berisi huruf, angka, serta titik atau garis bawah tunggal, dan harus diawali huruf",
+		"unique":              "{0} sudah digunakan",
+		"password_min_length": "panjang minimal {0} adalah {1} karakter",
+		"password_max_length": "panjang maksimal {0} adalah {1} byte",
+		"password_upper":      "{0} harus mengandung huruf besar",
+		"password_lower":      "{0} harus mengandung huruf kecil",
+		"password_digit":      "{0} harus mengandung angka",
+		"password_symbol":     "{0} harus mengandung simbol",
+		"password_entropy":    "{0} terlalu mudah ditebak",
+		"breached":            "{0} pernah muncul dalam kebocoran data dan tidak dapat digunakan",
+		"common":              "{0} terlalu umum",
+		"reused":              "{0} tidak boleh sama dengan {1} kata sandi terakhir Anda",
+	},
+}
+
+// registerRules registers the custom validation tags and their translations
+func registerRules() {
+	must(validate.RegisterValidation("phone", func(fl validator.FieldLevel) bool {
+		_, err := NormalizePhone(fl.Field().String())
+		return err == nil
+	}))
+	must(validate.RegisterValidation("username", func(fl validator.FieldLevel) bool {
+		return usernameRegex.MatchString(fl.Field().String())
+	}))
+	must(validate.RegisterValidation("password", func(fl validator.FieldLevel) bool {
+		return options.PasswordPolicy.Check(fl.Field().String()) == ""
+	}))
+
+	for locale, texts := range messages {
+		trans, _ := uni.GetTranslator(locale)
+		for key, text := range texts {
+			must(trans.Add(key, text, true))
+		}
+
+		for _, tag := range []string{"phone", "username"} {
+			must(validate.RegisterTranslation(tag, trans, noopRegister, translateTag))
+		}
+		must(validate.RegisterTranslation("password", trans, noopRegister, translatePassword))
+	}
+}
+
+// NewFieldError builds a translated field error for checks that run outside
+// struct validation, such as uniqueness
+func NewFieldError(field, rule, param, acceptLanguage string) apperror.FieldError {
+	_, trans := Translator(acceptLanguage)
+
+	message, err := trans.T(rule, field, param)
+	if err != nil {
+		message = field + " is invalid"
+	}
+
+	return apperror.FieldError{
+		Field:   field,
+		Rule:    rule,
+		Param:   param,
+		Message: message,
+	}
+}
+
+// noopRegister is used because the messages are added up front
+func noopRegister(ut.Translator) error {
+	return nil
+}
+
+// translateTag translates a custom tag keyed by its name
+func translateTag(trans ut.Translator, fe validator.FieldError) string {
+	message, err := trans.T(fe.Tag(), fe.Field())
+	if err != nil {
+		return fe.Error()
+	}
+	return message
+}
+
+// translatePassword explains which part of the password policy failed
+func translatePassword(trans ut.Translator, fe validator.FieldError) string {
+	policy := options.PasswordPolicy
+	password, _ := fe.Value().(string)
+	reason := policy.Check(password)
+
+	param := ""
+	switch reason {
+	case PasswordTooShort:
+		param = strconv.Itoa(policy.MinLength)
+	case PasswordTooLong:
+		param = strconv.Itoa(policy.MaxLength)
+	}
+
+	message, err := trans.T("password_"+reason, fe.Field(), param)
+	if err != nil {
+		return fe.Error()
+	}
+	return message
+}
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
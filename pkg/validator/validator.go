@@ -1,13 +1,59 @@
 package validator
 
 import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"boilerplate-go-fiber-v2/pkg/apperror"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	id_translations "github.com/go-playground/validator/v10/translations/id"
+	"golang.org/x/text/language"
 )
 
-var validate *validator.Validate
+// ErrValidationFailed is returned when a struct fails validation; the
+// failing fields are attached as field errors
+var ErrValidationFailed = apperror.Validation("validation_failed", "Validation failed")
+
+// DefaultLocale is used when the client accepts none of the supported locales
+const DefaultLocale = "en"
+
+var (
+	validate *validator.Validate
+	uni      *ut.UniversalTranslator
+)
+
+// summaries translates the top-level validation message
+var summaries = map[string]string{
+	"en": "Validation failed",
+	"id": "Validasi gagal",
+}
 
 func init() {
 	validate = validator.New()
+
+	// Report fields by the name clients send them as
+	validate.RegisterTagNameFunc(fieldName)
+
+	english := en.New()
+	uni = ut.New(english, english, id.New())
+
+	enTrans, _ := uni.GetTranslator("en")
+	if err := en_translations.RegisterDefaultTranslations(validate, enTrans); err != nil {
+		panic(err)
+	}
+
+	idTrans, _ := uni.GetTranslator("id")
+	if err := id_translations.RegisterDefaultTranslations(validate, idTrans); err != nil {
+		panic(err)
+	}
+
+	registerRules()
 }
 
 // GetValidator returns the validator instance
@@ -15,7 +61,74 @@ func GetValidator() *validator.Validate {
 	return validate
 }
 
-// ValidateStruct validates a struct
+// ValidateStruct validates a struct, reporting field errors in English
 func ValidateStruct(s interface{}) error {
-	return validate.Struct(s)
+	return Validate(s, DefaultLocale)
+}
+
+// Validate validates a struct and translates field errors to the best match
+// of an Accept-Language header
+func Validate(s interface{}, acceptLanguage string) error {
+	err := validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return apperror.Internal(err)
+	}
+
+	locale, trans := Translator(acceptLanguage)
+
+	fields := make([]apperror.FieldError, len(validationErrors))
+	for i, fe := range validationErrors {
+		fields[i] = apperror.FieldError{
+			Field:   fieldPath(fe),
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: fe.Translate(trans),
+		}
+	}
+
+	return ErrValidationFailed.WithMessage(summaries[locale]).WithFields(fields...)
+}
+
+// Translator returns the supported locale and translator that best match an
+// Accept-Language header
+func Translator(acceptLanguage string) (string, ut.Translator) {
+	tags, _, _ := language.ParseAcceptLanguage(acceptLanguage)
+
+	for _, tag := range tags {
+		base, _ := tag.Base()
+		if trans, found := uni.GetTranslator(base.String()); found {
+			return trans.Locale(), trans
+		}
+	}
+
+	trans, _ := uni.GetTranslator(DefaultLocale)
+	return DefaultLocale, trans
+}
+
+// fieldName returns the client-facing name of a struct field
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "query", "params", "reqHeader", "form"} {
+		name := strings.SplitN(field.Tag.Get(key), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// fieldPath returns the dotted path of a field without the root struct name
+func fieldPath(fe validator.FieldError) string {
+	namespace := fe.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return fe.Field()
 }
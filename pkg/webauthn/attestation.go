@@ -0,0 +1,119 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+)
+
+// Attestation types
+const (
+	AttestationTypeNone  = "none"
+	AttestationTypeSelf  = "self"
+	AttestationTypeBasic = "basic"
+)
+
+// oidAAGUID is the FIDO extension that carries the authenticator's AAGUID
+// in an attestation certificate
+var oidAAGUID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 45724, 1, 1, 4}
+
+// verifyAttestation checks an attestation statement and returns the
+// attestation type (WebAuthn Level 2 section 8)
+func verifyAttestation(format string, statement map[interface{}]interface{}, rawAuthData, clientDataHash []byte, authData *authenticatorData, publicKey *PublicKey) (string, error) {
+	switch format {
+	case AttestationFormatNone:
+		if len(statement) != 0 {
+			return "", fmt.Errorf("%w: none attestation with a statement", ErrInvalidAttestation)
+		}
+		return AttestationTypeNone, nil
+	case AttestationFormatPacked:
+		return verifyPacked(statement, signedData(rawAuthData, clientDataHash), authData, publicKey)
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedAttestation, format)
+	}
+}
+
+// verifyPacked checks a "packed" attestation statement (WebAuthn Level 2
+// section 8.2), either signed by an attestation certificate or by the
+// credential itself
+func verifyPacked(statement map[interface{}]interface{}, signed []byte, authData *authenticatorData, publicKey *PublicKey) (string, error) {
+	alg, ok := statement["alg"].(int64)
+	if !ok {
+		return "", fmt.Errorf("%w: packed attestation without alg", ErrInvalidAttestation)
+	}
+	sig, ok := statement["sig"].([]byte)
+	if !ok {
+		return "", fmt.Errorf("%w: packed attestation without sig", ErrInvalidAttestation)
+	}
+
+	x5c, hasX5C := statement["x5c"].([]interface{})
+	if !hasX5C {
+		// Self attestation: signed with the credential private key
+		if alg != publicKey.Algorithm {
+			return "", fmt.Errorf("%w: self attestation algorithm differs from the credential", ErrInvalidAttestation)
+		}
+		if err := publicKey.Verify(signed, sig); err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidAttestation, err)
+		}
+		return AttestationTypeSelf, nil
+	}
+
+	if len(x5c) == 0 {
+		return "", fmt.Errorf("%w: empty certificate chain", ErrInvalidAttestation)
+	}
+	der, ok := x5c[0].([]byte)
+	if !ok {
+		return "", fmt.Errorf("%w: certificate is not a byte string", ErrInvalidAttestation)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidAttestation, err)
+	}
+
+	if err := verifySignature(alg, cert.PublicKey, signed, sig); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidAttestation, err)
+	}
+	if err := checkPackedCertificate(cert, authData.AAGUID); err != nil {
+		return "", err
+	}
+	return AttestationTypeBasic, nil
+}
+
+// checkPackedCertificate applies the attestation certificate requirements of
+// WebAuthn Level 2 section 8.2.1
+func checkPackedCertificate(cert *x509.Certificate, aaguid []byte) error {
+	if cert.Version != 3 {
+		return fmt.Errorf("%w: attestation certificate must be version 3", ErrInvalidAttestation)
+	}
+	subject := cert.Subject
+	if len(subject.Country) == 0 || len(subject.Organization) == 0 || subject.CommonName == "" ||
+		len(subject.OrganizationalUnit) != 1 || subject.OrganizationalUnit[0] != "Authenticator Attestation" {
+		return fmt.Errorf("%w: attestation certificate subject", ErrInvalidAttestation)
+	}
+	if cert.IsCA {
+		return fmt.Errorf("%w: attestation certificate is a CA", ErrInvalidAttestation)
+	}
+
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidAAGUID) {
+			continue
+		}
+		if ext.Critical {
+			return fmt.Errorf("%w: critical AAGUID extension", ErrInvalidAttestation)
+		}
+		var value []byte
+		if _, err := asn1.Unmarshal(ext.Value, &value); err != nil || !bytes.Equal(value, aaguid) {
+			return fmt.Errorf("%w: AAGUID does not match the authenticator", ErrInvalidAttestation)
+		}
+	}
+	return nil
+}
+
+// signedData is what authenticators sign: authenticator data followed by
+// the hash of the client data
+func signedData(rawAuthData, clientDataHash []byte) []byte {
+	data := make([]byte, 0, len(rawAuthData)+len(clientDataHash))
+	data = append(data, rawAuthData...)
+	return append(data, clientDataHash...)
+}
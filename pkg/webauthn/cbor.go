@@ -0,0 +1,189 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// errCBOR reports malformed CBOR (RFC 8949)
+var errCBOR = errors.New("webauthn: malformed cbor")
+
+// maxCBORDepth bounds nesting so hostile input cannot exhaust the stack
+const maxCBORDepth = 16
+
+// decodeCBOR decodes the first CBOR item in data and returns it with the
+// number of bytes it used. Authenticators encode definite lengths only
+// (CTAP2 canonical CBOR), so indefinite lengths are rejected. Integers
+// decode to int64, byte strings to []byte, text to string, arrays to
+// []interface{} and maps to map[interface{}]interface{}; tags are dropped.
+func decodeCBOR(data []byte) (interface{}, int, error) {
+	d := &cborDecoder{data: data}
+	v, err := d.decode(0)
+	if err != nil {
+		return nil, 0, err
+	}
+	return v, d.pos, nil
+}
+
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *cborDecoder) decode(depth int) (interface{}, error) {
+	if depth > maxCBORDepth {
+		return nil, fmt.Errorf("%w: nested too deeply", errCBOR)
+	}
+	if d.pos >= len(d.data) {
+		return nil, fmt.Errorf("%w: unexpected end", errCBOR)
+	}
+
+	initial := d.data[d.pos]
+	d.pos++
+	major, info := initial>>5, initial&0x1f
+
+	if major == 7 {
+		return d.simple(info)
+	}
+
+	arg, err := d.argument(info)
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case 0:
+		if arg > math.MaxInt64 {
+			return nil, fmt.Errorf("%w: integer overflow", errCBOR)
+		}
+		return int64(arg), nil
+	case 1:
+		if arg > math.MaxInt64 {
+			return nil, fmt.Errorf("%w: integer overflow", errCBOR)
+		}
+		return -1 - int64(arg), nil
+	case 2:
+		b, err := d.bytes(arg)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), b...), nil
+	case 3:
+		b, err := d.bytes(arg)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case 4:
+		if arg > uint64(len(d.data)-d.pos) {
+			return nil, fmt.Errorf("%w: array too long", errCBOR)
+		}
+		items := make([]interface{}, 0, arg)
+		for i := uint64(0); i < arg; i++ {
+			item, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case 5:
+		if arg > uint64(len(d.data)-d.pos) {
+			return nil, fmt.Errorf("%w: map too long", errCBOR)
+		}
+		m := make(map[interface{}]interface{}, arg)
+		for i := uint64(0); i < arg; i++ {
+			key, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, fmt.Errorf("%w: unsupported map key", errCBOR)
+			}
+			if _, ok := m[key]; ok {
+				return nil, fmt.Errorf("%w: duplicate map key", errCBOR)
+			}
+			value, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = value
+		}
+		return m, nil
+	default: // 6, tags
+		return d.decode(depth + 1)
+	}
+}
+
+// argument reads the length or value that follows an initial byte
+func (d *cborDecoder) argument(info byte) (uint64, error) {
+	var size int
+	switch {
+	case info < 24:
+		return uint64(info), nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		return 0, fmt.Errorf("%w: indefinite or reserved length", errCBOR)
+	}
+
+	b, err := d.bytes(uint64(size))
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	default:
+		return binary.BigEndian.Uint64(b), nil
+	}
+}
+
+// simple decodes major type 7: false, true, null, undefined and floats
+func (d *cborDecoder) simple(info byte) (interface{}, error) {
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 26:
+		b, err := d.bytes(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case 27:
+		b, err := d.bytes(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported simple value", errCBOR)
+	}
+}
+
+func (d *cborDecoder) bytes(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, fmt.Errorf("%w: unexpected end", errCBOR)
+	}
+	b := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
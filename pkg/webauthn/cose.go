@@ -0,0 +1,134 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+)
+
+// COSE algorithms (RFC 9053) accepted for credentials, in order of preference
+const (
+	AlgES256 int64 = -7
+	AlgEdDSA int64 = -8
+	AlgRS256 int64 = -257
+)
+
+// COSE key types and curves
+const (
+	coseKeyTypeOKP = 1
+	coseKeyTypeEC2 = 2
+	coseKeyTypeRSA = 3
+
+	coseCurveP256    = 1
+	coseCurveEd25519 = 6
+)
+
+// COSE key parameters
+const (
+	coseKeyKty = 1
+	coseKeyAlg = 3
+	coseKeyCrv = -1 // EC2 and OKP
+	coseKeyX   = -2 // EC2 and OKP
+	coseKeyY   = -3 // EC2
+	coseKeyN   = -1 // RSA
+	coseKeyE   = -2 // RSA
+)
+
+// PublicKey is a credential public key decoded from its COSE form
+type PublicKey struct {
+	Algorithm int64
+	key       crypto.PublicKey
+}
+
+// ParsePublicKey decodes a COSE_Key (RFC 9052 section 7) as stored with a
+// credential
+func ParsePublicKey(cose []byte) (*PublicKey, error) {
+	v, n, err := decodeCBOR(cose)
+	if err != nil {
+		return nil, err
+	}
+	if n != len(cose) {
+		return nil, fmt.Errorf("%w: trailing data after key", errCBOR)
+	}
+	m, ok := v.(map[interface{}]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: key is not a map", ErrUnsupportedAlgorithm)
+	}
+	return publicKeyFromMap(m)
+}
+
+func publicKeyFromMap(m map[interface{}]interface{}) (*PublicKey, error) {
+	kty, _ := m[int64(coseKeyKty)].(int64)
+	alg, _ := m[int64(coseKeyAlg)].(int64)
+
+	switch {
+	case kty == coseKeyTypeEC2 && alg == AlgES256:
+		crv, _ := m[int64(coseKeyCrv)].(int64)
+		x, _ := m[int64(coseKeyX)].([]byte)
+		y, _ := m[int64(coseKeyY)].([]byte)
+		if crv != coseCurveP256 || len(x) != 32 || len(y) != 32 {
+			return nil, fmt.Errorf("%w: bad P-256 key", ErrUnsupportedAlgorithm)
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, fmt.Errorf("%w: point not on curve", ErrUnsupportedAlgorithm)
+		}
+		return &PublicKey{Algorithm: alg, key: key}, nil
+
+	case kty == coseKeyTypeOKP && alg == AlgEdDSA:
+		crv, _ := m[int64(coseKeyCrv)].(int64)
+		x, _ := m[int64(coseKeyX)].([]byte)
+		if crv != coseCurveEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w: bad Ed25519 key", ErrUnsupportedAlgorithm)
+		}
+		return &PublicKey{Algorithm: alg, key: ed25519.PublicKey(x)}, nil
+
+	case kty == coseKeyTypeRSA && alg == AlgRS256:
+		n, _ := m[int64(coseKeyN)].([]byte)
+		e, _ := m[int64(coseKeyE)].([]byte)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("%w: bad RSA key", ErrUnsupportedAlgorithm)
+		}
+		exponent := int(new(big.Int).SetBytes(e).Int64())
+		return &PublicKey{Algorithm: alg, key: &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}}, nil
+
+	default:
+		return nil, fmt.Errorf("%w: key type %d, algorithm %d", ErrUnsupportedAlgorithm, kty, alg)
+	}
+}
+
+// Verify checks a signature made by the credential over data
+func (k *PublicKey) Verify(data, sig []byte) error {
+	return verifySignature(k.Algorithm, k.key, data, sig)
+}
+
+// verifySignature checks sig over data with a key of a COSE algorithm
+func verifySignature(alg int64, key crypto.PublicKey, data, sig []byte) error {
+	switch alg {
+	case AlgES256:
+		pub, ok := key.(*ecdsa.PublicKey)
+		digest := sha256.Sum256(data)
+		if ok && ecdsa.VerifyASN1(pub, digest[:], sig) {
+			return nil
+		}
+	case AlgEdDSA:
+		pub, ok := key.(ed25519.PublicKey)
+		if ok && ed25519.Verify(pub, data, sig) {
+			return nil
+		}
+	case AlgRS256:
+		pub, ok := key.(*rsa.PublicKey)
+		digest := sha256.Sum256(data)
+		if ok && rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig) == nil {
+			return nil
+		}
+	default:
+		return fmt.Errorf("%w: algorithm %d", ErrUnsupportedAlgorithm, alg)
+	}
+	return ErrInvalidSignature
+}
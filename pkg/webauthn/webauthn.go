@@ -0,0 +1,527 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Errors returned while checking ceremony responses
+var (
+	ErrInvalidResponse        = errors.New("webauthn: malformed response")
+	ErrChallengeMismatch      = errors.New("webauthn: challenge mismatch")
+	ErrOriginMismatch         = errors.New("webauthn: origin not allowed")
+	ErrRPIDMismatch           = errors.New("webauthn: relying party id mismatch")
+	ErrUserNotPresent         = errors.New("webauthn: user presence not confirmed")
+	ErrUserNotVerified        = errors.New("webauthn: user verification required")
+	ErrUnsupportedAlgorithm   = errors.New("webauthn: unsupported key algorithm")
+	ErrUnsupportedAttestation = errors.New("webauthn: unsupported attestation format")
+	ErrInvalidAttestation     = errors.New("webauthn: invalid attestation")
+	ErrInvalidSignature       = errors.New("webauthn: invalid signature")
+	ErrSignCount              = errors.New("webauthn: signature counter went backwards, the authenticator may be cloned")
+)
+
+// User verification requirements
+const (
+	UserVerificationRequired    = "required"
+	UserVerificationPreferred   = "preferred"
+	UserVerificationDiscouraged = "discouraged"
+)
+
+// Attestation formats (WebAuthn Level 2 section 8)
+const (
+	AttestationFormatNone   = "none"
+	AttestationFormatPacked = "packed"
+)
+
+// Authenticator data flags
+const (
+	flagUserPresent    = 0x01
+	flagUserVerified   = 0x04
+	flagBackupEligible = 0x08
+	flagBackedUp       = 0x10
+	flagAttestedData   = 0x40
+	flagExtensionData  = 0x80
+)
+
+// Config describes the relying party
+type Config struct {
+	RPID             string   // effective domain, e.g. example.com
+	RPName           string   // shown by the authenticator
+	Origins          []string // allowed origins, e.g. https://app.example.com
+	Timeout          time.Duration
+	UserVerification string // required, preferred or discouraged
+	Attestation      string // attestation conveyance: none, indirect or direct
+}
+
+// RelyingParty runs registration and authentication ceremonies (WebAuthn
+// Level 2 sections 7.1 and 7.2). It keeps no state; callers store the
+// challenge between the options and the response.
+type RelyingParty struct {
+	config Config
+}
+
+// New creates a new relying party
+func New(config Config) *RelyingParty {
+	if config.UserVerification == "" {
+		config.UserVerification = UserVerificationPreferred
+	}
+	if config.Attestation == "" {
+		config.Attestation = "none"
+	}
+	return &RelyingParty{config: config}
+}
+
+// NewChallenge returns a random base64url challenge
+func NewChallenge() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(b), nil
+}
+
+var encoding = base64.RawURLEncoding
+
+// decode accepts base64url with or without padding, as browsers and
+// libraries differ
+func decode(s string) ([]byte, error) {
+	return encoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// User is the account a credential is created for. ID is the opaque user
+// handle and must not contain personal data.
+type User struct {
+	ID          []byte
+	Name        string
+	DisplayName string
+}
+
+// CredentialDescriptor identifies an existing credential
+type CredentialDescriptor struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+// NewCredentialDescriptor describes a credential by its raw ID
+func NewCredentialDescriptor(id []byte, transports []string) CredentialDescriptor {
+	return CredentialDescriptor{Type: "public-key", ID: encoding.EncodeToString(id), Transports: transports}
+}
+
+// CredentialParameter is a key type the relying party accepts
+type CredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+// CreationOptions are passed to navigator.credentials.create, in the JSON
+// form of PublicKeyCredentialCreationOptions
+type CreationOptions struct {
+	RP struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"rp"`
+	User struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+	} `json:"user"`
+	Challenge              string                 `json:"challenge"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection struct {
+		ResidentKey        string `json:"residentKey"`
+		RequireResidentKey bool   `json:"requireResidentKey"`
+		UserVerification   string `json:"userVerification"`
+	} `json:"authenticatorSelection"`
+	Attestation string `json:"attestation"`
+}
+
+// RequestOptions are passed to navigator.credentials.get, in the JSON form
+// of PublicKeyCredentialRequestOptions
+type RequestOptions struct {
+	Challenge        string                 `json:"challenge"`
+	Timeout          int64                  `json:"timeout"`
+	RPID             string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+// CreationOptions returns the options that register a discoverable
+// credential (a passkey) for user. Credentials in exclude are not created
+// again on the same authenticator.
+func (rp *RelyingParty) CreationOptions(challenge string, user User, exclude []CredentialDescriptor) *CreationOptions {
+	opts := &CreationOptions{
+		Challenge:          challenge,
+		Timeout:            rp.config.Timeout.Milliseconds(),
+		ExcludeCredentials: exclude,
+		Attestation:        rp.config.Attestation,
+	}
+	if opts.ExcludeCredentials == nil {
+		opts.ExcludeCredentials = []CredentialDescriptor{}
+	}
+	opts.RP.ID = rp.config.RPID
+	opts.RP.Name = rp.config.RPName
+	opts.User.ID = encoding.EncodeToString(user.ID)
+	opts.User.Name = user.Name
+	opts.User.DisplayName = user.DisplayName
+	for _, alg := range []int64{AlgES256, AlgEdDSA, AlgRS256} {
+		opts.PubKeyCredParams = append(opts.PubKeyCredParams, CredentialParameter{Type: "public-key", Alg: alg})
+	}
+	opts.AuthenticatorSelection.ResidentKey = "required"
+	opts.AuthenticatorSelection.RequireResidentKey = true
+	opts.AuthenticatorSelection.UserVerification = rp.config.UserVerification
+	return opts
+}
+
+// RequestOptions returns the options that ask for an assertion. An empty
+// allow list lets the user pick any passkey for the relying party.
+func (rp *RelyingParty) RequestOptions(challenge string, allow []CredentialDescriptor) *RequestOptions {
+	if allow == nil {
+		allow = []CredentialDescriptor{}
+	}
+	return &RequestOptions{
+		Challenge:        challenge,
+		Timeout:          rp.config.Timeout.Milliseconds(),
+		RPID:             rp.config.RPID,
+		AllowCredentials: allow,
+		UserVerification: rp.config.UserVerification,
+	}
+}
+
+// RegistrationResponse is the JSON form of a PublicKeyCredential returned
+// by navigator.credentials.create
+type RegistrationResponse struct {
+	ID       string `json:"id"`
+	RawID    string `json:"rawId"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string   `json:"clientDataJSON"`
+		AttestationObject string   `json:"attestationObject"`
+		Transports        []string `json:"transports"`
+	} `json:"response"`
+}
+
+// AssertionResponse is the JSON form of a PublicKeyCredential returned by
+// navigator.credentials.get
+type AssertionResponse struct {
+	ID       string `json:"id"`
+	RawID    string `json:"rawId"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string `json:"clientDataJSON"`
+		AuthenticatorData string `json:"authenticatorData"`
+		Signature         string `json:"signature"`
+		UserHandle        string `json:"userHandle"`
+	} `json:"response"`
+}
+
+// Challenge returns the challenge the response answers, so the caller can
+// find the ceremony it belongs to. It is checked again during verification.
+func (r *RegistrationResponse) Challenge() (string, error) {
+	cd, _, err := parseClientData(r.Response.ClientDataJSON)
+	if err != nil {
+		return "", err
+	}
+	return cd.Challenge, nil
+}
+
+// Challenge returns the challenge the response answers
+func (r *AssertionResponse) Challenge() (string, error) {
+	cd, _, err := parseClientData(r.Response.ClientDataJSON)
+	if err != nil {
+		return "", err
+	}
+	return cd.Challenge, nil
+}
+
+// CredentialID returns the raw credential ID of the response
+func (r *AssertionResponse) CredentialID() ([]byte, error) {
+	id, err := decode(r.RawID)
+	if err != nil || len(id) == 0 {
+		return nil, fmt.Errorf("%w: credential id", ErrInvalidResponse)
+	}
+	return id, nil
+}
+
+// UserHandle returns the user handle of a discoverable credential, if the
+// authenticator returned one
+func (r *AssertionResponse) UserHandle() ([]byte, error) {
+	if r.Response.UserHandle == "" {
+		return nil, nil
+	}
+	handle, err := decode(r.Response.UserHandle)
+	if err != nil {
+		return nil, fmt.Errorf("%w: user handle", ErrInvalidResponse)
+	}
+	return handle, nil
+}
+
+// Credential is a verified new credential, ready to be stored
+type Credential struct {
+	ID                []byte
+	PublicKey         []byte // COSE_Key, see ParsePublicKey
+	Algorithm         int64
+	SignCount         uint32
+	AAGUID            []byte
+	AttestationFormat string
+	AttestationType   string // none, self or basic
+	Transports        []string
+	UserVerified      bool
+	BackupEligible    bool
+	BackedUp          bool
+}
+
+// Assertion is the result of a verified authentication
+type Assertion struct {
+	SignCount    uint32
+	UserVerified bool
+	BackedUp     bool
+}
+
+// VerifyRegistration checks a registration response against the challenge
+// it was issued with and returns the new credential (WebAuthn Level 2
+// section 7.1). Attestation statements in the "none" and "packed" formats
+// are verified; certificate chains are not checked against trust anchors.
+func (rp *RelyingParty) VerifyRegistration(resp *RegistrationResponse, challenge string) (*Credential, error) {
+	if resp.Type != "public-key" {
+		return nil, fmt.Errorf("%w: credential type", ErrInvalidResponse)
+	}
+
+	cd, clientDataJSON, err := parseClientData(resp.Response.ClientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+	if err := rp.checkClientData(cd, "webauthn.create", challenge); err != nil {
+		return nil, err
+	}
+
+	rawAttestation, err := decode(resp.Response.AttestationObject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: attestation object", ErrInvalidResponse)
+	}
+	v, n, err := decodeCBOR(rawAttestation)
+	if err != nil {
+		return nil, err
+	}
+	attestation, ok := v.(map[interface{}]interface{})
+	if !ok || n != len(rawAttestation) {
+		return nil, fmt.Errorf("%w: attestation object", ErrInvalidResponse)
+	}
+	format, _ := attestation["fmt"].(string)
+	rawAuthData, _ := attestation["authData"].([]byte)
+	statement, _ := attestation["attStmt"].(map[interface{}]interface{})
+	if statement == nil {
+		return nil, fmt.Errorf("%w: attestation statement", ErrInvalidResponse)
+	}
+
+	authData, err := parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if err := rp.checkAuthenticatorData(authData); err != nil {
+		return nil, err
+	}
+	if authData.Flags&flagAttestedData == 0 {
+		return nil, fmt.Errorf("%w: no attested credential data", ErrInvalidResponse)
+	}
+
+	publicKey, err := ParsePublicKey(authData.CredentialPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	rawID, err := decode(resp.RawID)
+	if err != nil || !bytes.Equal(rawID, authData.CredentialID) {
+		return nil, fmt.Errorf("%w: credential id", ErrInvalidResponse)
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	attestationType, err := verifyAttestation(format, statement, rawAuthData, clientDataHash[:], authData, publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Credential{
+		ID:                authData.CredentialID,
+		PublicKey:         authData.CredentialPublicKey,
+		Algorithm:         publicKey.Algorithm,
+		SignCount:         authData.SignCount,
+		AAGUID:            authData.AAGUID,
+		AttestationFormat: format,
+		AttestationType:   attestationType,
+		Transports:        resp.Response.Transports,
+		UserVerified:      authData.Flags&flagUserVerified != 0,
+		BackupEligible:    authData.Flags&flagBackupEligible != 0,
+		BackedUp:          authData.Flags&flagBackedUp != 0,
+	}, nil
+}
+
+// VerifyAssertion checks an authentication response against the challenge
+// it was issued with and the stored credential (WebAuthn Level 2 section
+// 7.2). signCount is the counter stored with the credential.
+func (rp *RelyingParty) VerifyAssertion(resp *AssertionResponse, challenge string, publicKey []byte, signCount uint32) (*Assertion, error) {
+	if resp.Type != "public-key" {
+		return nil, fmt.Errorf("%w: credential type", ErrInvalidResponse)
+	}
+
+	cd, clientDataJSON, err := parseClientData(resp.Response.ClientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+	if err := rp.checkClientData(cd, "webauthn.get", challenge); err != nil {
+		return nil, err
+	}
+
+	rawAuthData, err := decode(resp.Response.AuthenticatorData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: authenticator data", ErrInvalidResponse)
+	}
+	authData, err := parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if err := rp.checkAuthenticatorData(authData); err != nil {
+		return nil, err
+	}
+
+	sig, err := decode(resp.Response.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature", ErrInvalidResponse)
+	}
+
+	key, err := ParsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	if err := key.Verify(signedData(rawAuthData, clientDataHash[:]), sig); err != nil {
+		return nil, err
+	}
+
+	// Authenticators without a counter always report 0
+	if (authData.SignCount != 0 || signCount != 0) && authData.SignCount <= signCount {
+		return nil, ErrSignCount
+	}
+
+	return &Assertion{
+		SignCount:    authData.SignCount,
+		UserVerified: authData.Flags&flagUserVerified != 0,
+		BackedUp:     authData.Flags&flagBackedUp != 0,
+	}, nil
+}
+
+// clientData is the client data collected by the browser
+type clientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+func parseClientData(encoded string) (*clientData, []byte, error) {
+	raw, err := decode(encoded)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: client data", ErrInvalidResponse)
+	}
+	var cd clientData
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return nil, nil, fmt.Errorf("%w: client data", ErrInvalidResponse)
+	}
+	return &cd, raw, nil
+}
+
+func (rp *RelyingParty) checkClientData(cd *clientData, ceremony, challenge string) error {
+	if cd.Type != ceremony {
+		return fmt.Errorf("%w: client data type %q", ErrInvalidResponse, cd.Type)
+	}
+	if subtle.ConstantTimeCompare([]byte(cd.Challenge), []byte(challenge)) != 1 {
+		return ErrChallengeMismatch
+	}
+	if cd.CrossOrigin || !slices.Contains(rp.config.Origins, cd.Origin) {
+		return ErrOriginMismatch
+	}
+	return nil
+}
+
+// authenticatorData is the parsed authenticator data (WebAuthn Level 2
+// section 6.1)
+type authenticatorData struct {
+	RPIDHash            []byte
+	Flags               byte
+	SignCount           uint32
+	AAGUID              []byte
+	CredentialID        []byte
+	CredentialPublicKey []byte
+}
+
+func parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	if len(data) < 37 {
+		return nil, fmt.Errorf("%w: authenticator data too short", ErrInvalidResponse)
+	}
+	ad := &authenticatorData{
+		RPIDHash:  data[:32],
+		Flags:     data[32],
+		SignCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+	rest := data[37:]
+
+	if ad.Flags&flagAttestedData != 0 {
+		if len(rest) < 18 {
+			return nil, fmt.Errorf("%w: attested credential data too short", ErrInvalidResponse)
+		}
+		ad.AAGUID = rest[:16]
+		idLen := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if idLen == 0 || idLen > 1023 || len(rest) < idLen {
+			return nil, fmt.Errorf("%w: credential id length", ErrInvalidResponse)
+		}
+		ad.CredentialID = rest[:idLen]
+		rest = rest[idLen:]
+
+		_, n, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, err
+		}
+		ad.CredentialPublicKey = rest[:n]
+		rest = rest[n:]
+	}
+
+	if ad.Flags&flagExtensionData != 0 {
+		_, n, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, err
+		}
+		rest = rest[n:]
+	}
+
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%w: trailing authenticator data", ErrInvalidResponse)
+	}
+	return ad, nil
+}
+
+func (rp *RelyingParty) checkAuthenticatorData(ad *authenticatorData) error {
+	rpIDHash := sha256.Sum256([]byte(rp.config.RPID))
+	if !bytes.Equal(ad.RPIDHash, rpIDHash[:]) {
+		return ErrRPIDMismatch
+	}
+	if ad.Flags&flagUserPresent == 0 {
+		return ErrUserNotPresent
+	}
+	if rp.config.UserVerification == UserVerificationRequired && ad.Flags&flagUserVerified == 0 {
+		return ErrUserNotVerified
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+const (
+	testRPID   = "example.com"
+	testOrigin = "https://app.example.com"
+)
+
+// testAuthenticator signs assertions with a P-256 key, like a platform
+// authenticator would
+type testAuthenticator struct {
+	key *ecdsa.PrivateKey
+}
+
+func newTestAuthenticator(t *testing.T) *testAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return &testAuthenticator{key: key}
+}
+
+// publicKey returns the COSE_Key of the authenticator:
+// {1: 2, 3: -7, -1: 1, -2: x, -3: y}
+func (a *testAuthenticator) publicKey() []byte {
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	a.key.X.FillBytes(x)
+	a.key.Y.FillBytes(y)
+
+	cose := []byte{0xa5, 0x01, 0x02, 0x03, 0x26, 0x20, 0x01, 0x21, 0x58, 0x20}
+	cose = append(cose, x...)
+	cose = append(cose, 0x22, 0x58, 0x20)
+	return append(cose, y...)
+}
+
+// assertion describes what the authenticator and browser report
+type assertion struct {
+	rpID       string
+	flags      byte
+	signCount  uint32
+	extensions []byte // raw CBOR appended after the counter
+	clientData clientData
+}
+
+func defaultAssertion(challenge string) assertion {
+	return assertion{
+		rpID:      testRPID,
+		flags:     flagUserPresent,
+		signCount: 11,
+		clientData: clientData{
+			Type:      "webauthn.get",
+			Challenge: challenge,
+			Origin:    testOrigin,
+		},
+	}
+}
+
+func (a *testAuthenticator) sign(t *testing.T, in assertion) *AssertionResponse {
+	t.Helper()
+
+	rpIDHash := sha256.Sum256([]byte(in.rpID))
+	authData := append([]byte(nil), rpIDHash[:]...)
+	authData = append(authData, in.flags)
+	authData = binary.BigEndian.AppendUint32(authData, in.signCount)
+	authData = append(authData, in.extensions...)
+
+	clientDataJSON, err := json.Marshal(in.clientData)
+	if err != nil {
+		t.Fatalf("marshal client data: %v", err)
+	}
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(signedData(authData, clientDataHash[:]))
+	sig, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	resp := &AssertionResponse{ID: "cred", RawID: encoding.EncodeToString([]byte("cred")), Type: "public-key"}
+	resp.Response.ClientDataJSON = encoding.EncodeToString(clientDataJSON)
+	resp.Response.AuthenticatorData = encoding.EncodeToString(authData)
+	resp.Response.Signature = encoding.EncodeToString(sig)
+	return resp
+}
+
+func TestVerifyAssertion(t *testing.T) {
+	const challenge = "c2lnbi1tZS1pbg"
+	authenticator := newTestAuthenticator(t)
+	other := newTestAuthenticator(t)
+
+	tests := []struct {
+		name             string
+		modify           func(in *assertion)
+		tamper           func(resp *AssertionResponse)
+		publicKey        []byte
+		storedCount      uint32
+		userVerification string
+		wantErr          error
+		wantUV           bool
+	}{
+		{name: "valid"},
+		{name: "valid with user verification", modify: func(in *assertion) { in.flags |= flagUserVerified }, wantUV: true},
+		{name: "valid extension data", modify: func(in *assertion) {
+			in.flags |= flagExtensionData
+			in.extensions = []byte{0xa1, 0x63, 'c', 'r', 'd', 0xf5} // {"crd": true}
+		}},
+		{name: "counter that moved forward", storedCount: 10},
+		{name: "authenticator without a counter", modify: func(in *assertion) { in.signCount = 0 }},
+		{name: "counter that went backwards", storedCount: 12, wantErr: ErrSignCount},
+		{name: "counter that did not move", storedCount: 11, wantErr: ErrSignCount},
+		{name: "counter reset to zero", modify: func(in *assertion) { in.signCount = 0 }, storedCount: 5, wantErr: ErrSignCount},
+		{name: "rpIdHash of another site", modify: func(in *assertion) { in.rpID = "evil.example" }, wantErr: ErrRPIDMismatch},
+		{name: "user presence missing", modify: func(in *assertion) { in.flags = flagUserVerified }, wantErr: ErrUserNotPresent},
+		{name: "user verification required", userVerification: UserVerificationRequired, wantErr: ErrUserNotVerified},
+		{name: "origin of another site", modify: func(in *assertion) { in.clientData.Origin = "https://evil.example" }, wantErr: ErrOriginMismatch},
+		{name: "origin with another scheme", modify: func(in *assertion) { in.clientData.Origin = "http://app.example.com" }, wantErr: ErrOriginMismatch},
+		{name: "cross-origin iframe", modify: func(in *assertion) { in.clientData.CrossOrigin = true }, wantErr: ErrOriginMismatch},
+		{name: "other challenge", modify: func(in *assertion) { in.clientData.Challenge = "b3RoZXI" }, wantErr: ErrChallengeMismatch},
+		{name: "registration client data", modify: func(in *assertion) { in.clientData.Type = "webauthn.create" }, wantErr: ErrInvalidResponse},
+		{name: "truncated extension CBOR", modify: func(in *assertion) {
+			in.flags |= flagExtensionData
+			in.extensions = []byte{0xa1, 0x63, 'c', 'r'}
+		}, wantErr: errCBOR},
+		{name: "trailing authenticator data", modify: func(in *assertion) { in.extensions = []byte{0x00} }, wantErr: ErrInvalidResponse},
+		{name: "truncated authenticator data", tamper: func(resp *AssertionResponse) {
+			resp.Response.AuthenticatorData = encoding.EncodeToString(make([]byte, 36))
+		}, wantErr: ErrInvalidResponse},
+		{name: "tampered authenticator data", tamper: func(resp *AssertionResponse) {
+			raw, _ := decode(resp.Response.AuthenticatorData)
+			raw[32] |= flagUserVerified
+			resp.Response.AuthenticatorData = encoding.EncodeToString(raw)
+		}, wantErr: ErrInvalidSignature},
+		{name: "signed by another key", publicKey: other.publicKey(), wantErr: ErrInvalidSignature},
+		{name: "not a public key credential", tamper: func(resp *AssertionResponse) { resp.Type = "password" }, wantErr: ErrInvalidResponse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := defaultAssertion(challenge)
+			if tt.modify != nil {
+				tt.modify(&in)
+			}
+			resp := authenticator.sign(t, in)
+			if tt.tamper != nil {
+				tt.tamper(resp)
+			}
+			publicKey := tt.publicKey
+			if publicKey == nil {
+				publicKey = authenticator.publicKey()
+			}
+
+			rp := New(Config{RPID: testRPID, Origins: []string{testOrigin}, UserVerification: tt.userVerification})
+			got, err := rp.VerifyAssertion(resp, challenge, publicKey, tt.storedCount)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("VerifyAssertion error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyAssertion: %v", err)
+			}
+			if got.SignCount != in.signCount || got.UserVerified != tt.wantUV {
+				t.Errorf("assertion = %+v, want sign count %d and user verified %v", got, in.signCount, tt.wantUV)
+			}
+		})
+	}
+}
+
+// nested wraps item in depth single-element arrays
+func nested(depth int, item byte) []byte {
+	return append(bytes.Repeat([]byte{0x81}, depth), item)
+}
+
+func TestDecodeCBOR(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    interface{}
+		wantLen int
+		wantErr bool
+	}{
+		{name: "unsigned integer", data: []byte{0x18, 0x64}, want: int64(100), wantLen: 2},
+		{name: "negative integer", data: []byte{0x26}, want: int64(-7), wantLen: 1},
+		{name: "byte string", data: []byte{0x42, 0x01, 0x02}, want: []byte{0x01, 0x02}, wantLen: 3},
+		{name: "text string", data: []byte{0x63, 'f', 'm', 't'}, want: "fmt", wantLen: 4},
+		{name: "first item only", data: []byte{0x01, 0x02}, want: int64(1), wantLen: 1},
+		{name: "tag is dropped", data: []byte{0xc1, 0x01}, want: int64(1), wantLen: 2},
+		{name: "nesting at the limit", data: nested(maxCBORDepth, 0x00), wantLen: maxCBORDepth + 1},
+
+		{name: "empty input", data: nil, wantErr: true},
+		{name: "truncated argument", data: []byte{0x19, 0x01}, wantErr: true},
+		{name: "truncated byte string", data: []byte{0x58, 0x20, 0x01, 0x02}, wantErr: true},
+		{name: "truncated text string", data: []byte{0x65, 'a', 'b'}, wantErr: true},
+		{name: "truncated array", data: []byte{0x82, 0x01}, wantErr: true},
+		{name: "truncated map value", data: []byte{0xa1, 0x01}, wantErr: true},
+		{name: "array longer than the input", data: []byte{0x9b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, wantErr: true},
+		{name: "map longer than the input", data: []byte{0xba, 0xff, 0xff, 0xff, 0xff}, wantErr: true},
+		{name: "byte string longer than the input", data: []byte{0x5b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, wantErr: true},
+		{name: "nested too deeply", data: nested(maxCBORDepth+1, 0x00), wantErr: true},
+		{name: "tags nested too deeply", data: append(bytes.Repeat([]byte{0xc1}, maxCBORDepth+1), 0x00), wantErr: true},
+		{name: "indefinite length", data: []byte{0x5f, 0x41, 0x01, 0xff}, wantErr: true},
+		{name: "integer overflow", data: []byte{0x1b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, wantErr: true},
+		{name: "duplicate map key", data: []byte{0xa2, 0x01, 0x02, 0x01, 0x03}, wantErr: true},
+		{name: "byte string map key", data: []byte{0xa1, 0x41, 0x01, 0x02}, wantErr: true},
+		{name: "unsupported simple value", data: []byte{0xf8, 0x20}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, n, err := decodeCBOR(tt.data)
+			if tt.wantErr {
+				if !errors.Is(err, errCBOR) {
+					t.Fatalf("decodeCBOR error = %v, want %v", err, errCBOR)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeCBOR: %v", err)
+			}
+			if n != tt.wantLen {
+				t.Errorf("decodeCBOR used %d bytes, want %d", n, tt.wantLen)
+			}
+			if tt.want != nil {
+				gotJSON, _ := json.Marshal(got)
+				wantJSON, _ := json.Marshal(tt.want)
+				if !bytes.Equal(gotJSON, wantJSON) {
+					t.Errorf("decodeCBOR = %#v, want %#v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	valid := newTestAuthenticator(t).publicKey()
+
+	tests := []struct {
+		name    string
+		cose    []byte
+		wantErr error
+	}{
+		{name: "P-256 key", cose: valid},
+		{name: "trailing data", cose: append(append([]byte(nil), valid...), 0x00), wantErr: errCBOR},
+		{name: "truncated key", cose: valid[:len(valid)-1], wantErr: errCBOR},
+		{name: "not a map", cose: []byte{0x80}, wantErr: ErrUnsupportedAlgorithm},
+		{name: "point not on the curve", cose: func() []byte {
+			cose := append([]byte(nil), valid...)
+			cose[len(cose)-1] ^= 0x01
+			return cose
+		}(), wantErr: ErrUnsupportedAlgorithm},
+		{name: "unknown algorithm", cose: []byte{0xa2, 0x01, 0x02, 0x03, 0x38, 0x22}, wantErr: ErrUnsupportedAlgorithm},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := ParsePublicKey(tt.cose)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParsePublicKey error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePublicKey: %v", err)
+			}
+			if key.Algorithm != AlgES256 {
+				t.Errorf("algorithm = %d, want %d", key.Algorithm, AlgES256)
+			}
+		})
+	}
+}